@@ -0,0 +1,98 @@
+package graph_test
+
+import (
+	"bytes"
+	"github.com/nathankerr/graph"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encoding"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The subpackages whose names package graph gives under their old flat-package names
+var splitPackages = []string{"core", "simple", "path", "topo", "flow", "gen", "encoding"}
+
+// Returns the exported top-level names declared in the non-test files of a directory, with the file each is in
+func exportedNames(t *testing.T, dir string) map[string]string {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make(map[string]string)
+	for _, pkg := range pkgs {
+		for file, f := range pkg.Files {
+			for name := range f.Scope.Objects {
+				if ast.IsExported(name) {
+					names[name] = filepath.Base(file)
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestCompatibilityNames(t *testing.T) {
+	root := exportedNames(t, ".")
+	for _, dir := range splitPackages {
+		for name, file := range exportedNames(t, dir) {
+			if _, ok := root[name]; !ok {
+				t.Errorf("%s.%s (in %s) has no compatibility name in package graph", dir, name, file)
+			}
+		}
+	}
+}
+
+func TestCompatibilityValues(t *testing.T) {
+	// Graphs, nodes and edges built through either package are the same types
+	var g *simple.GonumGraph = graph.NewGonumGraph(true)
+	g.AddNode(graph.GonumNode(1), []core.Node{core.GonumNode(2)})
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1)})
+	g.SetEdgeCost(graph.GonumEdge{H: graph.GonumNode(0), T: graph.GonumNode(1)}, 2)
+	var flat graph.Graph = g
+
+	route, cost, _ := graph.AStar(graph.GonumNode(0), graph.GonumNode(2), flat, nil, nil)
+	split, splitCost, _ := path.AStar(core.GonumNode(0), core.GonumNode(2), g, nil, nil)
+	if len(route) != 3 || cost != 3 || len(split) != 3 || splitCost != cost {
+		t.Errorf("AStar found %v costing %v, path.AStar %v costing %v, want both to cost 3", route, cost, split, splitCost)
+	}
+	if !graph.IsPath(split, g) {
+		t.Errorf("graph.IsPath rejected path.AStar's path %v", split)
+	}
+
+	// Errors compare equal whichever package they're taken from
+	if err := g.AddNodeE(graph.GonumNode(0), nil); err != core.ErrNodeExists || err != graph.ErrNodeExists {
+		t.Errorf("Adding a node twice gave %v, want ErrNodeExists from both packages", err)
+	}
+	if _, err := topo.TopologicalSort(g); err != nil {
+		t.Errorf("topo.TopologicalSort on a DAG gave %v", err)
+	}
+	g.AddEdge(graph.GonumEdge{H: graph.GonumNode(2), T: graph.GonumNode(0)})
+	if _, err := graph.TopologicalSort(g); err == nil {
+		t.Error("graph.TopologicalSort found no cycle")
+	} else if _, ok := err.(topo.CycleError); !ok {
+		t.Errorf("graph.TopologicalSort gave a %T, want a topo.CycleError", err)
+	}
+
+	// Encodings read through one package write through the other
+	var text bytes.Buffer
+	if err := encoding.WriteEdgeList(&text, flat); err != nil {
+		t.Fatal(err)
+	}
+	read, err := graph.ReadEdgeList(&text, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !core.Equal(read, g, 0) {
+		t.Errorf("Read back %v, want %v", graph.SortedEdgeList(read), graph.SortedEdgeList(g))
+	}
+}
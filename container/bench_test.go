@@ -0,0 +1,63 @@
+package container_test
+
+import (
+	"github.com/nathankerr/graph/container"
+	"math/rand"
+	"testing"
+)
+
+// Runs Dijkstra's Algorithm over an implicit 300x300 4-connected grid with random edge costs in [1,2), which exercises the queue the same way the shortest path algorithms do
+func benchmarkQueue(b *testing.B, newQueue func() container.PriorityQueue) {
+	const size = 300
+	rnd := rand.New(rand.NewSource(1))
+	costs := make([]float64, size*size*4)
+	for i := range costs {
+		costs[i] = 1 + rnd.Float64()
+	}
+
+	dist := make([]float64, size*size)
+	done := make([]bool, size*size)
+	offsets := [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range dist {
+			dist[j], done[j] = -1, false
+		}
+
+		queue := newQueue()
+		queue.Push(0, 0)
+		dist[0] = 0
+		for !queue.IsEmpty() {
+			id, d := queue.Pop()
+			done[id] = true
+			r, c := id/size, id%size
+			for k, off := range offsets {
+				nr, nc := r+off[0], c+off[1]
+				if nr < 0 || nr >= size || nc < 0 || nc >= size {
+					continue
+				}
+				next := nr*size + nc
+				if done[next] {
+					continue
+				}
+				if nd := d + costs[id*4+k]; dist[next] < 0 || nd < dist[next] {
+					dist[next] = nd
+					queue.Push(next, nd)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkIndexedHeap(b *testing.B) {
+	benchmarkQueue(b, func() container.PriorityQueue { return container.NewIndexedHeap() })
+}
+
+func BenchmarkPairingHeap(b *testing.B) {
+	benchmarkQueue(b, func() container.PriorityQueue { return container.NewPairingHeap() })
+}
+
+func BenchmarkBucketQueue(b *testing.B) {
+	benchmarkQueue(b, func() container.PriorityQueue { return container.NewBucketQueue(1) })
+}
@@ -0,0 +1,128 @@
+package container
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Bucket indices must be at least -maxBucket and less than maxBucket to fit in an int
+const maxBucket = 1 << (strconv.IntSize - 1)
+
+// A BucketQueue is a PriorityQueue that groups elements into buckets of a fixed priority width, like Dial's algorithm but for real valued priorities. It keeps the indices of the
+// non-empty buckets in an IndexedHeap, so Push and DecreaseKey are O(log b) in the number b of non-empty buckets, and Pop goes straight to the lowest one and searches it for the minimum,
+// however far apart the priorities are.
+//
+// It's fastest when the width is around the size of a typical edge cost, as in Dijkstra's Algorithm: too small and every element gets a bucket of its own, too large and Pop spends its
+// time searching full ones. Priorities must be finite, and Push panics if one divided by the width doesn't fit in an int.
+//
+// The zero value is not usable, create one with NewBucketQueue.
+type BucketQueue struct {
+	width      float64
+	buckets    map[int][]int
+	priorities map[int]float64
+	positions  map[int]int
+	occupied   *IndexedHeap // The indices of the non-empty buckets, each with itself as its priority
+}
+
+func NewBucketQueue(width float64) *BucketQueue {
+	return &BucketQueue{
+		width:      width,
+		buckets:    make(map[int][]int),
+		priorities: make(map[int]float64),
+		positions:  make(map[int]int),
+		occupied:   NewIndexedHeap(),
+	}
+}
+
+func (q *BucketQueue) Len() int {
+	return len(q.priorities)
+}
+
+func (q *BucketQueue) IsEmpty() bool {
+	return len(q.priorities) == 0
+}
+
+func (q *BucketQueue) Contains(id int) bool {
+	_, ok := q.priorities[id]
+	return ok
+}
+
+// Adds id to the queue with the given priority. If id is already queued, its priority is changed.
+func (q *BucketQueue) Push(id int, priority float64) {
+	if _, ok := q.priorities[id]; ok {
+		q.remove(id)
+	}
+
+	b := q.bucket(priority)
+	if len(q.buckets[b]) == 0 {
+		q.occupied.Push(b, float64(b))
+	}
+
+	q.positions[id] = len(q.buckets[b])
+	q.buckets[b] = append(q.buckets[b], id)
+	q.priorities[id] = priority
+}
+
+// Removes and returns the element with the lowest priority. Panics if the queue is empty.
+func (q *BucketQueue) Pop() (id int, priority float64) {
+	if len(q.priorities) == 0 {
+		panic("No element to pop")
+	}
+
+	b, _ := q.occupied.Peek()
+	bucket := q.buckets[b]
+	id, priority = bucket[0], q.priorities[bucket[0]]
+	for _, other := range bucket[1:] {
+		if p := q.priorities[other]; p < priority {
+			id, priority = other, p
+		}
+	}
+	q.remove(id)
+
+	return id, priority
+}
+
+// Lowers the priority of id. If id isn't queued, or priority isn't lower than its current priority, nothing happens and DecreaseKey returns false.
+func (q *BucketQueue) DecreaseKey(id int, priority float64) bool {
+	if current, ok := q.priorities[id]; !ok || priority >= current {
+		return false
+	}
+
+	q.Push(id, priority)
+	return true
+}
+
+func (q *BucketQueue) Clear() {
+	q.buckets = make(map[int][]int)
+	q.priorities = make(map[int]float64)
+	q.positions = make(map[int]int)
+	q.occupied.Clear()
+}
+
+// Returns the index of the bucket priority goes in. Panics if it's out of an int's range, rather than letting the conversion wrap it around to some other bucket.
+func (q *BucketQueue) bucket(priority float64) int {
+	index := math.Floor(priority / q.width)
+	if !(index >= -maxBucket && index < maxBucket) {
+		panic(fmt.Sprintf("Priority %g is out of range for a bucket width of %g", priority, q.width))
+	}
+
+	return int(index)
+}
+
+func (q *BucketQueue) remove(id int) {
+	b := q.bucket(q.priorities[id])
+	bucket := q.buckets[b]
+	pos, last := q.positions[id], len(bucket)-1
+
+	bucket[pos] = bucket[last]
+	q.positions[bucket[pos]] = pos
+	q.buckets[b] = bucket[:last]
+	if last == 0 {
+		delete(q.buckets, b)
+		q.occupied.Remove(b)
+	}
+
+	delete(q.priorities, id)
+	delete(q.positions, id)
+}
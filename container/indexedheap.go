@@ -0,0 +1,188 @@
+package container
+
+// An IndexedHeap is a binary min-heap of integer IDs (such as Node IDs) ordered by a float64 priority. Unlike a plain heap, it keeps track of where every ID is, so an element's priority
+// can be changed (as in DecreaseKey) or the element removed in O(log n) time, rather than having to push duplicate entries and skip the stale ones later.
+//
+// Each ID may only be in the heap once, and elements with equal priorities come out lowest ID first, so the order they come out in doesn't depend on the order they were pushed in. The
+// zero value is not usable, create one with NewIndexedHeap.
+type IndexedHeap struct {
+	ids        []int
+	priorities []float64
+	index      map[int]int
+}
+
+func NewIndexedHeap() *IndexedHeap {
+	return &IndexedHeap{index: make(map[int]int)}
+}
+
+func (h *IndexedHeap) Len() int {
+	return len(h.ids)
+}
+
+func (h *IndexedHeap) IsEmpty() bool {
+	return len(h.ids) == 0
+}
+
+// Returns whether id is currently in the heap
+func (h *IndexedHeap) Contains(id int) bool {
+	_, ok := h.index[id]
+	return ok
+}
+
+// Returns the priority of id, and false if it isn't in the heap
+func (h *IndexedHeap) Priority(id int) (priority float64, ok bool) {
+	i, ok := h.index[id]
+	if !ok {
+		return 0, false
+	}
+
+	return h.priorities[i], true
+}
+
+// Adds id to the heap with the given priority. If id is already in the heap, this is the same as Update.
+func (h *IndexedHeap) Push(id int, priority float64) {
+	if _, ok := h.index[id]; ok {
+		h.Update(id, priority)
+		return
+	}
+
+	h.ids = append(h.ids, id)
+	h.priorities = append(h.priorities, priority)
+	h.index[id] = len(h.ids) - 1
+	h.up(len(h.ids) - 1)
+}
+
+// Removes and returns the element with the lowest priority. Panics if the heap is empty.
+func (h *IndexedHeap) Pop() (id int, priority float64) {
+	if len(h.ids) == 0 {
+		panic("No element to pop")
+	}
+
+	id, priority = h.ids[0], h.priorities[0]
+	h.removeAt(0)
+
+	return id, priority
+}
+
+// Returns the element with the lowest priority without removing it. Panics if the heap is empty.
+func (h *IndexedHeap) Peek() (id int, priority float64) {
+	if len(h.ids) == 0 {
+		panic("No element to peek at")
+	}
+
+	return h.ids[0], h.priorities[0]
+}
+
+// Lowers the priority of id. If id isn't in the heap, or priority isn't lower than its current priority, nothing happens and DecreaseKey returns false.
+func (h *IndexedHeap) DecreaseKey(id int, priority float64) bool {
+	i, ok := h.index[id]
+	if !ok || priority >= h.priorities[i] {
+		return false
+	}
+
+	h.priorities[i] = priority
+	h.up(i)
+
+	return true
+}
+
+// Changes the priority of id in either direction. Returns false (doing nothing) if id isn't in the heap.
+func (h *IndexedHeap) Update(id int, priority float64) bool {
+	i, ok := h.index[id]
+	if !ok {
+		return false
+	}
+
+	h.priorities[i] = priority
+	if !h.up(i) {
+		h.down(i)
+	}
+
+	return true
+}
+
+// Removes id from the heap, returning false if it wasn't there.
+func (h *IndexedHeap) Remove(id int) bool {
+	i, ok := h.index[id]
+	if !ok {
+		return false
+	}
+
+	h.removeAt(i)
+	return true
+}
+
+// Removes every element, keeping the allocated storage for reuse
+func (h *IndexedHeap) Clear() {
+	h.ids = h.ids[:0]
+	h.priorities = h.priorities[:0]
+	for id := range h.index {
+		delete(h.index, id)
+	}
+}
+
+func (h *IndexedHeap) removeAt(i int) {
+	last := len(h.ids) - 1
+	delete(h.index, h.ids[i])
+	if i != last {
+		h.ids[i], h.priorities[i] = h.ids[last], h.priorities[last]
+		h.index[h.ids[i]] = i
+	}
+	h.ids = h.ids[:last]
+	h.priorities = h.priorities[:last]
+
+	if i != last {
+		if !h.up(i) {
+			h.down(i)
+		}
+	}
+}
+
+func (h *IndexedHeap) swap(i, j int) {
+	h.ids[i], h.ids[j] = h.ids[j], h.ids[i]
+	h.priorities[i], h.priorities[j] = h.priorities[j], h.priorities[i]
+	h.index[h.ids[i]] = i
+	h.index[h.ids[j]] = j
+}
+
+// Returns whether the element at i comes out before the one at j
+func (h *IndexedHeap) less(i, j int) bool {
+	if h.priorities[i] != h.priorities[j] {
+		return h.priorities[i] < h.priorities[j]
+	}
+	return h.ids[i] < h.ids[j]
+}
+
+// Moves the element at i up until the heap property holds, returning whether it moved at all
+func (h *IndexedHeap) up(i int) bool {
+	moved := false
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+		moved = true
+	}
+
+	return moved
+}
+
+func (h *IndexedHeap) down(i int) {
+	n := len(h.ids)
+	for {
+		smallest := i
+		if l := 2*i + 1; l < n && h.less(l, smallest) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < n && h.less(r, smallest) {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+}
@@ -0,0 +1,77 @@
+package container_test
+
+import (
+	"github.com/nathankerr/graph/container"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestIndexedHeapOrder(t *testing.T) {
+	h := container.NewIndexedHeap()
+	rnd := rand.New(rand.NewSource(1))
+	priorities := make([]float64, 100)
+	for i := range priorities {
+		priorities[i] = rnd.Float64()
+		h.Push(i, priorities[i])
+	}
+
+	sort.Float64s(priorities)
+	for i, want := range priorities {
+		if _, got := h.Pop(); got != want {
+			t.Fatalf("Pop %d returned priority %f, want %f", i, got, want)
+		}
+	}
+
+	if !h.IsEmpty() {
+		t.Error("Heap not empty after popping every element")
+	}
+}
+
+func TestIndexedHeapTies(t *testing.T) {
+	// Equal priorities come out by ID, whatever order they went in
+	h := container.NewIndexedHeap()
+	for _, id := range rand.New(rand.NewSource(1)).Perm(50) {
+		h.Push(id, float64(id/10))
+	}
+	for want := 0; want < 50; want++ {
+		if id, _ := h.Pop(); id != want {
+			t.Fatalf("Pop %d returned %d", want, id)
+		}
+	}
+}
+
+func TestIndexedHeapDecreaseKey(t *testing.T) {
+	h := container.NewIndexedHeap()
+	h.Push(1, 10)
+	h.Push(2, 20)
+	h.Push(3, 30)
+
+	if !h.DecreaseKey(3, 5) {
+		t.Error("DecreaseKey to a lower priority failed")
+	}
+	if h.DecreaseKey(2, 25) {
+		t.Error("DecreaseKey to a higher priority succeeded")
+	}
+	if h.DecreaseKey(4, 1) {
+		t.Error("DecreaseKey on a missing element succeeded")
+	}
+	if id, priority := h.Peek(); id != 3 || priority != 5 {
+		t.Errorf("Peek returned %d (%f) after DecreaseKey, want 3 (5)", id, priority)
+	}
+
+	h.Update(3, 40)
+	if id, _ := h.Peek(); id != 1 {
+		t.Errorf("Peek returned %d after increasing the minimum's priority, want 1", id)
+	}
+
+	if !h.Remove(1) || h.Contains(1) || h.Len() != 2 {
+		t.Error("Remove did not remove the element")
+	}
+	if id, _ := h.Pop(); id != 2 {
+		t.Errorf("Pop returned %d after Remove, want 2", id)
+	}
+	if priority, ok := h.Priority(3); !ok || priority != 40 {
+		t.Errorf("Priority of 3 is %f, want 40", priority)
+	}
+}
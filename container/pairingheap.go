@@ -0,0 +1,175 @@
+package container
+
+// A PairingHeap is a PriorityQueue with O(1) Push and amortized O(1) DecreaseKey (Pop is amortized O(log n)). In practice this makes it competitive with a Fibonacci heap while being far simpler.
+// Searches that perform many more DecreaseKeys than Pops, such as Dijkstra's Algorithm on dense graphs, may benefit from it over an IndexedHeap.
+//
+// The zero value is not usable, create one with NewPairingHeap.
+type PairingHeap struct {
+	root  *pairingNode
+	nodes map[int]*pairingNode
+}
+
+type pairingNode struct {
+	id       int
+	priority float64
+	child    *pairingNode
+	sibling  *pairingNode
+	prev     *pairingNode // The parent if this is the leftmost child, otherwise the left sibling
+}
+
+func NewPairingHeap() *PairingHeap {
+	return &PairingHeap{nodes: make(map[int]*pairingNode)}
+}
+
+func (h *PairingHeap) Len() int {
+	return len(h.nodes)
+}
+
+func (h *PairingHeap) IsEmpty() bool {
+	return len(h.nodes) == 0
+}
+
+func (h *PairingHeap) Contains(id int) bool {
+	_, ok := h.nodes[id]
+	return ok
+}
+
+// Adds id to the heap with the given priority. If id is already in the heap, its priority is changed.
+func (h *PairingHeap) Push(id int, priority float64) {
+	if n, ok := h.nodes[id]; ok {
+		if priority < n.priority {
+			h.DecreaseKey(id, priority)
+			return
+		}
+		h.Remove(id)
+	}
+
+	n := &pairingNode{id: id, priority: priority}
+	h.nodes[id] = n
+	h.root = meld(h.root, n)
+}
+
+// Removes and returns the element with the lowest priority. Panics if the heap is empty.
+func (h *PairingHeap) Pop() (id int, priority float64) {
+	if h.root == nil {
+		panic("No element to pop")
+	}
+
+	root := h.root
+	delete(h.nodes, root.id)
+	h.root = mergePairs(root.child)
+
+	return root.id, root.priority
+}
+
+// Returns the element with the lowest priority without removing it. Panics if the heap is empty.
+func (h *PairingHeap) Peek() (id int, priority float64) {
+	if h.root == nil {
+		panic("No element to peek at")
+	}
+
+	return h.root.id, h.root.priority
+}
+
+// Lowers the priority of id. If id isn't in the heap, or priority isn't lower than its current priority, nothing happens and DecreaseKey returns false.
+func (h *PairingHeap) DecreaseKey(id int, priority float64) bool {
+	n, ok := h.nodes[id]
+	if !ok || priority >= n.priority {
+		return false
+	}
+
+	n.priority = priority
+	if n != h.root {
+		cut(n)
+		h.root = meld(h.root, n)
+	}
+
+	return true
+}
+
+// Removes id from the heap, returning false if it wasn't there.
+func (h *PairingHeap) Remove(id int) bool {
+	n, ok := h.nodes[id]
+	if !ok {
+		return false
+	}
+
+	if n == h.root {
+		h.Pop()
+		return true
+	}
+
+	delete(h.nodes, id)
+	cut(n)
+	h.root = meld(h.root, mergePairs(n.child))
+
+	return true
+}
+
+func (h *PairingHeap) Clear() {
+	h.root = nil
+	h.nodes = make(map[int]*pairingNode)
+}
+
+// Makes the root with the larger priority the leftmost child of the other, returning the new root. Both arguments must be roots (have no siblings or parent).
+func meld(a, b *pairingNode) *pairingNode {
+	if a == nil {
+		return b
+	} else if b == nil {
+		return a
+	}
+
+	if b.priority < a.priority {
+		a, b = b, a
+	}
+
+	b.prev = a
+	b.sibling = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+
+	return a
+}
+
+// The standard two-pass merge: meld the children in pairs from left to right, then meld the results from right to left
+func mergePairs(first *pairingNode) *pairingNode {
+	if first == nil {
+		return nil
+	}
+
+	pairs := make([]*pairingNode, 0)
+	for first != nil {
+		a, b := first, first.sibling
+		a.prev, a.sibling = nil, nil
+		if b == nil {
+			pairs = append(pairs, a)
+			break
+		}
+
+		first = b.sibling
+		b.prev, b.sibling = nil, nil
+		pairs = append(pairs, meld(a, b))
+	}
+
+	result := pairs[len(pairs)-1]
+	for i := len(pairs) - 2; i >= 0; i-- {
+		result = meld(pairs[i], result)
+	}
+
+	return result
+}
+
+// Detaches a non-root node (and its subtree) from the tree
+func cut(n *pairingNode) {
+	if n.prev.child == n {
+		n.prev.child = n.sibling
+	} else {
+		n.prev.sibling = n.sibling
+	}
+	if n.sibling != nil {
+		n.sibling.prev = n.prev
+	}
+	n.prev, n.sibling = nil, nil
+}
@@ -0,0 +1,13 @@
+package container
+
+// A PriorityQueue is a min-priority queue of integer IDs that can change the priority of an element it already holds. This is the set of operations Dijkstra-like searches need, and
+// lets them swap the queue implementation depending on the workload.
+type PriorityQueue interface {
+	Push(id int, priority float64) // Adds id, or changes its priority if it's already queued
+	Pop() (id int, priority float64)
+	DecreaseKey(id int, priority float64) bool
+	Contains(id int) bool
+	Len() int
+	IsEmpty() bool
+	Clear()
+}
@@ -0,0 +1,90 @@
+package container_test
+
+import (
+	"github.com/nathankerr/graph/container"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Runs a random sequence of pushes, decreases and pops against a queue and a brute force model of it
+func testPriorityQueue(queue container.PriorityQueue, t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	model := make(map[int]float64)
+
+	for step := 0; step < 5000; step++ {
+		switch op := rnd.Intn(4); {
+		case op == 0 && len(model) != 0:
+			id, priority := queue.Pop()
+			for other, p := range model {
+				if p < priority {
+					t.Fatalf("Step %d: popped %d with priority %f, but %d has priority %f", step, id, priority, other, p)
+				}
+			}
+			if model[id] != priority {
+				t.Fatalf("Step %d: popped %d with priority %f, want %f", step, id, priority, model[id])
+			}
+			delete(model, id)
+		case op == 1:
+			id := rnd.Intn(200)
+			if p, ok := model[id]; ok {
+				priority := p - rnd.Float64()*10
+				if !queue.DecreaseKey(id, priority) {
+					t.Fatalf("Step %d: DecreaseKey failed on queued element", step)
+				}
+				model[id] = priority
+			} else if queue.DecreaseKey(id, 0) {
+				t.Fatalf("Step %d: DecreaseKey succeeded on missing element", step)
+			}
+		default:
+			id, priority := rnd.Intn(200), rnd.Float64()*100
+			queue.Push(id, priority)
+			model[id] = priority
+		}
+
+		if queue.Len() != len(model) || queue.IsEmpty() != (len(model) == 0) {
+			t.Fatalf("Step %d: queue has %d elements, want %d", step, queue.Len(), len(model))
+		}
+	}
+
+	queue.Clear()
+	if !queue.IsEmpty() || queue.Contains(0) {
+		t.Error("Queue not empty after Clear")
+	}
+}
+
+func TestIndexedHeapQueue(t *testing.T) {
+	testPriorityQueue(container.NewIndexedHeap(), t)
+}
+
+func TestPairingHeapQueue(t *testing.T) {
+	testPriorityQueue(container.NewPairingHeap(), t)
+}
+
+func TestBucketQueue(t *testing.T) {
+	testPriorityQueue(container.NewBucketQueue(5), t)
+}
+
+func TestBucketQueueSparse(t *testing.T) {
+	// A billion billion empty buckets between the two elements
+	queue := container.NewBucketQueue(1)
+	queue.Push(1, 1e15)
+	queue.Push(0, 0)
+	if id, priority := queue.Pop(); id != 0 || priority != 0 {
+		t.Errorf("Popped %d with priority %f, want 0 with 0", id, priority)
+	}
+	if id, priority := queue.Pop(); id != 1 || priority != 1e15 {
+		t.Errorf("Popped %d with priority %f, want 1 with 1e15", id, priority)
+	}
+
+	for _, priority := range []float64{1e300, -1e300, math.Inf(1), math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("No panic when pushing priority %g", priority)
+				}
+			}()
+			queue.Push(2, priority)
+		}()
+	}
+}
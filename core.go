@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"github.com/nathankerr/graph/core"
+)
+
+// The types of package core, under the names they had before the package was split
+type (
+	Node                = core.Node
+	Edge                = core.Edge
+	Graph               = core.Graph
+	Coster              = core.Coster
+	CostGraph           = core.CostGraph
+	IntCoster           = core.IntCoster
+	HeuristicCoster     = core.HeuristicCoster
+	NodeCoster          = core.NodeCoster
+	SuccessorsAppender  = core.SuccessorsAppender
+	DegreeCounter       = core.DegreeCounter
+	MutableGraph        = core.MutableGraph
+	CheckedMutableGraph = core.CheckedMutableGraph
+	EdgeKey             = core.EdgeKey
+	CostEdge            = core.CostEdge
+	IdentifiedEdge      = core.IdentifiedEdge
+	EdgeIdentifier      = core.EdgeIdentifier
+	GonumIDEdge         = core.GonumIDEdge
+	GonumCostEdge       = core.GonumCostEdge
+	WeightedEdge        = core.WeightedEdge
+	GonumNode           = core.GonumNode
+	GonumEdge           = core.GonumEdge
+	WeightStats         = core.WeightStats
+	NegativeCostError   = core.NegativeCostError
+	Metadata            = core.Metadata
+	MetadataHolder      = core.MetadataHolder
+)
+
+// The variables of package core, under the names they had before the package was split
+var (
+	ErrNodeNotFound  = core.ErrNodeNotFound
+	ErrNodeExists    = core.ErrNodeExists
+	ErrEdgeNotFound  = core.ErrEdgeNotFound
+	ErrEdgeExists    = core.ErrEdgeExists
+	ErrGraphNotEmpty = core.ErrGraphNotEmpty
+)
+
+// See core.KeyOf
+func KeyOf(e Edge, directed bool) EdgeKey {
+	return core.KeyOf(e, directed)
+}
+
+// See core.CopyGraph
+func CopyGraph(dst MutableGraph, src Graph) {
+	core.CopyGraph(dst, src)
+}
+
+// See core.Equal
+func Equal(a, b Graph, epsilon float64) bool {
+	return core.Equal(a, b, epsilon)
+}
+
+// See core.SortedNodeList
+func SortedNodeList(graph Graph) []Node {
+	return core.SortedNodeList(graph)
+}
+
+// See core.SortedEdgeList
+func SortedEdgeList(graph Graph) []Edge {
+	return core.SortedEdgeList(graph)
+}
+
+// See core.SortedSuccessors
+func SortedSuccessors(graph Graph, node Node) []Node {
+	return core.SortedSuccessors(graph, node)
+}
+
+// See core.InDegree
+func InDegree(graph Graph, node Node) int {
+	return core.InDegree(graph, node)
+}
+
+// See core.OutDegree
+func OutDegree(graph Graph, node Node) int {
+	return core.OutDegree(graph, node)
+}
+
+// See core.DegreeSequence
+func DegreeSequence(graph Graph) []int {
+	return core.DegreeSequence(graph)
+}
+
+// See core.DegreeHistogram
+func DegreeHistogram(degrees []int) (histogram []int) {
+	return core.DegreeHistogram(degrees)
+}
+
+// See core.OutStrength
+func OutStrength(graph Graph, node Node, Cost func(Node, Node) float64) float64 {
+	return core.OutStrength(graph, node, Cost)
+}
+
+// See core.InStrength
+func InStrength(graph Graph, node Node, Cost func(Node, Node) float64) float64 {
+	return core.InStrength(graph, node, Cost)
+}
+
+// See core.Strength
+func Strength(graph Graph, node Node, Cost func(Node, Node) float64) float64 {
+	return core.Strength(graph, node, Cost)
+}
+
+// See core.EdgeWeightStats
+func EdgeWeightStats(graph Graph, Cost func(Node, Node) float64) WeightStats {
+	return core.EdgeWeightStats(graph, Cost)
+}
+
+// See core.CostsEqual
+func CostsEqual(a, b, epsilon float64) bool {
+	return core.CostsEqual(a, b, epsilon)
+}
+
+// See core.ValidateCosts
+func ValidateCosts(graph Graph, Cost func(Node, Node) float64, epsilon float64) error {
+	return core.ValidateCosts(graph, Cost, epsilon)
+}
+
+// See core.NullHeuristic
+func NullHeuristic(a, b Node) float64 {
+	return core.NullHeuristic(a, b)
+}
+
+// See core.UniformCost
+func UniformCost(a, b Node) float64 {
+	return core.UniformCost(a, b)
+}
+
+// See core.GraphMetadata
+func GraphMetadata(graph Graph) Metadata {
+	return core.GraphMetadata(graph)
+}
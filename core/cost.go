@@ -0,0 +1,11 @@
+package core
+
+// An admissible, consistent heuristic that won't speed up computation time at all.
+func NullHeuristic(a, b Node) float64 {
+	return 0.0
+}
+
+// Assumes all edges in the graph have the same weight (including edges that don't exist!)
+func UniformCost(a, b Node) float64 {
+	return 1.0
+}
@@ -0,0 +1,7 @@
+// Package core holds what every other graph package shares: the Graph, Node and Edge interfaces and their optional extensions (Coster, HeuristicCoster, MutableGraph and the rest), the
+// basic node and edge types, and graph metadata. It imports none of the others, so they can all depend on it. Package graph gives all of its names under their old flat-package names.
+//
+//	graph.go     the interfaces, node and edge types, and simple operations (copying, comparing, sorting, degrees, strengths, cost statistics and validation)
+//	cost.go      UniformCost and NullHeuristic, the costs algorithms fall back on when a graph has none
+//	metadata.go  Metadata, the graph-level name and attributes that serializers read and write
+package core
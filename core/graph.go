@@ -0,0 +1,567 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+type Node interface {
+	ID() int
+}
+
+type Edge interface {
+	Head() Node
+	Tail() Node
+}
+
+// A Graph implements all methods necessary to run graph-specific algorithms on it. Strictly speaking EdgeList() and NodeList() would be sufficient, but the others (such as Successors)
+// are required to make the algorithms more efficient and/or easier to write and maintain.
+type Graph interface {
+	Successors(node Node) []Node               // Gives the nodes connected by OUTBOUND edges, if the graph is an undirected graph, this set is equal to Predecessors
+	IsSuccessor(node, successor Node) bool     // If successor shows up in the list returned by Successors(node), then it's a successor. If node doesn't exist, this should always return false
+	Predecessors(node Node) []Node             // Gives the nodes connected by INBOUND edges, if the graph is an undirected graph, this set is equal to Successors
+	IsPredecessor(node, predecessor Node) bool // If predecessor shows up in the list returned by Predecessors(node), then it's a predecessor. If node doesn't exist, this should always return false
+	IsAdjacent(node, neighbor Node) bool       // IsSuccessor || IsPredecessor
+	NodeExists(node Node) bool                 // Returns whether a node with the given Node is currently in the graph
+	Degree(node Node) int                      // Degree is equivalent to len(Successors(node)) + len(Predecessors(node)); this means that reflexive edges are counted twice
+	EdgeList() []Edge                          // Returns a list of all edges in the graph. In the case of an directed graph edge[0] goes TO edge[1]. In an undirected graph, provide both directions as separate edges
+	NodeList() []Node                          // Returns a list of all node IDs in no particular order, useful for determining things like if a graph is fully connected. The caller is free to modify this list (so don't pass a reference to your own list)
+	IsDirected() bool                          // Returns whether this graph is directed or not
+}
+
+// A Graph that implements Coster has an actual cost between adjacent nodes, also known as a weighted graph. If a graph implements coster and a function needs to read cost (e.g. A*), this function will
+// take precedence over the Uniform Cost function (all weights are 1) if "nil" is passed in for the function argument
+//
+// Every function in the graph packages that takes a Cost argument resolves it the same way: a non-nil argument is always used, and nil means the graph's Cost method if the graph is a Coster
+// and UniformCost if not. The docs call this "Argument > Interface > UniformCost". Functions that take no Cost argument, such as BFSTree and MarshalDOT, use the Coster if there is one.
+// GonumGraph (and the graphs built on it) and TileGraph implement Coster, and AsWeighted gives any graph one.
+//
+// Coster only need worry about the case when an edge from node 1 to node 2 exists (i.e. node2 is a successor to node1) -- asking for the weight in any other case is considered undefined behavior.
+// The only possible exception to this is in D*-Lite, if an edge previously existed and then is removed when the graph changes between steps, a suitably discouraging cost such as Inf would likely produce the best behavior.
+type Coster interface {
+	Cost(node1, node2 Node) float64
+}
+
+type CostGraph interface {
+	Coster
+	Graph
+}
+
+// A graph that implements IntCoster has integer edge costs. Algorithms specialized for integer costs, such as DijkstraInt, use IntCost the same way other algorithms use Coster.
+type IntCoster interface {
+	IntCost(node1, node2 Node) int
+}
+
+// A graph that implements HeuristicCoster implements a heuristic between any two given nodes. Like Coster, if a graph implements this and a function needs a heuristic cost (e.g. A*), this function will
+// take precedence over the Null Heuristic (always returns 0) if "nil" is passed in for the function argument
+//
+// A graph that knows its own geometry should implement this, so that searches on it are guided without every caller having to write a heuristic; without one, AStar explores as
+// Dijkstra does. The heuristic must be admissible (never more than the cost of the cheapest path between the nodes) for AStar to find shortest paths, and should be consistent for it
+// to expand each node once. TileGraph implements HeuristicCoster with a Manhattan (or octile) distance bound.
+type HeuristicCoster interface {
+	Coster
+	HeuristicCost(node1, node2 Node) float64 // If HeuristicCost is not intended to be used, it can be implemented as the null heuristic (always returns 0)
+}
+
+// A graph that implements NodeCoster has a cost for entering each node, on top of the costs of its edges, such as a terrain penalty on a cell of a grid map. Searches don't add it on their
+// own, since a graph's Cost may already include it (TileGraph's does); pass NodeWeightedCost as their Cost argument to add it.
+type NodeCoster interface {
+	NodeCost(node Node) float64
+}
+
+// A graph that implements SuccessorsAppender can write a node's successors into a buffer supplied by the caller, instead of allocating a new slice each time as Successors does. The searches
+// in package path (such as A* and Dijkstra) check for this interface and reuse one buffer for the whole search, which removes most of their garbage on graphs where successors are computed
+// on the fly, like TileGraph. SuccessorsAppend must append exactly the nodes Successors would return, and behave like the built-in append.
+type SuccessorsAppender interface {
+	SuccessorsAppend(node Node, buf []Node) []Node
+}
+
+// A graph that implements DegreeCounter can count a node's inbound and outbound edges directly, without building the neighbor lists that len(Predecessors) and len(Successors) would.
+// InDegree and OutDegree use it when it's available. For an undirected graph both counts are the number of neighbors.
+type DegreeCounter interface {
+	InDegree(node Node) int
+	OutDegree(node Node) int
+}
+
+// A Mutable Graph is a graph that can be changed in an arbitrary way. It is useful for several algorithms; for instance, Johnson's Algorithm requires adding a temporary node and changing edge weights.
+// Another case where this is used is computing minimum spanning trees. Since trees are graphs, a minimum spanning tree can be created using this interface.
+//
+// Note that just because a graph does not implement MutableGraph does not mean that the algorithms expect it to be invariant (though even a MutableGraph should be treated as invariant while an algorithm
+// is operating on it), it simply means that without this interface they can not properly handle the graph in order to, say, fill it with a minimum spanning tree.
+//
+// In functions that take a MutableGraph as an argument, it should not be the same as the Graph argument as concurrent modification will likely cause problems in most cases.
+//
+// The methods don't return errors, and ignore calls they can't carry out (such as adding an edge from a missing node), which can hide bugs. See CheckedMutableGraph for the methods
+// that report them.
+type MutableGraph interface {
+	CostGraph
+	NewNode(successors []Node) Node       // Adds a node with an arbitrary ID, and returns the new, unique ID used
+	AddNode(node Node, successors []Node) // The graph itself is responsible for adding reciprocal edges if it's undirected. Likewise, the graph itself must add any non-existant nodes listed in successors.
+	AddEdge(e Edge)                       // For a digraph, adds node1->node2; the graph is free to initialize this to any value it wishes. Node1 must exist, or it will result in undefined behavior, node2 must be created by the function if absent
+	SetEdgeCost(e Edge, cost float64)     // The behavior is undefined if the edge has not been created with AddEdge (or the edge was removed before this function was called). For a directed graph only sets node1->node2
+	RemoveNode(node Node)                 // The graph is reponsible for removing edges to a node that is removed
+	RemoveEdge(e Edge)                    // The graph is responsible for removing reciprocal edges if it's undirected
+	EmptyGraph()                          // Clears the graph of all nodes and edges
+	SetDirected(bool)                     // This package will only call SetDirected on an empty graph, so there's no need to worry about the case where a graph suddenly becomes (un)directed
+}
+
+// A CheckedMutableGraph has an E-suffixed twin of each MutableGraph mutation that can be refused, which returns an error saying why instead of silently doing nothing. The unchecked
+// methods are unchanged, so MutableGraph implementations outside the graph packages keep compiling.
+//
+// To migrate, code that builds graphs from data it doesn't control (files, user input, the network) should call the E methods and handle the errors. It can take a
+// CheckedMutableGraph directly, or keep taking a MutableGraph and call AsChecked, which adds the checks to any MutableGraph. Implementations should add the E methods themselves where
+// they have invariants of their own to report, as Forest does. GonumGraph and Forest implement CheckedMutableGraph.
+type CheckedMutableGraph interface {
+	MutableGraph
+	AddNodeE(node Node, successors []Node) error // ErrNodeExists if the node is already in the graph
+	AddEdgeE(e Edge) error                       // ErrNodeNotFound if the head doesn't exist, ErrEdgeExists if the edge does
+	SetEdgeCostE(e Edge, cost float64) error     // ErrNodeNotFound if the head doesn't exist, ErrEdgeNotFound if the edge doesn't
+	RemoveNodeE(node Node) error                 // ErrNodeNotFound if the node doesn't exist
+	RemoveEdgeE(e Edge) error                    // ErrNodeNotFound if either end doesn't exist, ErrEdgeNotFound if the edge doesn't
+	SetDirectedE(directed bool) error            // ErrGraphNotEmpty if the graph has nodes and would change between directed and undirected
+}
+
+// Errors returned by the checked (E-suffixed) mutation methods, such as GonumGraph.AddEdgeE. The unchecked MutableGraph methods silently ignore these conditions, which can hide
+// bugs in code that loads graphs from external data.
+var (
+	ErrNodeNotFound  = errors.New("Node not found")
+	ErrNodeExists    = errors.New("Node already exists")
+	ErrEdgeNotFound  = errors.New("Edge not found")
+	ErrEdgeExists    = errors.New("Edge already exists")
+	ErrGraphNotEmpty = errors.New("Graph isn't empty")
+)
+
+// An EdgeKey identifies an edge by its endpoints' IDs. It's comparable, so it can key a map of edge attributes. For undirected graphs use KeyOf, which puts the endpoints in a canonical
+// order so that both directions of an edge have the same key.
+type EdgeKey struct {
+	Head, Tail int
+}
+
+// Returns the key of the edge. If directed is false, the endpoint with the lower ID is always the Head.
+func KeyOf(e Edge, directed bool) EdgeKey {
+	head, tail := e.Head().ID(), e.Tail().ID()
+	if !directed && tail < head {
+		head, tail = tail, head
+	}
+
+	return EdgeKey{head, tail}
+}
+
+// A CostEdge is an Edge that carries its cost, as the graph's Cost method gave it at the time the edge was retrieved, so code holding the edge doesn't need the graph to learn it.
+// GonumGraph and TileGraph return CostEdges from EdgeList, and the algorithms and serializers that walk EdgeList read the weight from them instead of calling Cost. The weight only
+// stands in for the graph's Cost method, never for a Cost argument, so the order of precedence stays Argument > Interface > UniformCost, and a graph whose edges are CostEdges must
+// also be a Coster for their weights to be used.
+type CostEdge interface {
+	Edge
+	Weight() float64
+}
+
+// An IdentifiedEdge is an Edge that knows its ID within the graph it came from, and its cost at the time it was retrieved.
+type IdentifiedEdge interface {
+	CostEdge
+	ID() int
+}
+
+// A graph that implements EdgeIdentifier gives each edge an integer ID that's unique within the graph and stays the same until the edge is removed, even as other edges come and go. Attribute
+// stores and serializers can use the IDs to refer to edges unambiguously. In an undirected graph both directions of an edge share one ID. GonumGraph implements EdgeIdentifier.
+type EdgeIdentifier interface {
+	EdgeID(e Edge) (id int, ok bool)                // ok is false if the edge isn't in the graph
+	EdgeByID(id int) (edge IdentifiedEdge, ok bool) // ok is false if no edge has the ID, for instance because it was removed
+	IdentifiedEdges() []IdentifiedEdge              // Every edge once (even in an undirected graph), sorted by ID
+}
+
+// A simple IdentifiedEdge, as returned by GonumGraph
+type GonumIDEdge struct {
+	H, T Node
+	EID  int
+	W    float64
+}
+
+func (edge GonumIDEdge) Head() Node {
+	return edge.H
+}
+
+func (edge GonumIDEdge) Tail() Node {
+	return edge.T
+}
+
+func (edge GonumIDEdge) ID() int {
+	return edge.EID
+}
+
+func (edge GonumIDEdge) Weight() float64 {
+	return edge.W
+}
+
+// A simple CostEdge, as returned by the EdgeList of GonumGraph and TileGraph
+type GonumCostEdge struct {
+	H, T Node
+	W    float64
+}
+
+func (edge GonumCostEdge) Head() Node {
+	return edge.H
+}
+
+func (edge GonumCostEdge) Tail() Node {
+	return edge.T
+}
+
+func (edge GonumCostEdge) Weight() float64 {
+	return edge.W
+}
+
+// A package that contains an edge (as from EdgeList), and a Weight (as if Cost(Edge.Head(), Edge.Tail()) had been called)
+type WeightedEdge struct {
+	Edge
+	Weight float64
+}
+
+type GonumNode int
+
+func (node GonumNode) ID() int {
+	return int(node)
+}
+
+type GonumEdge struct {
+	H, T Node
+}
+
+func (edge GonumEdge) Head() Node {
+	return edge.H
+}
+
+func (edge GonumEdge) Tail() Node {
+	return edge.T
+}
+
+/* Simple operations */
+
+// Replaces the contents of dst with a copy of src, including the edge costs if src is a Coster, and the metadata if both graphs are MetadataHolders
+func CopyGraph(dst MutableGraph, src Graph) {
+	dst.EmptyGraph()
+	dir := src.IsDirected()
+	dst.SetDirected(dir)
+
+	if mdst, ok := dst.(MetadataHolder); ok {
+		if msrc, ok := src.(MetadataHolder); ok {
+			*mdst.Metadata() = msrc.Metadata().Clone()
+		}
+	}
+
+	var Cost func(Node, Node) float64
+	if cgraph, ok := src.(Coster); ok {
+		Cost = cgraph.Cost
+	}
+
+	for _, node := range src.NodeList() {
+		if !dst.NodeExists(node) {
+			dst.AddNode(node, nil)
+
+		}
+		for _, succ := range src.Successors(node) {
+			edge := GonumEdge{H: node, T: succ}
+			dst.AddEdge(edge)
+			if Cost != nil {
+				dst.SetEdgeCost(edge, Cost(node, succ))
+			}
+		}
+
+	}
+}
+
+// Returns whether two graphs have the same structure: both directed or both undirected, with the same nodes and edges (compared by ID) and costs equal within epsilon, as by
+// CostsEqual. A graph that isn't a Coster has uniform costs. Metadata and the nodes' other contents aren't compared, so a graph equals its CopyGraph copy even in another
+// implementation, which makes the pair a way to check that a destructive experiment on a copy left the original alone.
+func Equal(a, b Graph, epsilon float64) bool {
+	if a.IsDirected() != b.IsDirected() {
+		return false
+	}
+	aNodes, bNodes := a.NodeList(), b.NodeList()
+	if len(aNodes) != len(bNodes) {
+		return false
+	}
+	bByID := make(map[int]Node, len(bNodes))
+	for _, node := range bNodes {
+		bByID[node.ID()] = node
+	}
+
+	aCost, bCost := defaultCost(a, nil), defaultCost(b, nil)
+	for _, node := range aNodes {
+		other, ok := bByID[node.ID()]
+		if !ok {
+			return false
+		}
+		aSuccs, bSuccs := a.Successors(node), b.Successors(other)
+		if len(aSuccs) != len(bSuccs) {
+			return false
+		}
+		bSuccByID := make(map[int]Node, len(bSuccs))
+		for _, succ := range bSuccs {
+			bSuccByID[succ.ID()] = succ
+		}
+		for _, succ := range aSuccs {
+			otherSucc, ok := bSuccByID[succ.ID()]
+			if !ok || !CostsEqual(aCost(node, succ), bCost(other, otherSucc), epsilon) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Returns the graph's nodes sorted by ID. Most graphs list their nodes in map order, which changes from run to run; use this wherever the order shows, as in golden tests.
+func SortedNodeList(graph Graph) []Node {
+	nodes := graph.NodeList()
+	sort.Sort(nodeSorter(nodes))
+	return nodes
+}
+
+// Returns the graph's edges sorted by the IDs of their heads and then of their tails, see SortedNodeList
+func SortedEdgeList(graph Graph) []Edge {
+	edges := graph.EdgeList()
+	sort.Sort(edgeListSorter(edges))
+	return edges
+}
+
+// Returns the node's successors sorted by ID, see SortedNodeList
+func SortedSuccessors(graph Graph, node Node) []Node {
+	succs := graph.Successors(node)
+	sort.Sort(nodeSorter(succs))
+	return succs
+}
+
+// Returns the number of edges into the node. This is O(1) for graphs that implement DegreeCounter, such as GonumGraph.
+func InDegree(graph Graph, node Node) int {
+	if dgraph, ok := graph.(DegreeCounter); ok {
+		return dgraph.InDegree(node)
+	}
+
+	return len(graph.Predecessors(node))
+}
+
+// Returns the number of edges out of the node. This is O(1) for graphs that implement DegreeCounter, such as GonumGraph.
+func OutDegree(graph Graph, node Node) int {
+	if dgraph, ok := graph.(DegreeCounter); ok {
+		return dgraph.OutDegree(node)
+	}
+
+	return len(graph.Successors(node))
+}
+
+// Returns the degree (as given by Graph.Degree) of every node, sorted from largest to smallest
+func DegreeSequence(graph Graph) []int {
+	nodes := graph.NodeList()
+	degrees := make([]int, len(nodes))
+	for i, node := range nodes {
+		degrees[i] = graph.Degree(node)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(degrees)))
+
+	return degrees
+}
+
+// Counts how often each degree appears in a list of degrees (such as the one returned by DegreeSequence, or a list of InDegree values), so that histogram[d] is the number of nodes of
+// degree d. The histogram runs up to the largest degree in the list. Negative degrees are ignored.
+func DegreeHistogram(degrees []int) (histogram []int) {
+	histogram = make([]int, 0)
+	for _, d := range degrees {
+		if d < 0 {
+			continue
+		}
+		for len(histogram) <= d {
+			histogram = append(histogram, 0)
+		}
+		histogram[d]++
+	}
+
+	return histogram
+}
+
+// Returns the sum of the costs of the edges out of the node, its weighted out-degree.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func OutStrength(graph Graph, node Node, Cost func(Node, Node) float64) float64 {
+	Cost = defaultCost(graph, Cost)
+
+	strength := 0.0
+	for _, succ := range graph.Successors(node) {
+		strength += Cost(node, succ)
+	}
+
+	return strength
+}
+
+// Returns the sum of the costs of the edges into the node, its weighted in-degree.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func InStrength(graph Graph, node Node, Cost func(Node, Node) float64) float64 {
+	Cost = defaultCost(graph, Cost)
+
+	strength := 0.0
+	for _, pred := range graph.Predecessors(node) {
+		strength += Cost(pred, node)
+	}
+
+	return strength
+}
+
+// Returns the strength (weighted degree) of the node: the sum of the costs of all the edges incident to it. In a directed graph that's InStrength + OutStrength; in an undirected graph each
+// edge is only counted once, so it's the same as OutStrength. Note that this differs from Graph.Degree, which counts undirected edges twice.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func Strength(graph Graph, node Node, Cost func(Node, Node) float64) float64 {
+	if !graph.IsDirected() {
+		return OutStrength(graph, node, Cost)
+	}
+
+	return OutStrength(graph, node, Cost) + InStrength(graph, node, Cost)
+}
+
+// Summary statistics of a graph's edge costs, as returned by EdgeWeightStats. StdDev is the population standard deviation.
+type WeightStats struct {
+	Count                         int
+	Total, Min, Max, Mean, Median float64
+	StdDev                        float64
+}
+
+// Computes summary statistics over the costs of all the edges in the graph. Each edge of an undirected graph is only counted once, even though EdgeList lists both directions. An empty
+// graph gives a zero WeightStats.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func EdgeWeightStats(graph Graph, Cost func(Node, Node) float64) WeightStats {
+	edgeCost := defaultEdgeCost(graph, Cost)
+
+	weights := make([]float64, 0)
+	for _, edge := range graph.EdgeList() {
+		if !graph.IsDirected() && edge.Head().ID() > edge.Tail().ID() {
+			continue
+		}
+		weights = append(weights, edgeCost(edge))
+	}
+	if len(weights) == 0 {
+		return WeightStats{}
+	}
+	sort.Float64s(weights)
+
+	stats := WeightStats{Count: len(weights), Min: weights[0], Max: weights[len(weights)-1]}
+	for _, w := range weights {
+		stats.Total += w
+	}
+	stats.Mean = stats.Total / float64(len(weights))
+	if mid := len(weights) / 2; len(weights)%2 == 1 {
+		stats.Median = weights[mid]
+	} else {
+		stats.Median = (weights[mid-1] + weights[mid]) / 2
+	}
+
+	variance := 0.0
+	for _, w := range weights {
+		variance += (w - stats.Mean) * (w - stats.Mean)
+	}
+	stats.StdDev = math.Sqrt(variance / float64(len(weights)))
+
+	return stats
+}
+
+// Returns Cost if it isn't nil, and otherwise the graph's Cost method if it's a Coster, or UniformCost
+func defaultCost(graph Graph, Cost func(Node, Node) float64) func(Node, Node) float64 {
+	if Cost != nil {
+		return Cost
+	}
+	if cgraph, ok := graph.(Coster); ok {
+		return cgraph.Cost
+	}
+
+	return UniformCost
+}
+
+// Returns the cost of an edge from the graph's EdgeList, resolved as defaultCost resolves Cost, except that when the graph's Cost method would be used a CostEdge's weight is read instead
+func defaultEdgeCost(graph Graph, Cost func(Node, Node) float64) func(Edge) float64 {
+	if cgraph, ok := graph.(Coster); ok && Cost == nil {
+		return func(edge Edge) float64 {
+			if cedge, ok := edge.(CostEdge); ok {
+				return cedge.Weight()
+			}
+			return cgraph.Cost(edge.Head(), edge.Tail())
+		}
+	}
+
+	Cost = defaultCost(graph, Cost)
+	return func(edge Edge) float64 {
+		return Cost(edge.Head(), edge.Tail())
+	}
+}
+
+// Returns whether two costs are equal within a tolerance. The tolerance is relative for costs larger than 1 in magnitude, and absolute otherwise, so that the rounding error accumulated
+// over long paths doesn't cause spurious mismatches. That is, it returns |a-b| <= epsilon*max(1, |a|, |b|). Equal infinities are always equal.
+func CostsEqual(a, b, epsilon float64) bool {
+	if a == b {
+		return true
+	}
+
+	scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	return math.Abs(a-b) <= epsilon*scale
+}
+
+// A NegativeCostError is returned by ValidateCosts when an edge has a cost below zero
+type NegativeCostError struct {
+	Edge Edge
+	Cost float64
+}
+
+func (err NegativeCostError) Error() string {
+	return fmt.Sprintf("Edge from %d to %d has negative cost %g", err.Edge.Head().ID(), err.Edge.Tail().ID(), err.Cost)
+}
+
+// Checks that every edge in the graph has a non-negative cost, as required by A* and Dijkstra's Algorithm. Costs no lower than -epsilon are accepted, so that costs which are only negative
+// because of floating point error don't get rejected. Returns a NegativeCostError for the first offending edge found, or nil.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func ValidateCosts(graph Graph, Cost func(Node, Node) float64, epsilon float64) error {
+	Cost = defaultCost(graph, Cost)
+
+	for _, edge := range graph.EdgeList() {
+		if cost := Cost(edge.Head(), edge.Tail()); cost < -epsilon {
+			return NegativeCostError{Edge: edge, Cost: cost}
+		}
+	}
+
+	return nil
+}
+
+/** Sorts a list of nodes by ID, used wherever an algorithm needs a deterministic node order **/
+
+type nodeSorter []Node
+
+func (nl nodeSorter) Len() int {
+	return len(nl)
+}
+
+func (nl nodeSorter) Less(i, j int) bool {
+	return nl[i].ID() < nl[j].ID()
+}
+
+func (nl nodeSorter) Swap(i, j int) {
+	nl[i], nl[j] = nl[j], nl[i]
+}
+
+/** Sorts a list of edges by their heads' IDs and then their tails' **/
+
+type edgeListSorter []Edge
+
+func (el edgeListSorter) Len() int {
+	return len(el)
+}
+
+func (el edgeListSorter) Less(i, j int) bool {
+	a, b := el[i], el[j]
+	return a.Head().ID() < b.Head().ID() || (a.Head().ID() == b.Head().ID() && a.Tail().ID() < b.Tail().ID())
+}
+
+func (el edgeListSorter) Swap(i, j int) {
+	el[i], el[j] = el[j], el[i]
+}
@@ -0,0 +1,143 @@
+package core_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func TestDegrees(t *testing.T) {
+	// A star with 0 at the centre and edges pointing out to 1, 2 and 3, plus 3 -> 1
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1), core.GonumNode(2), core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(1)})
+
+	for id, want := range map[int][2]int{0: {0, 3}, 1: {2, 0}, 2: {1, 0}, 3: {1, 1}} {
+		if in, out := core.InDegree(g, core.GonumNode(id)), core.OutDegree(g, core.GonumNode(id)); in != want[0] || out != want[1] {
+			t.Errorf("Node %d has in-degree %d and out-degree %d, want %v", id, in, out, want)
+		}
+	}
+
+	sequence := core.DegreeSequence(g)
+	for i, want := range []int{3, 2, 2, 1} {
+		if sequence[i] != want {
+			t.Fatalf("Degree sequence is %v", sequence)
+		}
+	}
+
+	histogram := core.DegreeHistogram(sequence)
+	for d, want := range []int{0, 1, 2, 1} {
+		if histogram[d] != want {
+			t.Fatalf("Degree histogram is %v", histogram)
+		}
+	}
+
+	tg := simple.NewTileGraph(3, 3, true)
+	if in, out := core.InDegree(tg, core.GonumNode(4)), core.OutDegree(tg, core.GonumNode(0)); in != 4 || out != 2 {
+		t.Errorf("Tile graph has in-degree %d for the centre and out-degree %d for a corner", in, out)
+	}
+}
+
+func TestStrength(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1), core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(0)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}, 2)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, 3)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(0)}, 7)
+
+	if out, in, total := core.OutStrength(g, core.GonumNode(0), nil), core.InStrength(g, core.GonumNode(0), nil), core.Strength(g, core.GonumNode(0), nil); out != 5 || in != 7 || total != 12 {
+		t.Errorf("Node 0 has out-strength %f, in-strength %f and strength %f, want 5, 7 and 12", out, in, total)
+	}
+
+	stats := core.EdgeWeightStats(g, nil)
+	if stats.Count != 3 || stats.Total != 12 || stats.Min != 2 || stats.Max != 7 || stats.Median != 3 || stats.Mean != 4 {
+		t.Errorf("Got weight stats %+v", stats)
+	}
+
+	// Undirected edges are counted once
+	u := pathGraph(4)
+	u.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, 4)
+	if strength := core.Strength(u, core.GonumNode(1), nil); strength != 5 {
+		t.Errorf("Undirected node 1 has strength %f, want 5", strength)
+	}
+	if stats := core.EdgeWeightStats(u, nil); stats.Count != 3 || stats.Total != 6 {
+		t.Errorf("Got undirected weight stats %+v", stats)
+	}
+	if stats := core.EdgeWeightStats(simple.NewGonumGraph(false), nil); stats != (core.WeightStats{}) {
+		t.Errorf("Got weight stats %+v for an empty graph", stats)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	g.Metadata().Name = "citations"
+	g.Metadata().Set("source", "arXiv")
+
+	md := core.GraphMetadata(g)
+	if md.Name != "citations" || !md.Directed {
+		t.Errorf("Got metadata %+v", md)
+	}
+	md.Set("source", "changed")
+	if source, _ := g.Metadata().Get("source"); source != "arXiv" {
+		t.Error("Changing a copy of the metadata changed the graph's metadata")
+	}
+
+	dst := simple.NewGonumGraph(false)
+	core.CopyGraph(dst, g)
+	if dst.Metadata().Name != "citations" || len(dst.Metadata().Keys()) != 1 {
+		t.Errorf("CopyGraph copied the metadata as %+v", *dst.Metadata())
+	}
+
+	if md := core.GraphMetadata(simple.NewTileGraph(2, 2, true)); md.Name != "" || md.Directed {
+		t.Errorf("Got metadata %+v for a graph without any", md)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("  ▀ \n    \n ▀  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	undirected := simple.NewGonumGraph(false)
+	core.CopyGraph(undirected, tg)
+	if !core.Equal(tg, undirected, 0) || !core.Equal(undirected, tg, 0) {
+		t.Error("A TileGraph doesn't equal its copy")
+	}
+
+	original := simple.NewGonumGraph(true)
+	original.AddNode(core.GonumNode(1), []core.Node{core.GonumNode(2), core.GonumNode(3)})
+	original.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	tenth, fifth := 0.1, 0.2
+	original.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, tenth+fifth)
+	snapshot := simple.NewGonumGraph(true)
+	core.CopyGraph(snapshot, original)
+	if !core.Equal(original, snapshot, 0) {
+		t.Error("A GonumGraph doesn't equal its copy")
+	}
+
+	undirected = simple.NewGonumGraph(false)
+	undirected.AddNode(core.GonumNode(1), []core.Node{core.GonumNode(2), core.GonumNode(3)})
+	undirected.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	if core.Equal(original, undirected, 1) {
+		t.Error("A directed graph equals an undirected one")
+	}
+
+	snapshot.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, 0.3)
+	if core.Equal(original, snapshot, 0) || !core.Equal(original, snapshot, 1e-9) {
+		t.Error("Costs aren't compared within epsilon")
+	}
+	snapshot.RemoveEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	if core.Equal(original, snapshot, 1e-9) {
+		t.Error("Graphs with different edges are equal")
+	}
+	snapshot.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(2)})
+	if core.Equal(original, snapshot, 1e-9) {
+		t.Error("Graphs with an edge in different directions are equal")
+	}
+	snapshot.RemoveNode(core.GonumNode(3))
+	snapshot.AddNode(core.GonumNode(4), []core.Node{core.GonumNode(2)})
+	if core.Equal(original, snapshot, 1e-9) {
+		t.Error("Graphs with different nodes are equal")
+	}
+}
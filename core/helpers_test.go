@@ -0,0 +1,17 @@
+package core_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+)
+
+// Builds an undirected path graph 0-1-2-...-(n-1)
+func pathGraph(n int) *simple.GonumGraph {
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nil)
+	for i := 1; i < n; i++ {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(i - 1), T: core.GonumNode(i)})
+	}
+
+	return g
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"sort"
+	"time"
+)
+
+// Metadata describes a graph as a whole, rather than any of its nodes or edges: its name, where it came from, and any other key/value attributes. Serializers write it out and read it back
+// in through GraphMetadata and the MetadataHolder interface, so that it survives a round trip through any of the supported formats.
+//
+// Directed is only meaningful in the copy returned by GraphMetadata, which fills it in from the graph's IsDirected (the graph itself is always the authority on whether it's directed),
+// and in metadata a reader has just parsed, where it says what the file declared.
+type Metadata struct {
+	Name       string
+	Directed   bool
+	Created    time.Time // The zero time if unknown
+	Creator    string    // The person or program that created the graph
+	Attributes map[string]string
+}
+
+// A graph that implements MetadataHolder carries Metadata which callers can read and modify in place. GonumGraph implements it.
+type MetadataHolder interface {
+	Metadata() *Metadata
+}
+
+// Returns the value of an attribute, and whether it was set
+func (md *Metadata) Get(key string) (value string, ok bool) {
+	value, ok = md.Attributes[key]
+	return value, ok
+}
+
+// Sets an attribute, creating the Attributes map if needed
+func (md *Metadata) Set(key, value string) {
+	if md.Attributes == nil {
+		md.Attributes = make(map[string]string)
+	}
+	md.Attributes[key] = value
+}
+
+// Returns the attribute keys in sorted order, so that serializers can write them deterministically
+func (md *Metadata) Keys() []string {
+	keys := make([]string, 0, len(md.Attributes))
+	for key := range md.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Returns a copy of the metadata, including a copy of the Attributes map
+func (md *Metadata) Clone() Metadata {
+	clone := *md
+	if md.Attributes != nil {
+		clone.Attributes = make(map[string]string, len(md.Attributes))
+		for key, value := range md.Attributes {
+			clone.Attributes[key] = value
+		}
+	}
+
+	return clone
+}
+
+// Returns a copy of the graph's metadata if it's a MetadataHolder, or empty metadata if not, with Directed set from the graph's IsDirected
+func GraphMetadata(graph Graph) Metadata {
+	var md Metadata
+	if mgraph, ok := graph.(MetadataHolder); ok {
+		md = mgraph.Metadata().Clone()
+	}
+	md.Directed = graph.IsDirected()
+
+	return md
+}
@@ -0,0 +1,140 @@
+// Package datasets loads the public graph datasets that benchmarks are run on, such as the SNAP and KONECT edge lists, the DIMACS road networks and the SuiteSparse Matrix Market
+// files, and downloads them into a local cache. The parsers themselves are in the encoding package (ReadEdgeList, ReadDIMACS, ReadDIMACSCoordinates and ReadMatrixMarket); this package picks the right one from
+// the file name and takes care of compression.
+package datasets
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/encoding"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Reads the graph in the file, choosing the parser from its extension: .gr for ReadDIMACS, .col for ReadDIMACSColoring, .mtx for ReadMatrixMarket, and anything else (.txt, .csv, .tsv, .edges, ...) for
+// ReadEdgeList, with the given options. A trailing .gz is decompressed first, so "USA-road-d.NY.gr.gz" is read as a gzipped DIMACS file. The options may be nil, and only apply to
+// edge lists. A .co file holds node coordinates rather than a graph, so Load returns an error for one instead of reading it as an edge list; use LoadCoordinates.
+func Load(name string, opts *encoding.EdgeListOptions) (*simple.GonumGraph, error) {
+	r, base, closer, err := open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer closer()
+
+	var g *simple.GonumGraph
+	switch filepath.Ext(base) {
+	case ".gr":
+		g, err = encoding.ReadDIMACS(r)
+	case ".col":
+		g, err = encoding.ReadDIMACSColoring(r)
+	case ".mtx":
+		g, err = encoding.ReadMatrixMarket(r)
+	case ".co":
+		err = errors.New("Unsupported extension .co for a graph, it holds DIMACS coordinates (see LoadCoordinates)")
+	default:
+		g, err = encoding.ReadEdgeList(r, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	return g, nil
+}
+
+// Reads the node coordinates in a DIMACS .co file (or .co.gz), such as "USA-road-d.NY.co.gz", with ReadDIMACSCoordinates, for the road network in the .gr file of the same name. Returns
+// an error for any other extension.
+func LoadCoordinates(name string) (map[int][2]float64, error) {
+	r, base, closer, err := open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer closer()
+
+	if ext := filepath.Ext(base); ext != ".co" {
+		return nil, fmt.Errorf("%s: Unsupported extension %q for coordinates, want .co", name, ext)
+	}
+	coords, err := encoding.ReadDIMACSCoordinates(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	return coords, nil
+}
+
+// Opens the file, decompressing it if its name ends in .gz, and returns the reader, the lower case base name without the .gz, and a function that closes everything
+func open(name string) (r io.Reader, base string, closer func(), err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	r = f
+	base = strings.ToLower(filepath.Base(name))
+	if !strings.HasSuffix(base, ".gz") {
+		return r, base, func() { f.Close() }, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, "", nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	return gz, strings.TrimSuffix(base, ".gz"), func() { gz.Close(); f.Close() }, nil
+}
+
+// Downloads the file at the URL into the directory, unless a file of the same name is already there, and returns its path; pass the path to Load. The file keeps the last element of
+// the URL's path as its name, so keep one dataset per name in a directory. The download is written to a temporary file that's only renamed into place once it's complete, so an
+// interrupted download is fetched again rather than loaded half-written. The directory is created if it doesn't exist.
+func Download(rawurl, dir string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	base := path.Base(u.Path)
+	if base == "/" || base == "." {
+		return "", fmt.Errorf("%s doesn't name a file", rawurl)
+	}
+	name := filepath.Join(dir, base)
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	resp, err := http.Get(rawurl)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Downloading %s: %s", rawurl, resp.Status)
+	}
+
+	tmp, err := ioutil.TempFile(dir, base+".part")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("Downloading %s: %v", rawurl, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
@@ -0,0 +1,112 @@
+package datasets_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/datasets"
+	"github.com/nathankerr/graph/encoding"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const roads = "c a tiny road network\np sp 3 3\na 1 2 5\na 2 3 7\na 3 1 2\n"
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datasets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(roads))
+	w.Close()
+
+	files := map[string][]byte{
+		"roads.gr.gz": gz.Bytes(),
+		"path.col":    []byte("p edge 3 2\ne 1 2\ne 2 3\n"),
+		"matrix.mtx":  []byte("%%MatrixMarket matrix coordinate pattern symmetric\n3 3 2\n2 1\n3 2\n"),
+		"edges.csv":   []byte("source,target\n1,2\n2,3\n"),
+		"bad.gr":      []byte("a 1 2 3\n"),
+		"roads.co":    []byte("p aux sp co 3\nv 1 10 20\nv 2 30 40\nv 3 50 60\n"),
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, err := datasets.Load(filepath.Join(dir, "roads.gr.gz"), nil)
+	if err != nil || len(g.EdgeList()) != 3 || g.Cost(core.GonumNode(2), core.GonumNode(3)) != 7 {
+		t.Errorf("Loaded roads %v with error %v", g, err)
+	}
+	if g, err := datasets.Load(filepath.Join(dir, "matrix.mtx"), nil); err != nil || g.IsDirected() || len(g.EdgeList()) != 4 {
+		t.Errorf("Loaded matrix %v with error %v", g, err)
+	}
+	if g, err := datasets.Load(filepath.Join(dir, "path.col"), nil); err != nil || g.IsDirected() || len(g.NodeList()) != 3 {
+		t.Errorf("Loaded coloring instance %v with error %v", g, err)
+	}
+	if g, err := datasets.Load(filepath.Join(dir, "edges.csv"), &encoding.EdgeListOptions{Undirected: true}); err != nil || g.IsDirected() || len(g.EdgeList()) != 4 {
+		t.Errorf("Loaded edge list %v with error %v", g, err)
+	}
+	for _, bad := range []string{"bad.gr", "missing.txt", "roads.co"} {
+		if _, err := datasets.Load(filepath.Join(dir, bad), nil); err == nil {
+			t.Errorf("No error loading %s", bad)
+		}
+	}
+
+	coords, err := datasets.LoadCoordinates(filepath.Join(dir, "roads.co"))
+	if err != nil || len(coords) != 3 || coords[2] != [2]float64{30, 40} {
+		t.Errorf("Loaded coordinates %v with error %v", coords, err)
+	}
+	if _, err := datasets.LoadCoordinates(filepath.Join(dir, "roads.gr.gz")); err == nil {
+		t.Error("No error loading coordinates from a .gr file")
+	}
+}
+
+func TestDownload(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/data/roads.gr" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(roads))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "datasets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cache := filepath.Join(dir, "cache")
+
+	// The second download is served from the cache
+	for i := 0; i < 2; i++ {
+		name, err := datasets.Download(server.URL+"/data/roads.gr", cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, err := datasets.Load(name, nil); err != nil || len(g.NodeList()) != 3 {
+			t.Errorf("Loaded %v with error %v", g, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Made %d requests, want 1", requests)
+	}
+
+	if _, err := datasets.Download(server.URL+"/data/missing.gr", cache); err == nil {
+		t.Error("No error for a missing file")
+	}
+	if entries, _ := ioutil.ReadDir(cache); len(entries) != 1 {
+		t.Errorf("Cache holds %d files after a failed download, want 1", len(entries))
+	}
+}
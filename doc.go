@@ -0,0 +1,21 @@
+// Package graph is a generalized graph package. It provides the Graph, Node and Edge interfaces (and their optional extensions such as Coster, HeuristicCoster and MutableGraph),
+// some concrete graph types, and algorithms that operate on anything implementing the interfaces.
+//
+// The code lives in subpackages with one job each, and this package gives every one of their exported names under the name it had when the package was a single flat package, so code
+// written against the flat package keeps building. Types and constants are aliases, so a *graph.GonumGraph is a *simple.GonumGraph and values pass freely between the two; functions
+// call through to the subpackage's. Exported variables, such as ErrNodeNotFound and DefaultTileAlphabet, are copies: comparing errors with them works as before, but assigning to one
+// here doesn't change the subpackage's. New code can import the subpackages directly:
+//
+//	core      the interfaces, the basic node and edge types, graph metadata, and the simple operations every other package uses (copying, comparing, sorting, degrees, path costs)
+//	simple    the concrete graphs (GonumGraph, DenseGraph, FrozenGraph, TileGraph and the rest), the views of other graphs (filtered, reversed, dynamic costs), and their own encodings
+//	path      shortest path searches, incremental replanning with D*-Lite, search traces, and cost transforms
+//	topo      traversals, components, topological sorting, spanning trees, dominators, clustering, centrality, and the parallel, streaming and sampling algorithms for large graphs
+//	flow      maximum flows and minimum cuts
+//	gen       graphs built from other graphs: node mappings and quotients
+//	encoding  reading and writing DOT, edge lists, DIMACS and Matrix Market files, and patches between versions of a graph
+//
+// Each depends only on the packages above it in that list, and on core, so the algorithms can be imported without the encodings or the concrete graphs without the algorithms.
+// Self-contained data structures that don't depend on the graph interfaces live in their own subpackages, such as set, xifo and container. The encodingtest subpackage is the round trip,
+// golden file and decoder fuzzing harness that every serializer's tests use, the datasets subpackage loads and downloads benchmark datasets in any of the formats above, and the
+// examples subpackage has puzzle and word ladder state spaces searched through ImplicitGraph.
+package graph
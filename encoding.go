@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"github.com/nathankerr/graph/encoding"
+	"io"
+)
+
+// The types of package encoding, under the names they had before the package was split
+type (
+	DOTOptions      = encoding.DOTOptions
+	DOTAttributes   = encoding.DOTAttributes
+	EdgeListHeader  = encoding.EdgeListHeader
+	EdgeListOptions = encoding.EdgeListOptions
+	Patch           = encoding.Patch
+	PatchEdge       = encoding.PatchEdge
+)
+
+// The constants of package encoding, under the names they had before the package was split
+const (
+	DetectHeader = encoding.DetectHeader
+	NoHeader     = encoding.NoHeader
+	SkipHeader   = encoding.SkipHeader
+)
+
+// See encoding.ReadDIMACS
+func ReadDIMACS(r io.Reader) (*GonumGraph, error) {
+	return encoding.ReadDIMACS(r)
+}
+
+// See encoding.WriteDIMACS
+func WriteDIMACS(w io.Writer, graph Graph) error {
+	return encoding.WriteDIMACS(w, graph)
+}
+
+// See encoding.ReadDIMACSCoordinates
+func ReadDIMACSCoordinates(r io.Reader) (map[int][2]float64, error) {
+	return encoding.ReadDIMACSCoordinates(r)
+}
+
+// See encoding.ReadDIMACSFlow
+func ReadDIMACSFlow(r io.Reader) (g *GonumGraph, source, sink Node, err error) {
+	return encoding.ReadDIMACSFlow(r)
+}
+
+// See encoding.WriteDIMACSFlow
+func WriteDIMACSFlow(w io.Writer, graph Graph, source, sink Node, capacity func(Edge) float64) error {
+	return encoding.WriteDIMACSFlow(w, graph, source, sink, capacity)
+}
+
+// See encoding.ReadDIMACSColoring
+func ReadDIMACSColoring(r io.Reader) (*GonumGraph, error) {
+	return encoding.ReadDIMACSColoring(r)
+}
+
+// See encoding.WriteDIMACSColoring
+func WriteDIMACSColoring(w io.Writer, graph Graph) error {
+	return encoding.WriteDIMACSColoring(w, graph)
+}
+
+// See encoding.MarshalDOT
+func MarshalDOT(graph Graph, opts *DOTOptions) ([]byte, error) {
+	return encoding.MarshalDOT(graph, opts)
+}
+
+// See encoding.UnmarshalDOT
+func UnmarshalDOT(r io.Reader) (*GonumGraph, *DOTAttributes, error) {
+	return encoding.UnmarshalDOT(r)
+}
+
+// See encoding.ReadEdgeList
+func ReadEdgeList(r io.Reader, opts *EdgeListOptions) (*GonumGraph, error) {
+	return encoding.ReadEdgeList(r, opts)
+}
+
+// See encoding.WriteEdgeList
+func WriteEdgeList(w io.Writer, graph Graph) error {
+	return encoding.WriteEdgeList(w, graph)
+}
+
+// See encoding.ReadMatrixMarket
+func ReadMatrixMarket(r io.Reader) (*GonumGraph, error) {
+	return encoding.ReadMatrixMarket(r)
+}
+
+// See encoding.WriteMatrixMarket
+func WriteMatrixMarket(w io.Writer, graph Graph) error {
+	return encoding.WriteMatrixMarket(w, graph)
+}
+
+// See encoding.ComputePatch
+func ComputePatch(old, new Graph) (*Patch, error) {
+	return encoding.ComputePatch(old, new)
+}
+
+// See encoding.ApplyPatch
+func ApplyPatch(graph MutableGraph, patch *Patch) error {
+	return encoding.ApplyPatch(graph, patch)
+}
@@ -0,0 +1,504 @@
+package encoding
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/flow"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Reads a graph in the DIMACS shortest path format (.gr), which the 9th DIMACS Implementation Challenge's road networks and most routing benchmarks are distributed in. Lines start
+// with a letter saying what they hold:
+//
+//	c This is a comment
+//	p sp 4 5     the problem line: a shortest path problem with 4 nodes and 5 arcs, before any arcs
+//	a 1 2 7      an arc from node 1 to node 2 costing 7
+//
+// The graph is directed, with nodes 1 to n, including any that no arc touches. If an arc is listed more than once the cheapest cost is kept, since that's the only one a shortest path
+// would use. Returns an error, with the line number, for a missing or repeated problem line, an arc with an endpoint outside 1 to n or a cost that isn't a number, an unknown line type,
+// or a number of arcs that doesn't match the problem line.
+//
+// The input may come from an untrusted source. Nodes are added as arcs reach them and the rest once the input has been read, so a problem line with a node count far larger than the
+// input could describe (see addOneBasedNodes) is an error rather than a way to use up memory.
+func ReadDIMACS(r io.Reader) (*simple.GonumGraph, error) {
+	g := simple.NewGonumGraph(true)
+	n, arcs := -1, 0
+	wantArcs := 0
+
+	input := &countingReader{r: r}
+	err := scanDIMACS(input, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if n != -1 {
+				return errors.New("Repeated problem line")
+			}
+			if len(fields) != 4 || fields[1] != "sp" {
+				return fmt.Errorf("Expected a problem line of the form \"p sp nodes arcs\", got %q", strings.Join(fields, " "))
+			}
+			var err error
+			if n, err = strconv.Atoi(fields[2]); err != nil || n < 0 {
+				return fmt.Errorf("Bad node count %q", fields[2])
+			}
+			if wantArcs, err = strconv.Atoi(fields[3]); err != nil || wantArcs < 0 {
+				return fmt.Errorf("Bad arc count %q", fields[3])
+			}
+		case "a":
+			if n == -1 {
+				return errors.New("Arc before the problem line")
+			}
+			if len(fields) != 4 {
+				return fmt.Errorf("Expected an arc of the form \"a from to cost\", got %q", strings.Join(fields, " "))
+			}
+			head, err := oneBasedNode(fields[1], n)
+			if err != nil {
+				return err
+			}
+			tail, err := oneBasedNode(fields[2], n)
+			if err != nil {
+				return err
+			}
+			cost, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil {
+				return fmt.Errorf("Bad cost %q", fields[3])
+			}
+
+			e := core.GonumEdge{H: head, T: tail}
+			if !g.IsSuccessor(head, tail) {
+				addNode(g, head)
+				g.AddEdge(e)
+			} else if g.Cost(head, tail) <= cost {
+				cost = g.Cost(head, tail)
+			}
+			g.SetEdgeCost(e, cost)
+			arcs++
+		default:
+			return fmt.Errorf("Unknown line type %q", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n == -1 {
+		return nil, errors.New("No problem line")
+	}
+	if arcs != wantArcs {
+		return nil, fmt.Errorf("The problem line has %d arcs, but %d were listed", wantArcs, arcs)
+	}
+	if err := addOneBasedNodes(g, n, input.n); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Writes the graph in the DIMACS shortest path format that ReadDIMACS reads, with an arc for every edge costing what the graph's Coster says (1 if it has none). The format numbers nodes
+// from 1, so the nodes are renumbered 1 to n in order of ID; a graph read by ReadDIMACS already is, and comes back unchanged. An undirected graph's edges are written as an arc in each
+// direction, as a shortest path search follows them. The metadata isn't written.
+func WriteDIMACS(w io.Writer, graph core.Graph) error {
+	nodes, numbers := numberNodes(graph)
+	edgeCost := graphutil.DefaultEdgeCost(graph, nil)
+
+	var arcs []string
+	for _, node := range nodes {
+		for _, succ := range core.SortedSuccessors(graph, node) {
+			arcs = append(arcs, fmt.Sprintf("a %d %d %s\n", numbers[node.ID()], numbers[succ.ID()], strconv.FormatFloat(edgeCost(core.GonumEdge{H: node, T: succ}), 'g', -1, 64)))
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "p sp %d %d\n", len(nodes), len(arcs))
+	for _, arc := range arcs {
+		buf.WriteString(arc)
+	}
+
+	return buf.Flush()
+}
+
+// Reads node coordinates in the DIMACS coordinate format (.co) that accompanies a .gr file, returning each node's x and y by ID. The routing benchmarks give longitude and latitude
+// in millionths of a degree. The format is like ReadDIMACS's:
+//
+//	c This is a comment
+//	p aux sp co 4  the problem line: coordinates for 4 nodes, before any of them
+//	v 1 -73530767 41085396
+//
+// Returns an error, with the line number, for a missing or repeated problem line, a node outside 1 to n, listed twice, or with coordinates that aren't numbers, an unknown line type,
+// or a number of nodes that doesn't match the problem line. The input may come from an untrusted source.
+func ReadDIMACSCoordinates(r io.Reader) (map[int][2]float64, error) {
+	coords := make(map[int][2]float64)
+	n := -1
+
+	err := scanDIMACS(r, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if n != -1 {
+				return errors.New("Repeated problem line")
+			}
+			if len(fields) != 5 || fields[1] != "aux" || fields[2] != "sp" || fields[3] != "co" {
+				return fmt.Errorf("Expected a problem line of the form \"p aux sp co nodes\", got %q", strings.Join(fields, " "))
+			}
+			var err error
+			if n, err = strconv.Atoi(fields[4]); err != nil || n < 0 {
+				return fmt.Errorf("Bad node count %q", fields[4])
+			}
+		case "v":
+			if n == -1 {
+				return errors.New("Coordinates before the problem line")
+			}
+			if len(fields) != 4 {
+				return fmt.Errorf("Expected coordinates of the form \"v node x y\", got %q", strings.Join(fields, " "))
+			}
+			node, err := oneBasedNode(fields[1], n)
+			if err != nil {
+				return err
+			}
+			if _, ok := coords[node.ID()]; ok {
+				return fmt.Errorf("Node %d listed twice", node.ID())
+			}
+			var xy [2]float64
+			for i, field := range fields[2:] {
+				if xy[i], err = strconv.ParseFloat(field, 64); err != nil {
+					return fmt.Errorf("Bad coordinate %q", field)
+				}
+			}
+			coords[node.ID()] = xy
+		default:
+			return fmt.Errorf("Unknown line type %q", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n == -1 {
+		return nil, errors.New("No problem line")
+	}
+	if len(coords) != n {
+		return nil, fmt.Errorf("The problem line has %d nodes, but %d were listed", n, len(coords))
+	}
+
+	return coords, nil
+}
+
+// Reads a maximum flow problem in the DIMACS flow format (.max), in which the flow and cut benchmarks are distributed. The format is like ReadDIMACS's, with the source and sink marked
+// by node lines:
+//
+//	c This is a comment
+//	p max 4 5    the problem line: a maximum flow problem with 4 nodes and 5 arcs, before anything else
+//	n 1 s        node 1 is the source
+//	n 4 t        node 4 is the sink
+//	a 1 2 7      an arc from node 1 to node 2 with a capacity of 7
+//
+// The graph is directed, with nodes 1 to n, and each arc's capacity as its cost, so pass CostCapacity(g) to MaxFlow. If an arc is listed more than once the capacities are added up,
+// since parallel arcs carry flow side by side. Returns an error, with the line number, for a missing or repeated problem line, a missing or repeated source or sink or one that's both,
+// an arc with an endpoint outside 1 to n or a capacity that isn't a non-negative number, an unknown line type, or a number of arcs that doesn't match the problem line.
+//
+// The input may come from an untrusted source: as in ReadDIMACS, a node count far larger than the input could describe is an error.
+func ReadDIMACSFlow(r io.Reader) (g *simple.GonumGraph, source, sink core.Node, err error) {
+	g = simple.NewGonumGraph(true)
+	n, arcs := -1, 0
+	wantArcs := 0
+
+	input := &countingReader{r: r}
+	err = scanDIMACS(input, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if n != -1 {
+				return errors.New("Repeated problem line")
+			}
+			if len(fields) != 4 || fields[1] != "max" {
+				return fmt.Errorf("Expected a problem line of the form \"p max nodes arcs\", got %q", strings.Join(fields, " "))
+			}
+			var err error
+			if n, err = strconv.Atoi(fields[2]); err != nil || n < 0 {
+				return fmt.Errorf("Bad node count %q", fields[2])
+			}
+			if wantArcs, err = strconv.Atoi(fields[3]); err != nil || wantArcs < 0 {
+				return fmt.Errorf("Bad arc count %q", fields[3])
+			}
+		case "n":
+			if n == -1 {
+				return errors.New("Node before the problem line")
+			}
+			if len(fields) != 3 || (fields[2] != "s" && fields[2] != "t") {
+				return fmt.Errorf("Expected a node of the form \"n node s\" or \"n node t\", got %q", strings.Join(fields, " "))
+			}
+			node, err := oneBasedNode(fields[1], n)
+			if err != nil {
+				return err
+			}
+			end := &source
+			if fields[2] == "t" {
+				end = &sink
+			}
+			if *end != nil {
+				return fmt.Errorf("Repeated %s node", fields[2])
+			}
+			*end = node
+		case "a":
+			if n == -1 {
+				return errors.New("Arc before the problem line")
+			}
+			if len(fields) != 4 {
+				return fmt.Errorf("Expected an arc of the form \"a from to capacity\", got %q", strings.Join(fields, " "))
+			}
+			head, err := oneBasedNode(fields[1], n)
+			if err != nil {
+				return err
+			}
+			tail, err := oneBasedNode(fields[2], n)
+			if err != nil {
+				return err
+			}
+			capacity, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil || !(capacity >= 0) {
+				return fmt.Errorf("Bad capacity %q", fields[3])
+			}
+
+			e := core.GonumEdge{H: head, T: tail}
+			if !g.IsSuccessor(head, tail) {
+				addNode(g, head)
+				g.AddEdge(e)
+			} else {
+				capacity += g.Cost(head, tail)
+			}
+			g.SetEdgeCost(e, capacity)
+			arcs++
+		default:
+			return fmt.Errorf("Unknown line type %q", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if n == -1 {
+		return nil, nil, nil, errors.New("No problem line")
+	}
+	if source == nil || sink == nil || source.ID() == sink.ID() {
+		return nil, nil, nil, errors.New("Expected one source and one sink, on different nodes")
+	}
+	if arcs != wantArcs {
+		return nil, nil, nil, fmt.Errorf("The problem line has %d arcs, but %d were listed", wantArcs, arcs)
+	}
+	if err := addOneBasedNodes(g, n, input.n); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return g, source, sink, nil
+}
+
+// Writes a maximum flow problem from source to sink in the DIMACS flow format that ReadDIMACSFlow reads, with an arc for every edge, of the capacity given by capacity (UnitCapacity if
+// nil), as MaxFlow would use it. The format numbers nodes from 1, so the nodes are renumbered 1 to n in order of ID; a graph read by ReadDIMACSFlow already is. An undirected graph's
+// edges are written as an arc in each direction, which is how MaxFlow treats them. Returns an error if the source or sink isn't in the graph.
+func WriteDIMACSFlow(w io.Writer, graph core.Graph, source, sink core.Node, capacity func(core.Edge) float64) error {
+	if capacity == nil {
+		capacity = flow.UnitCapacity
+	}
+	nodes, numbers := numberNodes(graph)
+	if _, ok := numbers[source.ID()]; !ok {
+		return core.ErrNodeNotFound
+	}
+	if _, ok := numbers[sink.ID()]; !ok {
+		return core.ErrNodeNotFound
+	}
+
+	var arcs []string
+	for _, node := range nodes {
+		for _, succ := range core.SortedSuccessors(graph, node) {
+			arcs = append(arcs, fmt.Sprintf("a %d %d %s\n", numbers[node.ID()], numbers[succ.ID()], strconv.FormatFloat(capacity(core.GonumEdge{H: node, T: succ}), 'g', -1, 64)))
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "p max %d %d\n", len(nodes), len(arcs))
+	fmt.Fprintf(buf, "n %d s\n", numbers[source.ID()])
+	fmt.Fprintf(buf, "n %d t\n", numbers[sink.ID()])
+	for _, arc := range arcs {
+		buf.WriteString(arc)
+	}
+
+	return buf.Flush()
+}
+
+// Reads a graph coloring problem in the DIMACS coloring format (.col), in which the graph coloring and clique benchmarks are distributed. The format is like ReadDIMACS's:
+//
+//	c This is a comment
+//	p edge 4 5   the problem line: a graph with 4 nodes and 5 edges, before any edges ("p col" is also accepted)
+//	e 1 2        an edge between nodes 1 and 2
+//
+// The graph is undirected, with nodes 1 to n, including any that no edge touches. Edges listed more than once, in either direction, are added once. Returns an error, with the line
+// number, for a missing or repeated problem line, an edge with an endpoint outside 1 to n, an unknown line type, or a number of edges that doesn't match the problem line.
+//
+// The input may come from an untrusted source: as in ReadDIMACS, a node count far larger than the input could describe is an error.
+func ReadDIMACSColoring(r io.Reader) (*simple.GonumGraph, error) {
+	g := simple.NewGonumGraph(false)
+	n, edges := -1, 0
+	wantEdges := 0
+
+	input := &countingReader{r: r}
+	err := scanDIMACS(input, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if n != -1 {
+				return errors.New("Repeated problem line")
+			}
+			if len(fields) != 4 || (fields[1] != "edge" && fields[1] != "col") {
+				return fmt.Errorf("Expected a problem line of the form \"p edge nodes edges\", got %q", strings.Join(fields, " "))
+			}
+			var err error
+			if n, err = strconv.Atoi(fields[2]); err != nil || n < 0 {
+				return fmt.Errorf("Bad node count %q", fields[2])
+			}
+			if wantEdges, err = strconv.Atoi(fields[3]); err != nil || wantEdges < 0 {
+				return fmt.Errorf("Bad edge count %q", fields[3])
+			}
+		case "e":
+			if n == -1 {
+				return errors.New("Edge before the problem line")
+			}
+			if len(fields) != 3 {
+				return fmt.Errorf("Expected an edge of the form \"e node node\", got %q", strings.Join(fields, " "))
+			}
+			head, err := oneBasedNode(fields[1], n)
+			if err != nil {
+				return err
+			}
+			tail, err := oneBasedNode(fields[2], n)
+			if err != nil {
+				return err
+			}
+
+			if !g.IsSuccessor(head, tail) {
+				addNode(g, head)
+				g.AddEdge(core.GonumEdge{H: head, T: tail})
+			}
+			edges++
+		default:
+			return fmt.Errorf("Unknown line type %q", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n == -1 {
+		return nil, errors.New("No problem line")
+	}
+	if edges != wantEdges {
+		return nil, fmt.Errorf("The problem line has %d edges, but %d were listed", wantEdges, edges)
+	}
+	if err := addOneBasedNodes(g, n, input.n); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Writes the graph in the DIMACS coloring format that ReadDIMACSColoring reads, with every edge once, from the lower numbered node. Coloring ignores edge directions, so a directed
+// graph's edges are written the same way, and a pair of opposite edges is written once. The format numbers nodes from 1, so the nodes are renumbered 1 to n in order of ID; a graph read
+// by ReadDIMACSColoring already is. Costs aren't written.
+func WriteDIMACSColoring(w io.Writer, graph core.Graph) error {
+	nodes, numbers := numberNodes(graph)
+
+	keys := make(graphutil.EdgeKeySorter, 0)
+	seen := make(map[core.EdgeKey]bool)
+	for _, node := range nodes {
+		for _, succ := range graph.Successors(node) {
+			key := core.KeyOf(core.GonumEdge{H: core.GonumNode(numbers[node.ID()]), T: core.GonumNode(numbers[succ.ID()])}, false)
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Sort(keys)
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "p edge %d %d\n", len(nodes), len(keys))
+	for _, key := range keys {
+		fmt.Fprintf(buf, "e %d %d\n", key.Head, key.Tail)
+	}
+
+	return buf.Flush()
+}
+
+// Calls line with the fields of every line of a DIMACS file that isn't blank or a comment, adding the line number to any error it returns
+func scanDIMACS(r io.Reader, line func(fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	for i := 1; scanner.Scan(); i++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		if err := line(fields); err != nil {
+			return fmt.Errorf("Line %d: %v", i, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Parses a node ID of a format that numbers the nodes from 1, such as DIMACS or Matrix Market, which must be between 1 and n
+func oneBasedNode(field string, n int) (core.Node, error) {
+	id, err := strconv.Atoi(field)
+	if err != nil || id < 1 || id > n {
+		return nil, fmt.Errorf("Bad node %q, nodes are numbered 1 to %d", field, n)
+	}
+
+	return core.GonumNode(id), nil
+}
+
+// Decoders of formats that declare a node count up front (DIMACS, Matrix Market) take it on trust for at most this many nodes more than the input has bytes. That's plenty for the isolated
+// nodes of any real file, but keeps a forged count, such as a one line file claiming billions of nodes, from using up memory.
+const nodeCountSlack = 1 << 16
+
+// Adds nodes 1 to n to the graph, other than those already in it, once the input that declared n has been read, returning an error instead if n is more than read bytes of input can justify
+func addOneBasedNodes(g *simple.GonumGraph, n int, read int64) error {
+	if int64(n) > read+nodeCountSlack {
+		return fmt.Errorf("The node count %d is too large for an input of %d bytes", n, read)
+	}
+
+	for id := 1; id <= n; id++ {
+		addNode(g, core.GonumNode(id))
+	}
+
+	return nil
+}
+
+// Adds the node to the graph unless it's already there, since AddNode would drop its edges
+func addNode(g *simple.GonumGraph, node core.Node) {
+	if !g.NodeExists(node) {
+		g.AddNode(node, nil)
+	}
+}
+
+// A countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// Returns the graph's nodes in order of ID, and the number of each by ID, counting from 1, for writing formats that number the nodes from 1
+func numberNodes(graph core.Graph) (nodes []core.Node, numbers map[int]int) {
+	nodes = core.SortedNodeList(graph)
+	numbers = make(map[int]int, len(nodes))
+	for i, node := range nodes {
+		numbers[node.ID()] = i + 1
+	}
+
+	return nodes, numbers
+}
@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package encoding_test
+
+import (
+	"bytes"
+	"github.com/nathankerr/graph/encoding"
+	"github.com/nathankerr/graph/encodingtest"
+	"testing"
+)
+
+func FuzzReadDIMACS(f *testing.F) {
+	encodingtest.FuzzDecoder(f, dimacsCodec{},
+		[]byte(""),
+		[]byte("c comment\np sp 3 3\na 1 2 NaN\na 1 2 -Inf\n\na 3 3 0x1p-2\n"),
+		[]byte("p sp 9223372036854775807 0\n"),
+	)
+}
+
+// FuzzDecoder's codec would have to know the source and sink up front, so this decodes the input itself and round trips whatever it reads with the source and sink it names
+func FuzzReadDIMACSFlow(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"p max 4 5\nn 1 s\nn 4 t\na 1 2 7\na 1 3 1e300\na 2 4 0\na 3 4 0x1p-2\na 2 4 Inf\n",
+		"p max 9223372036854775807 0\nn 1 s\nn 2 t\n",
+		"p max 2 1\nn 2 s\nn 2 t\na 1 2 -1\n",
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, source, sink, err := encoding.ReadDIMACSFlow(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		encodingtest.RoundTrip(t, g, dimacsFlowCodec{source: source.ID(), sink: sink.ID()})
+	})
+}
+
+func FuzzReadDIMACSColoring(f *testing.F) {
+	encodingtest.FuzzDecoder(f, dimacsColoringCodec{},
+		[]byte(""),
+		[]byte("c comment\np col 4 3\ne 1 2\ne 2 1\ne 3 3\n"),
+		[]byte("p edge 9223372036854775807 0\n"),
+	)
+}
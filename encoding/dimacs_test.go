@@ -0,0 +1,279 @@
+package encoding_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encoding"
+	"github.com/nathankerr/graph/encodingtest"
+	"github.com/nathankerr/graph/flow"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadDIMACS(t *testing.T) {
+	const input = `c 9th DIMACS Implementation Challenge: Shortest Paths
+c node 4 has no arcs
+p sp 4 4
+a 1 2 7
+a 2 3 1.5
+
+a 1 2 3
+a 3 1 2
+`
+	g, err := encoding.ReadDIMACS(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The repeated arc keeps its cheaper cost
+	if len(g.NodeList()) != 4 || len(g.EdgeList()) != 3 || !g.IsDirected() || g.Cost(core.GonumNode(1), core.GonumNode(2)) != 3 || g.Cost(core.GonumNode(2), core.GonumNode(3)) != 1.5 {
+		t.Errorf("Got nodes %v and edges %v", g.NodeList(), g.EdgeList())
+	}
+
+	for _, bad := range []string{
+		"",
+		"a 1 2 3\np sp 2 1\n",
+		"p sp 2 1\np sp 2 1\na 1 2 3\n",
+		"p sp 2 1\na 1 3 3\n",
+		"p sp 2 1\na 0 1 3\n",
+		"p sp 2 1\na 1 2 far\n",
+		"p sp 2 2\na 1 2 3\n",
+		"p max 2 1\na 1 2 3\n",
+		"p sp 2 1\nn 1 s\n",
+		"p sp 2000000000 0\n",
+	} {
+		if _, err := encoding.ReadDIMACS(strings.NewReader(bad)); err == nil {
+			t.Errorf("No error for %q", bad)
+		}
+	}
+
+	// Nodes without arcs are still added, as long as the count isn't out of all proportion to the input
+	if g, err := encoding.ReadDIMACS(strings.NewReader("p sp 1000 1\na 1000 1 2\n")); err != nil || len(g.NodeList()) != 1000 || g.Cost(core.GonumNode(1000), core.GonumNode(1)) != 2 {
+		t.Errorf("Got error %v reading 1000 nodes with one arc", err)
+	}
+}
+
+// DIMACS renumbers the nodes 1 to n, so the codec maps them back to the IDs of the graph it was made for. The zero value keeps the numbers, for decoding arbitrary input.
+type dimacsCodec struct {
+	ids []int
+}
+
+func (dimacsCodec) Encode(w io.Writer, g core.Graph) error {
+	return encoding.WriteDIMACS(w, g)
+}
+
+func (codec dimacsCodec) Decode(r io.Reader) (core.Graph, error) {
+	read, err := encoding.ReadDIMACS(r)
+	if err != nil {
+		return nil, err
+	}
+	return renumber(read, codec.ids), nil
+}
+
+func TestDIMACSFixtures(t *testing.T) {
+	// A .gr file is always directed and can't hold metadata, so only these survive
+	fixtures := encodingtest.Fixtures()
+	for _, name := range []string{"empty", "directed"} {
+		g := fixtures[name]
+		encodingtest.RoundTrip(t, g, dimacsCodec{sortedIDs(g)})
+		encodingtest.Golden(t, "dimacs_"+name, g, dimacsCodec{sortedIDs(g)})
+	}
+}
+
+func TestReadDIMACSCoordinates(t *testing.T) {
+	coords, err := encoding.ReadDIMACSCoordinates(strings.NewReader("c coordinates\np aux sp co 2\nv 1 -73530767 41085396\nv 2 -73530538 41086098\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coords) != 2 || coords[1] != [2]float64{-73530767, 41085396} {
+		t.Errorf("Got coordinates %v", coords)
+	}
+
+	for _, bad := range []string{
+		"p aux sp co 2\nv 1 0 0\n",
+		"p aux sp co 1\nv 1 0 0\nv 1 0 0\n",
+		"p aux sp co 1\nv 1 0 north\n",
+		"v 1 0 0\n",
+	} {
+		if _, err := encoding.ReadDIMACSCoordinates(strings.NewReader(bad)); err == nil {
+			t.Errorf("No error for %q", bad)
+		}
+	}
+}
+
+func TestDIMACSFlow(t *testing.T) {
+	const input = `c The flow network from TestMaxFlow, numbered from 1
+p max 6 10
+n 1 s
+n 6 t
+a 1 2 16
+a 1 3 13
+a 2 4 12
+a 3 2 4
+a 3 5 14
+a 4 3 9
+a 4 6 20
+a 5 4 3
+a 5 6 4
+a 5 4 4
+`
+	g, source, sink, err := encoding.ReadDIMACSFlow(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The repeated arc's capacities add up to TestMaxFlow's 7
+	if len(g.NodeList()) != 6 || len(g.EdgeList()) != 9 || source.ID() != 1 || sink.ID() != 6 || g.Cost(core.GonumNode(5), core.GonumNode(4)) != 7 {
+		t.Errorf("Got nodes %v and edges %v from %v to %v", g.NodeList(), g.EdgeList(), source, sink)
+	}
+	if result, _ := flow.MaxFlow(g, source, sink, flow.CostCapacity(g)); result.Value != 23 {
+		t.Errorf("Got flow %f, want 23", result.Value)
+	}
+
+	var buf bytes.Buffer
+	if err := encoding.WriteDIMACSFlow(&buf, g, source, sink, flow.CostCapacity(g)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "p max 6 9\nn 1 s\nn 6 t\na 1 2 16\na 1 3 13\na 2 4 12\na 3 2 4\na 3 5 14\na 4 3 9\na 4 6 20\na 5 4 7\na 5 6 4\n"; buf.String() != want {
+		t.Errorf("Wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// An undirected graph's edges become arcs both ways, and the nodes are renumbered
+	route := simple.NewGonumGraph(false)
+	route.AddNode(core.GonumNode(0), nodes(10))
+	buf.Reset()
+	if err := encoding.WriteDIMACSFlow(&buf, route, core.GonumNode(0), core.GonumNode(10), nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "p max 2 2\nn 1 s\nn 2 t\na 1 2 1\na 2 1 1\n"; buf.String() != want {
+		t.Errorf("Wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	if err := encoding.WriteDIMACSFlow(&buf, route, core.GonumNode(0), core.GonumNode(5), nil); err != core.ErrNodeNotFound {
+		t.Errorf("Got error %v for a sink outside the graph", err)
+	}
+
+	for _, bad := range []string{
+		"p max 2 1\nn 1 s\na 1 2 3\n",
+		"p max 2 1\nn 1 s\nn 1 t\na 1 2 3\n",
+		"p max 2 1\nn 1 s\nn 2 s\nn 2 t\na 1 2 3\n",
+		"p max 2 1\nn 1 s\nn 2 t\na 1 2 -3\n",
+		"p max 2 1\nn 1 s\nn 2 t\na 1 2 NaN\n",
+		"p max 2 1\nn 1 source\nn 2 t\na 1 2 3\n",
+		"n 1 s\np max 2 1\nn 2 t\na 1 2 3\n",
+		"p sp 2 1\nn 1 s\nn 2 t\na 1 2 3\n",
+		"p max 2000000000 0\nn 1 s\nn 2 t\n",
+	} {
+		if _, _, _, err := encoding.ReadDIMACSFlow(strings.NewReader(bad)); err == nil {
+			t.Errorf("No error for %q", bad)
+		}
+	}
+}
+
+// Reads and writes flow problems from source to sink, mapping the nodes back to the IDs of the graph the codec was made for as dimacsCodec does. Decode fails if the problem's source
+// and sink aren't the codec's.
+type dimacsFlowCodec struct {
+	ids          []int
+	source, sink int
+}
+
+func (codec dimacsFlowCodec) Encode(w io.Writer, g core.Graph) error {
+	return encoding.WriteDIMACSFlow(w, g, core.GonumNode(codec.source), core.GonumNode(codec.sink), flow.CostCapacity(g.(core.Coster)))
+}
+
+func (codec dimacsFlowCodec) Decode(r io.Reader) (core.Graph, error) {
+	read, source, sink, err := encoding.ReadDIMACSFlow(r)
+	if err != nil {
+		return nil, err
+	}
+	g := renumber(read, codec.ids)
+	if codec.ids != nil {
+		source, sink = core.GonumNode(codec.ids[source.ID()-1]), core.GonumNode(codec.ids[sink.ID()-1])
+	}
+	if source.ID() != codec.source || sink.ID() != codec.sink {
+		return nil, fmt.Errorf("Read a flow from %v to %v, want %d to %d", source, sink, codec.source, codec.sink)
+	}
+	return g, nil
+}
+
+func TestDIMACSFlowFixtures(t *testing.T) {
+	// A .max file is always directed and its capacities can't be negative, so the directed fixture's negative cost is made positive
+	g := encodingtest.Fixtures()["directed"]
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(0)}, 1)
+	codec := dimacsFlowCodec{sortedIDs(g), 10, 0}
+	encodingtest.RoundTrip(t, g, codec)
+	encodingtest.Golden(t, "dimacs_flow_directed", g, codec)
+}
+
+func TestDIMACSColoring(t *testing.T) {
+	// Some coloring instances list every edge in both directions
+	g, err := encoding.ReadDIMACSColoring(strings.NewReader("c a triangle and an isolated node\np edge 4 4\ne 1 2\ne 2 3\ne 3 1\ne 2 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.NodeList()) != 4 || g.IsDirected() || !g.IsSuccessor(core.GonumNode(1), core.GonumNode(3)) || len(g.Successors(core.GonumNode(2))) != 2 {
+		t.Errorf("Got nodes %v and edges %v", g.NodeList(), g.EdgeList())
+	}
+
+	var buf bytes.Buffer
+	if err := encoding.WriteDIMACSColoring(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	if want := "p edge 4 3\ne 1 2\ne 1 3\ne 2 3\n"; buf.String() != want {
+		t.Errorf("Wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// A directed graph's opposite edges are written once
+	directed := simple.NewGonumGraph(true)
+	directed.AddNode(core.GonumNode(5), nodes(7))
+	directed.AddNode(core.GonumNode(7), nodes(5))
+	buf.Reset()
+	if err := encoding.WriteDIMACSColoring(&buf, directed); err != nil {
+		t.Fatal(err)
+	}
+	if want := "p edge 2 1\ne 1 2\n"; buf.String() != want {
+		t.Errorf("Wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	for _, bad := range []string{
+		"p edge 2 2\ne 1 2\n",
+		"p edge 2 1\ne 1 3\n",
+		"p edge 2 1\ne 1\n",
+		"e 1 2\np edge 2 1\n",
+		"p sp 2 1\ne 1 2\n",
+		"p edge 2 1\na 1 2 1\n",
+		"p edge 2000000000 0\n",
+	} {
+		if _, err := encoding.ReadDIMACSColoring(strings.NewReader(bad)); err == nil {
+			t.Errorf("No error for %q", bad)
+		}
+	}
+}
+
+// Reads and writes coloring problems, mapping the nodes back to the IDs of the graph the codec was made for as dimacsCodec does. The format has no costs, so the decoded graph isn't a
+// Coster, and the harness doesn't compare them.
+type dimacsColoringCodec struct {
+	ids []int
+}
+
+func (dimacsColoringCodec) Encode(w io.Writer, g core.Graph) error {
+	return encoding.WriteDIMACSColoring(w, g)
+}
+
+func (codec dimacsColoringCodec) Decode(r io.Reader) (core.Graph, error) {
+	read, err := encoding.ReadDIMACSColoring(r)
+	if err != nil {
+		return nil, err
+	}
+	return plainGraph{renumber(read, codec.ids)}, nil
+}
+
+func TestDIMACSColoringFixtures(t *testing.T) {
+	// A .col file is always undirected, and can't hold costs or metadata
+	fixtures := encodingtest.Fixtures()
+	for _, name := range []string{"isolated", "undirected"} {
+		g := fixtures[name]
+		encodingtest.RoundTrip(t, g, dimacsColoringCodec{sortedIDs(g)})
+		encodingtest.Golden(t, "dimacs_coloring_"+name, g, dimacsColoringCodec{sortedIDs(g)})
+	}
+}
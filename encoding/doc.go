@@ -0,0 +1,9 @@
+// Package encoding reads and writes graphs in the interchange formats other tools use, reading them into GonumGraphs. Encodings that belong to one graph type, such as GonumGraph's JSON
+// and snapshots, are methods in package simple instead.
+//
+//	dot.go           reading and writing graphs, with their metadata and attributes, in Graphviz's DOT language
+//	edgelist.go      reading and writing the "src dst [weight]" edge lists that public datasets ship in
+//	dimacs.go        reading and writing the DIMACS shortest path (.gr) files of the routing benchmarks and reading their coordinate (.co) files, and DIMACS flow (.max) and coloring (.col) problems
+//	matrixmarket.go  reading and writing Matrix Market (.mtx) sparse matrices as weighted graphs
+//	patch.go         Patch, the difference between two versions of a graph, for keeping replicas up to date
+package encoding
@@ -0,0 +1,781 @@
+package encoding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options for MarshalDOT. The zero value (or a nil *DOTOptions) writes just the nodes, edges, weights and metadata.
+type DOTOptions struct {
+	NodeAttributes func(node core.Node) map[string]string // Extra attributes for each node, such as label, shape or color. May return nil
+	EdgeAttributes func(e core.Edge) map[string]string    // Extra attributes for each edge, such as label or color. May return nil. Must not set weight
+	Cost           func(core.Node, core.Node) float64     // The edge weights to write; see MarshalDOT
+}
+
+// The attributes read by UnmarshalDOT that don't have a place in a GonumGraph.
+type DOTAttributes struct {
+	Nodes map[int]map[string]string          // The attributes of each node that had any, by ID
+	Edges map[core.EdgeKey]map[string]string // The attributes of each edge that had any other than weight, by KeyOf. Edges from one edge statement share a map.
+	Names map[int]string                     // The original names of nodes that weren't named by an integer, by the ID they were given
+}
+
+// Writes the graph in Graphviz's DOT language, as a digraph if the graph is directed and an (undirected) graph if not, so that it can be rendered (e.g. "dot -Tsvg") or read back with
+// UnmarshalDOT. Nodes are named by their IDs. Every node is listed, so isolated nodes survive the round trip, followed by every edge, each edge of an undirected graph once.
+//
+// Edge costs are written as the weight attribute. As with other algorithms that use Cost, the order of precedence is Argument > Interface, but a graph that isn't a Coster and has no
+// Cost argument gets no weights at all rather than a page of weight=1. The graph's Metadata, if it's a MetadataHolder, is written as the graph's ID (Name) and graph attributes:
+// Creator as creator, Created as created (in RFC 3339 format), and each of the Attributes under its own key, so attributes Graphviz understands, such as rankdir or label, affect
+// rendering. Returns an error if an attribute would be written twice, for example an edge attribute named weight or a metadata attribute named creator.
+//
+// The output is the same for the same graph, however it lists its nodes and edges: nodes, edges and attributes are all written in sorted order.
+func MarshalDOT(graph core.Graph, opts *DOTOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &DOTOptions{}
+	}
+	var edgeCost func(core.Edge) float64
+	if _, ok := graph.(core.Coster); ok || opts.Cost != nil {
+		edgeCost = graphutil.DefaultEdgeCost(graph, opts.Cost)
+	}
+
+	var buf bytes.Buffer
+	keyword, edgeOp := "graph", "--"
+	if graph.IsDirected() {
+		keyword, edgeOp = "digraph", "->"
+	}
+
+	md := core.GraphMetadata(graph)
+	if md.Name != "" {
+		fmt.Fprintf(&buf, "%s %s {\n", keyword, dotID(md.Name))
+	} else {
+		fmt.Fprintf(&buf, "%s {\n", keyword)
+	}
+
+	graphAttrs := make(map[string]string, len(md.Attributes)+2)
+	for key, value := range md.Attributes {
+		graphAttrs[key] = value
+	}
+	if md.Creator != "" {
+		if _, ok := graphAttrs["creator"]; ok {
+			return nil, errors.New("Metadata has both a Creator and a creator attribute")
+		}
+		graphAttrs["creator"] = md.Creator
+	}
+	if !md.Created.IsZero() {
+		if _, ok := graphAttrs["created"]; ok {
+			return nil, errors.New("Metadata has both a Created time and a created attribute")
+		}
+		graphAttrs["created"] = md.Created.Format(time.RFC3339Nano)
+	}
+	for _, key := range sortedKeys(graphAttrs) {
+		fmt.Fprintf(&buf, "\t%s=%s;\n", dotID(key), dotID(graphAttrs[key]))
+	}
+
+	nodes := graphutil.NodeSorter(graph.NodeList())
+	sort.Sort(nodes)
+	for _, node := range nodes {
+		var attrs map[string]string
+		if opts.NodeAttributes != nil {
+			attrs = opts.NodeAttributes(node)
+		}
+		fmt.Fprintf(&buf, "\t%d%s;\n", node.ID(), dotAttrList(attrs))
+	}
+
+	keys := make(graphutil.EdgeKeySorter, 0)
+	edges := make(map[core.EdgeKey]core.Edge)
+	for _, edge := range graph.EdgeList() {
+		key := core.KeyOf(edge, graph.IsDirected())
+		if _, ok := edges[key]; !ok {
+			keys = append(keys, key)
+			edges[key] = edge
+		}
+	}
+	sort.Sort(keys)
+	for _, key := range keys {
+		edge := edges[key]
+		attrs := make(map[string]string)
+		if opts.EdgeAttributes != nil {
+			for name, value := range opts.EdgeAttributes(edge) {
+				attrs[name] = value
+			}
+		}
+		if edgeCost != nil {
+			if _, ok := attrs["weight"]; ok {
+				return nil, fmt.Errorf("Edge %d %s %d has a weight attribute as well as a cost", key.Head, edgeOp, key.Tail)
+			}
+			attrs["weight"] = strconv.FormatFloat(edgeCost(edge), 'g', -1, 64)
+		}
+		fmt.Fprintf(&buf, "\t%d %s %d%s;\n", key.Head, edgeOp, key.Tail, dotAttrList(attrs))
+	}
+
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// Reads a graph written in Graphviz's DOT language, by MarshalDOT or anything else. A digraph gives a directed GonumGraph and a graph an undirected one. Nodes named by an integer get
+// it as their ID; any other names are given the lowest IDs no integer name uses, in the order they first appear, and are recorded in the returned attributes' Names. An edge's weight
+// attribute becomes its cost (edges without one cost 1), and must be a number.
+//
+// The whole language is accepted: attribute statements set defaults for the nodes and edges that follow them, subgraphs are flattened into the graph (an edge to a subgraph is an edge to
+// each of its nodes), ports are ignored, and an edge given more than once gets the attributes of its last appearance. Graph attributes set at the top level, outside any subgraph, are
+// read into the graph's Metadata as MarshalDOT writes it, along with the graph's ID as its Name. Only one graph is read; anything after it is an error, as is a created attribute that
+// isn't an RFC 3339 time.
+//
+// The input may come from an untrusted source: subgraphs nested too deep, or edge statements between subgraphs that give more edges than a few per byte of input, are errors rather than
+// exhausting the stack or memory. Errors give the line number of the problem.
+func UnmarshalDOT(r io.Reader) (*simple.GonumGraph, *DOTAttributes, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokens, err := lexDOT(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := &dotParser{
+		tokens:     tokens,
+		maxEdges:   maxDOTEdgesPerByte * len(input),
+		nodeAttrs:  make(map[string]map[string]string),
+		graphAttrs: make(map[string]string),
+	}
+	if err := p.parseGraph(); err != nil {
+		return nil, nil, err
+	}
+
+	return p.build()
+}
+
+// Returns s as a DOT ID: as it is if it's a plain identifier or number, and quoted otherwise
+func dotID(s string) string {
+	if isDOTIdentifier(s) && !isDOTKeyword(s) || isDOTNumeral(s) {
+		return s
+	}
+
+	return `"` + strings.Replace(strings.Replace(s, `\`, `\\`, -1), `"`, `\"`, -1) + `"`
+}
+
+func isDOTIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDOTKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "strict", "graph", "digraph", "node", "edge", "subgraph":
+		return true
+	}
+
+	return false
+}
+
+// A numeral is [-]?(.[0-9]+ | [0-9]+(.[0-9]*)?)
+func isDOTNumeral(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	digits, dot := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case '0' <= c && c <= '9':
+			digits++
+		case c == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+
+	return digits > 0
+}
+
+// Returns " [k=v, ...]" in key order, or "" if there are no attributes
+func dotAttrList(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(attrs))
+	for _, key := range sortedKeys(attrs) {
+		pairs = append(pairs, dotID(key)+"="+dotID(attrs[key]))
+	}
+
+	return " [" + strings.Join(pairs, ", ") + "]"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+const (
+	dotIDToken    = iota
+	dotPunctToken // One of { } [ ] ; , = :
+	dotEdgeOpToken
+	dotEOFToken
+)
+
+type dotToken struct {
+	kind   int
+	text   string
+	quoted bool // A quoted or HTML ID, which is never a keyword
+	line   int
+}
+
+// Splits DOT source into tokens, dropping whitespace, comments and preprocessor lines
+func lexDOT(input []byte) ([]dotToken, error) {
+	tokens := make([]dotToken, 0)
+	line := 1
+	lineStart := true
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == '\n':
+			line++
+			lineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		case c == '#' && lineStart, c == '/' && i+1 < len(input) && input[i+1] == '/':
+			for i < len(input) && input[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(input) && input[i+1] == '*':
+			end := bytes.Index(input[i+2:], []byte("*/"))
+			if end == -1 {
+				return nil, fmt.Errorf("Line %d: Unterminated comment", line)
+			}
+			line += bytes.Count(input[i:i+2+end], []byte("\n"))
+			i += end + 4
+			continue
+		}
+		lineStart = false
+
+		start := i
+		switch {
+		case strings.IndexByte("{}[];,=:", c) != -1:
+			tokens = append(tokens, dotToken{kind: dotPunctToken, text: string(c), line: line})
+			i++
+		case c == '-' && i+1 < len(input) && (input[i+1] == '>' || input[i+1] == '-'):
+			tokens = append(tokens, dotToken{kind: dotEdgeOpToken, text: string(input[i : i+2]), line: line})
+			i += 2
+		case c == '"':
+			var text bytes.Buffer
+			i++
+			for ; i < len(input) && input[i] != '"'; i++ {
+				if input[i] == '\\' && i+1 < len(input) {
+					switch input[i+1] {
+					case '"', '\\':
+						i++
+					case '\n':
+						i++
+						line++
+						continue
+					}
+				}
+				if input[i] == '\n' {
+					line++
+				}
+				text.WriteByte(input[i])
+			}
+			if i == len(input) {
+				return nil, fmt.Errorf("Line %d: Unterminated string", line)
+			}
+			i++
+			tokens = append(tokens, dotToken{kind: dotIDToken, text: text.String(), quoted: true, line: line})
+		case c == '<':
+			depth := 0
+			for ; i < len(input); i++ {
+				if input[i] == '<' {
+					depth++
+				} else if input[i] == '>' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				} else if input[i] == '\n' {
+					line++
+				}
+			}
+			if i == len(input) {
+				return nil, fmt.Errorf("Line %d: Unterminated HTML string", line)
+			}
+			i++
+			tokens = append(tokens, dotToken{kind: dotIDToken, text: string(input[start+1 : i-1]), quoted: true, line: line})
+		case c == '-' || c == '.' || '0' <= c && c <= '9':
+			i++
+			for i < len(input) && (input[i] == '.' || '0' <= input[i] && input[i] <= '9') {
+				i++
+			}
+			if !isDOTNumeral(string(input[start:i])) {
+				return nil, fmt.Errorf("Line %d: Bad number %q", line, input[start:i])
+			}
+			tokens = append(tokens, dotToken{kind: dotIDToken, text: string(input[start:i]), line: line})
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80:
+			for i < len(input) && (input[i] == '_' || 'a' <= input[i] && input[i] <= 'z' || 'A' <= input[i] && input[i] <= 'Z' || '0' <= input[i] && input[i] <= '9' || input[i] >= 0x80) {
+				i++
+			}
+			tokens = append(tokens, dotToken{kind: dotIDToken, text: string(input[start:i]), line: line})
+		default:
+			return nil, fmt.Errorf("Line %d: Unexpected %q", line, c)
+		}
+	}
+
+	return append(tokens, dotToken{kind: dotEOFToken, line: line}), nil
+}
+
+// Subgraphs can nest, and each level is a level of recursion, so untrusted input can't be allowed to nest them without limit
+const maxDOTDepth = 100
+
+// An edge statement between subgraphs gives an edge for every pair of their nodes, so a short input can ask for a huge number of edges; more than this many per byte of input is an error
+const maxDOTEdgesPerByte = 4
+
+type dotEdge struct {
+	from, to string
+	attrs    map[string]string
+	cost     float64
+}
+
+// The defaults set by node and edge attribute statements, which last until the end of the subgraph they're in
+type dotScope struct {
+	node, edge map[string]string
+}
+
+// Parses the token stream into node names and edges, which build then gives IDs
+type dotParser struct {
+	tokens     []dotToken
+	pos        int
+	depth      int
+	directed   bool
+	name       string
+	order      []string // Node names in the order they first appear
+	nodeAttrs  map[string]map[string]string
+	edges      []dotEdge
+	maxEdges   int
+	graphAttrs map[string]string // Other than creator and created
+	creator    string
+	created    time.Time
+}
+
+func (p *dotParser) peek() dotToken {
+	return p.tokens[p.pos]
+}
+
+func (p *dotParser) next() dotToken {
+	token := p.tokens[p.pos]
+	if token.kind != dotEOFToken {
+		p.pos++
+	}
+
+	return token
+}
+
+// Whether the next token is the given punctuation, consuming it if so
+func (p *dotParser) accept(punct string) bool {
+	if token := p.peek(); token.kind == dotPunctToken && token.text == punct {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *dotParser) isKeyword(token dotToken, keyword string) bool {
+	return token.kind == dotIDToken && !token.quoted && strings.EqualFold(token.text, keyword)
+}
+
+func (p *dotParser) fail(token dotToken, format string, args ...interface{}) error {
+	return fmt.Errorf("Line %d: %s", token.line, fmt.Sprintf(format, args...))
+}
+
+func (p *dotParser) expect(punct string) error {
+	if token := p.next(); token.kind != dotPunctToken || token.text != punct {
+		return p.fail(token, "Expected %q, got %s", punct, describeDOTToken(token))
+	}
+
+	return nil
+}
+
+func (p *dotParser) id() (string, error) {
+	token := p.next()
+	if token.kind != dotIDToken {
+		return "", p.fail(token, "Expected an ID, got %s", describeDOTToken(token))
+	}
+
+	return token.text, nil
+}
+
+func (p *dotParser) edgeOp() string {
+	if p.directed {
+		return "->"
+	}
+
+	return "--"
+}
+
+// Sets a top level graph attribute, which goes into the metadata
+func (p *dotParser) setGraphAttr(token dotToken, key, value string) error {
+	switch key {
+	case "creator":
+		p.creator = value
+	case "created":
+		created, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return p.fail(token, "Bad created time %q", value)
+		}
+		p.created = created
+	default:
+		p.graphAttrs[key] = value
+	}
+
+	return nil
+}
+
+func describeDOTToken(token dotToken) string {
+	if token.kind == dotEOFToken {
+		return "end of input"
+	}
+
+	return strconv.Quote(token.text)
+}
+
+// graph : [strict] (graph | digraph) [ID] '{' stmt_list '}'
+func (p *dotParser) parseGraph() error {
+	if p.isKeyword(p.peek(), "strict") {
+		p.next()
+	}
+	switch token := p.next(); {
+	case p.isKeyword(token, "digraph"):
+		p.directed = true
+	case p.isKeyword(token, "graph"):
+	default:
+		return p.fail(token, "Expected graph or digraph, got %s", describeDOTToken(token))
+	}
+	if p.peek().kind == dotIDToken {
+		p.name = p.next().text
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	if _, err := p.parseStmtList(dotScope{make(map[string]string), make(map[string]string)}, true); err != nil {
+		return err
+	}
+	if token := p.next(); token.kind != dotEOFToken {
+		return p.fail(token, "Expected end of input, got %s", describeDOTToken(token))
+	}
+
+	return nil
+}
+
+// Parses statements up to and including the closing brace, returning the nodes they mention
+func (p *dotParser) parseStmtList(scope dotScope, top bool) ([]string, error) {
+	members := make([]string, 0)
+	seen := make(map[string]bool) // Each node is a member once, however often it's mentioned
+	for !p.accept("}") {
+		token := p.peek()
+		var stmtNodes []string
+		var err error
+		switch {
+		case token.kind == dotEOFToken:
+			return nil, p.fail(token, "Expected \"}\", got end of input")
+		case p.isKeyword(token, "graph") || p.isKeyword(token, "node") || p.isKeyword(token, "edge"):
+			p.next()
+			var attrs map[string]string
+			if attrs, err = p.parseAttrLists(true); err != nil {
+				return nil, err
+			}
+			for key, value := range attrs {
+				switch strings.ToLower(token.text) {
+				case "node":
+					scope.node[key] = value
+				case "edge":
+					scope.edge[key] = value
+				default:
+					if top && err == nil {
+						err = p.setGraphAttr(token, key, value)
+					}
+				}
+			}
+		case token.kind == dotIDToken && !p.isKeyword(token, "subgraph") && p.tokens[p.pos+1].kind == dotPunctToken && p.tokens[p.pos+1].text == "=":
+			p.pos += 2
+			var value string
+			if value, err = p.id(); err != nil {
+				return nil, err
+			}
+			if top {
+				err = p.setGraphAttr(token, token.text, value)
+			}
+		default:
+			stmtNodes, err = p.parseNodeOrEdgeStmt(scope)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range stmtNodes {
+			if !seen[name] {
+				seen[name] = true
+				members = append(members, name)
+			}
+		}
+		p.accept(";")
+	}
+
+	return members, nil
+}
+
+// Parses a node statement, an edge statement or a subgraph (which may begin an edge statement), returning the nodes it mentions
+func (p *dotParser) parseNodeOrEdgeStmt(scope dotScope) ([]string, error) {
+	operands := make([][]string, 0)
+	for {
+		operand, err := p.parseOperand(scope)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+
+		token := p.peek()
+		if token.kind != dotEdgeOpToken {
+			break
+		}
+		if token.text != p.edgeOp() {
+			return nil, p.fail(token, "Expected %s, got %s", p.edgeOp(), token.text)
+		}
+		p.next()
+	}
+
+	attrs, err := p.parseAttrLists(false)
+	if err != nil {
+		return nil, err
+	}
+	token := p.peek()
+	members := make([]string, 0)
+	for _, operand := range operands {
+		members = append(members, operand...)
+	}
+	if len(operands) == 1 {
+		// A node statement; a subgraph on its own has nothing to apply attributes to
+		if len(members) == 1 && attrs != nil {
+			for key, value := range attrs {
+				p.nodeAttrs[members[0]][key] = value
+			}
+		}
+		return members, nil
+	}
+
+	// Every edge the statement gives has the same attributes, so they share one map
+	edgeAttrs := make(map[string]string, len(scope.edge)+len(attrs))
+	for key, value := range scope.edge {
+		edgeAttrs[key] = value
+	}
+	for key, value := range attrs {
+		edgeAttrs[key] = value
+	}
+	cost := 1.0
+	if weight, ok := edgeAttrs["weight"]; ok {
+		if cost, err = strconv.ParseFloat(weight, 64); err != nil {
+			return nil, p.fail(token, "Edge statement has a bad weight %q", weight)
+		}
+		delete(edgeAttrs, "weight")
+	}
+
+	for i := 1; i < len(operands); i++ {
+		if len(operands[i-1]) > 0 && len(operands[i]) > (p.maxEdges-len(p.edges))/len(operands[i-1]) {
+			return nil, p.fail(token, "More than %d edges, %d per byte of input", p.maxEdges, maxDOTEdgesPerByte)
+		}
+		for _, from := range operands[i-1] {
+			for _, to := range operands[i] {
+				p.edges = append(p.edges, dotEdge{from, to, edgeAttrs, cost})
+			}
+		}
+	}
+
+	return members, nil
+}
+
+// Parses a node ID, with an optional port, or a subgraph, returning the nodes it stands for
+func (p *dotParser) parseOperand(scope dotScope) ([]string, error) {
+	token := p.peek()
+	if p.isKeyword(token, "subgraph") || token.kind == dotPunctToken && token.text == "{" {
+		return p.parseSubgraph(scope)
+	}
+
+	name, err := p.id()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < 2 && p.accept(":"); i++ {
+		if _, err := p.id(); err != nil {
+			return nil, err
+		}
+	}
+	p.declare(name, scope)
+
+	return []string{name}, nil
+}
+
+// subgraph : [subgraph [ID]] '{' stmt_list '}'
+func (p *dotParser) parseSubgraph(scope dotScope) ([]string, error) {
+	if p.isKeyword(p.peek(), "subgraph") {
+		p.next()
+		if p.peek().kind == dotIDToken {
+			p.next()
+		}
+	}
+	token := p.peek()
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	if p.depth == maxDOTDepth {
+		return nil, p.fail(token, "Subgraphs nested more than %d deep", maxDOTDepth)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	inner := dotScope{make(map[string]string, len(scope.node)), make(map[string]string, len(scope.edge))}
+	for key, value := range scope.node {
+		inner.node[key] = value
+	}
+	for key, value := range scope.edge {
+		inner.edge[key] = value
+	}
+
+	return p.parseStmtList(inner, false)
+}
+
+// Parses any number of '[' a_list ']', returning nil if there were none. If required, there must be at least one.
+func (p *dotParser) parseAttrLists(required bool) (map[string]string, error) {
+	var attrs map[string]string
+	if required {
+		if token := p.peek(); token.kind != dotPunctToken || token.text != "[" {
+			return nil, p.fail(token, "Expected \"[\", got %s", describeDOTToken(token))
+		}
+	}
+
+	for p.accept("[") {
+		if attrs == nil {
+			attrs = make(map[string]string)
+		}
+		for !p.accept("]") {
+			key, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			value, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			attrs[key] = value
+			if !p.accept(",") {
+				p.accept(";")
+			}
+		}
+	}
+
+	return attrs, nil
+}
+
+// Records a node the first time it's mentioned, with the node defaults then in effect
+func (p *dotParser) declare(name string, scope dotScope) {
+	if _, ok := p.nodeAttrs[name]; ok {
+		return
+	}
+	attrs := make(map[string]string, len(scope.node))
+	for key, value := range scope.node {
+		attrs[key] = value
+	}
+	p.nodeAttrs[name] = attrs
+	p.order = append(p.order, name)
+}
+
+// Gives the nodes their IDs and builds the graph
+func (p *dotParser) build() (*simple.GonumGraph, *DOTAttributes, error) {
+	attrs := &DOTAttributes{
+		Nodes: make(map[int]map[string]string),
+		Edges: make(map[core.EdgeKey]map[string]string),
+		Names: make(map[int]string),
+	}
+
+	ids := make(map[string]int, len(p.order))
+	used := make(map[int]bool, len(p.order))
+	for _, name := range p.order {
+		if id, err := strconv.Atoi(name); err == nil {
+			ids[name] = id
+			used[id] = true
+		}
+	}
+	free := 0
+	for _, name := range p.order {
+		if _, ok := ids[name]; ok {
+			continue
+		}
+		for used[free] {
+			free++
+		}
+		ids[name] = free
+		used[free] = true
+		attrs.Names[free] = name
+	}
+
+	g := simple.NewGonumGraph(p.directed)
+	for _, name := range p.order {
+		id := ids[name]
+		g.AddNode(core.GonumNode(id), nil)
+		for key, value := range p.nodeAttrs[name] {
+			if attrs.Nodes[id] == nil {
+				attrs.Nodes[id] = make(map[string]string)
+			}
+			attrs.Nodes[id][key] = value
+		}
+	}
+
+	for _, edge := range p.edges {
+		e := core.GonumEdge{H: core.GonumNode(ids[edge.from]), T: core.GonumNode(ids[edge.to])}
+		g.AddEdge(e)
+		g.SetEdgeCost(e, edge.cost)
+		key := core.KeyOf(e, p.directed)
+		if len(edge.attrs) > 0 {
+			attrs.Edges[key] = edge.attrs
+		} else {
+			delete(attrs.Edges, key)
+		}
+	}
+
+	md := g.Metadata()
+	md.Name = p.name
+	md.Directed = p.directed
+	md.Creator = p.creator
+	md.Created = p.created
+	for key, value := range p.graphAttrs {
+		md.Set(key, value)
+	}
+
+	return g, attrs, nil
+}
@@ -0,0 +1,21 @@
+//go:build go1.18
+// +build go1.18
+
+package encoding_test
+
+import (
+	"github.com/nathankerr/graph/encodingtest"
+	"strings"
+	"testing"
+)
+
+func FuzzUnmarshalDOT(f *testing.F) {
+	encodingtest.FuzzDecoder(f, dotCodec{},
+		[]byte(""),
+		[]byte("graph { a -- b -- a }"),
+		[]byte("digraph { 9223372036854775807 -> a; { b c } -> { d e } [weight=NaN] }"),
+		[]byte("strict digraph \"x\" { node [label=<<b>hi</b>>]; created=\"2014-03-01T12:00:00Z\"; a:p:n -> b }"),
+		[]byte("digraph { \"\\\\\" = \"\\\n\" }"),
+		[]byte("digraph{{"+strings.Repeat("a ", 6000)+"}->{"+strings.Repeat("a ", 6000)+"}}"),
+	)
+}
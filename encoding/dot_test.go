@@ -0,0 +1,163 @@
+package encoding_test
+
+import (
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encoding"
+	"github.com/nathankerr/graph/encodingtest"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"strings"
+	"testing"
+)
+
+type dotCodec struct{}
+
+func (dotCodec) Encode(w io.Writer, g core.Graph) error {
+	out, err := encoding.MarshalDOT(g, nil)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func (dotCodec) Decode(r io.Reader) (core.Graph, error) {
+	g, _, err := encoding.UnmarshalDOT(r)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func TestDOTFixtures(t *testing.T) {
+	for name, g := range encodingtest.Fixtures() {
+		encodingtest.RoundTrip(t, g, dotCodec{})
+		encodingtest.Golden(t, "dot_"+name, g, dotCodec{})
+	}
+}
+
+func TestMarshalDOTAttributes(t *testing.T) {
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(1), nodes(2))
+	out, err := encoding.MarshalDOT(g, &encoding.DOTOptions{
+		NodeAttributes: func(node core.Node) map[string]string {
+			return map[string]string{"label": fmt.Sprintf("node %d", node.ID()), "shape": "box"}
+		},
+		EdgeAttributes: func(e core.Edge) map[string]string { return map[string]string{"label": `say "hi"`} },
+		Cost:           func(core.Node, core.Node) float64 { return 1e6 },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "graph {\n\t1 [label=\"node 1\", shape=box];\n\t2 [label=\"node 2\", shape=box];\n\t1 -- 2 [label=\"say \\\"hi\\\"\", weight=\"1e+06\"];\n}\n"
+	if string(out) != want {
+		t.Errorf("Got\n%s\nwant\n%s", out, want)
+	}
+
+	if _, err := encoding.MarshalDOT(g, &encoding.DOTOptions{EdgeAttributes: func(core.Edge) map[string]string { return map[string]string{"weight": "2"} }}); err == nil {
+		t.Error("No error for an edge attribute named weight")
+	}
+}
+
+func TestUnmarshalDOT(t *testing.T) {
+	const input = `/* Written by hand, as Graphviz users do */
+# a preprocessor line
+strict digraph G {
+	rankdir=LR; graph [label="A \"test\"", creator=me]
+	node [shape=box]
+	a -> b -> c [weight=2.5, color=red]
+	a:n -> 7 // a port, and a numbered node
+	edge [style=dashed]
+	subgraph cluster_0 { d; e [shape=circle] } -> c
+	{ a; "b" } -> "f\
+g"
+	b -> c
+	-3
+}
+`
+	g, attrs, err := encoding.UnmarshalDOT(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 7 and -3 keep their numbers, and the named nodes fill in around them. The escaped newline continues the line, so f and g are one name
+	names := map[string]int{"a": 0, "b": 1, "c": 2, "d": 3, "e": 4, "fg": 5}
+	for name, id := range names {
+		if attrs.Names[id] != name {
+			t.Errorf("Node %d is named %q, want %q", id, attrs.Names[id], name)
+		}
+	}
+	if got := fmt.Sprint(g.NodeList()); len(g.NodeList()) != 8 {
+		t.Errorf("Got nodes %s", got)
+	}
+	if len(g.EdgeList()) != 7 || !g.IsSuccessor(core.GonumNode(0), core.GonumNode(7)) || !g.IsSuccessor(core.GonumNode(4), core.GonumNode(2)) {
+		t.Errorf("Got edges %v", g.EdgeList())
+	}
+
+	if cost := g.Cost(core.GonumNode(0), core.GonumNode(1)); cost != 2.5 {
+		t.Errorf("a -> b costs %v, want 2.5", cost)
+	}
+	// b -> c was given again, without a weight or color, but with the dashed default
+	if cost, got := g.Cost(core.GonumNode(1), core.GonumNode(2)), fmt.Sprint(attrs.Edges[core.EdgeKey{Head: 1, Tail: 2}]); cost != 1 || got != "map[style:dashed]" {
+		t.Errorf("b -> c costs %v with attributes %s", cost, got)
+	}
+	if got := fmt.Sprint(attrs.Edges[core.EdgeKey{Head: 0, Tail: 1}]); got != "map[color:red]" {
+		t.Errorf("a -> b has attributes %s", got)
+	}
+	if attrs.Nodes[4]["shape"] != "circle" || attrs.Nodes[3]["shape"] != "box" || attrs.Nodes[-3]["shape"] != "box" {
+		t.Errorf("Got node attributes %v", attrs.Nodes)
+	}
+
+	md := g.Metadata()
+	if md.Name != "G" || md.Creator != "me" || !md.Directed || fmt.Sprint(md.Attributes) != `map[label:A "test" rankdir:LR]` {
+		t.Errorf("Got metadata %+v", md)
+	}
+
+	for _, bad := range []string{
+		"",
+		"digraph {",
+		"graph { a -> b }",
+		"digraph { a -- b }",
+		"digraph { a -> b [weight=heavy] }",
+		"digraph { created=yesterday }",
+		"digraph { a [label] }",
+		"digraph { \"a }",
+		"digraph { /* }",
+		"digraph { a } digraph { b }",
+		"digraph { 1.2.3 }",
+		"digraph { a + b }",
+		"digraph {" + strings.Repeat("{", 200) + strings.Repeat("}", 200) + "}",
+	} {
+		if _, _, err := encoding.UnmarshalDOT(strings.NewReader(bad)); err == nil {
+			t.Errorf("No error for %q", bad)
+		}
+	}
+}
+
+func TestUnmarshalDOTEdgeStatements(t *testing.T) {
+	// Repeating a node in a subgraph doesn't repeat its edges
+	same := strings.Repeat("a ", 6000)
+	g, attrs, err := encoding.UnmarshalDOT(strings.NewReader("digraph{{" + same + "}->{" + same + "} [color=red]}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.NodeList()) != 1 || len(g.EdgeList()) != 1 || attrs.Edges[core.EdgeKey{Head: 0, Tail: 0}]["color"] != "red" {
+		t.Errorf("Got edges %v with attributes %v", g.EdgeList(), attrs.Edges)
+	}
+
+	// But every pair of different nodes is an edge, so enough of them is too many for the input's size
+	names := make([]string, 1000)
+	for i := range names {
+		names[i] = fmt.Sprintf("n%d", i)
+	}
+	many := strings.Join(names, " ")
+	if _, _, err := encoding.UnmarshalDOT(strings.NewReader("digraph{{" + many + "}->{" + many + "}}")); err == nil {
+		t.Error("No error for a million edges from 10 KB")
+	}
+	if g, _, err := encoding.UnmarshalDOT(strings.NewReader("digraph{{" + many + "}->{a b}}")); err != nil {
+		t.Error(err)
+	} else if len(g.EdgeList()) != 2000 {
+		t.Errorf("Got %d edges from a fan-in of 1000 nodes, want 2000", len(g.EdgeList()))
+	}
+}
@@ -0,0 +1,141 @@
+package encoding
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// How ReadEdgeList treats the first line that isn't blank or a comment
+type EdgeListHeader int
+
+const (
+	DetectHeader EdgeListHeader = iota // Skip it if its first two fields aren't both integers, as in "source,target,weight"
+	NoHeader                           // Read it as an edge like any other
+	SkipHeader                         // Skip it whatever it holds
+)
+
+// Options for ReadEdgeList. The zero value (or a nil *EdgeListOptions) reads a directed graph, skips lines starting with '#' or '%' and detects a header.
+type EdgeListOptions struct {
+	Undirected bool                       // Read each line as an undirected edge
+	Comments   string                     // The characters that start a comment line. If empty, "#%", which covers SNAP ('#') and KONECT ('%')
+	Header     EdgeListHeader             // How to treat the first line
+	Combine    func(a, b float64) float64 // Combines the weights of an edge that's listed more than once. If nil, the last weight listed is kept
+}
+
+// Reads a graph from an edge list, the plain text format most public graph datasets (such as SNAP and KONECT) are distributed in. Each line is one edge, "src dst [weight]": the IDs
+// of its head and tail, and optionally its cost, which is 1 if it's left out. Fields are separated by whitespace, or by commas (with optional spaces around them) for CSV files. Fields
+// after the weight, such as KONECT's timestamps, are ignored. Blank lines and comment lines are skipped, as is a header line (see EdgeListHeader), and Windows line endings are
+// accepted.
+//
+// Nodes only appear in the graph through their edges. Returns an error, with the line number, for a line with fewer than two fields, or with an ID or weight that isn't a number. The
+// input may come from an untrusted source.
+func ReadEdgeList(r io.Reader, opts *EdgeListOptions) (*simple.GonumGraph, error) {
+	if opts == nil {
+		opts = &EdgeListOptions{}
+	}
+	comments := opts.Comments
+	if comments == "" {
+		comments = "#%"
+	}
+
+	g := simple.NewGonumGraph(!opts.Undirected)
+	scanner := bufio.NewScanner(r)
+	first := true
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.IndexByte(comments, text[0]) != -1 {
+			continue
+		}
+
+		fields, err := graphutil.SplitEdgeLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", line, err)
+		}
+		if first {
+			first = false
+			if opts.Header == SkipHeader {
+				continue
+			}
+			if opts.Header == DetectHeader && len(fields) >= 2 {
+				_, headErr := strconv.Atoi(fields[0])
+				_, tailErr := strconv.Atoi(fields[1])
+				if headErr != nil || tailErr != nil {
+					continue
+				}
+			}
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("Line %d: Expected two node IDs, got %q", line, text)
+		}
+
+		head, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: Bad node ID %q", line, fields[0])
+		}
+		tail, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: Bad node ID %q", line, fields[1])
+		}
+		weight := 1.0
+		if len(fields) > 2 {
+			if weight, err = strconv.ParseFloat(fields[2], 64); err != nil {
+				return nil, fmt.Errorf("Line %d: Bad weight %q", line, fields[2])
+			}
+		}
+
+		e := core.GonumEdge{H: core.GonumNode(head), T: core.GonumNode(tail)}
+		if !g.NodeExists(e.H) {
+			g.AddNode(e.H, nil)
+		}
+		if g.IsSuccessor(e.H, e.T) && opts.Combine != nil {
+			weight = opts.Combine(g.Cost(e.H, e.T), weight)
+		} else {
+			g.AddEdge(e)
+		}
+		g.SetEdgeCost(e, weight)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Writes the graph as an edge list that ReadEdgeList can read back: one "src dst weight" line per edge, separated by spaces, in order of src and then dst. An undirected graph's edges
+// are written once each, from the lower ID, so read it back with Undirected set. The weight is the edge's cost if the graph is a Coster, and is left out otherwise. Nothing else is
+// written, so isolated nodes, the metadata and whether the graph is directed don't survive the trip.
+func WriteEdgeList(w io.Writer, graph core.Graph) error {
+	var edgeCost func(core.Edge) float64
+	if _, ok := graph.(core.Coster); ok {
+		edgeCost = graphutil.DefaultEdgeCost(graph, nil)
+	}
+
+	keys := make(graphutil.EdgeKeySorter, 0)
+	edges := make(map[core.EdgeKey]core.Edge)
+	for _, edge := range graph.EdgeList() {
+		key := core.KeyOf(edge, graph.IsDirected())
+		if _, ok := edges[key]; !ok {
+			keys = append(keys, key)
+			edges[key] = edge
+		}
+	}
+	sort.Sort(keys)
+
+	buf := bufio.NewWriter(w)
+	for _, key := range keys {
+		if edgeCost != nil {
+			fmt.Fprintf(buf, "%d %d %s\n", key.Head, key.Tail, strconv.FormatFloat(edgeCost(edges[key]), 'g', -1, 64))
+		} else {
+			fmt.Fprintf(buf, "%d %d\n", key.Head, key.Tail)
+		}
+	}
+
+	return buf.Flush()
+}
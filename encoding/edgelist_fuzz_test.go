@@ -0,0 +1,14 @@
+//go:build go1.18
+// +build go1.18
+
+package encoding_test
+
+import (
+	"github.com/nathankerr/graph/encodingtest"
+	"testing"
+)
+
+func FuzzReadEdgeList(f *testing.F) {
+	seeds := [][]byte{[]byte(""), []byte("src,dst\n1,2,NaN\n"), []byte("% x\n1 1 -Inf\n2 1\n1 2\n")}
+	encodingtest.FuzzDecoder(f, edgeListCodec{true}, seeds...)
+}
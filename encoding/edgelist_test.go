@@ -0,0 +1,86 @@
+package encoding_test
+
+import (
+	"bytes"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encoding"
+	"github.com/nathankerr/graph/encodingtest"
+	"io"
+	"strings"
+	"testing"
+)
+
+type edgeListCodec struct {
+	directed bool
+}
+
+func (edgeListCodec) Encode(w io.Writer, g core.Graph) error {
+	return encoding.WriteEdgeList(w, g)
+}
+
+func (codec edgeListCodec) Decode(r io.Reader) (core.Graph, error) {
+	g, err := encoding.ReadEdgeList(r, &encoding.EdgeListOptions{Undirected: !codec.directed})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func TestEdgeListFixtures(t *testing.T) {
+	// Edge lists can't hold isolated nodes or metadata, so only these survive
+	fixtures := encodingtest.Fixtures()
+	for _, name := range []string{"directed", "undirected"} {
+		g := fixtures[name]
+		encodingtest.RoundTrip(t, g, edgeListCodec{g.IsDirected()})
+		encodingtest.Golden(t, "edgelist_"+name, g, edgeListCodec{g.IsDirected()})
+	}
+}
+
+func TestReadEdgeList(t *testing.T) {
+	// A CSV export with a header, and a duplicate edge whose weights add up
+	g, err := encoding.ReadEdgeList(strings.NewReader("source, target, weight\r\n1, 2, 0.5\r\n2,3,2\r\n\r\n1, 2, 1.5\r\n"), &encoding.EdgeListOptions{
+		Combine: func(a, b float64) float64 { return a + b },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.EdgeList()) != 2 || !g.IsDirected() || g.Cost(core.GonumNode(1), core.GonumNode(2)) != 2 || g.Cost(core.GonumNode(2), core.GonumNode(3)) != 2 {
+		t.Errorf("Got edges %v", g.EdgeList())
+	}
+
+	// KONECT: a '%' header, unweighted lines, and a timestamp column after the weight
+	g, err = encoding.ReadEdgeList(strings.NewReader("% sym unweighted\n% 3 3 3\n1 2\n2 3 1 1234567\n3 1\n"), &encoding.EdgeListOptions{Undirected: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.EdgeList()) != 6 || g.IsDirected() || !g.IsSuccessor(core.GonumNode(1), core.GonumNode(3)) {
+		t.Errorf("Got edges %v", g.EdgeList())
+	}
+
+	// Without header detection the header is an error, and with it skipped the first edge is lost
+	if _, err := encoding.ReadEdgeList(strings.NewReader("a b\n1 2\n"), &encoding.EdgeListOptions{Header: encoding.NoHeader}); err == nil {
+		t.Error("No error for a header with NoHeader")
+	}
+	if g, err := encoding.ReadEdgeList(strings.NewReader("1 2\n2 3\n"), &encoding.EdgeListOptions{Header: encoding.SkipHeader}); err != nil || len(g.EdgeList()) != 1 {
+		t.Errorf("Skipping the header gave %v, %v", g, err)
+	}
+	if g, err := encoding.ReadEdgeList(strings.NewReader("; comment\n1 2\n"), &encoding.EdgeListOptions{Comments: ";"}); err != nil || len(g.EdgeList()) != 1 {
+		t.Errorf("Custom comments gave %v, %v", g, err)
+	}
+
+	for _, bad := range []string{
+		"1 2\n3\n",
+		"1 2\n3 x\n",
+		"1 2 heavy\n",
+		"1,,2\n",
+	} {
+		if _, err := encoding.ReadEdgeList(strings.NewReader(bad), nil); err == nil {
+			t.Errorf("No error for %q", bad)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := encoding.WriteEdgeList(&buf, plainGraph{g}); err != nil || buf.String() != "1 2\n1 3\n2 3\n" {
+		t.Errorf("Wrote %q for an unweighted graph, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,19 @@
+package encoding_test
+
+import (
+	"github.com/nathankerr/graph/core"
+)
+
+// Hides everything but the Graph methods
+type plainGraph struct {
+	core.Graph
+}
+
+func nodes(ids ...int) []core.Node {
+	nodes := make([]core.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = core.GonumNode(id)
+	}
+
+	return nodes
+}
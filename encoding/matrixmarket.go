@@ -0,0 +1,170 @@
+package encoding
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Reads a graph from a sparse matrix in the Matrix Market coordinate format (.mtx), which the SuiteSparse Matrix Collection and many other graph datasets ship in. The matrix is read
+// as a weighted adjacency matrix, in which the entry in row i and column j is an edge from node i to node j costing the entry's value:
+//
+//	%%MatrixMarket matrix coordinate real general
+//	% comments
+//	3 3 2        rows, columns and entries
+//	1 2 0.5      an edge from node 1 to node 2 costing 0.5
+//	3 1 2
+//
+// The header's field may be real, integer or pattern (entries without values, which cost 1), and its symmetry general, which gives a directed graph, or symmetric, which gives an
+// undirected one with only the lower triangle listed. The nodes are 1 to the larger of the row and column counts, including any without entries. An entry listed more than once keeps
+// the last value.
+//
+// Returns an error, with the line number, for a missing or malformed header, the array format or the complex and skew-symmetric matrices (which have no graph reading), an entry out of
+// range or that isn't a number, or a number of entries that doesn't match the size line. The input may come from an untrusted source: as in ReadDIMACS, dimensions far larger than the
+// input could describe are an error.
+func ReadMatrixMarket(r io.Reader) (*simple.GonumGraph, error) {
+	input := &countingReader{r: r}
+	scanner := bufio.NewScanner(input)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("Line 1: No Matrix Market header")
+	}
+	header := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(header) != 5 || header[0] != "%%matrixmarket" || header[1] != "matrix" {
+		return nil, fmt.Errorf("Line 1: Expected a header of the form \"%%%%MatrixMarket matrix coordinate real general\", got %q", scanner.Text())
+	}
+	if header[2] != "coordinate" {
+		return nil, fmt.Errorf("Line 1: Unsupported format %q, only coordinate matrices are graphs", header[2])
+	}
+	field, symmetry := header[3], header[4]
+	if field != "real" && field != "integer" && field != "pattern" {
+		return nil, fmt.Errorf("Line 1: Unsupported field %q", field)
+	}
+	if symmetry != "general" && symmetry != "symmetric" {
+		return nil, fmt.Errorf("Line 1: Unsupported symmetry %q", symmetry)
+	}
+
+	g := simple.NewGonumGraph(symmetry == "general")
+	n, entries, wantEntries := -1, 0, 0
+	for line := 2; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "%") {
+			continue
+		}
+
+		if n == -1 {
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("Line %d: Expected a size line of the form \"rows columns entries\", got %q", line, scanner.Text())
+			}
+			var dims [3]int
+			for i, f := range fields {
+				var err error
+				if dims[i], err = strconv.Atoi(f); err != nil || dims[i] < 0 {
+					return nil, fmt.Errorf("Line %d: Bad size %q", line, f)
+				}
+			}
+			n, wantEntries = dims[0], dims[2]
+			if dims[1] > n {
+				n = dims[1]
+			}
+			continue
+		}
+
+		if field == "pattern" && len(fields) != 2 {
+			return nil, fmt.Errorf("Line %d: Expected an entry of the form \"row column\", got %q", line, scanner.Text())
+		} else if field != "pattern" && len(fields) != 3 {
+			return nil, fmt.Errorf("Line %d: Expected an entry of the form \"row column value\", got %q", line, scanner.Text())
+		}
+		head, err := oneBasedNode(fields[0], n)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", line, err)
+		}
+		tail, err := oneBasedNode(fields[1], n)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", line, err)
+		}
+		cost := 1.0
+		if len(fields) == 3 {
+			if cost, err = strconv.ParseFloat(fields[2], 64); err != nil {
+				return nil, fmt.Errorf("Line %d: Bad value %q", line, fields[2])
+			}
+		}
+
+		e := core.GonumEdge{H: head, T: tail}
+		if !g.IsSuccessor(head, tail) {
+			addNode(g, head)
+			g.AddEdge(e)
+		}
+		g.SetEdgeCost(e, cost)
+		entries++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if n == -1 {
+		return nil, errors.New("No size line")
+	}
+	if entries != wantEntries {
+		return nil, fmt.Errorf("The size line has %d entries, but %d were listed", wantEntries, entries)
+	}
+	if err := addOneBasedNodes(g, n, input.n); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Writes the graph as a Matrix Market coordinate matrix that ReadMatrixMarket can read back, with an entry in row i and column j for every edge from the i-th node to the j-th. The format
+// numbers rows from 1, so the nodes are renumbered 1 to n in order of ID; a graph read by ReadMatrixMarket already is, and comes back unchanged. A directed graph is written as a general
+// matrix, and an undirected one as a symmetric matrix with only the lower triangle listed. The entries are the edges' costs if the graph is a Coster, giving a real matrix, and a
+// pattern matrix otherwise. The metadata isn't written.
+func WriteMatrixMarket(w io.Writer, graph core.Graph) error {
+	nodes, rows := numberNodes(graph)
+
+	var edgeCost func(core.Edge) float64
+	field := "pattern"
+	if _, ok := graph.(core.Coster); ok {
+		edgeCost = graphutil.DefaultEdgeCost(graph, nil)
+		field = "real"
+	}
+	symmetry := "general"
+	if !graph.IsDirected() {
+		symmetry = "symmetric"
+	}
+
+	keys := make(graphutil.EdgeKeySorter, 0)
+	edges := make(map[core.EdgeKey]core.Edge)
+	for _, edge := range graph.EdgeList() {
+		key := core.EdgeKey{Head: rows[edge.Head().ID()], Tail: rows[edge.Tail().ID()]}
+		if !graph.IsDirected() && key.Head < key.Tail {
+			key.Head, key.Tail = key.Tail, key.Head
+		}
+		if _, ok := edges[key]; !ok {
+			keys = append(keys, key)
+			edges[key] = edge
+		}
+	}
+	sort.Sort(keys)
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "%%%%MatrixMarket matrix coordinate %s %s\n", field, symmetry)
+	fmt.Fprintf(buf, "%d %d %d\n", len(nodes), len(nodes), len(keys))
+	for _, key := range keys {
+		if edgeCost != nil {
+			fmt.Fprintf(buf, "%d %d %s\n", key.Head, key.Tail, strconv.FormatFloat(edgeCost(edges[key]), 'g', -1, 64))
+		} else {
+			fmt.Fprintf(buf, "%d %d\n", key.Head, key.Tail)
+		}
+	}
+
+	return buf.Flush()
+}
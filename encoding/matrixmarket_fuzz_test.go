@@ -0,0 +1,17 @@
+//go:build go1.18
+// +build go1.18
+
+package encoding_test
+
+import (
+	"github.com/nathankerr/graph/encodingtest"
+	"testing"
+)
+
+func FuzzReadMatrixMarket(f *testing.F) {
+	encodingtest.FuzzDecoder(f, matrixMarketCodec{},
+		[]byte(""),
+		[]byte("%%MatrixMarket matrix coordinate pattern symmetric\n% comment\n3 2 2\n2 1\n2 1\n"),
+		[]byte("%%MatrixMarket matrix coordinate real general\n2000000000 1 0\n"),
+	)
+}
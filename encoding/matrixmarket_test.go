@@ -0,0 +1,136 @@
+package encoding_test
+
+import (
+	"bytes"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encoding"
+	"github.com/nathankerr/graph/encodingtest"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Matrix Market renumbers the nodes 1 to n, so the codec maps them back to the IDs of the graph it was made for. The zero value keeps the numbers, for decoding arbitrary input.
+type matrixMarketCodec struct {
+	ids []int
+}
+
+func newMatrixMarketCodec(g core.Graph) matrixMarketCodec {
+	return matrixMarketCodec{sortedIDs(g)}
+}
+
+func (matrixMarketCodec) Encode(w io.Writer, g core.Graph) error {
+	return encoding.WriteMatrixMarket(w, g)
+}
+
+func (codec matrixMarketCodec) Decode(r io.Reader) (core.Graph, error) {
+	read, err := encoding.ReadMatrixMarket(r)
+	if err != nil {
+		return nil, err
+	}
+	return renumber(read, codec.ids), nil
+}
+
+// Returns the IDs of the graph's nodes in order, which formats that number the nodes from 1 number them in
+func sortedIDs(g core.Graph) []int {
+	var ids []int
+	for _, node := range core.SortedNodeList(g) {
+		ids = append(ids, node.ID())
+	}
+	return ids
+}
+
+// Returns a copy of a graph read from a format that numbers the nodes from 1, with each node n given the ID ids[n-1]. If ids is nil the numbers are kept.
+func renumber(read *simple.GonumGraph, ids []int) *simple.GonumGraph {
+	id := func(node core.Node) core.Node {
+		if ids == nil {
+			return node
+		}
+		return core.GonumNode(ids[node.ID()-1])
+	}
+
+	g := simple.NewGonumGraph(read.IsDirected())
+	for _, node := range read.NodeList() {
+		g.AddNode(id(node), nil)
+	}
+	for _, edge := range read.EdgeList() {
+		e := core.GonumEdge{H: id(edge.Head()), T: id(edge.Tail())}
+		g.AddEdge(e)
+		g.SetEdgeCost(e, read.Cost(edge.Head(), edge.Tail()))
+	}
+	return g
+}
+
+func TestMatrixMarketFixtures(t *testing.T) {
+	// Matrix Market can't hold metadata, so only these survive
+	fixtures := encodingtest.Fixtures()
+	for _, name := range []string{"empty", "isolated", "directed", "undirected"} {
+		g := fixtures[name]
+		encodingtest.RoundTrip(t, g, newMatrixMarketCodec(g))
+		encodingtest.Golden(t, "matrixmarket_"+name, g, newMatrixMarketCodec(g))
+	}
+}
+
+func TestWriteMatrixMarket(t *testing.T) {
+	// A file read in is written back out as it was, apart from the order of the entries
+	in := "%%MatrixMarket matrix coordinate real symmetric\n4 4 3\n2 1 0.5\n4 3 -2\n3 3 1\n"
+	g, err := encoding.ReadMatrixMarket(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := encoding.WriteMatrixMarket(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	if want := "%%MatrixMarket matrix coordinate real symmetric\n4 4 3\n2 1 0.5\n3 3 1\n4 3 -2\n"; buf.String() != want {
+		t.Errorf("Wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// A graph without costs is a pattern
+	buf.Reset()
+	tg := simple.NewTileGraph(1, 2, true)
+	if err := encoding.WriteMatrixMarket(&buf, plainGraph{tg}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "%%MatrixMarket matrix coordinate pattern symmetric\n2 2 1\n2 1\n"; buf.String() != want {
+		t.Errorf("Wrote:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestReadMatrixMarket(t *testing.T) {
+	g, err := encoding.ReadMatrixMarket(strings.NewReader("%%MatrixMarket matrix coordinate real general\n% a comment\n3 4 3\n1 2 0.5\n3 1 2e1\n\n2 2 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The column count is larger, so there's a node 4
+	if len(g.NodeList()) != 4 || len(g.EdgeList()) != 3 || !g.IsDirected() || g.Cost(core.GonumNode(3), core.GonumNode(1)) != 20 {
+		t.Errorf("Got nodes %v and edges %v", g.NodeList(), g.EdgeList())
+	}
+
+	g, err = encoding.ReadMatrixMarket(strings.NewReader("%%MatrixMarket matrix coordinate pattern symmetric\n3 3 2\n2 1\n3 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.IsDirected() || !g.IsSuccessor(core.GonumNode(1), core.GonumNode(3)) || g.Cost(core.GonumNode(1), core.GonumNode(2)) != 1 {
+		t.Errorf("Got edges %v", g.EdgeList())
+	}
+
+	for _, bad := range []string{
+		"",
+		"3 3 1\n1 2 1\n",
+		"%%MatrixMarket matrix array real general\n2 2\n1\n2\n3\n4\n",
+		"%%MatrixMarket matrix coordinate complex general\n2 2 1\n1 2 1 0\n",
+		"%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 1\n2 1 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 3 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 2 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 lots\n",
+		"%%MatrixMarket matrix coordinate real general\n% no size line\n",
+		"%%MatrixMarket matrix coordinate real general\n1 2000000000 0\n",
+	} {
+		if _, err := encoding.ReadMatrixMarket(strings.NewReader(bad)); err == nil {
+			t.Errorf("No error for %q", bad)
+		}
+	}
+}
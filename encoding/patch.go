@@ -0,0 +1,286 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"math"
+	"sort"
+)
+
+// A Patch is the difference between two versions of a graph: the nodes and edges to remove, the nodes and edges to add, and the edges whose costs change. ComputePatch finds it and
+// ApplyPatch carries it out, so a process that keeps a replica of an evolving graph can be sent patches, which are usually far smaller than the graph, instead of the whole graph
+// every time it changes. Patches are encoded as JSON, see MarshalJSON.
+//
+// Nodes and edges are identified by ID, as in an EdgeList file, and nodes are added as GonumNodes. The edges of an undirected graph are listed once, with the lower ID as the Head, as
+// KeyOf gives them. Edges into or out of a removed node are removed with it, so they aren't listed in RemovedEdges as well.
+type Patch struct {
+	Directed     bool
+	RemovedNodes []int
+	RemovedEdges []core.EdgeKey
+	AddedNodes   []int
+	AddedEdges   []PatchEdge
+	CostChanges  []PatchEdge // Edges that are in both versions, with their new costs
+}
+
+// An edge with its cost, as listed in a Patch
+type PatchEdge struct {
+	core.EdgeKey
+	Cost float64
+}
+
+// Returns whether the patch changes nothing
+func (patch *Patch) IsEmpty() bool {
+	return len(patch.RemovedNodes) == 0 && len(patch.RemovedEdges) == 0 && len(patch.AddedNodes) == 0 && len(patch.AddedEdges) == 0 && len(patch.CostChanges) == 0
+}
+
+// Returns the patch that turns old into new, comparing their nodes and edges by ID and their costs exactly (a graph that isn't a Coster has uniform costs). Everything in the patch is
+// sorted by ID, so the same two graphs always give the same patch. Returns an error if one graph is directed and the other isn't.
+func ComputePatch(old, new core.Graph) (*Patch, error) {
+	if old.IsDirected() != new.IsDirected() {
+		return nil, errors.New("Can't patch between a directed and an undirected graph")
+	}
+	directed := new.IsDirected()
+	patch := &Patch{Directed: directed}
+
+	oldNodes, newNodes := make(map[int]bool), make(map[int]bool)
+	for _, node := range old.NodeList() {
+		oldNodes[node.ID()] = true
+	}
+	for _, node := range new.NodeList() {
+		newNodes[node.ID()] = true
+		if !oldNodes[node.ID()] {
+			patch.AddedNodes = append(patch.AddedNodes, node.ID())
+		}
+	}
+	for id := range oldNodes {
+		if !newNodes[id] {
+			patch.RemovedNodes = append(patch.RemovedNodes, id)
+		}
+	}
+
+	oldEdges, newEdges := patchEdgeCosts(old, directed), patchEdgeCosts(new, directed)
+	for key, cost := range newEdges {
+		oldCost, ok := oldEdges[key]
+		if !ok {
+			patch.AddedEdges = append(patch.AddedEdges, PatchEdge{key, cost})
+		} else if cost != oldCost && !(math.IsNaN(cost) && math.IsNaN(oldCost)) {
+			patch.CostChanges = append(patch.CostChanges, PatchEdge{key, cost})
+		}
+	}
+	for key := range oldEdges {
+		if _, ok := newEdges[key]; !ok && newNodes[key.Head] && newNodes[key.Tail] {
+			patch.RemovedEdges = append(patch.RemovedEdges, key)
+		}
+	}
+
+	sort.Ints(patch.RemovedNodes)
+	sort.Ints(patch.AddedNodes)
+	sort.Sort(graphutil.EdgeKeySorter(patch.RemovedEdges))
+	sort.Sort(patchEdgeSorter(patch.AddedEdges))
+	sort.Sort(patchEdgeSorter(patch.CostChanges))
+
+	return patch, nil
+}
+
+// Returns the cost of each of the graph's edges, keyed as KeyOf does
+func patchEdgeCosts(graph core.Graph, directed bool) map[core.EdgeKey]float64 {
+	cost := graphutil.DefaultCost(graph, nil)
+	costs := make(map[core.EdgeKey]float64)
+	for _, edge := range graph.EdgeList() {
+		if key := core.KeyOf(edge, directed); key.Head == edge.Head().ID() {
+			costs[key] = cost(edge.Head(), edge.Tail())
+		}
+	}
+
+	return costs
+}
+
+// Changes the graph as the patch says: removes its edges and nodes, then adds its nodes and edges, then sets its new costs. The whole patch is checked against the graph first, and if
+// any of it can't be carried out (the graph's directedness differs, or the patch removes or changes something that isn't there or adds something that already is) an error says
+// what, and the graph is left as it was. A replica that gets such an error has drifted from the graph it copies, and should be sent the whole graph again.
+func ApplyPatch(graph core.MutableGraph, patch *Patch) error {
+	if graph.IsDirected() != patch.Directed {
+		return errors.New("Can't apply a patch between a directed and an undirected graph")
+	}
+	if err := checkPatch(graph, patch); err != nil {
+		return err
+	}
+
+	for _, key := range patch.RemovedEdges {
+		graph.RemoveEdge(core.GonumEdge{H: core.GonumNode(key.Head), T: core.GonumNode(key.Tail)})
+	}
+	for _, id := range patch.RemovedNodes {
+		graph.RemoveNode(core.GonumNode(id))
+	}
+	for _, id := range patch.AddedNodes {
+		graph.AddNode(core.GonumNode(id), nil)
+	}
+	for _, edge := range patch.AddedEdges {
+		e := core.GonumEdge{H: core.GonumNode(edge.Head), T: core.GonumNode(edge.Tail)}
+		graph.AddEdge(e)
+		graph.SetEdgeCost(e, edge.Cost)
+	}
+	for _, edge := range patch.CostChanges {
+		graph.SetEdgeCost(core.GonumEdge{H: core.GonumNode(edge.Head), T: core.GonumNode(edge.Tail)}, edge.Cost)
+	}
+
+	return nil
+}
+
+// Returns an error if the patch can't be applied to the graph as it is, without changing it
+func checkPatch(graph core.MutableGraph, patch *Patch) error {
+	removedNodes := make(map[int]bool)
+	for _, id := range patch.RemovedNodes {
+		if !graph.NodeExists(core.GonumNode(id)) || removedNodes[id] {
+			return fmt.Errorf("Removing node %d: %v", id, core.ErrNodeNotFound)
+		}
+		removedNodes[id] = true
+	}
+	// An edge, keyed as KeyOf does, is gone after the removals if it never existed, it was removed, or one of its ends was
+	removedEdges := make(map[core.EdgeKey]bool)
+	gone := func(key core.EdgeKey) bool {
+		return removedEdges[key] || removedNodes[key.Head] || removedNodes[key.Tail] || !graph.IsSuccessor(core.GonumNode(key.Head), core.GonumNode(key.Tail))
+	}
+	for _, edge := range patch.RemovedEdges {
+		key := core.KeyOf(core.GonumEdge{H: core.GonumNode(edge.Head), T: core.GonumNode(edge.Tail)}, patch.Directed)
+		if gone(key) {
+			return fmt.Errorf("Removing edge %d->%d: %v", edge.Head, edge.Tail, core.ErrEdgeNotFound)
+		}
+		removedEdges[key] = true
+	}
+
+	addedNodes := make(map[int]bool)
+	for _, id := range patch.AddedNodes {
+		if (graph.NodeExists(core.GonumNode(id)) && !removedNodes[id]) || addedNodes[id] {
+			return fmt.Errorf("Adding node %d: %v", id, core.ErrNodeExists)
+		}
+		addedNodes[id] = true
+	}
+	exists := func(id int) bool {
+		return addedNodes[id] || (graph.NodeExists(core.GonumNode(id)) && !removedNodes[id])
+	}
+
+	addedEdges := make(map[core.EdgeKey]bool)
+	for _, edge := range patch.AddedEdges {
+		key := core.KeyOf(core.GonumEdge{H: core.GonumNode(edge.Head), T: core.GonumNode(edge.Tail)}, patch.Directed)
+		if !exists(edge.Head) || !exists(edge.Tail) {
+			return fmt.Errorf("Adding edge %d->%d: %v", edge.Head, edge.Tail, core.ErrNodeNotFound)
+		}
+		if !gone(key) || addedEdges[key] {
+			return fmt.Errorf("Adding edge %d->%d: %v", edge.Head, edge.Tail, core.ErrEdgeExists)
+		}
+		addedEdges[key] = true
+	}
+	for _, edge := range patch.CostChanges {
+		key := core.KeyOf(core.GonumEdge{H: core.GonumNode(edge.Head), T: core.GonumNode(edge.Tail)}, patch.Directed)
+		if gone(key) && !addedEdges[key] {
+			return fmt.Errorf("Changing the cost of edge %d->%d: %v", edge.Head, edge.Tail, core.ErrEdgeNotFound)
+		}
+	}
+
+	return nil
+}
+
+// The JSON form of a Patch, see MarshalJSON
+type jsonPatch struct {
+	Directed     *bool                `json:"directed"`
+	RemovedNodes []int                `json:"removedNodes,omitempty"`
+	RemovedEdges []graphutil.JSONEdge `json:"removedEdges,omitempty"`
+	AddedNodes   []int                `json:"addedNodes,omitempty"`
+	AddedEdges   []graphutil.JSONEdge `json:"addedEdges,omitempty"`
+	CostChanges  []graphutil.JSONEdge `json:"costChanges,omitempty"`
+}
+
+// Encodes the patch as JSON, in a schema that will stay stable and that follows GonumGraph's:
+//
+//	{
+//		"directed": true,
+//		"removedNodes": [4],
+//		"removedEdges": [{"from": 0, "to": 1}],
+//		"addedNodes": [5],
+//		"addedEdges": [{"from": 0, "to": 5, "cost": 2.5}],
+//		"costChanges": [{"from": 1, "to": 2, "cost": "+Inf"}]
+//	}
+//
+// directed is always present, and the lists are left out when they're empty. Costs are written as GonumGraph writes them.
+func (patch *Patch) MarshalJSON() ([]byte, error) {
+	jp := jsonPatch{Directed: &patch.Directed, RemovedNodes: patch.RemovedNodes, AddedNodes: patch.AddedNodes}
+	for _, key := range patch.RemovedEdges {
+		jp.RemovedEdges = append(jp.RemovedEdges, graphutil.JSONEdge{From: key.Head, To: key.Tail})
+	}
+	jp.AddedEdges = jsonPatchEdges(patch.AddedEdges)
+	jp.CostChanges = jsonPatchEdges(patch.CostChanges)
+
+	return json.Marshal(jp)
+}
+
+func jsonPatchEdges(edges []PatchEdge) []graphutil.JSONEdge {
+	var jedges []graphutil.JSONEdge
+	for _, edge := range edges {
+		cost := graphutil.JSONCost(edge.Cost)
+		jedges = append(jedges, graphutil.JSONEdge{From: edge.Head, To: edge.Tail, Cost: &cost})
+	}
+
+	return jedges
+}
+
+// Decodes a patch from JSON in the schema MarshalJSON writes. Returns an error if directed is missing or an added edge or cost change has no cost. Whether the patch fits a graph is
+// only checked when it's applied.
+func (patch *Patch) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+
+	var jp jsonPatch
+	if err := json.Unmarshal(data, &jp); err != nil {
+		return err
+	}
+	if jp.Directed == nil {
+		return errors.New("Missing \"directed\"")
+	}
+
+	decoded := Patch{Directed: *jp.Directed, RemovedNodes: jp.RemovedNodes, AddedNodes: jp.AddedNodes}
+	for _, edge := range jp.RemovedEdges {
+		decoded.RemovedEdges = append(decoded.RemovedEdges, core.EdgeKey{Head: edge.From, Tail: edge.To})
+	}
+	var err error
+	if decoded.AddedEdges, err = patchEdgesFromJSON(jp.AddedEdges); err != nil {
+		return err
+	}
+	if decoded.CostChanges, err = patchEdgesFromJSON(jp.CostChanges); err != nil {
+		return err
+	}
+
+	*patch = decoded
+	return nil
+}
+
+func patchEdgesFromJSON(jedges []graphutil.JSONEdge) ([]PatchEdge, error) {
+	var edges []PatchEdge
+	for _, edge := range jedges {
+		if edge.Cost == nil {
+			return nil, fmt.Errorf("Edge %d->%d has no cost", edge.From, edge.To)
+		}
+		edges = append(edges, PatchEdge{core.EdgeKey{Head: edge.From, Tail: edge.To}, float64(*edge.Cost)})
+	}
+
+	return edges, nil
+}
+
+type patchEdgeSorter []PatchEdge
+
+func (pe patchEdgeSorter) Len() int {
+	return len(pe)
+}
+
+func (pe patchEdgeSorter) Less(i, j int) bool {
+	return pe[i].Head < pe[j].Head || (pe[i].Head == pe[j].Head && pe[i].Tail < pe[j].Tail)
+}
+
+func (pe patchEdgeSorter) Swap(i, j int) {
+	pe[i], pe[j] = pe[j], pe[i]
+}
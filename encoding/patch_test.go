@@ -0,0 +1,89 @@
+package encoding_test
+
+import (
+	"encoding/json"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encoding"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		old := simple.NewGonumGraph(directed)
+		old.AddNode(core.GonumNode(0), nodes(1, 2))
+		old.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+		old.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+		old.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(0)})
+
+		// Node 3 goes with its edges, 0-1 is removed, 1-2 gets more expensive, and node 4 joins
+		updated := simple.NewGonumGraph(directed)
+		core.CopyGraph(updated, old)
+		updated.RemoveNode(core.GonumNode(3))
+		updated.RemoveEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)})
+		updated.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, math.Inf(1))
+		updated.AddNode(core.GonumNode(4), nodes(0))
+		updated.SetEdgeCost(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(0)}, 2.5)
+
+		patch, err := encoding.ComputePatch(old, updated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(patch.RemovedNodes) != 1 || len(patch.RemovedEdges) != 1 || len(patch.AddedNodes) != 1 || len(patch.AddedEdges) != 1 || len(patch.CostChanges) != 1 {
+			t.Errorf("Directed %v: Got patch %+v", directed, patch)
+		}
+
+		// The patch goes through JSON on its way to the replica
+		data, err := json.Marshal(patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var received encoding.Patch
+		if err := json.Unmarshal(data, &received); err != nil {
+			t.Fatalf("Directed %v: Couldn't decode %s: %v", directed, data, err)
+		}
+
+		replica := simple.NewGonumGraph(directed)
+		core.CopyGraph(replica, old)
+		if err := encoding.ApplyPatch(replica, &received); err != nil || !core.Equal(replica, updated, 0) {
+			t.Errorf("Directed %v: Patching with %s gave %v and error %v", directed, data, replica.EdgeList(), err)
+		}
+		if again, _ := encoding.ComputePatch(replica, updated); !again.IsEmpty() {
+			t.Errorf("Directed %v: The patched replica still differs by %+v", directed, again)
+		}
+
+		// Applying it twice fails without changing anything
+		if err := encoding.ApplyPatch(replica, &received); err == nil || !core.Equal(replica, updated, 0) {
+			t.Errorf("Directed %v: Patching twice gave error %v", directed, err)
+		}
+	}
+
+	undirected := simple.NewGonumGraph(false)
+	if _, err := encoding.ComputePatch(simple.NewGonumGraph(true), undirected); err == nil {
+		t.Error("No error computing a patch between a directed and an undirected graph")
+	}
+	if err := encoding.ApplyPatch(undirected, &encoding.Patch{Directed: true}); err == nil {
+		t.Error("No error applying a directed patch to an undirected graph")
+	}
+
+	bad := []encoding.Patch{
+		{AddedEdges: []encoding.PatchEdge{{EdgeKey: core.EdgeKey{Head: 0, Tail: 1}, Cost: 1}}},
+		{AddedNodes: []int{0, 0}},
+		{RemovedNodes: []int{7}},
+		{CostChanges: []encoding.PatchEdge{{EdgeKey: core.EdgeKey{Head: 0, Tail: 9}, Cost: 1}}},
+	}
+	undirected.AddNode(core.GonumNode(0), nodes(1))
+	for _, patch := range bad {
+		if err := encoding.ApplyPatch(undirected, &patch); err == nil || len(undirected.NodeList()) != 2 {
+			t.Errorf("Patch %+v gave error %v and nodes %v", patch, err, undirected.NodeList())
+		}
+	}
+
+	for _, data := range []string{`{}`, `{"directed": true, "addedEdges": [{"from": 0, "to": 1}]}`} {
+		var patch encoding.Patch
+		if err := json.Unmarshal([]byte(data), &patch); err == nil {
+			t.Errorf("No error decoding %s", data)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Package encodingtest is a test harness for graph serializers. Every codec should pass RoundTrip for each of the Fixtures, which checks that nothing is lost on the way out and back in,
+// and Golden, which pins its exact output to a file under testdata so that format changes show up in review.
+package encodingtest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/nathankerr/graph"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files in testdata with the current output")
+
+// A Codec reads and writes graphs in some serialized format. Encode must be deterministic: encoding the same graph twice must give the same bytes, no matter what order the graph lists
+// its nodes and edges in.
+type Codec interface {
+	Encode(w io.Writer, g graph.Graph) error
+	Decode(r io.Reader) (graph.Graph, error)
+}
+
+// A Codec whose format keeps more than Diff compares, such as edge IDs, can implement Differ to have RoundTrip check that too
+type Differ interface {
+	Diff(want, got graph.Graph) string // Describes the differences, as Diff does
+}
+
+// Returns the standard graphs every codec is expected to round trip, by name. The names are suitable for Golden. They cover the empty graph, isolated nodes, directed and undirected
+// edges, self loops, non-uniform, negative and fractional costs, sparse node IDs, and metadata with attributes. A fresh set is built on every call, so tests may modify them.
+func Fixtures() map[string]*graph.GonumGraph {
+	fixtures := make(map[string]*graph.GonumGraph)
+
+	fixtures["empty"] = graph.NewGonumGraph(true)
+
+	isolated := graph.NewGonumGraph(false)
+	for _, id := range []int{0, 3, 7} {
+		isolated.AddNode(graph.GonumNode(id), nil)
+	}
+	fixtures["isolated"] = isolated
+
+	directed := graph.NewGonumGraph(true)
+	for _, e := range []struct {
+		h, t int
+		cost float64
+	}{{0, 1, 1}, {1, 2, 2.5}, {2, 0, -1}, {1, 0, 0.125}, {2, 2, 3}, {10, 2, 1e6}} {
+		directed.AddNode(graph.GonumNode(e.h), nil)
+		edge := graph.GonumEdge{H: graph.GonumNode(e.h), T: graph.GonumNode(e.t)}
+		directed.AddEdge(edge)
+		directed.SetEdgeCost(edge, e.cost)
+	}
+	fixtures["directed"] = directed
+
+	undirected := graph.NewGonumGraph(false)
+	undirected.AddNode(graph.GonumNode(0), []graph.Node{graph.GonumNode(1), graph.GonumNode(2)})
+	undirected.AddEdge(graph.GonumEdge{H: graph.GonumNode(1), T: graph.GonumNode(2)})
+	undirected.AddEdge(graph.GonumEdge{H: graph.GonumNode(2), T: graph.GonumNode(100)})
+	undirected.SetEdgeCost(graph.GonumEdge{H: graph.GonumNode(0), T: graph.GonumNode(2)}, 4)
+	fixtures["undirected"] = undirected
+
+	described := graph.NewGonumGraph(true)
+	described.AddNode(graph.GonumNode(1), []graph.Node{graph.GonumNode(2)})
+	md := described.Metadata()
+	md.Name = "described"
+	md.Creator = "encodingtest"
+	md.Created = time.Date(2014, time.March, 1, 12, 0, 0, 0, time.UTC)
+	md.Set("source", "fixture")
+	md.Set("quoted", `a "quoted" value, with spaces`)
+	fixtures["metadata"] = described
+
+	return fixtures
+}
+
+// Encodes the graph, decodes the result and checks that the decoded graph is the same as the original (see Diff, and Differ), then checks that encoding the decoded graph gives exactly the same
+// bytes. Failures are reported through t.
+func RoundTrip(t *testing.T, g graph.Graph, codec Codec) {
+	var first bytes.Buffer
+	if err := codec.Encode(&first, g); err != nil {
+		t.Errorf("Encode failed: %v", err)
+		return
+	}
+
+	decoded, err := codec.Decode(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Errorf("Decode failed: %v\nInput:\n%s", err, first.String())
+		return
+	}
+	if diff := Diff(g, decoded); diff != "" {
+		t.Errorf("Round trip changed the graph:\n%s\nEncoded:\n%s", diff, first.String())
+		return
+	}
+	if differ, ok := codec.(Differ); ok {
+		if diff := differ.Diff(g, decoded); diff != "" {
+			t.Errorf("Round trip changed the graph:\n%s\nEncoded:\n%s", diff, first.String())
+			return
+		}
+	}
+
+	var second bytes.Buffer
+	if err := codec.Encode(&second, decoded); err != nil {
+		t.Errorf("Encoding the decoded graph failed: %v", err)
+		return
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Errorf("Encoding isn't deterministic, first:\n%s\nsecond:\n%s", first.String(), second.String())
+	}
+}
+
+// Encodes the graph and compares the output with testdata/<name>.golden (relative to the package being tested). Run the tests with -update to write the golden files instead.
+func Golden(t *testing.T, name string, g graph.Graph, codec Codec) {
+	var buf bytes.Buffer
+	if err := codec.Encode(&buf, g); err != nil {
+		t.Errorf("Encode failed: %v", err)
+		return
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("Reading golden file (run with -update to create it): %v", err)
+		return
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Output doesn't match %s (run with -update if the change is intended), got:\n%s\nwant:\n%s", path, buf.String(), want)
+	}
+}
+
+// Describes the differences between two graphs, or returns "" if they're the same. Graphs are the same if they have the same directedness, node IDs and edges. Costs are compared if both
+// graphs implement graph.Coster, and metadata (other than Directed, which comes from the graph) if both implement graph.MetadataHolder, so codecs for formats that can't store those
+// aren't held to them.
+func Diff(want, got graph.Graph) string {
+	diffs := make([]string, 0)
+	if want.IsDirected() != got.IsDirected() {
+		diffs = append(diffs, fmt.Sprintf("directed is %v, want %v", got.IsDirected(), want.IsDirected()))
+	}
+
+	wantNodes, gotNodes := nodeIDs(want), nodeIDs(got)
+	if fmt.Sprint(wantNodes) != fmt.Sprint(gotNodes) {
+		diffs = append(diffs, fmt.Sprintf("nodes are %v, want %v", gotNodes, wantNodes))
+	}
+
+	wantEdges, gotEdges := edgeKeys(want), edgeKeys(got)
+	if fmt.Sprint(wantEdges) != fmt.Sprint(gotEdges) {
+		diffs = append(diffs, fmt.Sprintf("edges are %v, want %v", gotEdges, wantEdges))
+	} else if wc, ok := want.(graph.Coster); ok {
+		if gc, ok := got.(graph.Coster); ok {
+			for _, key := range wantEdges {
+				h, t := graph.GonumNode(key.Head), graph.GonumNode(key.Tail)
+				if w, g := wc.Cost(h, t), gc.Cost(h, t); w != g && !(math.IsNaN(w) && math.IsNaN(g)) {
+					diffs = append(diffs, fmt.Sprintf("edge %d->%d costs %v, want %v", key.Head, key.Tail, g, w))
+				}
+			}
+		}
+	}
+
+	if wm, ok := want.(graph.MetadataHolder); ok {
+		if gm, ok := got.(graph.MetadataHolder); ok {
+			w, g := wm.Metadata().Clone(), gm.Metadata().Clone()
+			w.Directed, g.Directed = false, false
+			if !w.Created.Equal(g.Created) {
+				diffs = append(diffs, fmt.Sprintf("metadata created at %v, want %v", g.Created, w.Created))
+			}
+			w.Created, g.Created = time.Time{}, time.Time{}
+			if len(w.Attributes) == 0 && len(g.Attributes) == 0 {
+				w.Attributes, g.Attributes = nil, nil
+			}
+			if fmt.Sprintf("%#v", w) != fmt.Sprintf("%#v", g) {
+				diffs = append(diffs, fmt.Sprintf("metadata is %+v, want %+v", g, w))
+			}
+		}
+	}
+
+	return strings.Join(diffs, "\n")
+}
+
+func nodeIDs(g graph.Graph) []int {
+	ids := make([]int, 0)
+	for _, node := range g.NodeList() {
+		ids = append(ids, node.ID())
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// Returns the graph's edges with their keys sorted, with undirected edges listed once
+func edgeKeys(g graph.Graph) []graph.EdgeKey {
+	seen := make(map[graph.EdgeKey]bool)
+	keys := make([]graph.EdgeKey, 0)
+	for _, edge := range g.EdgeList() {
+		key := graph.KeyOf(edge, g.IsDirected())
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Sort(keySorter(keys))
+
+	return keys
+}
+
+type keySorter []graph.EdgeKey
+
+func (ks keySorter) Len() int {
+	return len(ks)
+}
+
+func (ks keySorter) Less(i, j int) bool {
+	return ks[i].Head < ks[j].Head || (ks[i].Head == ks[j].Head && ks[i].Tail < ks[j].Tail)
+}
+
+func (ks keySorter) Swap(i, j int) {
+	ks[i], ks[j] = ks[j], ks[i]
+}
@@ -0,0 +1,144 @@
+package encodingtest_test
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/nathankerr/graph"
+	"github.com/nathankerr/graph/encodingtest"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A minimal line-based codec, to exercise the harness
+type lineCodec struct{}
+
+func (lineCodec) Encode(w io.Writer, g graph.Graph) error {
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "directed %v\n", g.IsDirected())
+
+	md := graph.GraphMetadata(g)
+	if md.Name != "" {
+		fmt.Fprintf(buf, "name %q\n", md.Name)
+	}
+	if md.Creator != "" {
+		fmt.Fprintf(buf, "creator %q\n", md.Creator)
+	}
+	if !md.Created.IsZero() {
+		fmt.Fprintf(buf, "created %s\n", md.Created.Format(time.RFC3339Nano))
+	}
+	for _, key := range md.Keys() {
+		fmt.Fprintf(buf, "attr %q %q\n", key, md.Attributes[key])
+	}
+
+	ids := make([]int, 0)
+	for _, node := range g.NodeList() {
+		ids = append(ids, node.ID())
+	}
+	sort.Ints(ids)
+	lines := make([]string, 0)
+	for _, id := range ids {
+		fmt.Fprintf(buf, "node %d\n", id)
+		for _, succ := range g.Successors(graph.GonumNode(id)) {
+			if g.IsDirected() || id <= succ.ID() {
+				lines = append(lines, fmt.Sprintf("%d %d", id, succ.ID()))
+			}
+		}
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		var h, t int
+		fmt.Sscan(line, &h, &t)
+		fmt.Fprintf(buf, "edge %d %d %s\n", h, t, strconv.FormatFloat(g.(graph.Coster).Cost(graph.GonumNode(h), graph.GonumNode(t)), 'g', -1, 64))
+	}
+
+	return buf.Flush()
+}
+
+func (lineCodec) Decode(r io.Reader) (graph.Graph, error) {
+	var g *graph.GonumGraph
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("Expected a keyword and a value, got %q", scanner.Text())
+		}
+		if g == nil && fields[0] != "directed" {
+			return nil, fmt.Errorf("Expected directed line, got %q", scanner.Text())
+		}
+		switch fields[0] {
+		case "directed":
+			g = graph.NewGonumGraph(fields[1] == "true")
+		case "name":
+			g.Metadata().Name, _ = strconv.Unquote(fields[1])
+		case "creator":
+			g.Metadata().Creator, _ = strconv.Unquote(fields[1])
+		case "created":
+			created, err := time.Parse(time.RFC3339Nano, fields[1])
+			if err != nil {
+				return nil, err
+			}
+			g.Metadata().Created = created
+		case "attr":
+			var key, value string
+			if _, err := fmt.Sscanf(fields[1], "%q %q", &key, &value); err != nil {
+				return nil, err
+			}
+			g.Metadata().Set(key, value)
+		case "node":
+			var id int
+			if _, err := fmt.Sscan(fields[1], &id); err != nil {
+				return nil, err
+			}
+			g.AddNode(graph.GonumNode(id), nil)
+		case "edge":
+			var h, t int
+			var cost float64
+			if _, err := fmt.Sscan(fields[1], &h, &t, &cost); err != nil {
+				return nil, err
+			}
+			edge := graph.GonumEdge{H: graph.GonumNode(h), T: graph.GonumNode(t)}
+			g.AddEdge(edge)
+			g.SetEdgeCost(edge, cost)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, fmt.Errorf("No directed line")
+	}
+
+	return g, nil
+}
+
+func TestFixtures(t *testing.T) {
+	for name, g := range encodingtest.Fixtures() {
+		encodingtest.RoundTrip(t, g, lineCodec{})
+		encodingtest.Golden(t, name, g, lineCodec{})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	fixtures := encodingtest.Fixtures()
+	if diff := encodingtest.Diff(fixtures["directed"], fixtures["directed"]); diff != "" {
+		t.Errorf("Graph differs from itself: %s", diff)
+	}
+
+	changed := encodingtest.Fixtures()
+	changed["directed"].SetEdgeCost(graph.GonumEdge{H: graph.GonumNode(0), T: graph.GonumNode(1)}, 2)
+	changed["undirected"].RemoveNode(graph.GonumNode(100))
+	changed["metadata"].Metadata().Set("source", "elsewhere")
+	for _, name := range []string{"directed", "undirected", "metadata"} {
+		if diff := encodingtest.Diff(fixtures[name], changed[name]); diff == "" {
+			t.Errorf("Changed %s fixture isn't reported as different", name)
+		}
+	}
+	if diff := encodingtest.Diff(fixtures["empty"], fixtures["isolated"]); !strings.Contains(diff, "directed") || !strings.Contains(diff, "nodes") {
+		t.Errorf("Got diff %q", diff)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.18
+// +build go1.18
+
+package encodingtest
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+)
+
+// Fuzzes the codec's Decode, for use from a fuzz target (func FuzzX(f *testing.F) { encodingtest.FuzzDecoder(f, codec) }). The corpus is seeded with the encoded Fixtures, along with any
+// extra seeds given, such as known-bad inputs. Decode must never panic, and must return either an error or a graph; a graph it does return must pass RoundTrip, so a decoder can't
+// accept input it then can't write back out. Decoders will be fed untrusted files, so this is expected of every codec.
+func FuzzDecoder(f *testing.F, codec Codec, seeds ...[]byte) {
+	fixtures := Fixtures()
+	names := make([]string, 0, len(fixtures))
+	for name := range fixtures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := codec.Encode(&buf, fixtures[name]); err != nil {
+			f.Fatalf("Encoding fixture %s: %v", name, err)
+		}
+		f.Add(buf.Bytes())
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, err := codec.Decode(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		if g == nil {
+			t.Fatalf("Decode returned neither a graph nor an error for %q", data)
+		}
+
+		RoundTrip(t, g, codec)
+	})
+}
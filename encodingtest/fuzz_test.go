@@ -0,0 +1,13 @@
+//go:build go1.18
+// +build go1.18
+
+package encodingtest_test
+
+import (
+	"github.com/nathankerr/graph/encodingtest"
+	"testing"
+)
+
+func FuzzLineCodec(f *testing.F) {
+	encodingtest.FuzzDecoder(f, lineCodec{}, []byte(""), []byte("node 1\n"), []byte("directed true\nedge"), []byte("directed false\nattr \"a\"\n"))
+}
@@ -0,0 +1,111 @@
+package examples_test
+
+import (
+	"github.com/nathankerr/graph"
+	"github.com/nathankerr/graph/examples"
+	"math/rand"
+	"testing"
+)
+
+func TestSlidingPuzzle(t *testing.T) {
+	puzzle, err := examples.NewSlidingPuzzle(3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One of the two hardest 8-puzzle positions, which takes 31 moves
+	hardest, err := puzzle.State([]int{8, 6, 7, 2, 5, 4, 3, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := puzzle.Solve(hardest)
+	if err != nil || len(states) != 32 || states[0] != hardest || states[31] != puzzle.Goal() {
+		t.Fatalf("Solved in %d moves with error %v, want 31", len(states)-1, err)
+	}
+	g := puzzle.Graph()
+	for i := 1; i < len(states); i++ {
+		if !g.IsSuccessor(states[i-1], states[i]) {
+			t.Fatalf("Move %d from %v to %v isn't a legal move", i, puzzle.Tiles(states[i-1]), puzzle.Tiles(states[i]))
+		}
+	}
+	if _, cost, _ := graph.AStar(hardest, puzzle.Goal(), g, nil, nil); cost != 31 {
+		t.Errorf("AStar solved it in %v moves, want 31", cost)
+	}
+
+	// Swapping two tiles makes any position unsolvable
+	swapped, _ := puzzle.State([]int{2, 1, 3, 4, 5, 6, 7, 8, 0})
+	if _, err := puzzle.Solve(swapped); err == nil {
+		t.Error("No error for an unsolvable position")
+	}
+	for _, bad := range [][]int{{1, 2, 3}, {1, 1, 2, 3, 4, 5, 6, 7, 8}, {1, 2, 3, 4, 5, 6, 7, 8, 9}} {
+		if _, err := puzzle.State(bad); err == nil {
+			t.Errorf("No error for tiles %v", bad)
+		}
+	}
+	if _, err := examples.NewSlidingPuzzle(5, 5); err == nil {
+		t.Error("No error for a board too big to pack")
+	}
+}
+
+func TestFifteenPuzzle(t *testing.T) {
+	puzzle, err := examples.NewSlidingPuzzle(4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := puzzle.Graph()
+
+	// Scrambling by random moves from the goal only ever reaches solvable positions
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 5; i++ {
+		var state graph.Node = puzzle.Goal()
+		for move := 0; move < 40; move++ {
+			succs := g.Successors(state)
+			state = succs[rnd.Intn(len(succs))]
+		}
+		if !puzzle.Solvable(state.(examples.PuzzleState)) {
+			t.Fatalf("Scrambled position %v isn't solvable", puzzle.Tiles(state.(examples.PuzzleState)))
+		}
+
+		states, err := puzzle.Solve(state.(examples.PuzzleState))
+		_, want, _ := graph.AStar(state, puzzle.Goal(), g, nil, nil)
+		if err != nil || float64(len(states)-1) != want || len(states)-1 > 40 {
+			t.Errorf("IDA* solved %v in %d moves, A* in %v", puzzle.Tiles(state.(examples.PuzzleState)), len(states)-1, want)
+		}
+	}
+}
+
+func TestWordLadder(t *testing.T) {
+	ladder := examples.NewWordLadder([]string{"cold", "cord", "card", "ward", "warm", "word", "worm", "corm", "wore", "bold", "cold", "heat", "head"})
+
+	// Every letter changes, so no ladder is shorter than 4 steps
+	words := ladder.Solve("cold", "warm")
+	if len(words) != 5 || words[0] != "cold" || words[4] != "warm" {
+		t.Fatalf("Got ladder %v", words)
+	}
+	for i := 1; i < len(words); i++ {
+		if ladder.HeuristicCost(mustNode(t, ladder, words[i-1]), mustNode(t, ladder, words[i])) != 1 {
+			t.Errorf("%s and %s don't differ in one letter", words[i-1], words[i])
+		}
+	}
+
+	start, goal := mustNode(t, ladder, "bold"), mustNode(t, ladder, "worm")
+	path, cost, _ := graph.IDAStar(start, goal, ladder.Graph(), nil, nil)
+	if _, want, _ := graph.AStar(start, goal, ladder.Graph(), nil, nil); path == nil || cost != want {
+		t.Errorf("IDA* found a ladder of %v steps, A* of %v", cost, want)
+	}
+
+	if words := ladder.Solve("cold", "heat"); words != nil {
+		t.Errorf("Got ladder %v between unconnected words", words)
+	}
+	if words := ladder.Solve("cold", "frog"); words != nil {
+		t.Errorf("Got ladder %v to a word that isn't in the dictionary", words)
+	}
+}
+
+func mustNode(t *testing.T, ladder *examples.WordLadder, word string) graph.Node {
+	node, ok := ladder.Node(word)
+	if !ok {
+		t.Fatalf("%s isn't in the dictionary", word)
+	}
+	return node
+}
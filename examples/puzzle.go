@@ -0,0 +1,188 @@
+// Package examples holds state spaces built on graph.ImplicitGraph, to show how a search problem is written as a graph that's never stored, and to test the implicit graph API
+// against problems with known answers. A SlidingPuzzle is the 8- or 15-puzzle and its relatives, and a WordLadder is the game of changing one word into another a letter at a time.
+package examples
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph"
+)
+
+// A SlidingPuzzle is a board of numbered tiles with one blank, in which a move slides a tile next to the blank into it. Its states are PuzzleStates, and its goal is the tiles in
+// order with the blank last.
+type SlidingPuzzle struct {
+	rows, cols int
+}
+
+// Returns the puzzle on a board with the given dimensions, which has rows*cols-1 tiles: 3 by 3 for the 8-puzzle, 4 by 4 for the 15-puzzle. The board may have at most 16 squares,
+// so that a state fits in a PuzzleState.
+func NewSlidingPuzzle(rows, cols int) (*SlidingPuzzle, error) {
+	if rows < 1 || cols < 1 || rows*cols < 2 || rows*cols > 16 {
+		return nil, fmt.Errorf("A %dx%d board doesn't have between 2 and 16 squares", rows, cols)
+	}
+
+	return &SlidingPuzzle{rows, cols}, nil
+}
+
+// A PuzzleState is the arrangement of a SlidingPuzzle's board, with the tile on each square packed into four bits, square i (counted in row-major order) in bits 4i to 4i+3, and 0 for
+// the blank. Since the packed board is the state's ID, two states are the same node exactly when their boards are the same.
+type PuzzleState uint64
+
+func (state PuzzleState) ID() int {
+	return int(state)
+}
+
+// Returns the tile on the square, or 0 for the blank
+func (state PuzzleState) Tile(square int) int {
+	return int(state>>(4*uint(square))) & 0xf
+}
+
+func (state PuzzleState) with(square, tile int) PuzzleState {
+	shift := 4 * uint(square)
+	return state&^(0xf<<shift) | PuzzleState(tile)<<shift
+}
+
+// Returns the state with the given tiles, listed in row-major order with 0 for the blank. Returns an error unless they're each of 0 to rows*cols-1 exactly once.
+func (puzzle *SlidingPuzzle) State(tiles []int) (PuzzleState, error) {
+	n := puzzle.rows * puzzle.cols
+	if len(tiles) != n {
+		return 0, fmt.Errorf("Got %d tiles for a board of %d squares", len(tiles), n)
+	}
+
+	var state PuzzleState
+	seen := make([]bool, n)
+	for square, tile := range tiles {
+		if tile < 0 || tile >= n || seen[tile] {
+			return 0, fmt.Errorf("Tile %d is out of range or repeated", tile)
+		}
+		seen[tile] = true
+		state = state.with(square, tile)
+	}
+
+	return state, nil
+}
+
+// Returns the tiles of the state in row-major order, with 0 for the blank
+func (puzzle *SlidingPuzzle) Tiles(state PuzzleState) []int {
+	tiles := make([]int, puzzle.rows*puzzle.cols)
+	for square := range tiles {
+		tiles[square] = state.Tile(square)
+	}
+
+	return tiles
+}
+
+// Returns the solved state: tiles 1 to rows*cols-1 in order, then the blank
+func (puzzle *SlidingPuzzle) Goal() PuzzleState {
+	n := puzzle.rows * puzzle.cols
+	var state PuzzleState
+	for square := 0; square < n-1; square++ {
+		state = state.with(square, square+1)
+	}
+
+	return state
+}
+
+// Returns whether the goal can be reached from the state. Half of all arrangements can't: a move never changes the parity of the number of inversions (pairs of tiles out of order),
+// plus, on a board with an even number of columns, the row the blank is on.
+func (puzzle *SlidingPuzzle) Solvable(state PuzzleState) bool {
+	return puzzle.parity(state) == puzzle.parity(puzzle.Goal())
+}
+
+func (puzzle *SlidingPuzzle) parity(state PuzzleState) int {
+	tiles := puzzle.Tiles(state)
+	inversions := 0
+	for i, a := range tiles {
+		for _, b := range tiles[i+1:] {
+			if a != 0 && b != 0 && a > b {
+				inversions++
+			}
+		}
+	}
+	if puzzle.cols%2 == 0 {
+		inversions += puzzle.blank(state) / puzzle.cols
+	}
+
+	return inversions % 2
+}
+
+func (puzzle *SlidingPuzzle) blank(state PuzzleState) int {
+	for square := 0; square < puzzle.rows*puzzle.cols; square++ {
+		if state.Tile(square) == 0 {
+			return square
+		}
+	}
+
+	return -1
+}
+
+// Returns the states one move away: the ones with a tile next to the blank slid into it
+func (puzzle *SlidingPuzzle) Successors(node graph.Node) []graph.Node {
+	state := node.(PuzzleState)
+	blank := puzzle.blank(state)
+	row, col := blank/puzzle.cols, blank%puzzle.cols
+
+	succs := make([]graph.Node, 0, 4)
+	for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		r, c := row+d[0], col+d[1]
+		if r < 0 || r >= puzzle.rows || c < 0 || c >= puzzle.cols {
+			continue
+		}
+		square := r*puzzle.cols + c
+		succs = append(succs, state.with(blank, state.Tile(square)).with(square, 0))
+	}
+
+	return succs
+}
+
+// Returns the sum of the Manhattan distances of the tiles from their squares in the goal. Every move slides one tile one square, so this never overestimates the number of moves left.
+func (puzzle *SlidingPuzzle) HeuristicCost(node, goal graph.Node) float64 {
+	state, target := node.(PuzzleState), goal.(PuzzleState)
+	n := puzzle.rows * puzzle.cols
+
+	squares := make([]int, n) // The square each tile is on in the goal
+	for square := 0; square < n; square++ {
+		squares[target.Tile(square)] = square
+	}
+
+	distance := 0
+	for square := 0; square < n; square++ {
+		tile := state.Tile(square)
+		if tile == 0 {
+			continue
+		}
+		to := squares[tile]
+		distance += abs(square/puzzle.cols-to/puzzle.cols) + abs(square%puzzle.cols-to%puzzle.cols)
+	}
+
+	return float64(distance)
+}
+
+// Returns the puzzle's state space, in which every move costs 1 and the heuristic is HeuristicCost
+func (puzzle *SlidingPuzzle) Graph() *graph.ImplicitGraph {
+	return graph.NewImplicitGraph(puzzle.Successors, nil, puzzle.HeuristicCost)
+}
+
+// Returns the fewest moves that solve the puzzle from the state, as the states along the way (starting with the state itself and ending with the goal), using IDA*, which only needs
+// memory for the current path. Returns an error if the state can't be solved.
+func (puzzle *SlidingPuzzle) Solve(state PuzzleState) ([]PuzzleState, error) {
+	if !puzzle.Solvable(state) {
+		return nil, errors.New("The puzzle can't be solved from this state")
+	}
+
+	path, _, _ := graph.IDAStar(state, puzzle.Goal(), puzzle.Graph(), nil, nil)
+	states := make([]PuzzleState, len(path))
+	for i, node := range path {
+		states[i] = node.(PuzzleState)
+	}
+
+	return states, nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
@@ -0,0 +1,112 @@
+package examples
+
+import (
+	"github.com/nathankerr/graph"
+)
+
+// A WordLadder is the state space of Lewis Carroll's word game: turn one word into another by changing a letter at a time, where every step along the way has to be a word. Its
+// states are the words of a dictionary, as GonumNodes numbered by their position in it, and a word's successors are the words of the same length that differ from it in one letter.
+//
+// The successors are found through wildcard patterns: every word is filed under each of the patterns that blank out one of its letters ("c_t" for "cat" and "cot"), so a word's
+// neighbors are the other words filed under its patterns, without comparing it to the whole dictionary.
+type WordLadder struct {
+	words    []string
+	indices  map[string]int
+	patterns map[string][]int
+}
+
+// Returns the word ladder over the given dictionary. Words are compared as they are, so the caller should normalize their case first. Repeated words are kept once.
+func NewWordLadder(words []string) *WordLadder {
+	ladder := &WordLadder{indices: make(map[string]int), patterns: make(map[string][]int)}
+	for _, word := range words {
+		if _, ok := ladder.indices[word]; ok {
+			continue
+		}
+		id := len(ladder.words)
+		ladder.words = append(ladder.words, word)
+		ladder.indices[word] = id
+		for _, pattern := range wildcards(word) {
+			ladder.patterns[pattern] = append(ladder.patterns[pattern], id)
+		}
+	}
+
+	return ladder
+}
+
+// Returns the patterns that blank out each of the word's letters
+func wildcards(word string) []string {
+	letters := []rune(word)
+	patterns := make([]string, len(letters))
+	for i := range letters {
+		patterns[i] = string(letters[:i]) + "_" + string(letters[i+1:])
+	}
+
+	return patterns
+}
+
+// Returns the node of the word, and false if it isn't in the dictionary
+func (ladder *WordLadder) Node(word string) (graph.Node, bool) {
+	id, ok := ladder.indices[word]
+	return graph.GonumNode(id), ok
+}
+
+// Returns the word of the node
+func (ladder *WordLadder) Word(node graph.Node) string {
+	return ladder.words[node.ID()]
+}
+
+// Returns the words that differ from the node's word in one letter
+func (ladder *WordLadder) Successors(node graph.Node) []graph.Node {
+	succs := make([]graph.Node, 0)
+	for _, pattern := range wildcards(ladder.Word(node)) {
+		for _, id := range ladder.patterns[pattern] {
+			if id != node.ID() {
+				succs = append(succs, graph.GonumNode(id))
+			}
+		}
+	}
+
+	return succs
+}
+
+// Returns the number of letters in which the words differ. A step changes one letter, so this never overestimates the number of steps left.
+func (ladder *WordLadder) HeuristicCost(node, goal graph.Node) float64 {
+	a, b := []rune(ladder.Word(node)), []rune(ladder.Word(goal))
+	if len(a) != len(b) {
+		return 0
+	}
+
+	differences := 0
+	for i := range a {
+		if a[i] != b[i] {
+			differences++
+		}
+	}
+
+	return float64(differences)
+}
+
+// Returns the word ladder's state space, in which every step costs 1 and the heuristic is HeuristicCost
+func (ladder *WordLadder) Graph() *graph.ImplicitGraph {
+	return graph.NewImplicitGraph(ladder.Successors, nil, ladder.HeuristicCost)
+}
+
+// Returns a shortest ladder from one word to another, found with A*, as the words along the way. Returns nil if either word isn't in the dictionary or there's no ladder between them.
+func (ladder *WordLadder) Solve(from, to string) []string {
+	start, startOK := ladder.Node(from)
+	goal, goalOK := ladder.Node(to)
+	if !startOK || !goalOK {
+		return nil
+	}
+
+	path, _, _ := graph.AStar(start, goal, ladder.Graph(), nil, nil)
+	if path == nil {
+		return nil
+	}
+	words := make([]string, len(path))
+	for i, node := range path {
+		words[i] = ladder.Word(node)
+	}
+
+	return words
+}
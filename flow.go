@@ -0,0 +1,42 @@
+package graph
+
+import (
+	"github.com/nathankerr/graph/flow"
+)
+
+// The types of package flow, under the names they had before the package was split
+type (
+	GomoryHuTree = flow.GomoryHuTree
+	Flow         = flow.Flow
+	Cut          = flow.Cut
+)
+
+// See flow.UnitCapacity
+func UnitCapacity(e Edge) float64 {
+	return flow.UnitCapacity(e)
+}
+
+// See flow.CostCapacity
+func CostCapacity(graph Coster) func(Edge) float64 {
+	return flow.CostCapacity(graph)
+}
+
+// See flow.GomoryHu
+func GomoryHu(graph Graph, capacity func(Edge) float64) *GomoryHuTree {
+	return flow.GomoryHu(graph, capacity)
+}
+
+// See flow.MaxFlow
+func MaxFlow(graph Graph, source, sink Node, capacity func(Edge) float64) (result *Flow, cut *Cut) {
+	return flow.MaxFlow(graph, source, sink, capacity)
+}
+
+// See flow.MultiSourceMaxFlow
+func MultiSourceMaxFlow(graph Graph, sources, sinks []Node, capacity func(Edge) float64, nodeCapacity func(Node) float64) *Flow {
+	return flow.MultiSourceMaxFlow(graph, sources, sinks, capacity, nodeCapacity)
+}
+
+// See flow.Circulation
+func Circulation(graph Graph, lower, upper func(Edge) float64, demand func(Node) float64) (circulation *Flow, feasible bool) {
+	return flow.Circulation(graph, lower, upper, demand)
+}
@@ -0,0 +1,3 @@
+// Package flow has maximum flows and minimum cuts: single and multi-source flows with node capacities, circulations with lower bounds, and Gomory-Hu trees of all the minimum cuts
+// of an undirected graph.
+package flow
@@ -0,0 +1,511 @@
+package flow
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/internal/linalg"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"sort"
+)
+
+// Returns a capacity of 1 for every edge, so that a maximum flow counts edge-disjoint paths and a minimum cut counts edges
+func UnitCapacity(e core.Edge) float64 {
+	return 1
+}
+
+// Returns a capacity function that reads each edge's capacity from the graph's costs, for graphs that store capacities as costs, such as those read by ReadDIMACSFlow
+func CostCapacity(graph core.Coster) func(core.Edge) float64 {
+	return func(e core.Edge) float64 {
+		return graph.Cost(e.Head(), e.Tail())
+	}
+}
+
+// Residual capacities at or below this are treated as saturated
+const flowEpsilon = 1e-12
+
+// A flowNetwork is the residual network used by the flow algorithms, over node indices. Arcs are stored in pairs so that arc^1 is always the reverse of arc, and a pair's two capacities
+// are kept so the network can be reset and reused for another flow.
+type flowNetwork struct {
+	arcs     [][]int // The arcs leaving each node
+	to       []int
+	capacity []float64
+	residual []float64
+	level    []int
+	next     []int // The next arc to try from each node in the current phase
+}
+
+func newFlowNetwork(n int) *flowNetwork {
+	return &flowNetwork{arcs: make([][]int, n), level: make([]int, n), next: make([]int, n)}
+}
+
+// Adds an arc from u to v with the given capacity, and its reverse arc with the reverse capacity (0 for a directed edge, or the same capacity for an undirected one). Returns the index of
+// the forward arc.
+func (fn *flowNetwork) addArc(u, v int, capacity, reverse float64) int {
+	arc := len(fn.to)
+	fn.arcs[u] = append(fn.arcs[u], arc)
+	fn.arcs[v] = append(fn.arcs[v], arc+1)
+	fn.to = append(fn.to, v, u)
+	fn.capacity = append(fn.capacity, capacity, reverse)
+	fn.residual = append(fn.residual, capacity, reverse)
+
+	return arc
+}
+
+// Returns the amount of flow on the arc, which is how much of its capacity has been used (negative if the flow goes the other way)
+func (fn *flowNetwork) flow(arc int) float64 {
+	return fn.capacity[arc] - fn.residual[arc]
+}
+
+// Restores every arc to its full capacity
+func (fn *flowNetwork) reset() {
+	copy(fn.residual, fn.capacity)
+}
+
+// Pushes as much additional flow as possible from s to t with Dinic's algorithm, and returns the amount pushed. This runs in O(n^2 m) time, and much faster in practice; on unit capacity
+// networks it's O(m sqrt(m)).
+func (fn *flowNetwork) maxFlow(s, t int) float64 {
+	total := 0.0
+	for fn.levels(s, t) {
+		for i := range fn.next {
+			fn.next[i] = 0
+		}
+		for {
+			pushed := fn.augment(s, t, math.Inf(1))
+			if pushed <= flowEpsilon {
+				break
+			}
+			total += pushed
+		}
+	}
+
+	return total
+}
+
+// Labels every node with its BFS distance from s in the residual network, and returns whether t can be reached
+func (fn *flowNetwork) levels(s, t int) bool {
+	for i := range fn.level {
+		fn.level[i] = -1
+	}
+	fn.level[s] = 0
+	queue := []int{s}
+	for k := 0; k < len(queue); k++ {
+		u := queue[k]
+		for _, arc := range fn.arcs[u] {
+			if v := fn.to[arc]; fn.level[v] == -1 && fn.residual[arc] > flowEpsilon {
+				fn.level[v] = fn.level[u] + 1
+				queue = append(queue, v)
+			}
+		}
+	}
+
+	return fn.level[t] != -1
+}
+
+// Finds an augmenting path from u to t along arcs that go up one level at a time, pushes up to limit units of flow along it, and returns the amount pushed
+func (fn *flowNetwork) augment(u, t int, limit float64) float64 {
+	if u == t {
+		return limit
+	}
+
+	for ; fn.next[u] < len(fn.arcs[u]); fn.next[u]++ {
+		arc := fn.arcs[u][fn.next[u]]
+		v := fn.to[arc]
+		if fn.level[v] != fn.level[u]+1 || fn.residual[arc] <= flowEpsilon {
+			continue
+		}
+
+		if pushed := fn.augment(v, t, math.Min(limit, fn.residual[arc])); pushed > flowEpsilon {
+			fn.residual[arc] -= pushed
+			fn.residual[arc^1] += pushed
+			return pushed
+		}
+	}
+
+	return 0
+}
+
+// Returns which nodes can be reached from s in the residual network. After a maximum flow, these are the source side of a minimum cut.
+func (fn *flowNetwork) sourceSide(s int) []bool {
+	reached := make([]bool, len(fn.arcs))
+	reached[s] = true
+	queue := []int{s}
+	for k := 0; k < len(queue); k++ {
+		for _, arc := range fn.arcs[queue[k]] {
+			if v := fn.to[arc]; !reached[v] && fn.residual[arc] > flowEpsilon {
+				reached[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+
+	return reached
+}
+
+// A GomoryHuTree is a weighted tree on the nodes of an undirected graph that encodes the minimum cut between every pair of nodes: the minimum cut between u and v is the lightest edge on
+// the tree path between them, and removing that edge splits the tree into the two sides of such a cut. It's built with n-1 maximum flow computations, after which MinCut answers any pair
+// in constant time.
+type GomoryHuTree struct {
+	nodes   []core.Node
+	indices map[int]int
+	parent  []int
+	weight  []float64   // The minimum cut between each node and its parent
+	cuts    [][]float64 // The minimum cut between every pair of nodes, by index
+}
+
+// Builds the Gomory-Hu tree of the graph using Gusfield's algorithm, which only runs maximum flows on the original graph (rather than on contracted ones). The capacity of each edge is
+// given by capacity, which defaults to UnitCapacity if nil (so the minimum cuts are the local edge connectivities). Directed graphs are treated as undirected, with the capacities of the
+// two directions summed; self loops are ignored.
+//
+// Building the tree takes n-1 maximum flows plus O(n^2) time and memory to tabulate the cuts between every pair.
+func GomoryHu(graph core.Graph, capacity func(core.Edge) float64) *GomoryHuTree {
+	if capacity == nil {
+		capacity = UnitCapacity
+	}
+	nodes, indices := graphutil.IndexNodes(graph)
+	n := len(nodes)
+
+	capacities := make(map[core.EdgeKey]float64)
+	for _, edge := range graph.EdgeList() {
+		h, t := indices[edge.Head().ID()], indices[edge.Tail().ID()]
+		if h == t || (!graph.IsDirected() && h > t) {
+			continue
+		}
+		capacities[graphutil.UndirectedKey(h, t)] += capacity(edge)
+	}
+	keys := make(graphutil.EdgeKeySorter, 0, len(capacities))
+	for key := range capacities {
+		keys = append(keys, key)
+	}
+	sort.Sort(keys)
+
+	network := newFlowNetwork(n)
+	for _, key := range keys {
+		network.addArc(key.Head, key.Tail, capacities[key], capacities[key])
+	}
+
+	parent := make([]int, n)
+	weight := make([]float64, n)
+	for s := 1; s < n; s++ {
+		t := parent[s]
+		network.reset()
+		cut := network.maxFlow(s, t)
+		side := network.sourceSide(s)
+
+		weight[s] = cut
+		for i := range parent {
+			if i != s && side[i] && parent[i] == t {
+				parent[i] = s
+			}
+		}
+		if side[parent[t]] {
+			parent[s], parent[t] = parent[t], s
+			weight[s], weight[t] = weight[t], cut
+		}
+	}
+
+	tree := &GomoryHuTree{nodes: nodes, indices: indices, parent: parent, weight: weight}
+	tree.tabulate()
+
+	return tree
+}
+
+// Fills in the minimum cut between every pair of nodes by walking the tree from each node, carrying the lightest edge seen so far
+func (tree *GomoryHuTree) tabulate() {
+	n := len(tree.nodes)
+	adjacent := make([][]int, n)
+	for i := 1; i < n; i++ {
+		adjacent[i] = append(adjacent[i], tree.parent[i])
+		adjacent[tree.parent[i]] = append(adjacent[tree.parent[i]], i)
+	}
+	edgeWeight := func(u, v int) float64 {
+		if tree.parent[u] == v && u != 0 {
+			return tree.weight[u]
+		}
+		return tree.weight[v]
+	}
+
+	tree.cuts = linalg.NewMatrix(n, n)
+	for source := range tree.nodes {
+		cuts := tree.cuts[source]
+		cuts[source] = math.Inf(1)
+		visited := make([]bool, n)
+		visited[source] = true
+		stack := []int{source}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range adjacent[u] {
+				if !visited[v] {
+					visited[v] = true
+					cuts[v] = math.Min(cuts[u], edgeWeight(u, v))
+					stack = append(stack, v)
+				}
+			}
+		}
+	}
+}
+
+// Returns the value of the minimum cut separating u and v in the original graph, which is also the maximum flow between them. Returns +Inf if u and v are the same node, and 0 if either
+// isn't in the graph.
+func (tree *GomoryHuTree) MinCut(u, v core.Node) float64 {
+	i, ok := tree.indices[u.ID()]
+	if !ok {
+		return 0
+	}
+	j, ok := tree.indices[v.ID()]
+	if !ok {
+		return 0
+	}
+
+	return tree.cuts[i][j]
+}
+
+// Returns the tree itself as an undirected graph, where the cost of each edge is the minimum cut between its endpoints. Nodes in different components of the original graph are joined
+// by edges with a cost of 0.
+func (tree *GomoryHuTree) Graph() *simple.GonumGraph {
+	graph := simple.NewGonumGraph(false)
+	for _, node := range tree.nodes {
+		graph.AddNode(node, nil)
+	}
+	for i := 1; i < len(tree.nodes); i++ {
+		edge := core.GonumEdge{H: tree.nodes[i], T: tree.nodes[tree.parent[i]]}
+		graph.AddEdge(edge)
+		graph.SetEdgeCost(edge, tree.weight[i])
+	}
+
+	return graph
+}
+
+// A Flow is a feasible flow through a graph, as found by the maximum flow algorithms
+type Flow struct {
+	Value float64                  // The total amount of flow from the sources to the sinks
+	Edges map[core.EdgeKey]float64 // The amount of flow along every edge that carries any, keyed by the direction the flow goes (even in an undirected graph)
+}
+
+// Returns the amount of flow from head to tail along the edge, which is negative if the flow goes from tail to head in an undirected graph
+func (flow *Flow) Along(e core.Edge) float64 {
+	head, tail := e.Head().ID(), e.Tail().ID()
+	return flow.Edges[core.EdgeKey{Head: head, Tail: tail}] - flow.Edges[core.EdgeKey{Head: tail, Tail: head}]
+}
+
+// Returns the flow along each of the graph's edges, given the arcs they became keyed by their endpoints' indices, in the form Flow.Edges uses
+func (fn *flowNetwork) edgeFlows(arcs map[core.EdgeKey]int, nodes []core.Node) map[core.EdgeKey]float64 {
+	flows := make(map[core.EdgeKey]float64)
+	for key, arc := range arcs {
+		// In an undirected graph the two directions are separate arcs, so cancel them out
+		amount := fn.flow(arc)
+		if reverse, ok := arcs[core.EdgeKey{Head: key.Tail, Tail: key.Head}]; ok {
+			amount -= fn.flow(reverse)
+		}
+		if amount > flowEpsilon {
+			flows[core.EdgeKey{Head: nodes[key.Head].ID(), Tail: nodes[key.Tail].ID()}] = amount
+		}
+	}
+
+	return flows
+}
+
+// A Cut is a partition of a graph's nodes into two sides, along with the edges that lead from the source side to the sink side. After a maximum flow, the cut edges are all saturated,
+// and their total capacity is the value of the flow.
+type Cut struct {
+	Source, Sink []core.Node // Sorted by ID
+	Edges        []core.Edge // Sorted by Head ID, then Tail ID
+}
+
+// Finds a maximum flow from source to sink with Dinic's algorithm, along with a minimum cut separating them. The capacity of each edge is given by capacity, which defaults to
+// UnitCapacity if nil, so that the flow counts edge-disjoint paths. The edges of an undirected graph can carry flow in either direction, but not both at once. Self loops are ignored.
+//
+// The cut's source side is every node still reachable from the source in the residual network, which makes it the minimum cut closest to the source. Returns nil for both if either node
+// isn't in the graph, or if they're the same node. Use MultiSourceMaxFlow for several sources or sinks, or to limit how much flow can pass through the nodes.
+func MaxFlow(graph core.Graph, source, sink core.Node, capacity func(core.Edge) float64) (flow *Flow, cut *Cut) {
+	if capacity == nil {
+		capacity = UnitCapacity
+	}
+	nodes, indices := graphutil.IndexNodes(graph)
+	src, sok := indices[source.ID()]
+	dst, tok := indices[sink.ID()]
+	if !sok || !tok || src == dst {
+		return nil, nil
+	}
+
+	network := newFlowNetwork(len(nodes))
+	arcs := make(map[core.EdgeKey]int)
+	for _, node := range nodes {
+		for _, succ := range graph.Successors(node) {
+			h, t := indices[node.ID()], indices[succ.ID()]
+			if h == t {
+				continue
+			}
+			arcs[core.EdgeKey{Head: h, Tail: t}] = network.addArc(h, t, capacity(core.GonumEdge{H: node, T: succ}), 0)
+		}
+	}
+
+	flow = &Flow{Value: network.maxFlow(src, dst), Edges: network.edgeFlows(arcs, nodes)}
+
+	side := network.sourceSide(src)
+	cut = &Cut{Source: make([]core.Node, 0), Sink: make([]core.Node, 0), Edges: make([]core.Edge, 0)}
+	for i, node := range nodes {
+		if side[i] {
+			cut.Source = append(cut.Source, node)
+		} else {
+			cut.Sink = append(cut.Sink, node)
+		}
+	}
+	crossing := make(graphutil.EdgeKeySorter, 0)
+	for key := range arcs {
+		if side[key.Head] && !side[key.Tail] {
+			crossing = append(crossing, key)
+		}
+	}
+	sort.Sort(crossing) // Indices are in ID order, so this sorts by ID too
+	for _, key := range crossing {
+		cut.Edges = append(cut.Edges, core.GonumEdge{H: nodes[key.Head], T: nodes[key.Tail]})
+	}
+
+	return flow, cut
+}
+
+// Finds a maximum flow from any of the sources to any of the sinks, where besides the capacity of each edge, the amount of flow passing through each node can be limited by nodeCapacity.
+// This is the usual reduction to a single-source single-sink flow: a super source feeds every source and every sink drains into a super sink, while every node is split into an entrance
+// and an exit joined by an arc of the node's capacity. The node capacities apply to the sources and sinks as well, where they cap how much each can supply or absorb. This covers problems
+// such as:
+//
+// Evacuation planning: the sources are the occupied rooms (with their occupancy as their capacity), the sinks are the exits, and corridors and doorways limit the flow through them.
+//
+// Bipartite b-matching: the sources and sinks are the two sides of a bipartite graph, every edge has a capacity of 1, and each node's capacity is how many partners it can be matched
+// with. The edges carrying flow are the matching.
+//
+// If capacity is nil, UnitCapacity is used, and if nodeCapacity is nil, nodes are unlimited. The edges of an undirected graph can carry flow in either direction, but not both at once. A
+// node that's both a source and a sink is only treated as a source.
+func MultiSourceMaxFlow(graph core.Graph, sources, sinks []core.Node, capacity func(core.Edge) float64, nodeCapacity func(core.Node) float64) *Flow {
+	if capacity == nil {
+		capacity = UnitCapacity
+	}
+	nodes, indices := graphutil.IndexNodes(graph)
+	n := len(nodes)
+
+	// Node i enters at 2i and exits at 2i+1, the super source is 2n and the super sink 2n+1
+	network := newFlowNetwork(2*n + 2)
+	source, sink := 2*n, 2*n+1
+	for i, node := range nodes {
+		limit := math.Inf(1)
+		if nodeCapacity != nil {
+			limit = nodeCapacity(node)
+		}
+		network.addArc(2*i, 2*i+1, limit, 0)
+	}
+
+	arcs := make(map[core.EdgeKey]int)
+	for _, node := range nodes {
+		for _, succ := range graph.Successors(node) {
+			h, t := indices[node.ID()], indices[succ.ID()]
+			if h == t {
+				continue
+			}
+			arcs[core.EdgeKey{Head: h, Tail: t}] = network.addArc(2*h+1, 2*t, capacity(core.GonumEdge{H: node, T: succ}), 0)
+		}
+	}
+
+	isSource := make(map[int]bool, len(sources))
+	for _, node := range sources {
+		if i, ok := indices[node.ID()]; ok && !isSource[i] {
+			isSource[i] = true
+			network.addArc(source, 2*i, math.Inf(1), 0)
+		}
+	}
+	isSink := make(map[int]bool, len(sinks))
+	for _, node := range sinks {
+		if i, ok := indices[node.ID()]; ok && !isSource[i] && !isSink[i] {
+			isSink[i] = true
+			network.addArc(2*i+1, sink, math.Inf(1), 0)
+		}
+	}
+
+	return &Flow{Value: network.maxFlow(source, sink), Edges: network.edgeFlows(arcs, nodes)}
+}
+
+// Finds a circulation: a flow that carries between lower(e) and upper(e) along every edge, and where the flow into each node minus the flow out of it equals demand(node). Nodes with a
+// positive demand consume flow, those with a negative demand supply it, and if demand is nil every node must pass on exactly what it receives. This models scheduling problems where some
+// work must happen on an edge (a shift that needs at least two workers, a route that must be flown), not just may.
+//
+// The lower bounds are removed in the standard way, by sending lower(e) along every edge up front, leaving a capacity of upper(e) - lower(e), and making up the resulting imbalance at each
+// node with a maximum flow from a super source to a super sink. A circulation exists exactly when that flow saturates every arc out of the source. If lower is nil, every lower bound is 0,
+// and if upper is nil, UnitCapacity is used.
+//
+// Returns the circulation and true if one exists, or nil and false if the demands don't sum to 0, some edge has lower(e) > upper(e), or no flow can meet all the bounds. The Value of the
+// returned Flow is the total demand that was met (the sum of the positive demands). The graph is expected to be directed; the edges of an undirected graph are treated as a pair of
+// opposite edges, each with its own bounds.
+func Circulation(graph core.Graph, lower, upper func(core.Edge) float64, demand func(core.Node) float64) (circulation *Flow, feasible bool) {
+	if upper == nil {
+		upper = UnitCapacity
+	}
+	nodes, indices := graphutil.IndexNodes(graph)
+	n := len(nodes)
+
+	balance := make([]float64, n) // The net inflow each node still needs, after the lower bounds
+	total := 0.0
+	if demand != nil {
+		for i, node := range nodes {
+			balance[i] = demand(node)
+			total += balance[i]
+		}
+	}
+	if math.Abs(total) > 1e-9 {
+		return nil, false
+	}
+
+	network := newFlowNetwork(n + 2)
+	source, sink := n, n+1
+	type lowered struct {
+		arc int
+		min float64
+	}
+	arcs := make(map[core.EdgeKey]lowered)
+	for _, node := range nodes {
+		for _, succ := range graph.Successors(node) {
+			e := core.GonumEdge{H: node, T: succ}
+			min, max := 0.0, upper(e)
+			if lower != nil {
+				min = lower(e)
+			}
+			if min > max {
+				return nil, false
+			}
+
+			h, t := indices[node.ID()], indices[succ.ID()]
+			balance[h] += min
+			balance[t] -= min
+			arcs[core.EdgeKey{Head: h, Tail: t}] = lowered{network.addArc(h, t, max-min, 0), min}
+		}
+	}
+
+	required := 0.0
+	for i, b := range balance {
+		if b > 0 {
+			network.addArc(i, sink, b, 0)
+		} else if b < 0 {
+			network.addArc(source, i, -b, 0)
+			required -= b
+		}
+	}
+	if network.maxFlow(source, sink) < required-1e-9*math.Max(1, required) {
+		return nil, false
+	}
+
+	circulation = &Flow{Edges: make(map[core.EdgeKey]float64)}
+	if demand != nil {
+		for _, node := range nodes {
+			if d := demand(node); d > 0 {
+				circulation.Value += d
+			}
+		}
+	}
+	for key, arc := range arcs {
+		if amount := arc.min + network.flow(arc.arc); amount > flowEpsilon {
+			circulation.Edges[core.EdgeKey{Head: nodes[key.Head].ID(), Tail: nodes[key.Tail].ID()}] = amount
+		}
+	}
+
+	return circulation, true
+}
@@ -0,0 +1,211 @@
+package flow_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/flow"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"testing"
+)
+
+// Returns the minimum cut between u and v by brute force, trying every subset of the nodes 0..n-1 that contains u but not v
+func bruteForceMinCut(g core.Graph, n, u, v int, capacity func(core.Edge) float64) float64 {
+	best := math.Inf(1)
+	for mask := 0; mask < 1<<uint(n); mask++ {
+		if mask&(1<<uint(u)) == 0 || mask&(1<<uint(v)) != 0 {
+			continue
+		}
+		cut := 0.0
+		for _, e := range g.EdgeList() {
+			if mask&(1<<uint(e.Head().ID())) != 0 && mask&(1<<uint(e.Tail().ID())) == 0 {
+				cut += capacity(e)
+			}
+		}
+		best = math.Min(best, cut)
+	}
+
+	return best
+}
+
+func TestGomoryHu(t *testing.T) {
+	// The classic example from Gomory and Hu's paper, with weights 1-6
+	g := simple.NewGonumGraph(false)
+	weights := map[[2]int]float64{{0, 1}: 1, {0, 2}: 7, {1, 2}: 1, {1, 3}: 3, {1, 4}: 2, {2, 4}: 4, {3, 4}: 1, {3, 5}: 6, {4, 5}: 2}
+	for i := 0; i < 6; i++ {
+		g.AddNode(core.GonumNode(i), nil)
+	}
+	for pair, w := range weights {
+		edge := core.GonumEdge{H: core.GonumNode(pair[0]), T: core.GonumNode(pair[1])}
+		g.AddEdge(edge)
+		g.SetEdgeCost(edge, w)
+	}
+	g.AddNode(core.GonumNode(6), nil)
+	capacity := func(e core.Edge) float64 {
+		return g.Cost(e.Head(), e.Tail())
+	}
+
+	tree := flow.GomoryHu(g, capacity)
+	for u := 0; u < 7; u++ {
+		for v := 0; v < 7; v++ {
+			if u == v {
+				continue
+			}
+			want := bruteForceMinCut(g, 7, u, v, capacity)
+			if got := tree.MinCut(core.GonumNode(u), core.GonumNode(v)); math.Abs(got-want) > 1e-9 {
+				t.Errorf("MinCut(%d, %d) = %f, want %f", u, v, got, want)
+			}
+		}
+	}
+
+	if edges := tree.Graph().EdgeList(); len(edges) != 12 {
+		t.Errorf("Tree has %d edges, want 6 in each direction", len(edges))
+	}
+	if cut := tree.MinCut(core.GonumNode(0), core.GonumNode(10)); cut != 0 {
+		t.Errorf("Cut with a missing node is %f", cut)
+	}
+}
+
+func TestMultiSourceMaxFlow(t *testing.T) {
+	// Evacuating rooms 0 (10 people) and 1 (2 people) through a corridor 2 that fits 3 at a time, or along a direct route from room 1 to exit 4
+	building := simple.NewGonumGraph(true)
+	building.AddNode(core.GonumNode(0), nodes(2))
+	building.AddNode(core.GonumNode(1), nodes(2, 4))
+	building.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	occupancy := map[int]float64{0: 10, 1: 2, 2: 3}
+	nodeCapacity := func(node core.Node) float64 {
+		if c, ok := occupancy[node.ID()]; ok {
+			return c
+		}
+		return math.Inf(1)
+	}
+
+	result := flow.MultiSourceMaxFlow(building, nodes(0, 1), nodes(3, 4), func(core.Edge) float64 { return 5 }, nodeCapacity)
+	if result.Value != 5 {
+		t.Errorf("Evacuated %f people, want 5", result.Value)
+	}
+	if along := result.Along(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)}); along != 3 {
+		t.Errorf("Corridor carries %f, want 3", along)
+	}
+
+	// Left nodes 0 and 1 can take 2 and 1 partners, right nodes 2, 3 and 4 one each
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nodes(2, 3))
+	g.AddNode(core.GonumNode(1), nodes(3, 4))
+	b := map[int]float64{0: 2, 1: 1, 2: 1, 3: 1, 4: 1}
+	result = flow.MultiSourceMaxFlow(g, nodes(0, 1), nodes(2, 3, 4), nil, func(node core.Node) float64 { return b[node.ID()] })
+	if result.Value != 3 || len(result.Edges) != 3 {
+		t.Fatalf("Got b-matching of size %f with edges %v, want 3", result.Value, result.Edges)
+	}
+	for _, key := range []core.EdgeKey{{Head: 0, Tail: 2}, {Head: 0, Tail: 3}, {Head: 1, Tail: 4}} {
+		if result.Edges[key] != 1 {
+			t.Errorf("Matching %v doesn't include %v", result.Edges, key)
+		}
+	}
+	if along := result.Along(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(1)}); along != -1 {
+		t.Errorf("Flow from 4 to 1 is %f, want -1", along)
+	}
+}
+
+func TestCirculation(t *testing.T) {
+	// The cycle 0->1->2->0 where 0->1 must carry at least 2, and 1->2 at most 3
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1))
+	g.AddNode(core.GonumNode(2), nodes(0))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	lower := func(e core.Edge) float64 {
+		if e.Head().ID() == 0 {
+			return 2
+		}
+		return 0
+	}
+	upper := func(e core.Edge) float64 {
+		if e.Head().ID() == 1 {
+			return 3
+		}
+		return 10
+	}
+
+	circulation, ok := flow.Circulation(g, lower, upper, nil)
+	if !ok {
+		t.Fatal("No circulation found")
+	}
+	for _, key := range []core.EdgeKey{{Head: 0, Tail: 1}, {Head: 1, Tail: 2}, {Head: 2, Tail: 0}} {
+		if f := circulation.Edges[key]; f < 2 || f > 3 || f != circulation.Edges[core.EdgeKey{Head: 0, Tail: 1}] {
+			t.Errorf("Edge %v carries %f", key, f)
+		}
+	}
+
+	// Node 1 consumes 1 and node 2 supplies it, which only works if the forced flow can make up the difference
+	demand := func(node core.Node) float64 {
+		return map[int]float64{1: 1, 2: -1}[node.ID()]
+	}
+	if circulation, ok = flow.Circulation(g, lower, upper, demand); !ok || circulation.Value != 1 {
+		t.Errorf("Circulation with demands gave %v, %v", circulation, ok)
+	} else if in, out := circulation.Edges[core.EdgeKey{Head: 0, Tail: 1}], circulation.Edges[core.EdgeKey{Head: 1, Tail: 2}]; in-out != 1 {
+		t.Errorf("Node 1 receives %f and passes on %f", in, out)
+	}
+
+	// Forcing 4 around a cycle with an edge that only fits 3 is infeasible, as are unbalanced demands
+	if _, ok := flow.Circulation(g, func(core.Edge) float64 { return 4 }, upper, nil); ok {
+		t.Error("Found a circulation that violates an upper bound")
+	}
+	if _, ok := flow.Circulation(g, nil, upper, func(core.Node) float64 { return 1 }); ok {
+		t.Error("Found a circulation for unbalanced demands")
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	capacities := map[core.EdgeKey]float64{{Head: 0, Tail: 1}: 16, {Head: 0, Tail: 2}: 13, {Head: 1, Tail: 3}: 12, {Head: 2, Tail: 1}: 4, {Head: 2, Tail: 4}: 14, {Head: 3, Tail: 2}: 9, {Head: 3, Tail: 5}: 20, {Head: 4, Tail: 3}: 7, {Head: 4, Tail: 5}: 4}
+	for key := range capacities {
+		g.AddNode(core.GonumNode(key.Head), nil)
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(key.Head), T: core.GonumNode(key.Tail)})
+	}
+	capacity := func(e core.Edge) float64 { return capacities[core.EdgeKey{Head: e.Head().ID(), Tail: e.Tail().ID()}] }
+
+	result, cut := flow.MaxFlow(g, core.GonumNode(0), core.GonumNode(5), capacity)
+	if result.Value != 23 || result.Value != bruteForceMinCut(g, 6, 0, 5, capacity) {
+		t.Errorf("Got flow %f, want 23", result.Value)
+	}
+
+	net := make(map[int]float64)
+	for key, amount := range result.Edges {
+		if amount > capacities[key] {
+			t.Errorf("Edge %v carries %f, over its capacity", key, amount)
+		}
+		net[key.Head] -= amount
+		net[key.Tail] += amount
+	}
+	for id, amount := range net {
+		if id != 0 && id != 5 && amount != 0 {
+			t.Errorf("Node %d has a net inflow of %f", id, amount)
+		}
+	}
+
+	if len(cut.Source) != 4 || cut.Source[3].ID() != 4 || len(cut.Sink) != 2 {
+		t.Errorf("Got cut %v | %v, want 0 1 2 4 | 3 5", cut.Source, cut.Sink)
+	}
+	total := 0.0
+	for _, e := range cut.Edges {
+		total += capacity(e)
+	}
+	if len(cut.Edges) != 3 || cut.Edges[0].Head().ID() != 1 || total != 23 {
+		t.Errorf("Got cut edges %v with capacity %f, want 1->3, 4->3 and 4->5 with 23", cut.Edges, total)
+	}
+
+	// Undirected edges carry flow either way
+	route := simple.NewGonumGraph(false)
+	route.AddNode(core.GonumNode(2), nodes(1))
+	route.AddNode(core.GonumNode(0), nodes(1))
+	result, cut = flow.MaxFlow(route, core.GonumNode(2), core.GonumNode(0), nil)
+	if result.Value != 1 || result.Edges[core.EdgeKey{Head: 2, Tail: 1}] != 1 || result.Edges[core.EdgeKey{Head: 1, Tail: 0}] != 1 || len(cut.Edges) != 1 {
+		t.Errorf("Got flow %v and cut %v along the path", result, cut)
+	}
+
+	if result, cut := flow.MaxFlow(route, core.GonumNode(0), core.GonumNode(0), nil); result != nil || cut != nil {
+		t.Error("Got a flow from a node to itself")
+	}
+	if result, cut := flow.MaxFlow(route, core.GonumNode(0), core.GonumNode(7), nil); result != nil || cut != nil {
+		t.Error("Got a flow to a missing node")
+	}
+}
@@ -0,0 +1,14 @@
+package flow_test
+
+import (
+	"github.com/nathankerr/graph/core"
+)
+
+func nodes(ids ...int) []core.Node {
+	nodes := make([]core.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = core.GonumNode(id)
+	}
+
+	return nodes
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"github.com/nathankerr/graph/gen"
+)
+
+// The types of package gen, under the names they had before the package was split
+type (
+	Group = gen.Group
+)
+
+// See gen.Map
+func Map(graph Graph, f func(Node) Node) *GonumGraph {
+	return gen.Map(graph, f)
+}
+
+// See gen.MapWithCost
+func MapWithCost(graph Graph, f func(Node) Node, Cost func(Node, Node) float64, combine func(a, b float64) float64) *GonumGraph {
+	return gen.MapWithCost(graph, f, Cost, combine)
+}
+
+// See gen.GroupBy
+func GroupBy(graph Graph, key func(Node) string, Cost func(Node, Node) float64) (quotient *GonumGraph, groups []*Group) {
+	return gen.GroupBy(graph, key, Cost)
+}
@@ -0,0 +1,127 @@
+package gen
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"sort"
+)
+
+// Returns the image of the graph under a node mapping: every node is replaced by f(node), and every edge u->v by f(u)->f(v). Nodes that map to the same target are merged into one, which
+// makes this a simple way to aggregate a graph, for example collapsing cities into the countries they're in. If f returns nil for a node, the node and its edges are dropped.
+//
+// Edges between nodes that are merged together would become self loops, and are dropped. When several edges map onto the same edge, it gets the lowest of their costs, so path costs in
+// the result are never more than in the original. Use MapWithCost to combine them differently. The result has the same directedness as the graph.
+func Map(graph core.Graph, f func(core.Node) core.Node) *simple.GonumGraph {
+	return MapWithCost(graph, f, nil, math.Min)
+}
+
+// Like Map, but combines the costs of edges that are mapped onto the same edge with combine (such as math.Min, math.Max, or a sum for counting connections). The combined cost is built up
+// by calling combine on the costs one at a time, in no particular order.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func MapWithCost(graph core.Graph, f func(core.Node) core.Node, Cost func(core.Node, core.Node) float64, combine func(a, b float64) float64) *simple.GonumGraph {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	image := simple.NewGonumGraph(graph.IsDirected())
+	targets := make(map[int]core.Node)
+	images := make(map[int]core.Node) // The node image holds for each ID, the first target with it
+	for _, node := range graph.NodeList() {
+		if target := f(node); target != nil {
+			targets[node.ID()] = target
+			if _, ok := images[target.ID()]; !ok {
+				images[target.ID()] = target
+			}
+			image.AddNode(target, nil)
+		}
+	}
+
+	costs := make(map[core.EdgeKey]float64)
+	keys := make(graphutil.EdgeKeySorter, 0)
+	for _, edge := range graph.EdgeList() {
+		if !graph.IsDirected() && edge.Head().ID() > edge.Tail().ID() { // Undirected edges are listed in both directions, only count them once
+			continue
+		}
+
+		head, ok := targets[edge.Head().ID()]
+		if !ok {
+			continue
+		}
+		tail, ok := targets[edge.Tail().ID()]
+		if !ok || head.ID() == tail.ID() {
+			continue
+		}
+
+		key := core.KeyOf(core.GonumEdge{H: head, T: tail}, graph.IsDirected())
+		cost := Cost(edge.Head(), edge.Tail())
+		if prev, ok := costs[key]; ok {
+			costs[key] = combine(prev, cost)
+		} else {
+			costs[key] = cost
+			keys = append(keys, key)
+		}
+	}
+	sort.Sort(keys) // So the edge IDs come out the same every time
+
+	for _, key := range keys {
+		edge := core.GonumEdge{H: images[key.Head], T: images[key.Tail]}
+		image.AddEdge(edge)
+		image.SetEdgeCost(edge, costs[key])
+	}
+
+	return image
+}
+
+// A Group is a node of the quotient graph built by GroupBy, standing for all the nodes that share a key
+type Group struct {
+	id      int
+	Key     string
+	Members []core.Node // Sorted by ID
+}
+
+func (group *Group) ID() int {
+	return group.id
+}
+
+// Collapses the graph into a quotient graph with one node per distinct key, such as collapsing a call graph's functions by package. Each group is joined to every other group its members have
+// edges to, and the cost of that edge is the total cost of the edges between the two groups' members (with UniformCost, the number of edges). Edges within a group are dropped; see
+// MapWithCost for the details.
+//
+// The nodes of the quotient graph are the returned *Groups, which are sorted by key and numbered from 0 in that order, so groups[i].ID() == i.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func GroupBy(graph core.Graph, key func(core.Node) string, Cost func(core.Node, core.Node) float64) (quotient *simple.GonumGraph, groups []*Group) {
+	nodes := graphutil.NodeSorter(graph.NodeList())
+	sort.Sort(nodes)
+
+	keys := make(map[int]string, len(nodes))
+	members := make(map[string][]core.Node)
+	for _, node := range nodes {
+		k := key(node)
+		keys[node.ID()] = k
+		members[k] = append(members[k], node)
+	}
+
+	sorted := make([]string, 0, len(members))
+	for k := range members {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	groups = make([]*Group, len(sorted))
+	byKey := make(map[string]*Group, len(sorted))
+	for i, k := range sorted {
+		groups[i] = &Group{i, k, members[k]}
+		byKey[k] = groups[i]
+	}
+
+	sum := func(a, b float64) float64 {
+		return a + b
+	}
+	quotient = MapWithCost(graph, func(node core.Node) core.Node {
+		return byKey[keys[node.ID()]]
+	}, Cost, sum)
+
+	return quotient, groups
+}
@@ -0,0 +1,63 @@
+package gen_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/gen"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	// Cities 0-3 in country 100, and 4-5 in country 200, with two roads between the countries
+	g := pathGraph(6)
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(5)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)}, 7)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(5)}, 3)
+	country := func(city core.Node) core.Node {
+		if city.ID() < 4 {
+			return core.GonumNode(100)
+		}
+		return core.GonumNode(200)
+	}
+
+	countries := gen.Map(g, country)
+	if len(countries.NodeList()) != 2 || len(countries.EdgeList()) != 2 || countries.Cost(core.GonumNode(100), core.GonumNode(200)) != 3 {
+		t.Errorf("Got countries graph with edges %v", countries.EdgeList())
+	}
+
+	sum := func(a, b float64) float64 { return a + b }
+	if cost := gen.MapWithCost(g, country, nil, sum).Cost(core.GonumNode(200), core.GonumNode(100)); cost != 10 {
+		t.Errorf("Summed cross-border cost is %f, want 10", cost)
+	}
+
+	// Dropping nodes
+	evens := gen.Map(g, func(node core.Node) core.Node {
+		if node.ID()%2 == 0 {
+			return node
+		}
+		return nil
+	})
+	if len(evens.NodeList()) != 3 || len(evens.EdgeList()) != 0 {
+		t.Errorf("Got %d nodes and edges %v after dropping odd nodes", len(evens.NodeList()), evens.EdgeList())
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	// A call graph: a.F -> a.G -> b.H, a.F -> b.I -> c.J
+	calls := simple.NewGonumGraph(true)
+	calls.AddNode(core.GonumNode(0), nodes(1, 3))
+	calls.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	calls.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)})
+	pkg := map[int]string{0: "a", 1: "a", 2: "b", 3: "b", 4: "c"}
+
+	quotient, groups := gen.GroupBy(calls, func(node core.Node) string { return pkg[node.ID()] }, nil)
+	if len(groups) != 3 || groups[1].Key != "b" || groups[1].ID() != 1 || len(groups[0].Members) != 2 {
+		t.Fatalf("Got groups %v", groups)
+	}
+	if cost := quotient.Cost(groups[0], groups[1]); cost != 2 {
+		t.Errorf("Package a calls into b %f times, want 2", cost)
+	}
+	if !quotient.IsSuccessor(groups[1], groups[2]) || quotient.IsSuccessor(groups[0], groups[2]) || len(quotient.EdgeList()) != 2 {
+		t.Errorf("Got quotient edges %v", quotient.EdgeList())
+	}
+}
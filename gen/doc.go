@@ -0,0 +1,3 @@
+// Package gen builds new graphs from existing ones: Map and MapWithCost send every node to an image and merge the edges that land together, and GroupBy builds the quotient graph of
+// a partition of the nodes.
+package gen
@@ -0,0 +1,226 @@
+// Package graphutil has the helpers that the graph subpackages share but don't export: resolving Cost arguments by the Argument > Interface > UniformCost rule,
+// sorting nodes, edges and edge keys by ID, and splitting work between goroutines.
+package graphutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Returns the nodes sorted by ID along with a map from ID to index in that order
+func IndexNodes(graph core.Graph) (nodes []core.Node, indices map[int]int) {
+	sorted := NodeSorter(graph.NodeList())
+	sort.Sort(sorted)
+	indices = make(map[int]int, len(sorted))
+	for i, node := range sorted {
+		indices[node.ID()] = i
+	}
+
+	return sorted, indices
+}
+
+// Returns the key of an undirected edge between two indices, with the lower index first
+func UndirectedKey(i, j int) core.EdgeKey {
+	if i > j {
+		i, j = j, i
+	}
+	return core.EdgeKey{Head: i, Tail: j}
+}
+
+/** Sorts nodes by ID **/
+
+type NodeSorter []core.Node
+
+func (nl NodeSorter) Len() int {
+	return len(nl)
+}
+
+func (nl NodeSorter) Less(i, j int) bool {
+	return nl[i].ID() < nl[j].ID()
+}
+
+func (nl NodeSorter) Swap(i, j int) {
+	nl[i], nl[j] = nl[j], nl[i]
+}
+
+/** Sorts a list of edges by their heads' IDs and then their tails' **/
+
+type EdgeListSorter []core.Edge
+
+func (el EdgeListSorter) Len() int {
+	return len(el)
+}
+
+func (el EdgeListSorter) Less(i, j int) bool {
+	a, b := el[i], el[j]
+	return a.Head().ID() < b.Head().ID() || (a.Head().ID() == b.Head().ID() && a.Tail().ID() < b.Tail().ID())
+}
+
+func (el EdgeListSorter) Swap(i, j int) {
+	el[i], el[j] = el[j], el[i]
+}
+
+/** Sorts edge keys by head and then tail **/
+
+type EdgeKeySorter []core.EdgeKey
+
+func (ek EdgeKeySorter) Len() int {
+	return len(ek)
+}
+
+func (ek EdgeKeySorter) Less(i, j int) bool {
+	return ek[i].Head < ek[j].Head || (ek[i].Head == ek[j].Head && ek[i].Tail < ek[j].Tail)
+}
+
+func (ek EdgeKeySorter) Swap(i, j int) {
+	ek[i], ek[j] = ek[j], ek[i]
+}
+
+// Splits an edge list line into its fields, which are separated by commas if there are any and by whitespace otherwise
+func SplitEdgeLine(text string) ([]string, error) {
+	if !strings.Contains(text, ",") {
+		return strings.Fields(text), nil
+	}
+
+	fields := strings.Split(text, ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+		if fields[i] == "" {
+			return nil, errors.New("Empty field")
+		}
+	}
+
+	return fields, nil
+}
+
+// An edge as GonumGraph's JSON encoding and Patch's write it
+type JSONEdge struct {
+	From int       `json:"from"`
+	To   int       `json:"to"`
+	Cost *JSONCost `json:"cost,omitempty"`
+}
+
+// A cost that can also be infinite or NaN, which JSON numbers can't express, so they're written as the strings "+Inf", "-Inf" and "NaN"
+type JSONCost float64
+
+func (cost JSONCost) MarshalJSON() ([]byte, error) {
+	f := float64(cost)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return []byte(strconv.Quote(strconv.FormatFloat(f, 'g', -1, 64))), nil
+	}
+
+	return strconv.AppendFloat(nil, f, 'g', -1, 64), nil
+}
+
+func (cost *JSONCost) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || !(math.IsInf(f, 0) || math.IsNaN(f)) {
+			return fmt.Errorf("Bad cost %q, only +Inf, -Inf and NaN are written as strings", s)
+		}
+		*cost = JSONCost(f)
+		return nil
+	}
+
+	var f float64
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*cost = JSONCost(f)
+	return nil
+}
+
+// Returns Cost if it isn't nil, and otherwise the graph's Cost method if it's a Coster, or UniformCost
+func DefaultCost(graph core.Graph, Cost func(core.Node, core.Node) float64) func(core.Node, core.Node) float64 {
+	if Cost != nil {
+		return Cost
+	}
+	if cgraph, ok := graph.(core.Coster); ok {
+		return cgraph.Cost
+	}
+
+	return core.UniformCost
+}
+
+// Returns the cost of an edge from the graph's EdgeList, resolved as DefaultCost resolves Cost, except that when the graph's Cost method would be used a CostEdge's weight is read instead
+func DefaultEdgeCost(graph core.Graph, Cost func(core.Node, core.Node) float64) func(core.Edge) float64 {
+	if cgraph, ok := graph.(core.Coster); ok && Cost == nil {
+		return func(edge core.Edge) float64 {
+			if cedge, ok := edge.(core.CostEdge); ok {
+				return cedge.Weight()
+			}
+			return cgraph.Cost(edge.Head(), edge.Tail())
+		}
+	}
+
+	Cost = DefaultCost(graph, Cost)
+	return func(edge core.Edge) float64 {
+		return Cost(edge.Head(), edge.Tail())
+	}
+}
+
+// Returns HeuristicCost if it isn't nil, and otherwise the graph's HeuristicCost method if it's a HeuristicCoster, or NullHeuristic
+func DefaultHeuristicCost(graph core.Graph, HeuristicCost func(core.Node, core.Node) float64) func(core.Node, core.Node) float64 {
+	if HeuristicCost != nil {
+		return HeuristicCost
+	}
+	if hgraph, ok := graph.(core.HeuristicCoster); ok {
+		return hgraph.HeuristicCost
+	}
+
+	return core.NullHeuristic
+}
+
+// Appends the successors of node to buf, through the graph's SuccessorsAppender method if it has one
+func SuccessorsAppend(graph core.Graph, node core.Node, buf []core.Node) []core.Node {
+	if agraph, ok := graph.(core.SuccessorsAppender); ok {
+		return agraph.SuccessorsAppend(node, buf)
+	}
+
+	return append(buf, graph.Successors(node)...)
+}
+
+// Returns workers, or runtime.GOMAXPROCS(0) if it's not positive
+func Workers(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+
+	return workers
+}
+
+// Splits [0, n) into at most workers contiguous chunks and calls f on each chunk (numbered from 0) in its own goroutine, returning once all of them have finished
+func ParallelRange(n, workers int, f func(chunk, lo, hi int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		if n > 0 {
+			f(0, 0, n)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		chunk, lo, hi := w, w*n/workers, (w+1)*n/workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f(chunk, lo, hi)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,110 @@
+// Package linalg has the small dense linear algebra routines the spectral and electrical algorithms share. None of them are meant to compete with a real linear algebra package,
+// they're all O(n^3) and intended for small to medium graphs.
+package linalg
+
+import (
+	"math"
+	"sort"
+)
+
+// Returns a rows by cols matrix of zeros, backed by one slice
+func NewMatrix(rows, cols int) [][]float64 {
+	backing := make([]float64, rows*cols)
+	m := make([][]float64, rows)
+	for i := range m {
+		m[i] = backing[i*cols : (i+1)*cols]
+	}
+
+	return m
+}
+
+// Computes the eigenvalues and eigenvectors of a symmetric matrix with the cyclic Jacobi method. The eigenvalues are returned in ascending order, and the eigenvector
+// corresponding to values[j] is the column vectors[.][j]. The input matrix is not modified.
+func SymmetricEigen(a [][]float64) (values []float64, vectors [][]float64) {
+	n := len(a)
+	m := NewMatrix(n, n)
+	v := NewMatrix(n, n)
+	for i := range a {
+		copy(m[i], a[i])
+		v[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += m[p][q] * m[p][q]
+			}
+		}
+		if off < 1e-22 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if math.Abs(m[p][q]) < 1e-300 {
+					continue
+				}
+
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p] = c*mkp - s*mkq
+					m[k][q] = s*mkp + c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k] = c*mpk - s*mqk
+					m[q][k] = s*mpk + c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Sort(eigenSorter{order, m})
+
+	values = make([]float64, n)
+	vectors = NewMatrix(n, n)
+	for j, col := range order {
+		values[j] = m[col][col]
+		for i := 0; i < n; i++ {
+			vectors[i][j] = v[i][col]
+		}
+	}
+
+	return values, vectors
+}
+
+// Sorts column indices by the diagonal entry of a matrix, i.e. by eigenvalue once the Jacobi method has converged
+type eigenSorter struct {
+	order []int
+	m     [][]float64
+}
+
+func (es eigenSorter) Len() int {
+	return len(es.order)
+}
+
+func (es eigenSorter) Less(i, j int) bool {
+	return es.m[es.order[i]][es.order[i]] < es.m[es.order[j]][es.order[j]]
+}
+
+func (es eigenSorter) Swap(i, j int) {
+	es.order[i], es.order[j] = es.order[j], es.order[i]
+}
@@ -0,0 +1,226 @@
+package graph
+
+import (
+	"github.com/nathankerr/graph/container"
+	"github.com/nathankerr/graph/path"
+)
+
+// The types of package path, under the names they had before the package was split
+type (
+	AStarTrace       = path.AStarTrace
+	AStarStep        = path.AStarStep
+	DStarGraph       = path.DStarGraph
+	DStarInstance    = path.DStarInstance
+	AStarOptions     = path.AStarOptions
+	PathObjective    = path.PathObjective
+	AStarStrategy    = path.AStarStrategy
+	AStarStats       = path.AStarStats
+	TieBreak         = path.TieBreak
+	Searcher         = path.Searcher
+	AStarInstance    = path.AStarInstance
+	AllShortestPaths = path.AllShortestPaths
+	CostTransform    = path.CostTransform
+)
+
+// The constants of package path, under the names they had before the package was split
+const (
+	ObjectiveSum     = path.ObjectiveSum
+	ObjectiveMinimax = path.ObjectiveMinimax
+	StrategyAStar    = path.StrategyAStar
+	StrategyDijkstra = path.StrategyDijkstra
+	StrategyBFS      = path.StrategyBFS
+	TieBreakNone     = path.TieBreakNone
+	TieBreakHighG    = path.TieBreakHighG
+	TieBreakFIFO     = path.TieBreakFIFO
+	TieBreakLowID    = path.TieBreakLowID
+)
+
+// See path.InitDStar
+func InitDStar(start, goal Node, graph Graph, Cost, HeuristicCost func(Node, Node) float64) *DStarInstance {
+	return path.InitDStar(start, goal, graph, Cost, HeuristicCost)
+}
+
+// See path.DStarLite
+func DStarLite(start, goal Node, graph DStarGraph, Cost, HeuristicCost func(Node, Node) float64) error {
+	return path.DStarLite(start, goal, graph, Cost, HeuristicCost)
+}
+
+// See path.SynchronizedDStarLite
+func SynchronizedDStarLite(start, goal Node, graph DStarGraph, Cost, HeuristicCost func(Node, Node) float64, step <-chan struct{}, done chan<- error) {
+	path.SynchronizedDStarLite(start, goal, graph, Cost, HeuristicCost, step, done)
+}
+
+// See path.PathCost
+func PathCost(route []Node, graph Graph, Cost func(Node, Node) float64) float64 {
+	return path.PathCost(route, graph, Cost)
+}
+
+// See path.IsPath
+func IsPath(route []Node, graph Graph) bool {
+	return path.IsPath(route, graph)
+}
+
+// See path.IsShortestPath
+func IsShortestPath(route []Node, graph Graph, Cost func(Node, Node) float64, epsilon float64) bool {
+	return path.IsShortestPath(route, graph, Cost, epsilon)
+}
+
+// See path.AStar
+func AStar(start, goal Node, graph Graph, Cost, HeuristicCost func(Node, Node) float64) (route []Node, cost float64, nodesExpanded int) {
+	return path.AStar(start, goal, graph, Cost, HeuristicCost)
+}
+
+// See path.AStarWithOptions
+func AStarWithOptions(start, goal Node, graph Graph, opts *AStarOptions) (route []Node, cost float64, nodesExpanded int) {
+	return path.AStarWithOptions(start, goal, graph, opts)
+}
+
+// See path.NewSearcher
+func NewSearcher() *Searcher {
+	return path.NewSearcher()
+}
+
+// See path.NewAStarInstance
+func NewAStarInstance(graph Graph, Cost func(Node, Node) float64) *AStarInstance {
+	return path.NewAStarInstance(graph, Cost)
+}
+
+// See path.IDAStar
+func IDAStar(start, goal Node, graph Graph, Cost, HeuristicCost func(Node, Node) float64) (route []Node, cost float64, nodesExpanded int) {
+	return path.IDAStar(start, goal, graph, Cost, HeuristicCost)
+}
+
+// See path.BidirectionalAStar
+func BidirectionalAStar(start, goal Node, graph Graph, Cost, HeuristicCost func(Node, Node) float64) (route []Node, cost float64, nodesExpanded int) {
+	return path.BidirectionalAStar(start, goal, graph, Cost, HeuristicCost)
+}
+
+// See path.Dijkstra
+func Dijkstra(source Node, graph Graph, Cost func(Node, Node) float64) (paths map[int][]Node, costs map[int]float64) {
+	return path.Dijkstra(source, graph, Cost)
+}
+
+// See path.DijkstraPath
+func DijkstraPath(start, goal Node, graph Graph, Cost func(Node, Node) float64) (route []Node, cost float64, nodesExpanded int) {
+	return path.DijkstraPath(start, goal, graph, Cost)
+}
+
+// See path.YenKSP
+func YenKSP(start, goal Node, graph Graph, Cost func(Node, Node) float64, k int) (paths [][]Node, costs []float64) {
+	return path.YenKSP(start, goal, graph, Cost, k)
+}
+
+// See path.WidestPath
+func WidestPath(start, goal Node, graph Graph, Cost func(Node, Node) float64) (route []Node, width float64, nodesExpanded int) {
+	return path.WidestPath(start, goal, graph, Cost)
+}
+
+// See path.MostReliablePath
+func MostReliablePath(start, goal Node, graph Graph, Cost func(Node, Node) float64) (route []Node, probability float64) {
+	return path.MostReliablePath(start, goal, graph, Cost)
+}
+
+// See path.DijkstraCosts
+func DijkstraCosts(source Node, graph Graph, Cost func(Node, Node) float64) map[int]float64 {
+	return path.DijkstraCosts(source, graph, Cost)
+}
+
+// See path.Isochrone
+func Isochrone(source Node, graph Graph, Cost func(Node, Node) float64, budget float64) (reached []Node, frontier []Edge) {
+	return path.Isochrone(source, graph, Cost, budget)
+}
+
+// See path.DijkstraWithQueue
+func DijkstraWithQueue(source Node, graph Graph, Cost func(Node, Node) float64, queue container.PriorityQueue) (paths map[int][]Node, costs map[int]float64) {
+	return path.DijkstraWithQueue(source, graph, Cost, queue)
+}
+
+// See path.DijkstraInt
+func DijkstraInt(source Node, graph Graph, Cost func(Node, Node) int) (paths map[int][]Node, costs map[int]int) {
+	return path.DijkstraInt(source, graph, Cost)
+}
+
+// See path.BellmanFord
+func BellmanFord(source Node, graph Graph, Cost func(Node, Node) float64) (paths map[int][]Node, costs map[int]float64, aborted bool) {
+	return path.BellmanFord(source, graph, Cost)
+}
+
+// See path.BellmanFordCycle
+func BellmanFordCycle(source Node, graph Graph, Cost func(Node, Node) float64) (paths map[int][]Node, costs map[int]float64, cycle []Node) {
+	return path.BellmanFordCycle(source, graph, Cost)
+}
+
+// See path.BoundedHopPath
+func BoundedHopPath(start, goal Node, graph Graph, Cost func(Node, Node) float64, maxHops int) (route []Node, cost float64) {
+	return path.BoundedHopPath(start, goal, graph, Cost, maxHops)
+}
+
+// See path.Johnson
+func Johnson(graph Graph, Cost func(Node, Node) float64) (nodePaths map[int]map[int][]Node, nodeCosts map[int]map[int]float64, aborted bool) {
+	return path.Johnson(graph, Cost)
+}
+
+// See path.FloydWarshall
+func FloydWarshall(graph Graph, Cost func(Node, Node) float64) (paths *AllShortestPaths, aborted bool) {
+	return path.FloydWarshall(graph, Cost)
+}
+
+// See path.DistanceMatrix
+func DistanceMatrix(graph Graph, nodes []Node, Cost func(Node, Node) float64) [][]float64 {
+	return path.DistanceMatrix(graph, nodes, Cost)
+}
+
+// See path.ParallelDistanceMatrix
+func ParallelDistanceMatrix(graph Graph, nodes []Node, Cost func(Node, Node) float64, workers int) [][]float64 {
+	return path.ParallelDistanceMatrix(graph, nodes, Cost, workers)
+}
+
+// See path.RouteVia
+func RouteVia(start Node, waypoints []Node, goal Node, graph Graph, Cost func(Node, Node) float64) (route []Node, cost float64, order []int) {
+	return path.RouteVia(start, waypoints, goal, graph, Cost)
+}
+
+// See path.DepthFirstSearch
+func DepthFirstSearch(start, goal Node, graph Graph) []Node {
+	return path.DepthFirstSearch(start, goal, graph)
+}
+
+// See path.UniformIntCost
+func UniformIntCost(a, b Node) int {
+	return path.UniformIntCost(a, b)
+}
+
+// See path.MinMaxTransform
+func MinMaxTransform(graph Graph, Cost func(Node, Node) float64, lo, hi float64) CostTransform {
+	return path.MinMaxTransform(graph, Cost, lo, hi)
+}
+
+// See path.ZScoreTransform
+func ZScoreTransform(graph Graph, Cost func(Node, Node) float64) CostTransform {
+	return path.ZScoreTransform(graph, Cost)
+}
+
+// See path.ReciprocalTransform
+func ReciprocalTransform(cost float64) float64 {
+	return path.ReciprocalTransform(cost)
+}
+
+// See path.NegLogTransform
+func NegLogTransform(probability float64) float64 {
+	return path.NegLogTransform(probability)
+}
+
+// See path.TransformedCost
+func TransformedCost(graph Graph, Cost func(Node, Node) float64, transform CostTransform) func(Node, Node) float64 {
+	return path.TransformedCost(graph, Cost, transform)
+}
+
+// See path.NodeWeightedCost
+func NodeWeightedCost(graph Graph, Cost func(Node, Node) float64, NodeCost func(Node) float64) func(Node, Node) float64 {
+	return path.NodeWeightedCost(graph, Cost, NodeCost)
+}
+
+// See path.ApplyTransform
+func ApplyTransform(graph MutableGraph, transform CostTransform) {
+	path.ApplyTransform(graph, transform)
+}
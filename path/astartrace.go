@@ -0,0 +1,127 @@
+package path
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"io"
+	"sort"
+)
+
+// An AStarTrace records the expansions made by an A* search, in order, so that pathological heuristic behavior (such as a heuristic that's far from the true cost, or that leads the
+// search down a dead end) can be inspected after the fact. Pass one in AStarOptions.Trace, then write it out with WriteJSON, or render the state of the search after any number of
+// expansions with WriteDOT.
+type AStarTrace struct {
+	Start, Goal core.Node
+	Steps       []AStarStep // In expansion order
+}
+
+// An AStarStep is a single node expansion. H is the heuristic estimate to the goal, and F = G + H is the priority the node was expanded at.
+type AStarStep struct {
+	Node        core.Node
+	Predecessor core.Node // The node it was reached from, or nil for the start
+	G, H, F     float64
+}
+
+// Empties the trace for a new search. A nil trace does nothing, so the searches don't need to check for one.
+func (trace *AStarTrace) reset(start, goal core.Node) {
+	if trace == nil {
+		return
+	}
+
+	trace.Start, trace.Goal = start, goal
+	trace.Steps = trace.Steps[:0]
+}
+
+func (trace *AStarTrace) record(node internalNode, predecessor core.Node) {
+	trace.Steps = append(trace.Steps, AStarStep{Node: node.Node, Predecessor: predecessor, G: node.gscore, H: node.fscore - node.gscore, F: node.fscore})
+}
+
+type jsonAStarStep struct {
+	ID          int     `json:"id"`
+	Predecessor *int    `json:"predecessor"`
+	G           float64 `json:"g"`
+	H           float64 `json:"h"`
+	F           float64 `json:"f"`
+}
+
+// Writes the trace as a JSON object of the form {"start": 0, "goal": 9, "steps": [{"id": 0, "predecessor": null, "g": 0, "h": 9, "f": 9}, ...]}, with nodes given by their IDs and the
+// steps in expansion order.
+func (trace *AStarTrace) WriteJSON(w io.Writer) error {
+	steps := make([]jsonAStarStep, len(trace.Steps))
+	for i, step := range trace.Steps {
+		steps[i] = jsonAStarStep{ID: step.Node.ID(), G: step.G, H: step.H, F: step.F}
+		if step.Predecessor != nil {
+			id := step.Predecessor.ID()
+			steps[i].Predecessor = &id
+		}
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		Start int             `json:"start"`
+		Goal  int             `json:"goal"`
+		Steps []jsonAStarStep `json:"steps"`
+	}{trace.Start.ID(), trace.Goal.ID(), steps})
+}
+
+// Writes the state of the search after the given number of expansions as a Graphviz DOT digraph, so that calling it for 1, 2, ... len(Steps) gives the frames of an animation (render
+// them with e.g. "dot -Tsvg"). Every node of the graph is drawn; the ones expanded so far are filled and labeled with their expansion number and g/h/f values, with the most recent
+// expansion highlighted, and the search tree built so far is drawn in bold. The start and goal are drawn as double circles. A number of steps outside 0..len(Steps) is clamped to it.
+//
+// Edges are drawn once per EdgeList entry, so an undirected graph's edges are drawn as a pair of arcs, and nodes are named by their IDs.
+func (trace *AStarTrace) WriteDOT(w io.Writer, graph core.Graph, steps int) error {
+	if steps < 0 {
+		steps = 0
+	} else if steps > len(trace.Steps) {
+		steps = len(trace.Steps)
+	}
+
+	expanded := make(map[int]int, steps) // ID -> index into Steps
+	treeEdges := make(map[core.EdgeKey]bool, steps)
+	for i, step := range trace.Steps[:steps] {
+		expanded[step.Node.ID()] = i
+		if step.Predecessor != nil {
+			treeEdges[core.EdgeKey{Head: step.Predecessor.ID(), Tail: step.Node.ID()}] = true
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "digraph astar {\n\tnode [shape=circle, fontsize=10];\n")
+
+	nodes := graphutil.NodeSorter(graph.NodeList())
+	sort.Sort(nodes)
+	for _, node := range nodes {
+		id := node.ID()
+		attrs := fmt.Sprintf("label=\"%d\"", id)
+		if i, ok := expanded[id]; ok {
+			step := trace.Steps[i]
+			color := "lightblue"
+			if i == steps-1 {
+				color = "orange"
+			}
+			attrs = fmt.Sprintf("label=\"%d\\n#%d\\ng=%g h=%g\\nf=%g\", style=filled, fillcolor=%s", id, i+1, step.G, step.H, step.F, color)
+		}
+		if id == trace.Start.ID() || id == trace.Goal.ID() {
+			attrs += ", shape=doublecircle"
+		}
+		fmt.Fprintf(buf, "\t%d [%s];\n", id, attrs)
+	}
+
+	edges := make(graphutil.EdgeKeySorter, 0)
+	for _, edge := range graph.EdgeList() {
+		edges = append(edges, core.EdgeKey{Head: edge.Head().ID(), Tail: edge.Tail().ID()})
+	}
+	sort.Sort(edges)
+	for _, edge := range edges {
+		if treeEdges[edge] {
+			fmt.Fprintf(buf, "\t%d -> %d [penwidth=3];\n", edge.Head, edge.Tail)
+		} else {
+			fmt.Fprintf(buf, "\t%d -> %d [color=gray];\n", edge.Head, edge.Tail)
+		}
+	}
+
+	fmt.Fprintf(buf, "}\n")
+	return buf.Flush()
+}
@@ -0,0 +1,8 @@
+// Package path finds paths: shortest path searches, incremental replanning, and the cost transforms that turn other problems into shortest path ones.
+//
+//	graphSearch.go  shortest path searches (A*, IDA*, bidirectional A*, Dijkstra, Bellman-Ford, Floyd-Warshall, Johnson, DFS), Yen's k shortest paths, hop-bounded, widest and most reliable paths, routes through waypoints, and isochrones
+//	pathcheck.go    checking paths: IsPath, IsShortestPath and PathCost
+//	astartrace.go   A* search traces, written as JSON or as DOT frames
+//	dstar.go        D*-Lite incremental replanning
+//	normalize.go    cost transforms (min-max, z-score, reciprocal, negative log) applied as Cost views or in place, and node entry costs added to edge costs
+package path
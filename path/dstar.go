@@ -1,11 +1,17 @@
-package graph
+package path
 
 import (
 	"container/heap"
 	"errors"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/simple"
 	"math"
 )
 
+// A DStarInstance repairs its path when a DynamicCostGraph it's subscribed to changes costs
+var _ simple.CostListener = (*DStarInstance)(nil)
+
 // A DStarGraph is a special interface that allows the DStarLite function to be used on a graph
 //
 // D*-lite is an algorithm that allows for the graph representation to change when actions are taken, whether this be from actions taken by the agent or simply new information gathered.
@@ -17,119 +23,120 @@ import (
 // will update the internal representation. If newCostFunc is non-nil it will be swapped with dStar's current cost function if and only if changedEdges is non-nil/len>0, however,
 // newCostFunc is not required to be non-nil if updates are present. DStar will continue using the current cost function if that is the case.
 type DStarGraph interface {
-	Graph
-	Move(target Node)
-	ChangedEdges() (newCostFunc func(Node, Node) float64, changedEdges []Edge)
+	core.Graph
+	Move(target core.Node)
+	ChangedEdges() (newCostFunc func(core.Node, core.Node) float64, changedEdges []core.Edge)
 }
 
-// A DStarInstance is a way for a general Graph implementer to run D*-Lite. D*-Lite has state over multiple calls, and allows the graph to chnge, so after initialization an instance is returned.
+// A DStarInstance is a D*-Lite planner: it holds the search state between calls, so when the graph changes it repairs the path it has rather than searching again from scratch, which
+// is what makes it cheaper than running AStar every tick for an agent whose map changes as it moves. Only the part of the search the changes affect is redone.
 //
 // The general flow of D*-lite is that it's initialized and an initial shortest path is computed (essentially the same as A*). This is all done in InitDStar.
 // This is followed up by a Step() which returns the node to move to. After this action is taken, the state of the algorithm is Update()d if any edge costs have changed. In general, running DStar
 // directly will look like:
 //
-//     myDStar := InitDStar(... info ...)
+//	myDStar := InitDStar(... info ...)
+//
+//	* Repeat the following until Step returns error or you reach your goal *
+//	    move := myDStar.Step()
+//	    perform the move returned
+//	    if the graph changed, call UpdateCost for each changed edge (or Update with all of them)
 //
-//     * Repeat the following until Step returns error or you reach your goal *
-//         move := myDStar.Step()
-//         perform the move returned
-//         if the graph changed, call Update(... change info ...)
+// Costs are always read from the graph (or the Cost function) as they are now, so the planner only needs to be told where they changed, not what they changed to. An edge whose cost
+// rose, one that was removed (whose cost is now +Inf, or whose tail is no longer a successor) and one that was added are all notified the same way. For a TileGraph, a tile that's
+// blocked or unblocked changes the edges between it and each of its neighbors.
 type DStarInstance struct {
-	graph             Graph
-	start, goal, last Node
-	gScores           map[int]float64
-	cost              func(Node, Node) float64
-	heuristicCost     func(Node, Node) float64
-	u                 *dStarPriorityQueue
+	graph             core.Graph
+	start, goal, last core.Node
+	gScores           map[int]float64 // Missing scores are +Inf
 	rhs               map[int]float64
+	cost              func(core.Node, core.Node) float64
+	heuristicCost     func(core.Node, core.Node) float64
+	u                 *dStarPriorityQueue
 	k_m               float64
+	changed           []core.Edge // Edges notified with UpdateCost and not yet repaired
+}
+
+func (ds *DStarInstance) g(node core.Node) float64 {
+	if g, ok := ds.gScores[node.ID()]; ok {
+		return g
+	}
+	return math.Inf(1)
 }
 
-func (ds *DStarInstance) calculateKey(node Node) key {
-	rhs := ds.rhs[node.ID()]
-	gScore := ds.gScores[node.ID()]
-	return key{math.Min(gScore, rhs) + ds.heuristicCost(ds.start, node) + ds.k_m, math.Min(gScore, rhs)}
+func (ds *DStarInstance) rhsScore(node core.Node) float64 {
+	if rhs, ok := ds.rhs[node.ID()]; ok {
+		return rhs
+	}
+	return math.Inf(1)
+}
+
+func (ds *DStarInstance) calculateKey(node core.Node) key {
+	min := math.Min(ds.g(node), ds.rhsScore(node))
+	return key{min + ds.heuristicCost(ds.start, node) + ds.k_m, min}
 }
 
 // Initialized an instance of D*-Lite for running on a graph. Note that this does not match directly with Initialize() in the original D*-Lite paper.
 // Instead, it is the lines:
 //
-//     s_last = s_start
-//     Initialize()
-//     ComputeShortestPath()
+//	s_last = s_start
+//	Initialize()
+//	ComputeShortestPath()
 //
 // In other words, it's all the lines before the main loop in Main() in the original paper. Essentially a full state initialization.
-func InitDStar(start, goal Node, graph Graph, Cost, HeuristicCost func(Node, Node) float64) *DStarInstance {
-	if Cost == nil {
-		if cgraph, ok := graph.(Coster); ok {
-			Cost = cgraph.Cost
-		} else {
-			Cost = UniformCost
-		}
-	}
-	if HeuristicCost == nil {
-		if hgraph, ok := graph.(HeuristicCoster); ok {
-			HeuristicCost = hgraph.HeuristicCost
-		} else {
-			HeuristicCost = NullHeuristic
-		}
-	}
-
-	u := &dStarPriorityQueue{indexList: make(map[int]int, 0), nodes: make([]dStarNode, 0)}
-	heap.Init(u)
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost, and likewise for HeuristicCost with NullHeuristic. D*-Lite searches backwards
+// from the goal, so HeuristicCost is asked for estimates from the start to each node.
+func InitDStar(start, goal core.Node, graph core.Graph, Cost, HeuristicCost func(core.Node, core.Node) float64) *DStarInstance {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	HeuristicCost = graphutil.DefaultHeuristicCost(graph, HeuristicCost)
 
 	ds := &DStarInstance{
 		graph:         graph,
 		start:         start,
 		goal:          goal,
 		last:          start,
-		u:             u,
-		k_m:           0.0,
-		gScores:       make(map[int]float64, 0),
-		rhs:           make(map[int]float64, 0),
+		u:             &dStarPriorityQueue{indexList: make(map[int]int)},
+		gScores:       make(map[int]float64),
+		rhs:           make(map[int]float64),
 		cost:          Cost,
 		heuristicCost: HeuristicCost,
 	}
 
-	for _, node := range graph.NodeList() {
-		ds.rhs[node.ID()] = math.Inf(1)
-		ds.gScores[node.ID()] = math.Inf(1)
-	}
-
 	ds.rhs[goal.ID()] = 0.0
-	heap.Push(ds.u, dStarNode{Node: goal, key: ds.calculateKey(goal)})
+	ds.u.set(goal, ds.calculateKey(goal))
 	ds.computeShortestPath()
 	return ds
 }
 
-func (ds *DStarInstance) updateVertex(node Node) {
+func (ds *DStarInstance) updateVertex(node core.Node) {
 	if node.ID() != ds.goal.ID() {
 		min := math.Inf(1)
 		for _, succ := range ds.graph.Successors(node) {
-			min = math.Min(min, ds.cost(node, succ)+ds.gScores[succ.ID()])
+			min = math.Min(min, ds.cost(node, succ)+ds.g(succ))
 		}
 		ds.rhs[node.ID()] = min
 	}
 
-	if math.Abs(ds.gScores[node.ID()]-ds.rhs[node.ID()]) > .000001 {
-		ds.u.Fix(node, ds.calculateKey(node))
+	if math.Abs(ds.g(node)-ds.rhsScore(node)) > .000001 {
+		ds.u.set(node, ds.calculateKey(node))
 	} else {
 		ds.u.Remove(node)
 	}
 }
 
 func (ds *DStarInstance) computeShortestPath() {
-	for ds.u.Peek().Less(dStarNode{Node: ds.start, key: ds.calculateKey(ds.start)}) || math.Abs(ds.rhs[ds.start.ID()]-ds.gScores[ds.start.ID()]) > .000001 {
+	for ds.u.Len() > 0 && (ds.u.Peek().key.Less(ds.calculateKey(ds.start)) || math.Abs(ds.rhsScore(ds.start)-ds.g(ds.start)) > .000001) {
 
 		vert := heap.Pop(ds.u).(dStarNode)
 		newKey := ds.calculateKey(vert.Node)
-		if vert.Less(dStarNode{Node: vert.Node, key: newKey}) {
+		if vert.key.Less(newKey) {
 
-			heap.Push(ds.u, dStarNode{Node: vert.Node, key: newKey})
+			ds.u.set(vert.Node, newKey)
 
-		} else if ds.gScores[vert.ID()] > ds.rhs[vert.ID()] {
+		} else if ds.g(vert.Node) > ds.rhsScore(vert.Node) {
 
-			ds.gScores[vert.ID()] = ds.rhs[vert.ID()]
+			ds.gScores[vert.ID()] = ds.rhsScore(vert.Node)
 			for _, pred := range ds.graph.Predecessors(vert.Node) {
 				ds.updateVertex(pred)
 			}
@@ -146,47 +153,100 @@ func (ds *DStarInstance) computeShortestPath() {
 	}
 }
 
-// Returns the next action to be taken, or nil and an error if it's determined that no path exists.
-// Should be called before Update every loop
-func (ds *DStarInstance) Step() (succ Node, err error) {
+// Returns the next action to be taken, or nil and an error if it's determined that no path exists. The planner assumes the move is made, so the node becomes its new start.
+// Any changes notified with UpdateCost are repaired first. Should be called before Update every loop
+func (ds *DStarInstance) Step() (succ core.Node, err error) {
+	ds.repair()
 	if ds.start.ID() == ds.goal.ID() {
 		return ds.start, nil
-	} else if ds.gScores[ds.start.ID()] == math.Inf(1) {
+	}
+
+	next, min := ds.next(ds.start)
+	if math.IsInf(min, 1) {
 		return nil, errors.New("No path exists")
 	}
 
-	min := math.Inf(1)
-	var next Node
-	for _, succ := range ds.graph.Successors(ds.start) {
-		newMin := math.Min(min, ds.cost(ds.start, succ)+ds.gScores[succ.ID()])
-		if newMin < min {
-			min = newMin
-			next = succ
+	ds.start = next
+	return next, nil
+}
+
+// Returns the successor of node on the cheapest path to the goal, and the cost of that path
+func (ds *DStarInstance) next(node core.Node) (next core.Node, cost float64) {
+	cost = math.Inf(1)
+	for _, succ := range ds.graph.Successors(node) {
+		if c := ds.cost(node, succ) + ds.g(succ); c < cost {
+			next, cost = succ, c
 		}
 	}
 
-	return next, nil
+	return next, cost
 }
 
-// Updates D*-Lite if new information has been discovered or the graph has changed in any way. Should be called after each call of Step()
-// This is a no-op if changedEdgeCosts is nil or its len is 0.
-func (ds *DStarInstance) Update(cost func(Node, Node) float64, changedEdgeCosts []Edge) {
-	if changedEdgeCosts == nil || len(changedEdgeCosts) == 0 {
-		return
+// Returns the current cheapest path from the planner's start to the goal and its cost, or a nil path if there's none, repairing any changes notified with UpdateCost first. The path
+// is what Step would follow if nothing changed on the way.
+func (ds *DStarInstance) Path() (path []core.Node, cost float64) {
+	ds.repair()
+	if math.IsInf(ds.g(ds.start), 1) {
+		return nil, 0.0
 	}
 
-	if cost != nil {
-		ds.cost = cost
+	path = []core.Node{ds.start}
+	visited := map[int]bool{ds.start.ID(): true}
+	for node := ds.start; node.ID() != ds.goal.ID(); {
+		next, _ := ds.next(node)
+		if next == nil || visited[next.ID()] {
+			return nil, 0.0
+		}
+		visited[next.ID()] = true
+		cost += ds.cost(node, next)
+		path = append(path, next)
+		node = next
 	}
+
+	return path, cost
+}
+
+// Tells the planner that the cost of an edge has changed (or that it was added or removed). The repair is put off until the next Step or Path, so a batch of changes, such as
+// the edges around a newly blocked tile, is repaired in one go. For an undirected graph either direction of the edge will do.
+func (ds *DStarInstance) UpdateCost(edge core.Edge) {
+	ds.changed = append(ds.changed, edge)
+}
+
+// Repairs the search after the changes notified with UpdateCost
+func (ds *DStarInstance) repair() {
+	if len(ds.changed) == 0 {
+		return
+	}
+
 	ds.k_m += ds.heuristicCost(ds.last, ds.start)
 	ds.last = ds.start
 
-	for _, edge := range changedEdgeCosts {
+	undirected := !ds.graph.IsDirected()
+	for _, edge := range ds.changed {
 		ds.updateVertex(edge.Head())
+		if undirected {
+			ds.updateVertex(edge.Tail())
+		}
 	}
+	ds.changed = ds.changed[:0]
 	ds.computeShortestPath()
 }
 
+// Updates D*-Lite if new information has been discovered or the graph has changed in any way. Should be called after each call of Step()
+// This is a no-op if changedEdgeCosts is nil or its len is 0. Otherwise cost, if it's non-nil, replaces the cost function, and the changes are repaired right away, along with any
+// notified with UpdateCost.
+func (ds *DStarInstance) Update(cost func(core.Node, core.Node) float64, changedEdgeCosts []core.Edge) {
+	if changedEdgeCosts == nil || len(changedEdgeCosts) == 0 {
+		return
+	}
+
+	if cost != nil {
+		ds.cost = cost
+	}
+	ds.changed = append(ds.changed, changedEdgeCosts...)
+	ds.repair()
+}
+
 // Runs D*-Lite in its entirety on an appropriate graph. What is D*-Lite? It's an incremental heuristic lifelong planning search. What this means is that
 // unlike A*, it reacts to new information and can be used when the graph representation changes to replan paths. Perhaps the best way to understand D*-Lite
 // is to read the paper it came from[1]. However, here is a (very) brief synopsis:
@@ -201,11 +261,11 @@ func (ds *DStarInstance) Update(cost func(Node, Node) float64, changedEdgeCosts
 // It is used often in real world robotics path planning -- a modification of this algorithm known as Field D* (which allows more degrees of freedom in movement) is used in the Mars rovers Spirit and Opportunity.
 // It was also notably used for traffic path planning in the recent reboot of the SimCity franchise.
 //
-// As with other algorithms with cost function arguments in this package, Cost and HeuristicCost are optional, and if absent will default to the graph's Cost/HeuristicCost functions (if present), and finally
+// As with other algorithms with cost function arguments, Cost and HeuristicCost are optional, and if absent will default to the graph's Cost/HeuristicCost functions (if present), and finally
 // to UniformCost and NullHeauristic respectively.
 //
 // [1] http://www.aaai.org/Papers/AAAI/2002/AAAI02-072.pdf
-func DStarLite(start, goal Node, graph DStarGraph, Cost, HeuristicCost func(Node, Node) float64) error {
+func DStarLite(start, goal core.Node, graph DStarGraph, Cost, HeuristicCost func(core.Node, core.Node) float64) error {
 	ds := InitDStar(start, goal, graph, Cost, HeuristicCost) // InitDStar does s_last = s_start and computeShortestPath for us
 	for ds.start.ID() != ds.goal.ID() {
 		next, err := ds.Step()
@@ -227,7 +287,7 @@ func DStarLite(start, goal Node, graph DStarGraph, Cost, HeuristicCost func(Node
 // If an error is encountered, it will be sent over the done channel. If D*-lite exits successfully the done channel will be closed with no error written to it.
 //
 // D*-lite is initialized upon call, albeit in the new goroutine. However, the first step/move/update cycle is not performed until a signal is received.
-func SynchronizedDStarLite(start, goal Node, graph DStarGraph, Cost, HeuristicCost func(Node, Node) float64, step <-chan struct{}, done chan<- error) {
+func SynchronizedDStarLite(start, goal core.Node, graph DStarGraph, Cost, HeuristicCost func(core.Node, core.Node) float64, step <-chan struct{}, done chan<- error) {
 	go func() {
 		ds := InitDStar(start, goal, graph, Cost, HeuristicCost) // InitDStar does s_last = s_start and computeShortestPath for us
 		for ds.start.ID() != ds.goal.ID() {
@@ -254,12 +314,13 @@ func SynchronizedDStarLite(start, goal Node, graph DStarGraph, Cost, HeuristicCo
 
 type key [2]float64
 
+// Compares keys lexicographically, as D*-Lite orders its queue
 func (k1 key) Less(k2 key) bool {
-	return k1[0] < k2[0] && k1[1] < k2[1]
+	return k1[0] < k2[0] || (k1[0] == k2[0] && k1[1] < k2[1])
 }
 
 type dStarNode struct {
-	Node
+	core.Node
 	key
 }
 
@@ -301,20 +362,24 @@ func (pq *dStarPriorityQueue) Pop() interface{} {
 	return x
 }
 
+// Returns the node with the lowest key, which must exist
 func (pq *dStarPriorityQueue) Peek() dStarNode {
-	return pq.nodes[len(pq.nodes)-1]
+	return pq.nodes[0]
 }
 
-func (pq *dStarPriorityQueue) Fix(node Node, newKey key) {
+// Queues the node with the given key, or changes its key if it's already queued
+func (pq *dStarPriorityQueue) set(node core.Node, newKey key) {
 	if i, ok := pq.indexList[node.ID()]; ok {
 		pq.nodes[i].key = newKey
 		heap.Fix(pq, i)
+		return
 	}
+
+	heap.Push(pq, dStarNode{Node: node, key: newKey})
 }
 
-func (pq *dStarPriorityQueue) Remove(node Node) {
+func (pq *dStarPriorityQueue) Remove(node core.Node) {
 	if i, ok := pq.indexList[node.ID()]; ok {
 		heap.Remove(pq, i)
-		delete(pq.indexList, node.ID())
 	}
 }
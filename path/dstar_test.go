@@ -0,0 +1,109 @@
+package path_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"testing"
+)
+
+// Returns the edges between a tile and its orthogonal neighbors, which change when it's blocked or unblocked
+func tileEdges(tg *simple.TileGraph, row, col int) []core.Edge {
+	var edges []core.Edge
+	for _, dir := range simple.Directions {
+		dRow, dCol := dir.Offset()
+		if neighbor := tg.CoordsToNode(row+dRow, col+dCol); neighbor != nil {
+			edges = append(edges, core.GonumEdge{H: tg.CoordsToNode(row, col), T: neighbor})
+		}
+	}
+
+	return edges
+}
+
+func TestDStarInstance(t *testing.T) {
+	tg := simple.NewTileGraph(10, 10, true)
+	start, goal := tg.CoordsToNode(0, 0), tg.CoordsToNode(9, 9)
+	ds := path.InitDStar(start, goal, tg, nil, nil)
+	if route, cost := ds.Path(); !path.IsPath(route, tg) || cost != 18 {
+		t.Fatalf("Got path %v costing %v, want cost 18", route, cost)
+	}
+
+	// Walls go up across the agent's way as it walks, leaving a gap at the far end of each
+	walls := map[int][][2]int{
+		2: {{3, 0}, {3, 1}, {3, 2}, {3, 3}, {3, 4}, {3, 5}, {3, 6}, {3, 7}, {3, 8}},
+		5: {{6, 9}, {6, 8}, {6, 7}, {6, 6}, {6, 5}, {6, 4}, {6, 3}, {6, 2}, {6, 1}},
+	}
+	for step := 0; ; step++ {
+		for _, tile := range walls[step] {
+			tg.SetPassability(tile[0], tile[1], false)
+			for _, edge := range tileEdges(tg, tile[0], tile[1]) {
+				ds.UpdateCost(edge)
+			}
+		}
+
+		route, cost := ds.Path()
+		_, want, _ := path.AStar(route[0], goal, tg, nil, nil)
+		if !path.IsPath(route, tg) || route[len(route)-1].ID() != goal.ID() || cost != want {
+			t.Fatalf("Step %d: Got path %v costing %v, want cost %v", step, route, cost, want)
+		}
+
+		next, err := ds.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.ID() != route[1].ID() {
+			t.Errorf("Step %d: Moved to %v, but the path went to %v", step, next, route[1])
+		}
+		if next.ID() == goal.ID() {
+			break
+		}
+	}
+
+	// Sealing the goal off leaves no path
+	ds = path.InitDStar(start, goal, tg, nil, nil)
+	for _, tile := range [][2]int{{8, 9}, {9, 8}} {
+		tg.SetPassability(tile[0], tile[1], false)
+		ds.Update(nil, tileEdges(tg, tile[0], tile[1]))
+	}
+	if route, _ := ds.Path(); route != nil {
+		t.Errorf("Got path %v to a sealed off goal", route)
+	}
+	if _, err := ds.Step(); err == nil {
+		t.Error("No error stepping towards a sealed off goal")
+	}
+}
+
+// A TileGraph whose tiles become walls when the agent first reaches the given step
+type revealingGraph struct {
+	*simple.TileGraph
+	steps   int
+	walls   map[int][2]int
+	changed []core.Edge
+}
+
+func (g *revealingGraph) Move(target core.Node) {
+	g.steps++
+	if tile, ok := g.walls[g.steps]; ok {
+		g.SetPassability(tile[0], tile[1], false)
+		g.changed = tileEdges(g.TileGraph, tile[0], tile[1])
+	}
+}
+
+func (g *revealingGraph) ChangedEdges() (func(core.Node, core.Node) float64, []core.Edge) {
+	changed := g.changed
+	g.changed = nil
+	return nil, changed
+}
+
+func TestDStarLite(t *testing.T) {
+	g := &revealingGraph{TileGraph: simple.NewTileGraph(5, 5, true), walls: map[int][2]int{1: {1, 1}, 2: {2, 2}, 3: {3, 3}}}
+	if err := path.DStarLite(g.CoordsToNode(0, 0), g.CoordsToNode(4, 4), g, nil, nil); err != nil || g.steps != 8 {
+		t.Errorf("Reached the goal in %d steps with error %v, want 8", g.steps, err)
+	}
+
+	g = &revealingGraph{TileGraph: simple.NewTileGraph(1, 3, true), walls: map[int][2]int{1: {0, 2}}}
+	if err := path.DStarLite(g.CoordsToNode(0, 0), g.CoordsToNode(0, 2), g, core.UniformCost, func(a, b core.Node) float64 { return math.Abs(float64(a.ID() - b.ID())) }); err == nil {
+		t.Error("No error when the goal became a wall")
+	}
+}
@@ -0,0 +1,1978 @@
+package path
+
+import (
+	"fmt"
+	"github.com/nathankerr/graph/container"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/internal/linalg"
+	"github.com/nathankerr/graph/set"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/xifo"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Returns an ordered list consisting of the nodes between start and goal. The path will be the shortest path assuming the function heuristicCost is admissible.
+// The second return value is the cost, and the third is the number of nodes expanded while searching (useful info for tuning heuristics). Negative Costs will cause
+// bad things to happen, as well as negative heuristic estimates.
+//
+// A heuristic is admissible if, for any node in the graph, the heuristic estimate of the cost between the node and the goal is less than or equal to the true cost.
+//
+// Performance may be improved by providing a consistent heuristic (though one is not needed to find the optimal path), a heuristic is consistent if its value for a given node is less than (or equal to) the
+// actual cost of reaching its neighbors + the heuristic estimate for the neighbor itself. You can force consistency by making your HeuristicCost function
+// return max(NonConsistentHeuristicCost(neighbor,goal), NonConsistentHeuristicCost(self,goal) - Cost(self,neighbor)). If there are multiple neighbors, take the max of all of them.
+//
+// Cost and HeuristicCost take precedence for evaluating cost/heuristic distance. If one is not present (i.e. nil) the function will check the graph's interface for the respective interface:
+// Coster for Cost and HeuristicCoster for HeuristicCost. If the correct one is present, it will use the graph's function for evaluation.
+//
+// Finally, if neither the argument nor the interface is present, the function will assume UniformCost for Cost and NullHeuristic for HeuristicCost.
+//
+// Without a heuristic A* is Uniform Cost Search, and without costs as well it's Breadth First Search, so when both functions are left to fall back (neither the argument nor the
+// interface is present) AStar runs a plain breadth first search with a FIFO queue, and when only HeuristicCost falls back it runs Dijkstra's Algorithm with an indexed heap, which
+// queues each node at most once and never evaluates the heuristic. Without a heuristic f and g are the same, so the default TieBreak and TieBreakLowID both order ties by ID alone, and
+// the fast paths expand nodes in the order the general search would and return the same path: the breadth first search sorts each level by ID for those two and keeps its queue's
+// order for the others, and Dijkstra's Algorithm breaks ties by ID, so it isn't used with TieBreakFIFO or TieBreakHighG. AStarOptions.Stats reports which of the three ran.
+//
+// With the ObjectiveMinimax option the cost of a path is its most expensive edge rather than the sum of its edges, and a path of no edges costs 0, so negative costs count as 0. A
+// heuristic for summed costs says nothing about the largest edge, so it's ignored, and the search runs as Dijkstra's Algorithm, or as the general search for a TieBreak Dijkstra's can't
+// honor. Of several paths with the same worst edge, the one returned isn't necessarily the cheapest in total.
+//
+// By default ties between nodes with the same f score go to the one further along its path, and ties between those to the lowest ID, so the same path is returned on every run even
+// on a graph that lists successors in map order. TieBreakFIFO and TieBreakHighG are only deterministic given the order successors are listed in, as on a TileGraph or a GonumGraph
+// after SetOrdered.
+//
+// AStar is equivalent to AStarWithOptions with only the Cost and HeuristicCost options set.
+func AStar(start, goal core.Node, graph core.Graph, Cost, HeuristicCost func(core.Node, core.Node) float64) (path []core.Node, cost float64, nodesExpanded int) {
+	return AStarWithOptions(start, goal, graph, &AStarOptions{Cost: Cost, HeuristicCost: HeuristicCost})
+}
+
+// AStarOptions configures a run of AStarWithOptions. The zero value gives the same behavior as AStar with nil cost functions.
+type AStarOptions struct {
+	Cost          func(core.Node, core.Node) float64 // If nil, the graph's Coster is used if it has one, otherwise UniformCost
+	HeuristicCost func(core.Node, core.Node) float64 // If nil, the graph's HeuristicCoster is used if it has one, otherwise NullHeuristic
+	TieBreak      TieBreak                           // How to order nodes with equal f scores, which decides which of several equally optimal paths is returned
+	MaxNodes      int                                // If positive, the search gives up (returning a nil path) once this many nodes have been expanded
+	DenseIDs      bool                               // If true, node IDs are assumed to be small non-negative integers (as in a TileGraph), so the closed set and g scores are kept in a set.BitSet and a slice instead of maps
+	Epsilon       float64                            // A new path to a node only replaces the known one if it is cheaper by more than Epsilon, which keeps floating point noise from causing needless re-expansions
+	Trace         *AStarTrace                        // If non-nil, it's reset and then every expansion is recorded in it, for inspecting how the heuristic steers the search
+	Stats         *AStarStats                        // If non-nil, it's filled in with statistics about the search once it finishes
+	Objective     PathObjective                      // What makes one path better than another: the sum of its costs by default, see PathObjective
+}
+
+// A PathObjective says how the costs of a path's edges add up to the path's cost, which AStarWithOptions minimizes
+type PathObjective int
+
+const (
+	ObjectiveSum     PathObjective = iota // The total of the edge costs, the usual shortest path
+	ObjectiveMinimax                      // The cost of the path's most expensive edge, so the search finds the route whose worst edge (the riskiest, most congested or steepest) is least bad
+)
+
+func (objective PathObjective) String() string {
+	switch objective {
+	case ObjectiveSum:
+		return "Sum"
+	case ObjectiveMinimax:
+		return "Minimax"
+	}
+
+	return fmt.Sprintf("PathObjective(%d)", int(objective))
+}
+
+// Which search AStarWithOptions ran; see AStar for when it takes each of the fast paths
+type AStarStrategy int
+
+const (
+	StrategyAStar    AStarStrategy = iota // The general A* search
+	StrategyDijkstra                      // Dijkstra's Algorithm, for a search without a heuristic
+	StrategyBFS                           // Breadth first search, for a search without a heuristic or costs
+)
+
+func (strategy AStarStrategy) String() string {
+	switch strategy {
+	case StrategyAStar:
+		return "A*"
+	case StrategyDijkstra:
+		return "Dijkstra"
+	case StrategyBFS:
+		return "BFS"
+	}
+
+	return fmt.Sprintf("AStarStrategy(%d)", int(strategy))
+}
+
+// AStarStats describes a finished search. Pass one in AStarOptions.Stats.
+//
+// Comparing the stats of searches with different heuristics shows how well each one steers the search: a better informed (but still admissible) heuristic expands and generates
+// fewer nodes, though it may cost more to evaluate. The counts depend only on the graph and the options, so unlike Duration they're the same on every run.
+type AStarStats struct {
+	Strategy             AStarStrategy
+	NodesExpanded        int
+	NodesGenerated       int           // The number of times a node was added to the open set, counting each cheaper path found to a node that's already queued
+	MaxOpenSize          int           // The most entries the open set held at once. The general search leaves stale entries in its heap when it finds a cheaper path, and they count
+	HeuristicEvaluations int           // The number of calls to HeuristicCost, which is 0 unless the general search ran
+	Duration             time.Duration // The wall clock time the search took
+}
+
+// A TieBreak decides which node A* expands first when several nodes in the open set have the same f score, which decides which of several equally optimal paths is returned. Many
+// graphs (such as GonumGraph) list successors in map order, so only a policy that doesn't depend on the order nodes were added to the open set gives the same path on every run.
+type TieBreak int
+
+const (
+	TieBreakNone  TieBreak = iota // The default: prefer the node with the higher g score, as TieBreakHighG does, and then the one with the lowest ID, as TieBreakLowID does
+	TieBreakHighG                 // Prefer the node with the higher g score (the one that is further along its path), which usually expands fewer nodes. Remaining ties are FIFO
+	TieBreakFIFO                  // Prefer the node that was added to the open set first, which on a graph that lists successors in map order can change from run to run
+	TieBreakLowID                 // Prefer the node with the lowest ID. For a TileGraph this is row-major coordinate order, and the results don't depend on successor order at all
+)
+
+// AStarWithOptions is A* configured with an options struct rather than a growing list of positional arguments; see AStar for a description of the algorithm and its return values.
+// A nil opts is the same as a pointer to the zero value.
+//
+// The internal buffers are taken from a pool and returned afterwards, so repeated calls don't allocate them from scratch. Callers that run many searches from a single goroutine can
+// hold onto a Searcher instead.
+func AStarWithOptions(start, goal core.Node, graph core.Graph, opts *AStarOptions) (path []core.Node, cost float64, nodesExpanded int) {
+	searcher := searcherPool.Get().(*Searcher)
+	defer searcherPool.Put(searcher)
+
+	return searcher.AStar(start, goal, graph, opts)
+}
+
+var searcherPool = sync.Pool{New: func() interface{} { return NewSearcher() }}
+
+// A Searcher runs A* searches while keeping its open set, closed set and score buffers between searches. This is useful when running thousands of searches a second (such as
+// pathfinding for every unit in a game loop), where allocating them for each search puts a lot of pressure on the garbage collector.
+//
+// A Searcher may be reused for any graph, but isn't safe for concurrent use. The paths it returns are newly allocated and remain valid after the next search.
+type Searcher struct {
+	open         aStarPriorityQueue
+	closedSparse mapIntSet
+	closedDense  *set.BitSet
+	gSparse      sparseScoreMap
+	gDense       *denseScoreMap
+	predecessor  map[int]core.Node
+	successors   []core.Node
+	fifo         []core.Node            // The breadth first search's queue
+	queue        *container.IndexedHeap // Dijkstra's Algorithm's queue, keyed by ID
+	queued       map[int]core.Node      // The nodes in queue
+	generated    int                    // For AStarStats.NodesGenerated
+	maxOpen      int                    // For AStarStats.MaxOpenSize
+}
+
+func NewSearcher() *Searcher {
+	return &Searcher{
+		closedSparse: make(mapIntSet),
+		closedDense:  set.NewBitSet(0),
+		gSparse:      make(sparseScoreMap),
+		gDense:       &denseScoreMap{},
+		predecessor:  make(map[int]core.Node),
+		queue:        container.NewIndexedHeap(),
+		queued:       make(map[int]core.Node),
+	}
+}
+
+// Runs A* as AStarWithOptions does, reusing the Searcher's buffers.
+func (s *Searcher) AStar(start, goal core.Node, graph core.Graph, opts *AStarOptions) (path []core.Node, cost float64, nodesExpanded int) {
+	if opts == nil {
+		opts = &AStarOptions{}
+	}
+
+	var began time.Time
+	if opts.Stats != nil {
+		began = time.Now()
+	}
+
+	strategy := searchStrategy(graph, opts)
+	Cost, HeuristicCost := opts.Cost, opts.HeuristicCost
+	Cost = graphutil.DefaultCost(graph, Cost)
+	HeuristicCost = graphutil.DefaultHeuristicCost(graph, HeuristicCost)
+	if opts.Objective == ObjectiveMinimax {
+		HeuristicCost = core.NullHeuristic
+	}
+	evaluations := 0
+	if opts.Stats != nil {
+		heuristic := HeuristicCost
+		HeuristicCost = func(node, goal core.Node) float64 {
+			evaluations++
+			return heuristic(node, goal)
+		}
+	}
+
+	s.reset()
+	var closedSet intSet
+	var gScores scoreMap
+	if opts.DenseIDs {
+		closedSet, gScores = s.closedDense, s.gDense
+	} else {
+		closedSet, gScores = s.closedSparse, s.gSparse
+	}
+	opts.Trace.reset(start, goal)
+
+	switch strategy {
+	case StrategyBFS:
+		path, cost, nodesExpanded = s.bfs(start, goal, graph, opts, closedSet, gScores)
+	case StrategyDijkstra:
+		path, cost, nodesExpanded = s.dijkstra(start, goal, graph, Cost, opts, closedSet, gScores)
+	default:
+		path, cost, nodesExpanded = s.aStar(start, goal, graph, Cost, HeuristicCost, opts, closedSet, gScores)
+	}
+	if opts.Stats != nil {
+		*opts.Stats = AStarStats{
+			Strategy:             strategy,
+			NodesExpanded:        nodesExpanded,
+			NodesGenerated:       s.generated,
+			MaxOpenSize:          s.maxOpen,
+			HeuristicEvaluations: evaluations,
+			Duration:             time.Since(began),
+		}
+	}
+
+	return path, cost, nodesExpanded
+}
+
+// Picks the fast path, if any, that gives the same result as A* with these options
+func searchStrategy(graph core.Graph, opts *AStarOptions) AStarStrategy {
+	byID := opts.TieBreak == TieBreakNone || opts.TieBreak == TieBreakLowID
+	if opts.Objective == ObjectiveMinimax {
+		if byID {
+			return StrategyDijkstra
+		}
+		return StrategyAStar
+	}
+	if opts.HeuristicCost != nil {
+		return StrategyAStar
+	}
+	if _, ok := graph.(core.HeuristicCoster); ok {
+		return StrategyAStar
+	}
+
+	_, coster := graph.(core.Coster)
+	if opts.Cost == nil && !coster {
+		return StrategyBFS
+	}
+	if byID {
+		return StrategyDijkstra
+	}
+
+	return StrategyAStar
+}
+
+// The general search, for when there's a heuristic or a TieBreak the fast paths can't honor
+func (s *Searcher) aStar(start, goal core.Node, graph core.Graph, Cost, HeuristicCost func(core.Node, core.Node) float64, opts *AStarOptions, closedSet intSet, gScores scoreMap) (path []core.Node, cost float64, nodesExpanded int) {
+	openSet := &s.open
+	openSet.tieBreak = opts.TieBreak
+	predecessor := s.predecessor
+	minimax := opts.Objective == ObjectiveMinimax
+
+	openSet.push(internalNode{Node: start, fscore: HeuristicCost(start, goal)})
+	s.opened(openSet.Len())
+	gScores.Set(start.ID(), 0)
+
+	for openSet.Len() != 0 {
+		curr := openSet.pop()
+
+		// This isn't in most implementations of A*, it's a restructuring of the step "if node not in openSet, add it"
+		// Instead of searching to check, we see if we already evaluated it. If we have we can ignore it
+		if closedSet.Contains(curr.ID()) {
+			continue
+		}
+
+		if opts.MaxNodes > 0 && nodesExpanded >= opts.MaxNodes {
+			return nil, 0.0, nodesExpanded
+		}
+		nodesExpanded += 1
+		if opts.Trace != nil {
+			var pred core.Node
+			if curr.ID() != start.ID() {
+				pred = predecessor[curr.ID()]
+			}
+			opts.Trace.record(curr, pred)
+		}
+
+		if curr.ID() == goal.ID() {
+			return rebuildPath(predecessor, curr.Node), curr.gscore, nodesExpanded
+		}
+
+		closedSet.Add(curr.ID())
+
+		s.successors = graphutil.SuccessorsAppend(graph, curr.Node, s.successors[:0])
+		for _, neighbor := range s.successors {
+			if closedSet.Contains(neighbor.ID()) {
+				continue
+			}
+
+			edgeCost := Cost(curr.Node, neighbor)
+			g := curr.gscore + edgeCost
+			if minimax {
+				g = math.Max(curr.gscore, edgeCost)
+			}
+			if best, ok := gScores.Get(neighbor.ID()); ok && g >= best-opts.Epsilon {
+				continue
+			}
+
+			gScores.Set(neighbor.ID(), g)
+			predecessor[neighbor.ID()] = curr.Node
+			openSet.push(internalNode{Node: neighbor, gscore: g, fscore: g + HeuristicCost(neighbor, goal)})
+			s.opened(openSet.Len())
+		}
+	}
+
+	return nil, 0.0, nodesExpanded
+}
+
+// Breadth first search, for when every edge costs 1 and there's no heuristic. The closed set holds every node that's been queued rather than every node that's been expanded, since
+// the first path found to a node is a shortest one. Unless the TieBreak is TieBreakFIFO or TieBreakHighG, each level is sorted by ID before it's expanded, which expands nodes in the
+// order the general search would for those TieBreaks.
+func (s *Searcher) bfs(start, goal core.Node, graph core.Graph, opts *AStarOptions, closedSet intSet, gScores scoreMap) (path []core.Node, cost float64, nodesExpanded int) {
+	predecessor := s.predecessor
+	byID := opts.TieBreak == TieBreakNone || opts.TieBreak == TieBreakLowID
+
+	s.fifo = append(s.fifo[:0], start)
+	s.opened(1)
+	closedSet.Add(start.ID())
+	gScores.Set(start.ID(), 0)
+
+	for next, level := 0, 1; next < len(s.fifo); next++ {
+		if next == level {
+			if byID {
+				sort.Sort(graphutil.NodeSorter(s.fifo[next:]))
+			}
+			level = len(s.fifo)
+		}
+		curr := s.fifo[next]
+		if opts.MaxNodes > 0 && nodesExpanded >= opts.MaxNodes {
+			return nil, 0.0, nodesExpanded
+		}
+		nodesExpanded += 1
+		g, _ := gScores.Get(curr.ID())
+		if opts.Trace != nil {
+			var pred core.Node
+			if curr.ID() != start.ID() {
+				pred = predecessor[curr.ID()]
+			}
+			opts.Trace.record(internalNode{Node: curr, gscore: g, fscore: g}, pred)
+		}
+
+		if curr.ID() == goal.ID() {
+			return rebuildPath(predecessor, curr), g, nodesExpanded
+		}
+
+		s.successors = graphutil.SuccessorsAppend(graph, curr, s.successors[:0])
+		for _, neighbor := range s.successors {
+			if closedSet.Contains(neighbor.ID()) {
+				continue
+			}
+
+			closedSet.Add(neighbor.ID())
+			gScores.Set(neighbor.ID(), g+1)
+			predecessor[neighbor.ID()] = curr
+			s.fifo = append(s.fifo, neighbor)
+			s.opened(len(s.fifo) - next - 1)
+		}
+	}
+
+	return nil, 0.0, nodesExpanded
+}
+
+// Dijkstra's Algorithm, for when there's no heuristic. The indexed heap improves a queued node's priority in place, so unlike the general search's open set it holds each node at
+// most once and never has stale entries to skip.
+func (s *Searcher) dijkstra(start, goal core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64, opts *AStarOptions, closedSet intSet, gScores scoreMap) (path []core.Node, cost float64, nodesExpanded int) {
+	predecessor := s.predecessor
+	minimax := opts.Objective == ObjectiveMinimax
+
+	s.queue.Push(start.ID(), 0)
+	s.queued[start.ID()] = start
+	s.opened(1)
+	gScores.Set(start.ID(), 0)
+
+	for !s.queue.IsEmpty() {
+		id, g := s.queue.Pop()
+		curr := s.queued[id]
+		delete(s.queued, id)
+
+		if opts.MaxNodes > 0 && nodesExpanded >= opts.MaxNodes {
+			return nil, 0.0, nodesExpanded
+		}
+		nodesExpanded += 1
+		if opts.Trace != nil {
+			var pred core.Node
+			if id != start.ID() {
+				pred = predecessor[id]
+			}
+			opts.Trace.record(internalNode{Node: curr, gscore: g, fscore: g}, pred)
+		}
+
+		if id == goal.ID() {
+			return rebuildPath(predecessor, curr), g, nodesExpanded
+		}
+
+		closedSet.Add(id)
+
+		s.successors = graphutil.SuccessorsAppend(graph, curr, s.successors[:0])
+		for _, neighbor := range s.successors {
+			if closedSet.Contains(neighbor.ID()) {
+				continue
+			}
+
+			edgeCost := Cost(curr, neighbor)
+			ng := g + edgeCost
+			if minimax {
+				ng = math.Max(g, edgeCost)
+			}
+			if best, ok := gScores.Get(neighbor.ID()); ok && ng >= best-opts.Epsilon {
+				continue
+			}
+
+			gScores.Set(neighbor.ID(), ng)
+			predecessor[neighbor.ID()] = curr
+			s.queued[neighbor.ID()] = neighbor
+			s.queue.Push(neighbor.ID(), ng)
+			s.opened(s.queue.Len())
+		}
+	}
+
+	return nil, 0.0, nodesExpanded
+}
+
+// Empties all the buffers without releasing their memory
+func (s *Searcher) reset() {
+	s.open.nodes = s.open.nodes[:0]
+	s.open.pushed = 0
+	for id := range s.closedSparse {
+		delete(s.closedSparse, id)
+	}
+	s.closedDense.Clear()
+	for id := range s.gSparse {
+		delete(s.gSparse, id)
+	}
+	s.gDense.scores = s.gDense.scores[:0]
+	for id := range s.predecessor {
+		delete(s.predecessor, id)
+	}
+	s.fifo = s.fifo[:0]
+	s.queue.Clear()
+	for id := range s.queued {
+		delete(s.queued, id)
+	}
+	s.generated, s.maxOpen = 0, 0
+}
+
+// Counts a node added to the open set, which now holds size entries
+func (s *Searcher) opened(size int) {
+	s.generated++
+	if size > s.maxOpen {
+		s.maxOpen = size
+	}
+}
+
+// An AStarInstance answers repeated A* queries on a single graph. NewAStarInstance does the per-graph work once: it numbers the nodes densely, and records every node's successors
+// and the costs to reach them in flat arrays. Each query then runs entirely on arrays, without calling Successors or Cost, and the per-node score arrays are reused between queries
+// (they're marked stale with a generation counter rather than cleared).
+//
+// Since the graph is copied into the instance, changes made to the graph after NewAStarInstance is called aren't seen, and a new instance must be created. Like a Searcher, an AStarInstance
+// isn't safe for concurrent use.
+type AStarInstance struct {
+	nodes         []core.Node
+	indices       map[int]int
+	successors    [][]int
+	costs         [][]float64
+	heuristicCost func(core.Node, core.Node) float64
+
+	gScores      []float64
+	predecessors []int
+	seen, closed []uint32 // A node's g score is valid only if seen[i] == generation, likewise for closed
+	generation   uint32
+	open         aStarPriorityQueue
+}
+
+// Builds an AStarInstance for the graph. As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost.
+func NewAStarInstance(graph core.Graph, Cost func(core.Node, core.Node) float64) *AStarInstance {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	nodes := graph.NodeList()
+	as := &AStarInstance{
+		nodes:        nodes,
+		indices:      make(map[int]int, len(nodes)),
+		successors:   make([][]int, len(nodes)),
+		costs:        make([][]float64, len(nodes)),
+		gScores:      make([]float64, len(nodes)),
+		predecessors: make([]int, len(nodes)),
+		seen:         make([]uint32, len(nodes)),
+		closed:       make([]uint32, len(nodes)),
+	}
+	as.heuristicCost = graphutil.DefaultHeuristicCost(graph, nil)
+
+	for i, node := range nodes {
+		as.indices[node.ID()] = i
+	}
+	for i, node := range nodes {
+		for _, succ := range graph.Successors(node) {
+			if j, ok := as.indices[succ.ID()]; ok {
+				as.successors[i] = append(as.successors[i], j)
+				as.costs[i] = append(as.costs[i], Cost(node, succ))
+			}
+		}
+	}
+
+	return as
+}
+
+// Runs A* from start to goal, returning the same values as AStar. The HeuristicCost, TieBreak, MaxNodes, Epsilon and Trace options are respected; if no heuristic is given the graph's
+// HeuristicCoster is used (or NullHeuristic). The Cost option is ignored since costs were fixed when the instance was created, as is DenseIDs since the instance always uses dense arrays.
+//
+// If start or goal isn't in the graph, the search fails immediately.
+func (as *AStarInstance) Search(start, goal core.Node, opts *AStarOptions) (path []core.Node, cost float64, nodesExpanded int) {
+	if opts == nil {
+		opts = &AStarOptions{}
+	}
+	HeuristicCost := opts.HeuristicCost
+	if HeuristicCost == nil {
+		HeuristicCost = as.heuristicCost
+	}
+
+	startIdx, ok := as.indices[start.ID()]
+	if !ok {
+		return nil, 0.0, 0
+	}
+	goalIdx, ok := as.indices[goal.ID()]
+	if !ok {
+		return nil, 0.0, 0
+	}
+
+	as.generation++
+	if as.generation == 0 { // Wrapped around, so old stamps could look current
+		for i := range as.seen {
+			as.seen[i], as.closed[i] = 0, 0
+		}
+		as.generation = 1
+	}
+	gen := as.generation
+
+	openSet := &as.open
+	openSet.nodes, openSet.pushed, openSet.tieBreak = openSet.nodes[:0], 0, opts.TieBreak
+
+	as.gScores[startIdx], as.seen[startIdx], as.predecessors[startIdx] = 0, gen, -1
+	openSet.push(internalNode{Node: start, fscore: HeuristicCost(start, goal), index: startIdx})
+	opts.Trace.reset(start, goal)
+
+	for openSet.Len() != 0 {
+		curr := openSet.pop()
+		if as.closed[curr.index] == gen {
+			continue
+		}
+
+		if opts.MaxNodes > 0 && nodesExpanded >= opts.MaxNodes {
+			return nil, 0.0, nodesExpanded
+		}
+		nodesExpanded += 1
+		if opts.Trace != nil {
+			var pred core.Node
+			if p := as.predecessors[curr.index]; p != -1 {
+				pred = as.nodes[p]
+			}
+			opts.Trace.record(curr, pred)
+		}
+
+		if curr.index == goalIdx {
+			path = make([]core.Node, 0)
+			for i := goalIdx; i != -1; i = as.predecessors[i] {
+				path = append(path, as.nodes[i])
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, curr.gscore, nodesExpanded
+		}
+
+		as.closed[curr.index] = gen
+
+		for k, next := range as.successors[curr.index] {
+			if as.closed[next] == gen {
+				continue
+			}
+
+			g := curr.gscore + as.costs[curr.index][k]
+			if as.seen[next] == gen && g >= as.gScores[next]-opts.Epsilon {
+				continue
+			}
+
+			as.gScores[next], as.seen[next], as.predecessors[next] = g, gen, curr.index
+			neighbor := as.nodes[next]
+			openSet.push(internalNode{Node: neighbor, gscore: g, fscore: g + HeuristicCost(neighbor, goal), index: next})
+		}
+	}
+
+	return nil, 0.0, nodesExpanded
+}
+
+// Returns the shortest path from start to goal found by Iterative Deepening A*, along with its cost and the number of nodes expanded, which counts a node again each time it's reached.
+// IDA* runs a series of depth first searches, each cut off where the f score (cost so far plus HeuristicCost) exceeds a bound, starting from the start's heuristic estimate and raising it
+// to the lowest f score that was cut off until the goal is reached. It keeps nothing but the current path, so its memory use grows with the length of the path rather than the size of
+// the graph, which makes it the search for huge state spaces (such as an ImplicitGraph of a puzzle) where A*'s closed set wouldn't fit. The price is re-expanding nodes, both between
+// iterations and within one, since a node reached by more than one path is searched from each; only cycles back onto the current path are cut. It suits graphs with few distinct
+// f scores, like unit costs, and a heuristic that's as close to the true cost as it can be.
+//
+// The heuristic must be admissible for the path to be the shortest, as with AStar. Returns a nil path if the goal can't be reached, which on a graph with an unbounded number of nodes
+// may never happen, and costs must be positive.
+//
+// As with other algorithms that use Cost and HeuristicCost, the order of precedence is Argument > Interface > UniformCost (and NullHeuristic).
+func IDAStar(start, goal core.Node, graph core.Graph, Cost, HeuristicCost func(core.Node, core.Node) float64) (path []core.Node, cost float64, nodesExpanded int) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	HeuristicCost = graphutil.DefaultHeuristicCost(graph, HeuristicCost)
+
+	path = []core.Node{start}
+	onPath := map[int]bool{start.ID(): true}
+
+	// Searches below the last node of the path, returning whether the goal was found, and otherwise the lowest f score beyond the bound
+	var search func(g, bound float64) (found bool, next float64)
+	search = func(g, bound float64) (found bool, next float64) {
+		node := path[len(path)-1]
+		if f := g + HeuristicCost(node, goal); f > bound {
+			return false, f
+		}
+		nodesExpanded++
+		if node.ID() == goal.ID() {
+			cost = g
+			return true, bound
+		}
+
+		next = math.Inf(1)
+		for _, succ := range graphutil.SuccessorsAppend(graph, node, nil) {
+			if onPath[succ.ID()] {
+				continue
+			}
+
+			path = append(path, succ)
+			onPath[succ.ID()] = true
+			if found, f := search(g+Cost(node, succ), bound); found {
+				return true, bound
+			} else if f < next {
+				next = f
+			}
+			path = path[:len(path)-1]
+			delete(onPath, succ.ID())
+		}
+
+		return false, next
+	}
+
+	for bound := HeuristicCost(start, goal); !math.IsInf(bound, 1); {
+		found, next := search(0, bound)
+		if found {
+			return path, cost, nodesExpanded
+		}
+		bound = next
+	}
+
+	return nil, 0.0, nodesExpanded
+}
+
+// Returns the shortest path from start to goal found by searching from both ends at once: forwards from start along Successors and backwards from goal along Predecessors, until the two
+// searches meet and no shorter path through any other meeting point is possible. The results are the same as AStar's (the path, its cost, and the number of nodes expanded by both
+// searches together, with a nil path if the goal can't be reached). On a long path each search only has to get about halfway, so on a grid with a weak heuristic, or none at all, the
+// two frontiers together are much smaller than AStar's one.
+//
+// Both searches are guided by the average of the forward heuristic to the goal and the backward one from the start, (HeuristicCost(node, goal) - HeuristicCost(start, node)) / 2, which
+// keeps the two of them consistent with each other. This needs the heuristic to be consistent, not just admissible, for the path to be the shortest; the usual distance heuristics,
+// such as a TileGraph's, are. With the NullHeuristic it's bidirectional Dijkstra. Costs must not be negative, and the graph's Predecessors must be as complete as its Successors, which
+// isn't the case for an ImplicitGraph.
+//
+// As with other algorithms that use Cost and HeuristicCost, the order of precedence is Argument > Interface > UniformCost (and NullHeuristic).
+func BidirectionalAStar(start, goal core.Node, graph core.Graph, Cost, HeuristicCost func(core.Node, core.Node) float64) (path []core.Node, cost float64, nodesExpanded int) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	HeuristicCost = graphutil.DefaultHeuristicCost(graph, HeuristicCost)
+	potential := func(node core.Node) float64 {
+		return (HeuristicCost(node, goal) - HeuristicCost(start, node)) / 2
+	}
+
+	forward := newBidirectionalFrontier(start, potential)
+	forward.neighbors = func(node core.Node, buf []core.Node) []core.Node { return graphutil.SuccessorsAppend(graph, node, buf) }
+	forward.cost = func(node, succ core.Node) float64 { return Cost(node, succ) }
+	backward := newBidirectionalFrontier(goal, func(node core.Node) float64 { return -potential(node) })
+	backward.neighbors = func(node core.Node, buf []core.Node) []core.Node { return append(buf, graph.Predecessors(node)...) }
+	backward.cost = func(node, pred core.Node) float64 { return Cost(pred, node) }
+
+	// The cheapest path found so far runs through meet
+	best, meet := math.Inf(1), core.Node(nil)
+	if start.ID() == goal.ID() {
+		best, meet = 0, start
+	}
+
+	var neighbors []core.Node
+	for !forward.queue.IsEmpty() && !backward.queue.IsEmpty() {
+		// The priorities are costs adjusted by the potentials, which cancel out along a path, so once the two smallest add up to the best path's cost no other path can beat it
+		_, forwardTop := forward.queue.Peek()
+		_, backwardTop := backward.queue.Peek()
+		if forwardTop+backwardTop >= best {
+			break
+		}
+
+		side, other := forward, backward
+		if backwardTop < forwardTop {
+			side, other = backward, forward
+		}
+
+		id, _ := side.queue.Pop()
+		node := side.nodes[id]
+		side.closed[id] = true
+		nodesExpanded++
+
+		neighbors = side.neighbors(node, neighbors[:0])
+		for _, neighbor := range neighbors {
+			nid := neighbor.ID()
+			if side.closed[nid] {
+				continue
+			}
+
+			tmpCost := side.costs[id] + side.cost(node, neighbor)
+			if old, ok := side.costs[nid]; ok && tmpCost >= old {
+				continue
+			}
+			side.costs[nid] = tmpCost
+			side.parent[nid] = node
+			side.nodes[nid] = neighbor
+			side.queue.Push(nid, tmpCost+side.potential(neighbor))
+
+			if otherCost, ok := other.costs[nid]; ok && tmpCost+otherCost < best {
+				best, meet = tmpCost+otherCost, neighbor
+			}
+		}
+	}
+
+	if meet == nil {
+		return nil, 0.0, nodesExpanded
+	}
+
+	// The forward search's parents lead back to the start and the backward search's on to the goal
+	path = rebuildPath(forward.parent, meet)
+	for next, ok := backward.parent[meet.ID()]; ok; next, ok = backward.parent[next.ID()] {
+		path = append(path, next)
+	}
+
+	return path, best, nodesExpanded
+}
+
+// One direction of BidirectionalAStar. For the backward search a node's parent is the next node on the way to the goal.
+type bidirectionalFrontier struct {
+	queue     *container.IndexedHeap
+	costs     map[int]float64
+	closed    map[int]bool
+	parent    map[int]core.Node
+	nodes     map[int]core.Node
+	potential func(core.Node) float64
+	neighbors func(node core.Node, buf []core.Node) []core.Node
+	cost      func(node, neighbor core.Node) float64
+}
+
+func newBidirectionalFrontier(root core.Node, potential func(core.Node) float64) *bidirectionalFrontier {
+	frontier := &bidirectionalFrontier{
+		queue:     container.NewIndexedHeap(),
+		costs:     map[int]float64{root.ID(): 0},
+		closed:    make(map[int]bool),
+		parent:    make(map[int]core.Node),
+		nodes:     map[int]core.Node{root.ID(): root},
+		potential: potential,
+	}
+	frontier.queue.Push(root.ID(), potential(root))
+
+	return frontier
+}
+
+// Dijkstra's Algorithm is essentially a goalless Uniform Cost Search. That is, its results are roughly equivalent to
+// running A* with the Null Heuristic from a single node to every other node in the graph -- though it's a fair bit faster
+// because running A* in that way will recompute things it's already computed every call. Note that you won't necessarily get the same path
+// you would get for A*, but the cost is guaranteed to be the same (that is, if multiple shortest paths exist, you may get a different shortest path).
+//
+// Like A*, Dijkstra's Algorithm likely won't run correctly with negative edge weights -- use Bellman-Ford for that instead
+//
+// Dijkstra's algorithm usually only returns a cost map, however, since the data is available this version will also reconstruct the path to every node.
+//
+// Dijkstra uses a container.IndexedHeap as its priority queue, see DijkstraWithQueue to use a different one.
+func Dijkstra(source core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) (paths map[int][]core.Node, costs map[int]float64) {
+	return DijkstraWithQueue(source, graph, Cost, nil)
+}
+
+// DijkstraPath finds the shortest path from start to goal with Dijkstra's Algorithm, stopping as soon as the goal is reached. The results are the same as AStar with the NullHeuristic
+// (the path, its cost, and the number of nodes expanded, with a nil path if the goal can't be reached), but without any heuristic evaluations or f scores to maintain: nodes are kept in
+// a container.IndexedHeap keyed by their cost alone, so each node is queued at most once and improved in place. Use this when there's no useful heuristic, and AStar when there is.
+//
+// Like Dijkstra, negative costs will produce incorrect results; use BellmanFord for those.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func DijkstraPath(start, goal core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) (path []core.Node, cost float64, nodesExpanded int) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	queue := container.NewIndexedHeap()
+	costs := map[int]float64{start.ID(): 0}
+	closed := make(map[int]bool)
+	predecessor := make(map[int]core.Node)
+	nodeIDMap := map[int]core.Node{start.ID(): start}
+	queue.Push(start.ID(), 0)
+
+	var successors []core.Node
+	for !queue.IsEmpty() {
+		id, cost := queue.Pop()
+		node := nodeIDMap[id]
+		closed[id] = true
+		nodesExpanded++
+
+		if id == goal.ID() {
+			return rebuildPath(predecessor, node), cost, nodesExpanded
+		}
+
+		successors = graphutil.SuccessorsAppend(graph, node, successors[:0])
+		for _, neighbor := range successors {
+			nid := neighbor.ID()
+			if closed[nid] {
+				continue
+			}
+
+			tmpCost := cost + Cost(node, neighbor)
+			if best, ok := costs[nid]; !ok || tmpCost < best {
+				costs[nid] = tmpCost
+				predecessor[nid] = node
+				nodeIDMap[nid] = neighbor
+				queue.Push(nid, tmpCost)
+			}
+		}
+	}
+
+	return nil, 0.0, nodesExpanded
+}
+
+// Returns the k cheapest loopless paths from start to goal, cheapest first, and their costs, found with Yen's algorithm. Fewer are returned if there aren't k such paths, and none if
+// the goal can't be reached. Routing applications use them to offer alternatives to the single best path AStar finds. Paths of equal cost come in the order they're found.
+//
+// Each path after the first is found by branching off one of the paths already chosen: for each node on it, a search from that node (DijkstraPath) runs on a FilteredGraph that hides
+// the nodes before it and the edges the chosen paths with the same beginning leave it by. So the whole search costs about k times the length of a path times one DijkstraPath, and the
+// graph is never copied. Costs must not be negative.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func YenKSP(start, goal core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64, k int) (paths [][]core.Node, costs []float64) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	if k <= 0 {
+		return nil, nil
+	}
+
+	path, cost, _ := DijkstraPath(start, goal, graph, Cost)
+	if path == nil {
+		return nil, nil
+	}
+	paths, costs = [][]core.Node{path}, []float64{cost}
+	found := map[string]bool{yenPathKey(path): true}
+
+	var candidates []yenCandidate
+	for len(paths) < k {
+		last := paths[len(paths)-1]
+		rootCost := 0.0
+		for i := 0; i < len(last)-1; i++ {
+			spur, root := last[i], last[:i]
+			if i > 0 {
+				rootCost += Cost(last[i-1], spur)
+			}
+
+			// The spur path can't go back through the root, or leave the spur node the way a chosen path with the same root did
+			hiddenNodes := make(map[int]bool, len(root))
+			for _, node := range root {
+				hiddenNodes[node.ID()] = true
+			}
+			hiddenEdges := make(map[core.EdgeKey]bool)
+			for _, chosen := range paths {
+				if len(chosen) > i+1 && yenSamePrefix(chosen, last, i+1) {
+					hiddenEdges[core.EdgeKey{Head: chosen[i].ID(), Tail: chosen[i+1].ID()}] = true
+				}
+			}
+			view := simple.NewFilteredGraph(graph, func(node core.Node) bool {
+				return !hiddenNodes[node.ID()]
+			}, func(edge core.Edge) bool {
+				return !hiddenEdges[core.EdgeKey{Head: edge.Head().ID(), Tail: edge.Tail().ID()}]
+			})
+
+			spurPath, spurCost, _ := DijkstraPath(spur, goal, view, Cost)
+			if spurPath == nil {
+				continue
+			}
+			candidate := append(append([]core.Node(nil), root...), spurPath...)
+			if key := yenPathKey(candidate); !found[key] {
+				found[key] = true
+				candidates = append(candidates, yenCandidate{candidate, rootCost + spurCost})
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+		best := 0
+		for i := range candidates {
+			if candidates[i].cost < candidates[best].cost {
+				best = i
+			}
+		}
+		paths = append(paths, candidates[best].path)
+		costs = append(costs, candidates[best].cost)
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+
+	return paths, costs
+}
+
+// A path YenKSP has found but not yet chosen
+type yenCandidate struct {
+	path []core.Node
+	cost float64
+}
+
+// Returns whether the first n nodes of the paths are the same
+func yenSamePrefix(a, b []core.Node, n int) bool {
+	for i := 0; i < n; i++ {
+		if a[i].ID() != b[i].ID() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns a string that identifies the path by its nodes' IDs
+func yenPathKey(path []core.Node) string {
+	key := make([]byte, 0, 4*len(path))
+	for _, node := range path {
+		key = strconv.AppendInt(key, int64(node.ID()), 10)
+		key = append(key, ',')
+	}
+
+	return string(key)
+}
+
+// Returns the widest path from start to goal: the one whose narrowest edge is as wide as possible, which is the most bandwidth a single route between them can carry. The edges' widths
+// are read as their costs, so a graph that stores bandwidths as costs can be searched directly. Also returns the path's width, which is +Inf for the path of start alone, and the number
+// of nodes expanded, with a nil path if the goal can't be reached.
+//
+// It's Dijkstra's Algorithm with the path cost replaced by the width so far and the cheapest node replaced by the widest, so it takes the same time as DijkstraPath and widths may be
+// negative. Of several equally wide paths, the one returned isn't necessarily the cheapest or shortest.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func WidestPath(start, goal core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) (path []core.Node, width float64, nodesExpanded int) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	// The heap pops the lowest priority, so the widest node is queued as the lowest negated width
+	queue := container.NewIndexedHeap()
+	widths := map[int]float64{start.ID(): math.Inf(1)}
+	closed := make(map[int]bool)
+	predecessor := make(map[int]core.Node)
+	nodeIDMap := map[int]core.Node{start.ID(): start}
+	queue.Push(start.ID(), math.Inf(-1))
+
+	var successors []core.Node
+	for !queue.IsEmpty() {
+		id, _ := queue.Pop()
+		node, width := nodeIDMap[id], widths[id]
+		closed[id] = true
+		nodesExpanded++
+
+		if id == goal.ID() {
+			return rebuildPath(predecessor, node), width, nodesExpanded
+		}
+
+		successors = graphutil.SuccessorsAppend(graph, node, successors[:0])
+		for _, neighbor := range successors {
+			nid := neighbor.ID()
+			if closed[nid] {
+				continue
+			}
+
+			tmpWidth := math.Min(width, Cost(node, neighbor))
+			if best, ok := widths[nid]; !ok || tmpWidth > best {
+				widths[nid] = tmpWidth
+				predecessor[nid] = node
+				nodeIDMap[nid] = neighbor
+				queue.Push(nid, -tmpWidth)
+			}
+		}
+	}
+
+	return nil, 0.0, nodesExpanded
+}
+
+// Returns the most reliable path from start to goal, reading each edge's cost as the probability in (0, 1] that it can be crossed (a link staying up, a bridge holding), and the
+// probability that the whole path can be, which is the product of its edges'. Returns a nil path and a probability of 0 if every path has an edge of probability 0, or there's none.
+//
+// The probabilities are turned into costs with NegLogTransform, which makes the most reliable path the cheapest, and DijkstraPath finds it. Probabilities above 1 are taken as 1.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost, though with uniform costs every edge is certain and the path is one with the
+// fewest edges.
+func MostReliablePath(start, goal core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) (path []core.Node, probability float64) {
+	path, cost, _ := DijkstraPath(start, goal, graph, TransformedCost(graph, Cost, NegLogTransform))
+	if path == nil || math.IsInf(cost, 1) {
+		return nil, 0.0
+	}
+
+	return path, math.Exp(-cost)
+}
+
+// DijkstraCosts returns the cost of the shortest path from source to every reachable node, keyed by ID. It's Dijkstra without the paths: no predecessors are recorded and no paths are
+// rebuilt, which saves most of the allocation when only the distances are needed.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func DijkstraCosts(source core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) map[int]float64 {
+	costs := make(map[int]float64)
+	dijkstraTargets(source, graph, graphutil.DefaultCost(graph, Cost), func(node core.Node, cost float64) bool {
+		costs[node.ID()] = cost
+		return true
+	})
+
+	return costs
+}
+
+// Returns every node that can be reached from source at a cost of at most budget, in order of increasing cost starting with source itself, which makes a reachability map such as
+// everywhere within ten minutes' travel when the costs are travel times. Returns nil if budget is negative.
+//
+// The frontier is the edges that lead out of the reached area: each goes from a reached node to a node that can't be reached within the budget, so together they mark the boundary
+// the budget runs out on. Edges between reached nodes aren't on it, even if crossing them would go over the budget, since their heads are reached more cheaply another way.
+//
+// The search is DijkstraCosts stopped at the budget, so it only visits the nodes it returns and their successors, and costs mustn't be negative.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func Isochrone(source core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64, budget float64) (reached []core.Node, frontier []core.Edge) {
+	if budget < 0 {
+		return nil, nil
+	}
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	inside := make(map[int]bool)
+	dijkstraTargets(source, graph, Cost, func(node core.Node, cost float64) bool {
+		if cost > budget {
+			return false
+		}
+		inside[node.ID()] = true
+		reached = append(reached, node)
+		return true
+	})
+
+	var successors []core.Node
+	for _, node := range reached {
+		successors = graphutil.SuccessorsAppend(graph, node, successors[:0])
+		for _, succ := range successors {
+			if !inside[succ.ID()] {
+				frontier = append(frontier, core.GonumEdge{H: node, T: succ})
+			}
+		}
+	}
+
+	return reached, frontier
+}
+
+// DijkstraWithQueue is Dijkstra's Algorithm using the given priority queue implementation, which is cleared before use. If queue is nil a container.IndexedHeap is used, which the
+// container package's benchmarks show to be the fastest of the provided queues on grid-like graphs. A container.PairingHeap has cheaper DecreaseKeys, so it may win on dense graphs where
+// nodes are reached by many different paths.
+func DijkstraWithQueue(source core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64, queue container.PriorityQueue) (paths map[int][]core.Node, costs map[int]float64) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	if queue == nil {
+		queue = container.NewIndexedHeap()
+	}
+	queue.Clear()
+
+	nodes := graph.NodeList()
+	openSet := queue
+	closedSet := set.NewSet()
+	costs = make(map[int]float64, len(nodes)) // May overallocate, will change if it becomes a problem
+	predecessor := make(map[int]core.Node, len(nodes))
+	nodeIDMap := make(map[int]core.Node, len(nodes))
+
+	costs[source.ID()] = 0
+	nodeIDMap[source.ID()] = source
+	openSet.Push(source.ID(), 0)
+
+	var successors []core.Node
+	for !openSet.IsEmpty() {
+		id, cost := openSet.Pop()
+		node := nodeIDMap[id]
+		closedSet.Add(id)
+
+		successors = graphutil.SuccessorsAppend(graph, node, successors[:0])
+		for _, neighbor := range successors {
+			if closedSet.Contains(neighbor.ID()) {
+				continue
+			}
+
+			tmpCost := cost + Cost(node, neighbor)
+			if best, ok := costs[neighbor.ID()]; !ok || tmpCost < best {
+				costs[neighbor.ID()] = tmpCost
+				predecessor[neighbor.ID()] = node
+				nodeIDMap[neighbor.ID()] = neighbor
+				openSet.Push(neighbor.ID(), tmpCost) // Since the heap is indexed, this lowers the priority of a node that's already queued instead of adding a duplicate
+			}
+		}
+	}
+
+	paths = make(map[int][]core.Node, len(costs))
+	for node, _ := range costs { // Only reconstruct the path if one exists
+		paths[node] = rebuildPath(predecessor, nodeIDMap[node])
+	}
+	return paths, costs
+}
+
+// DijkstraInt is Dijkstra's Algorithm specialized for non-negative integer costs. Instead of a binary heap it uses Dial's bucket queue, a circular array of buckets indexed by distance,
+// which makes every queue operation O(1) and keeps all the arithmetic exact. The number of buckets is one more than the largest edge cost, which is found by scanning EdgeList once,
+// so this is best suited to graphs whose costs are small integers. Cost must agree with EdgeList (it must not return a larger cost for an edge than any listed edge has), and negative costs
+// result in undefined behavior.
+//
+// Like Dijkstra, it returns the path and cost to every reachable node, keyed by node ID.
+//
+// The order of precedence for the cost function is Argument > IntCoster interface > UniformIntCost
+func DijkstraInt(source core.Node, graph core.Graph, Cost func(core.Node, core.Node) int) (paths map[int][]core.Node, costs map[int]int) {
+	if Cost == nil {
+		if igraph, ok := graph.(core.IntCoster); ok {
+			Cost = igraph.IntCost
+		} else {
+			Cost = UniformIntCost
+		}
+	}
+
+	maxCost := 1
+	for _, edge := range graph.EdgeList() {
+		if cost := Cost(edge.Head(), edge.Tail()); cost > maxCost {
+			maxCost = cost
+		}
+	}
+
+	buckets := make([][]core.Node, maxCost+1)
+	buckets[0] = []core.Node{source}
+	pending := 1
+
+	costs = map[int]int{source.ID(): 0}
+	predecessor := make(map[int]core.Node)
+	nodeIDMap := map[int]core.Node{source.ID(): source}
+	closedSet := make(map[int]bool)
+
+	for dist := 0; pending > 0; dist++ {
+		b := dist % len(buckets)
+		for len(buckets[b]) != 0 {
+			node := buckets[b][len(buckets[b])-1]
+			buckets[b] = buckets[b][:len(buckets[b])-1]
+			pending--
+
+			// Stale entries are left in the buckets rather than removed when a node's cost decreases
+			if closedSet[node.ID()] || costs[node.ID()] != dist {
+				continue
+			}
+			closedSet[node.ID()] = true
+
+			for _, neighbor := range graph.Successors(node) {
+				tmpCost := dist + Cost(node, neighbor)
+				if cost, ok := costs[neighbor.ID()]; !ok || tmpCost < cost {
+					costs[neighbor.ID()] = tmpCost
+					predecessor[neighbor.ID()] = node
+					nodeIDMap[neighbor.ID()] = neighbor
+					nb := tmpCost % len(buckets)
+					buckets[nb] = append(buckets[nb], neighbor)
+					pending++
+				}
+			}
+		}
+	}
+
+	paths = make(map[int][]core.Node, len(costs))
+	for node := range costs {
+		paths[node] = rebuildPath(predecessor, nodeIDMap[node])
+	}
+	return paths, costs
+}
+
+// The Bellman-Ford Algorithm is the same as Dijkstra's Algorithm with a key difference. They both take a single source and find the shortest path to every other
+// (reachable) node in the graph. Bellman-Ford, however, will detect negative edge loops and abort if one is present. A negative edge loop occurs when there is a cycle in the graph
+// such that it can take an edge with a negative cost over and over. A -(-2)> B -(2)> C isn't a loop because A->B can only be taken once, but A<-(-2)->B-(2)>C is one because
+// A and B have a bi-directional edge, and algorithms like Dijkstra's will infinitely flail between them getting progressively lower costs.
+//
+// That said, if you do not have a negative edge weight, use Dijkstra's Algorithm instead, because it's faster.
+//
+// Like Dijkstra's, along with the costs this implementation will also construct all the paths for you. In addition, it has a third return value which will be true if the algorithm was aborted
+// due to the presence of a negative edge weight cycle that can be reached from the source (cycles elsewhere in the graph don't affect the shortest paths, so they're ignored). See
+// BellmanFordCycle to find out which cycle it was.
+func BellmanFord(source core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) (paths map[int][]core.Node, costs map[int]float64, aborted bool) {
+	paths, costs, cycle := BellmanFordCycle(source, graph, Cost)
+	return paths, costs, cycle != nil
+}
+
+// BellmanFordCycle is BellmanFord, but instead of just reporting that a negative cycle was found, it returns the cycle: its nodes in the order the edges go, starting and ending with
+// the same node (so IsPath holds for it). If there is a cycle the paths and costs are nil, since the costs of the nodes it can reach are unbounded; otherwise the cycle is nil.
+//
+// It makes at most n-1 passes over the edges, stopping early once a pass changes nothing, so it takes O(nm) time in the worst case.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func BellmanFordCycle(source core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) (paths map[int][]core.Node, costs map[int]float64, cycle []core.Node) {
+	edgeCost := graphutil.DefaultEdgeCost(graph, Cost)
+
+	predecessor := make(map[int]core.Node)
+	costs = map[int]float64{source.ID(): 0}
+	nodeIDMap := map[int]core.Node{source.ID(): source}
+	nodes := graph.NodeList()
+	edges := graph.EdgeList()
+
+	// Relaxes every edge leaving a node that's been reached, returning the tail of the last edge that lowered a cost, or nil if none did
+	relax := func() core.Node {
+		var changed core.Node
+		for _, edge := range edges {
+			head, tail := edge.Head(), edge.Tail()
+			hcost, ok := costs[head.ID()]
+			if !ok {
+				continue
+			}
+			cost := hcost + edgeCost(edge)
+			if best, ok := costs[tail.ID()]; !ok || cost < best {
+				costs[tail.ID()] = cost
+				predecessor[tail.ID()] = head
+				nodeIDMap[tail.ID()] = tail
+				changed = tail
+			}
+		}
+		return changed
+	}
+
+	for i := 1; i < len(nodes); i++ {
+		if relax() == nil {
+			break
+		}
+	}
+
+	// A shortest path has at most n-1 edges, so anything that still improves is fed by a negative cycle. Following the predecessors back n times is sure to land on that cycle.
+	if node := relax(); node != nil {
+		for i := 0; i < len(nodes); i++ {
+			node = predecessor[node.ID()]
+		}
+		cycle = []core.Node{node}
+		for curr := predecessor[node.ID()]; curr.ID() != node.ID(); curr = predecessor[curr.ID()] {
+			cycle = append(cycle, curr)
+		}
+		cycle = append(cycle, node)
+		for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+			cycle[i], cycle[j] = cycle[j], cycle[i]
+		}
+		return nil, nil, cycle
+	}
+
+	paths = make(map[int][]core.Node, len(costs))
+	for node := range costs {
+		paths[node] = rebuildPath(predecessor, nodeIDMap[node])
+	}
+	return paths, costs, nil
+}
+
+// Returns the cheapest path from start to goal that has at most maxHops edges, and its cost, or a nil path if the goal can't be reached in that many. Routing applications need this
+// when each hop adds delay or a link budget limits how many there can be, so the cheapest path overall, which may be long, won't do. A path of no edges is start itself.
+//
+// It's Bellman-Ford cut off after maxHops passes, where each pass only relaxes the nodes whose costs the previous pass lowered, using their costs from before the pass, so a cost
+// found in pass h belongs to a path of at most h edges. That takes O(maxHops * m) time. Negative costs are fine, even in cycles, since the hops bound the path; the path may then
+// visit a node more than once.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func BoundedHopPath(start, goal core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64, maxHops int) (path []core.Node, cost float64) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	// Each time a node's cost is lowered, the pass that lowered it and the node before it on the path, so that the path can be followed back with fewer hops at each step
+	type hopStep struct {
+		hops int
+		pred core.Node
+	}
+	costs := map[int]float64{start.ID(): 0}
+	steps := map[int][]hopStep{start.ID(): {{0, nil}}}
+	nodeIDMap := map[int]core.Node{start.ID(): start}
+	frontier := []core.Node{start}
+
+	var successors []core.Node
+	for hops := 1; hops <= maxHops && len(frontier) > 0; hops++ {
+		before := make([]float64, len(frontier))
+		for i, node := range frontier {
+			before[i] = costs[node.ID()]
+		}
+
+		var next []core.Node
+		for i, node := range frontier {
+			successors = graphutil.SuccessorsAppend(graph, node, successors[:0])
+			for _, succ := range successors {
+				sid := succ.ID()
+				tmpCost := before[i] + Cost(node, succ)
+				if best, ok := costs[sid]; ok && tmpCost >= best {
+					continue
+				}
+
+				costs[sid] = tmpCost
+				nodeIDMap[sid] = succ
+				if s := steps[sid]; len(s) > 0 && s[len(s)-1].hops == hops {
+					s[len(s)-1].pred = node
+				} else {
+					steps[sid] = append(s, hopStep{hops, node})
+					next = append(next, succ)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	cost, ok := costs[goal.ID()]
+	if !ok {
+		return nil, 0.0
+	}
+
+	for id, limit := goal.ID(), maxHops; ; {
+		s := steps[id]
+		i := len(s) - 1
+		for s[i].hops > limit {
+			i--
+		}
+		path = append(path, nodeIDMap[id])
+		if s[i].pred == nil {
+			break
+		}
+		id, limit = s[i].pred.ID(), s[i].hops-1
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, cost
+}
+
+// Johnson's Algorithm generates the lowest cost path between every pair of nodes in the graph.
+//
+// It makes use of Bellman-Ford and a dummy graph. It creates a dummy node containing edges with a cost of zero to every other node. Then it runs Bellman-Ford with this
+// dummy node as the source. It then modifies the all the nodes' edge weights (which gets rid of all negative weights).
+//
+// Finally, it removes the dummy node and runs Dijkstra's starting at every node.
+//
+// This algorithm is fairly slow. Its purpose is to remove negative edge weights to allow Dijkstra's to function properly. It's probably not worth it to run this algorithm if you have
+// all non-negative edge weights. Also note that this implementation copies your whole graph into a GonumGraph (so it can add/remove the dummy node and edges and reweight the graph).
+//
+// Its return values are, in order: a map from the source node, to the destination node, to the path between them; a map from the source node, to the destination node, to the cost of the path between them
+// (in terms of the original costs);
+// and a bool that is true if Bellman-Ford detected a negative edge weight cycle -- thus causing it (and this algorithm) to abort (if aborted is true, both maps will be nil).
+func Johnson(graph core.Graph, Cost func(core.Node, core.Node) float64) (nodePaths map[int]map[int][]core.Node, nodeCosts map[int]map[int]float64, aborted bool) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	/* Copy graph into a mutable one since it has to be altered for this algorithm */
+	dummyGraph := simple.NewGonumGraph(true)
+	for _, node := range graph.NodeList() {
+		neighbors := graph.Successors(node)
+		if !dummyGraph.NodeExists(node) {
+			dummyGraph.AddNode(node, neighbors)
+			for _, neighbor := range neighbors {
+				dummyGraph.SetEdgeCost(core.GonumEdge{H: node, T: neighbor}, Cost(node, neighbor))
+			}
+		} else {
+			for _, neighbor := range neighbors {
+				dummyGraph.AddEdge(core.GonumEdge{H: node, T: neighbor})
+				dummyGraph.SetEdgeCost(core.GonumEdge{H: node, T: neighbor}, Cost(node, neighbor))
+			}
+		}
+	}
+
+	/* Step 1: Dummy node with 0 cost edge weights to every other node*/
+	dummyNode := dummyGraph.NewNode(graph.NodeList())
+	for _, node := range graph.NodeList() {
+		dummyGraph.SetEdgeCost(core.GonumEdge{H: dummyNode, T: node}, 0)
+	}
+
+	/* Step 2: Run Bellman-Ford starting at the dummy node, abort if it detects a cycle */
+	_, costs, aborted := BellmanFord(dummyNode, dummyGraph, nil)
+	if aborted {
+		return nil, nil, true
+	}
+
+	/* Step 3: reweight the graph and remove the dummy node */
+	for _, edge := range graph.EdgeList() {
+		dummyGraph.SetEdgeCost(edge, Cost(edge.Head(), edge.Tail())+costs[edge.Head().ID()]-costs[edge.Tail().ID()])
+	}
+
+	dummyGraph.RemoveNode(dummyNode)
+
+	/* Step 4: Run Dijkstra's starting at every node */
+	nodePaths = make(map[int]map[int][]core.Node, len(graph.NodeList()))
+	nodeCosts = make(map[int]map[int]float64)
+
+	for _, node := range graph.NodeList() {
+		nodePaths[node.ID()], nodeCosts[node.ID()] = Dijkstra(node, dummyGraph, nil)
+
+		// Undo the reweighting, which added h(u) - h(v) to the cost of every path from u to v
+		for id := range nodeCosts[node.ID()] {
+			nodeCosts[node.ID()][id] += costs[id] - costs[node.ID()]
+		}
+	}
+
+	return nodePaths, nodeCosts, false
+}
+
+// AllShortestPaths holds the shortest path costs between every pair of nodes in a graph, along with what's needed to rebuild the paths themselves. It's returned by FloydWarshall.
+type AllShortestPaths struct {
+	nodes   []core.Node
+	indices map[int]int
+	costs   [][]float64
+	next    [][]int // The index of the node after i on the shortest path from i to j, or -1 if there's no path
+}
+
+// Floyd-Warshall's Algorithm finds the shortest paths between every pair of nodes in O(n^3) time and O(n^2) memory, regardless of the number of edges, which makes it the right choice
+// for dense graphs (use Johnson for sparse ones). Like Bellman-Ford it allows negative costs, and aborted is true (and paths nil) if the graph contains a negative cycle, since some
+// shortest paths would then be unbounded. Self loops are ignored unless they're negative, in which case they're a negative cycle. On a DenseGraph with its own costs the edges are read
+// straight from its matrix.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func FloydWarshall(graph core.Graph, Cost func(core.Node, core.Node) float64) (paths *AllShortestPaths, aborted bool) {
+	dense, useMatrix := graph.(*simple.DenseGraph)
+	useMatrix = useMatrix && Cost == nil
+	Cost = graphutil.DefaultCost(graph, Cost)
+	nodes, indices := graphutil.IndexNodes(graph)
+	n := len(nodes)
+
+	costs := linalg.NewMatrix(n, n)
+	next := make([][]int, n)
+	for i := range costs {
+		next[i] = make([]int, n)
+		for j := range costs[i] {
+			costs[i][j], next[i][j] = math.Inf(1), -1
+		}
+		costs[i][i], next[i][i] = 0, i
+	}
+	if useMatrix {
+		// Read every pair's cost straight out of the matrix, rather than building every row's successors
+		for i, node := range nodes {
+			for j, succ := range nodes {
+				if cost := dense.Cost(node, succ); cost < costs[i][j] {
+					costs[i][j], next[i][j] = cost, j
+				}
+			}
+		}
+	} else {
+		for i, node := range nodes {
+			for _, succ := range graph.Successors(node) {
+				j, ok := indices[succ.ID()]
+				if !ok {
+					continue
+				}
+				if cost := Cost(node, succ); cost < costs[i][j] {
+					costs[i][j], next[i][j] = cost, j
+				}
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if math.IsInf(costs[i][k], 1) {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if tmp := costs[i][k] + costs[k][j]; tmp < costs[i][j] {
+					costs[i][j], next[i][j] = tmp, next[i][k]
+				}
+			}
+		}
+	}
+
+	for i := range costs {
+		if costs[i][i] < 0 {
+			return nil, true
+		}
+	}
+
+	return &AllShortestPaths{nodes, indices, costs, next}, false
+}
+
+// Returns the cost of the shortest path from u to v, which is 0 if they're the same node and +Inf if there's no path (or either isn't in the graph)
+func (asp *AllShortestPaths) Cost(u, v core.Node) float64 {
+	i, ok := asp.indices[u.ID()]
+	if !ok {
+		return math.Inf(1)
+	}
+	j, ok := asp.indices[v.ID()]
+	if !ok {
+		return math.Inf(1)
+	}
+
+	return asp.costs[i][j]
+}
+
+// Returns the shortest path from u to v, including both, or nil if there's no path
+func (asp *AllShortestPaths) Path(u, v core.Node) []core.Node {
+	i, ok := asp.indices[u.ID()]
+	if !ok {
+		return nil
+	}
+	j, ok := asp.indices[v.ID()]
+	if !ok || asp.next[i][j] == -1 {
+		return nil
+	}
+
+	path := []core.Node{asp.nodes[i]}
+	for i != j {
+		i = asp.next[i][j]
+		path = append(path, asp.nodes[i])
+	}
+
+	return path
+}
+
+// Returns the nodes sorted by ID and the matrix of shortest path costs between them, so that costs[i][j] is the cost from nodes[i] to nodes[j]. The matrix is shared with asp, so it
+// shouldn't be modified.
+func (asp *AllShortestPaths) Matrix() (nodes []core.Node, costs [][]float64) {
+	return append([]core.Node(nil), asp.nodes...), asp.costs
+}
+
+// Returns the shortest path costs from every node to every node it can reach, keyed by the nodes' IDs, in the same form Johnson returns them
+func (asp *AllShortestPaths) Costs() map[int]map[int]float64 {
+	costs := make(map[int]map[int]float64, len(asp.nodes))
+	for i, u := range asp.nodes {
+		costs[u.ID()] = make(map[int]float64)
+		for j, v := range asp.nodes {
+			if !math.IsInf(asp.costs[i][j], 1) {
+				costs[u.ID()][v.ID()] = asp.costs[i][j]
+			}
+		}
+	}
+
+	return costs
+}
+
+// Returns the matrix of shortest path costs between every pair of the given nodes, so that matrix[i][j] is the cost of the cheapest path from nodes[i] to nodes[j] (+Inf if there's none,
+// and 0 on the diagonal). It runs a Dijkstra search from each node that stops once all the other nodes have been reached, so it's much cheaper than all pairs shortest paths when the
+// subset is small. Like Dijkstra, it requires non-negative costs. The result is the usual input for TSP heuristics and distance-based clustering.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func DistanceMatrix(graph core.Graph, nodes []core.Node, Cost func(core.Node, core.Node) float64) [][]float64 {
+	return ParallelDistanceMatrix(graph, nodes, Cost, 1)
+}
+
+// Like DistanceMatrix, but runs the searches on up to workers goroutines (workers <= 0 means runtime.GOMAXPROCS(0)). The graph's read methods and Cost are called concurrently, see ParallelBFS.
+func ParallelDistanceMatrix(graph core.Graph, nodes []core.Node, Cost func(core.Node, core.Node) float64, workers int) [][]float64 {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	workers = graphutil.Workers(workers)
+
+	targets := make(map[int][]int, len(nodes)) // Node ID -> its columns, since the same node may be listed twice
+	for j, node := range nodes {
+		targets[node.ID()] = append(targets[node.ID()], j)
+	}
+
+	matrix := linalg.NewMatrix(len(nodes), len(nodes))
+	graphutil.ParallelRange(len(nodes), workers, func(_, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			row := matrix[i]
+			for j := range row {
+				row[j] = math.Inf(1)
+			}
+			if !graph.NodeExists(nodes[i]) {
+				continue
+			}
+
+			remaining := len(targets)
+			dijkstraTargets(nodes[i], graph, Cost, func(node core.Node, cost float64) bool {
+				if columns, ok := targets[node.ID()]; ok {
+					for _, j := range columns {
+						row[j] = cost
+					}
+					remaining--
+				}
+				return remaining > 0
+			})
+		}
+	})
+
+	return matrix
+}
+
+// RouteVia is exact for up to this many waypoints, and heuristic beyond
+const routeViaExactWaypoints = 12
+
+// Returns the cheapest path from start to goal that visits every waypoint, in whichever order is cheapest, along with its cost and the order, as indices into waypoints. Delivery
+// rounds and patrols need this. Returns a nil path if some waypoint can't be reached or can't reach the goal. Costs must not be negative.
+//
+// The costs between the ends and the waypoints are found with DistanceMatrix, then the order is chosen from them: exactly, by dynamic programming over the subsets of waypoints
+// (Held-Karp), for up to 12 waypoints, and otherwise by visiting the nearest unvisited waypoint each time and then reversing stretches of the order (2-opt) while that makes it
+// cheaper, which is quick but may miss the best order. Finally the legs are found with DijkstraPath and joined. The path may pass through a waypoint before the order visits it.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func RouteVia(start core.Node, waypoints []core.Node, goal core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) (path []core.Node, cost float64, order []int) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	// The matrix's rows and columns are the waypoints, then start, then goal
+	k := len(waypoints)
+	nodes := append(append(append([]core.Node(nil), waypoints...), start), goal)
+	dist := DistanceMatrix(graph, nodes, Cost)
+	if k <= routeViaExactWaypoints {
+		order = routeViaExact(dist, k)
+	} else {
+		order = routeViaHeuristic(dist, k)
+	}
+	if order == nil || math.IsInf(routeViaCost(dist, k, order), 1) {
+		return nil, 0.0, nil
+	}
+
+	stops := make([]core.Node, 0, k+2)
+	stops = append(stops, start)
+	for _, i := range order {
+		stops = append(stops, waypoints[i])
+	}
+	stops = append(stops, goal)
+
+	path = []core.Node{start}
+	for i := 1; i < len(stops); i++ {
+		leg, legCost, _ := DijkstraPath(stops[i-1], stops[i], graph, Cost)
+		if leg == nil {
+			return nil, 0.0, nil
+		}
+		path = append(path, leg[1:]...)
+		cost += legCost
+	}
+
+	return path, cost, order
+}
+
+// Returns the cost of visiting the waypoints in the given order, from start (index k of dist) to goal (index k+1)
+func routeViaCost(dist [][]float64, k int, order []int) float64 {
+	cost, prev := 0.0, k
+	for _, i := range order {
+		cost += dist[prev][i]
+		prev = i
+	}
+
+	return cost + dist[prev][k+1]
+}
+
+// Returns the cheapest order of the waypoints, by Held-Karp: best[set][last] is the cost of the cheapest route from start through the waypoints in set that ends at last. Returns nil
+// if no order reaches the goal.
+func routeViaExact(dist [][]float64, k int) []int {
+	if k == 0 {
+		return []int{}
+	}
+
+	best := make([][]float64, 1<<uint(k))
+	via := make([][]int, 1<<uint(k))
+	for set := range best {
+		best[set] = make([]float64, k)
+		via[set] = make([]int, k)
+		for last := range best[set] {
+			best[set][last] = math.Inf(1)
+		}
+	}
+	for last := 0; last < k; last++ {
+		best[1<<uint(last)][last] = dist[k][last]
+	}
+
+	for set := 1; set < len(best); set++ {
+		for last := 0; last < k; last++ {
+			if set&(1<<uint(last)) == 0 || math.IsInf(best[set][last], 1) {
+				continue
+			}
+			for next := 0; next < k; next++ {
+				if set&(1<<uint(next)) != 0 {
+					continue
+				}
+				grown := set | 1<<uint(next)
+				if cost := best[set][last] + dist[last][next]; cost < best[grown][next] {
+					best[grown][next] = cost
+					via[grown][next] = last
+				}
+			}
+		}
+	}
+
+	// Pick the best last waypoint, then follow the choices back
+	full, last := len(best)-1, 0
+	for i := 1; i < k; i++ {
+		if best[full][i]+dist[i][k+1] < best[full][last]+dist[last][k+1] {
+			last = i
+		}
+	}
+	if math.IsInf(best[full][last]+dist[last][k+1], 1) {
+		return nil
+	}
+	order := make([]int, k)
+	for set, i := full, k-1; i >= 0; i-- {
+		order[i] = last
+		set, last = set&^(1<<uint(last)), via[set][last]
+	}
+
+	return order
+}
+
+// Returns a good order of the waypoints: the nearest neighbor order, improved by 2-opt
+func routeViaHeuristic(dist [][]float64, k int) []int {
+	order := make([]int, 0, k)
+	visited := make([]bool, k)
+	for prev := k; len(order) < k; {
+		next := -1
+		for i := 0; i < k; i++ {
+			if !visited[i] && (next == -1 || dist[prev][i] < dist[prev][next]) {
+				next = i
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+		prev = next
+	}
+
+	// Reversing a stretch changes the direction of every leg in it, which matters on a directed graph, so each candidate is costed in full
+	cost := routeViaCost(dist, k, order)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < k-1; i++ {
+			for j := i + 1; j < k; j++ {
+				reverseInts(order[i : j+1])
+				if c := routeViaCost(dist, k, order); c < cost {
+					cost, improved = c, true
+				} else {
+					reverseInts(order[i : j+1])
+				}
+			}
+		}
+	}
+
+	return order
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// A Dijkstra search that only tracks costs, calling settled with each node's final cost in order of increasing cost, and stopping early once settled returns false
+func dijkstraTargets(source core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64, settled func(node core.Node, cost float64) bool) {
+	queue := container.NewIndexedHeap()
+	costs := map[int]float64{source.ID(): 0}
+	closed := make(map[int]bool)
+	nodeIDMap := map[int]core.Node{source.ID(): source}
+	queue.Push(source.ID(), 0)
+
+	var successors []core.Node
+	for !queue.IsEmpty() {
+		id, cost := queue.Pop()
+		node := nodeIDMap[id]
+		closed[id] = true
+		if !settled(node, cost) {
+			return
+		}
+
+		successors = graphutil.SuccessorsAppend(graph, node, successors[:0])
+		for _, neighbor := range successors {
+			nid := neighbor.ID()
+			if closed[nid] {
+				continue
+			}
+
+			tmpCost := cost + Cost(node, neighbor)
+			if best, ok := costs[nid]; !ok || tmpCost < best {
+				costs[nid] = tmpCost
+				nodeIDMap[nid] = neighbor
+				queue.Push(nid, tmpCost)
+			}
+		}
+	}
+}
+
+// Expands the first node it sees trying to find the destination. Depth First Search is *not* guaranteed to find the shortest path,
+// however, if a path exists DFS is guaranteed to find it (provided you don't find a way to implement a Graph with an infinite depth)
+func DepthFirstSearch(start, goal core.Node, graph core.Graph) []core.Node {
+	closedSet := set.NewSet()
+	openSet := xifo.GonumStack([]interface{}{start})
+	predecessor := make(map[int]core.Node)
+
+	for !openSet.IsEmpty() {
+		c := openSet.Pop()
+
+		curr := c.(core.Node)
+
+		if closedSet.Contains(curr.ID()) {
+			continue
+		}
+
+		if curr == goal {
+			return rebuildPath(predecessor, goal)
+		}
+
+		closedSet.Add(curr.ID())
+
+		for _, neighbor := range graph.Successors(curr) {
+			if closedSet.Contains(neighbor.ID()) {
+				continue
+			}
+
+			predecessor[neighbor.ID()] = curr
+			openSet.Push(neighbor)
+		}
+	}
+
+	return nil
+}
+
+// The integer equivalent of UniformCost, for use with DijkstraInt
+func UniformIntCost(a, b core.Node) int {
+	return 1
+}
+
+/** Keeps track of a node's scores so they can be used in a priority queue for A* **/
+
+type internalNode struct {
+	core.Node
+	gscore, fscore float64
+	seq            int // The order the node was pushed in, used by TieBreakFIFO
+	index          int // The node's dense index, only used by AStarInstance
+}
+
+type aStarPriorityQueue struct {
+	nodes    []internalNode
+	tieBreak TieBreak
+	pushed   int
+}
+
+func (pq *aStarPriorityQueue) Less(i, j int) bool {
+	a, b := pq.nodes[i], pq.nodes[j]
+	if a.fscore != b.fscore {
+		return a.fscore < b.fscore // As the heap documentation says, a priority queue is listed if the actual values are treated as if they were negative
+	}
+
+	switch pq.tieBreak {
+	case TieBreakNone:
+		if a.gscore != b.gscore {
+			return a.gscore > b.gscore
+		}
+		return a.ID() < b.ID()
+	case TieBreakLowID:
+		return a.ID() < b.ID()
+	case TieBreakHighG:
+		if a.gscore != b.gscore {
+			return a.gscore > b.gscore
+		}
+	}
+
+	return a.seq < b.seq // TieBreakFIFO, and the ties TieBreakHighG leaves
+}
+
+func (pq *aStarPriorityQueue) Swap(i, j int) {
+	pq.nodes[i], pq.nodes[j] = pq.nodes[j], pq.nodes[i]
+}
+
+func (pq *aStarPriorityQueue) Len() int {
+	return len(pq.nodes)
+}
+
+// The queue is managed by hand rather than through container/heap, since boxing every internalNode in an interface{} costs an allocation per push
+func (pq *aStarPriorityQueue) push(node internalNode) {
+	node.seq = pq.pushed
+	pq.pushed++
+	pq.nodes = append(pq.nodes, node)
+
+	for i := len(pq.nodes) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !pq.Less(i, parent) {
+			break
+		}
+		pq.Swap(i, parent)
+		i = parent
+	}
+}
+
+func (pq *aStarPriorityQueue) pop() internalNode {
+	x := pq.nodes[0]
+	last := len(pq.nodes) - 1
+	pq.nodes[0] = pq.nodes[last]
+	pq.nodes = pq.nodes[:last]
+
+	for i := 0; ; {
+		smallest := i
+		if l := 2*i + 1; l < last && pq.Less(l, smallest) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < last && pq.Less(r, smallest) {
+			smallest = r
+		}
+		if smallest == i {
+			break
+		}
+		pq.Swap(i, smallest)
+		i = smallest
+	}
+
+	return x
+}
+
+/** The closed set of a search, either a mapIntSet or a set.BitSet **/
+
+type intSet interface {
+	Add(int)
+	Contains(int) bool
+}
+
+type mapIntSet map[int]struct{}
+
+func (s mapIntSet) Add(el int) {
+	s[el] = struct{}{}
+}
+
+func (s mapIntSet) Contains(el int) bool {
+	_, ok := s[el]
+	return ok
+}
+
+/** The best known g scores of a search, either a sparseScoreMap or a denseScoreMap **/
+
+type scoreMap interface {
+	Get(id int) (float64, bool)
+	Set(id int, score float64)
+}
+
+type sparseScoreMap map[int]float64
+
+func (m sparseScoreMap) Get(id int) (float64, bool) {
+	score, ok := m[id]
+	return score, ok
+}
+
+func (m sparseScoreMap) Set(id int, score float64) {
+	m[id] = score
+}
+
+// Unset scores are NaN
+type denseScoreMap struct {
+	scores []float64
+}
+
+func (m *denseScoreMap) Get(id int) (float64, bool) {
+	if id >= len(m.scores) || math.IsNaN(m.scores[id]) {
+		return 0, false
+	}
+
+	return m.scores[id], true
+}
+
+func (m *denseScoreMap) Set(id int, score float64) {
+	if id >= len(m.scores) {
+		old := len(m.scores)
+		if id < cap(m.scores) {
+			m.scores = m.scores[:id+1]
+		} else {
+			grown := make([]float64, id+1, 2*(id+1))
+			copy(grown, m.scores)
+			m.scores = grown
+		}
+		for i := old; i < len(m.scores); i++ {
+			m.scores[i] = math.NaN()
+		}
+	}
+	m.scores[id] = score
+}
+
+// Rebuilds a path backwards from the goal.
+func rebuildPath(predecessors map[int]core.Node, goal core.Node) []core.Node {
+	path := []core.Node{goal}
+	curr := goal
+	for prev, ok := predecessors[curr.ID()]; ok; prev, ok = predecessors[curr.ID()] {
+		path = append(path, prev)
+		curr = prev
+	}
+
+	// Reverse the path since it was built backwards
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
@@ -0,0 +1,941 @@
+package path_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nathankerr/graph/container"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDijkstraInt(t *testing.T) {
+	g := simple.NewIntGraph(true)
+	edges := []struct{ h, t, cost int }{{0, 1, 4}, {0, 2, 1}, {2, 1, 2}, {1, 3, 1}, {2, 3, 5}, {3, 4, 0}}
+	g.AddNode(core.GonumNode(0), nil)
+	for _, e := range edges {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(e.h), T: core.GonumNode(e.t)})
+		g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(e.h), T: core.GonumNode(e.t)}, e.cost)
+	}
+	g.AddNode(core.GonumNode(5), nil)
+
+	paths, costs := path.DijkstraInt(core.GonumNode(0), g, nil)
+	for id, want := range map[int]int{0: 0, 1: 3, 2: 1, 3: 4, 4: 4} {
+		if costs[id] != want {
+			t.Errorf("Cost to %d is %d, want %d", id, costs[id], want)
+		}
+	}
+	if _, ok := costs[5]; ok {
+		t.Error("Unreachable node has a cost")
+	}
+
+	correctPath := []int{0, 2, 1, 3, 4}
+	if len(paths[4]) != len(correctPath) {
+		t.Fatalf("Path to 4 is %v, want %v", paths[4], correctPath)
+	}
+	for i, node := range paths[4] {
+		if node.ID() != correctPath[i] {
+			t.Fatalf("Path to 4 is %v, want %v", paths[4], correctPath)
+		}
+	}
+}
+
+func TestSimpleAStar(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀ ▀")
+	if err != nil {
+		t.Fatal("Couldn't generate tilegraph")
+	}
+
+	route, cost, _ := path.AStar(core.GonumNode(1), core.GonumNode(14), tg, nil, nil)
+	if math.Abs(cost-4.0) > .00001 {
+		t.Errorf("A* reports incorrect cost for simple tilegraph search")
+	}
+
+	if route == nil {
+		t.Fatalf("A* fails to find path for for simple tilegraph search")
+	} else {
+		correctPath := []int{1, 2, 6, 10, 14}
+		if len(route) != len(correctPath) {
+			t.Fatalf("Astar returns wrong length path for simple tilegraph search")
+		}
+		for i, node := range route {
+			if node.ID() != correctPath[i] {
+				t.Errorf("Astar returns wrong path at step %d got: %v actual: %d", i, node, correctPath[i])
+			}
+		}
+	}
+}
+
+func TestHarderAStar(t *testing.T) {
+	tg := simple.NewTileGraph(3, 3, true)
+
+	route, cost, _ := path.AStar(core.GonumNode(0), core.GonumNode(8), tg, nil, nil)
+
+	if math.Abs(cost-4.0) > .00001 || !path.IsPath(route, tg) {
+		t.Error("Non-optimal or impossible path found for 3x3 grid")
+	}
+
+	tg = simple.NewTileGraph(1000, 1000, true)
+	route, cost, _ = path.AStar(core.GonumNode(00), core.GonumNode(999*1000+999), tg, nil, nil)
+	if !path.IsPath(route, tg) || cost != 1998.0 {
+		t.Error("Non-optimal or impossible path found for 100x100 grid; cost:", cost, "path:\n"+tg.PathString(route))
+	}
+}
+
+func TestAStarOptions(t *testing.T) {
+	tg := simple.NewTileGraph(5, 5, true)
+	start, goal := core.GonumNode(0), core.GonumNode(24)
+
+	route, cost, _ := path.AStarWithOptions(start, goal, tg, nil)
+	if !path.IsPath(route, tg) || len(route) != 9 || cost != 8 {
+		t.Errorf("AStarWithOptions with nil options found cost %f, want 8", cost)
+	}
+
+	route, _, expanded := path.AStarWithOptions(start, goal, tg, &path.AStarOptions{MaxNodes: 3})
+	if route != nil || expanded != 3 {
+		t.Errorf("Node limit of 3 returned path %v after expanding %d nodes", route, expanded)
+	}
+
+	manhattan := func(a, b core.Node) float64 {
+		ar, ac := tg.IDToCoords(a.ID())
+		br, bc := tg.IDToCoords(b.ID())
+		return math.Abs(float64(ar-br)) + math.Abs(float64(ac-bc))
+	}
+	route, cost, _ = path.AStarWithOptions(start, goal, tg, &path.AStarOptions{HeuristicCost: manhattan, Epsilon: 1e-9})
+	if !path.IsPath(route, tg) || len(route) != 9 || cost != 8 {
+		t.Errorf("AStarWithOptions with a heuristic found cost %f, want 8", cost)
+	}
+}
+
+func TestAStarTieBreak(t *testing.T) {
+	// A diamond with two equally short paths, 0->1->3 and 0->2->3
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(2), core.GonumNode(1)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+
+	// The default and TieBreakLowID don't depend on the order the graph lists successors in, whether Dijkstra's Algorithm runs or the general search does
+	null := func(core.Node, core.Node) float64 { return 0 }
+	for i := 0; i < 20; i++ {
+		for _, opts := range []path.AStarOptions{{}, {HeuristicCost: null}, {TieBreak: path.TieBreakLowID}, {TieBreak: path.TieBreakLowID, HeuristicCost: null}} {
+			route, _, _ := path.AStarWithOptions(core.GonumNode(0), core.GonumNode(3), g, &opts)
+			if len(route) != 3 || route[1].ID() != 1 {
+				t.Fatalf("Tie break %d chose path %v, want [0 1 3]", opts.TieBreak, route)
+			}
+		}
+	}
+
+	tg := simple.NewTileGraph(10, 10, true)
+	for _, tieBreak := range []path.TieBreak{path.TieBreakNone, path.TieBreakHighG, path.TieBreakFIFO, path.TieBreakLowID} {
+		route, cost, _ := path.AStarWithOptions(core.GonumNode(0), core.GonumNode(99), tg, &path.AStarOptions{TieBreak: tieBreak})
+		if !path.IsPath(route, tg) || len(route) != 19 || cost != 18 {
+			t.Errorf("Tie break %d found cost %f, want 18", tieBreak, cost)
+		}
+	}
+}
+
+func TestAStarFastPaths(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("    ▀   \n ▀▀ ▀ ▀ \n    ▀ ▀ \n▀▀▀ ▀   \n      ▀ ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, _ := simple.AsMutable(tg)
+	for i, edge := range g.EdgeList() {
+		g.SetEdgeCost(edge, float64(1+i%3))
+	}
+	null := func(core.Node, core.Node) float64 { return 0 }
+
+	for _, goal := range []int{7, 16, 39} {
+		start, goal := core.GonumNode(0), core.GonumNode(goal)
+
+		// Passing the fallbacks explicitly forces the general search, which the fast paths match node for node with the same TieBreak
+		for _, tieBreak := range []path.TieBreak{path.TieBreakNone, path.TieBreakLowID, path.TieBreakFIFO} {
+			stats := &path.AStarStats{}
+			route, cost, expanded := path.AStarWithOptions(start, goal, plainGraph{tg}, &path.AStarOptions{Stats: stats, TieBreak: tieBreak})
+			want, wantCost, wantExpanded := path.AStarWithOptions(start, goal, tg, &path.AStarOptions{Cost: core.UniformCost, HeuristicCost: null, TieBreak: tieBreak})
+			if stats.Strategy != path.StrategyBFS || stats.NodesExpanded != expanded || fmt.Sprint(route) != fmt.Sprint(want) || cost != wantCost || expanded != wantExpanded {
+				t.Errorf("%s with tie break %d to %d found %v expanding %d nodes, want %v expanding %d", stats.Strategy, tieBreak, goal.ID(), route, expanded, want, wantExpanded)
+			}
+		}
+
+		for _, tieBreak := range []path.TieBreak{path.TieBreakNone, path.TieBreakLowID} {
+			stats := &path.AStarStats{}
+			route, cost, expanded := path.AStarWithOptions(start, goal, g, &path.AStarOptions{Stats: stats, DenseIDs: true, TieBreak: tieBreak})
+			want, wantCost, wantExpanded := path.AStarWithOptions(start, goal, g, &path.AStarOptions{HeuristicCost: null, TieBreak: tieBreak})
+			if stats.Strategy != path.StrategyDijkstra || stats.NodesExpanded != expanded || fmt.Sprint(route) != fmt.Sprint(want) || cost != wantCost || expanded != wantExpanded {
+				t.Errorf("%s with tie break %d to %d found %v expanding %d nodes, want %v expanding %d", stats.Strategy, tieBreak, goal.ID(), route, expanded, want, wantExpanded)
+			}
+		}
+	}
+
+	// The fast paths can't honor every TieBreak, and a heuristic always needs the general search
+	for _, c := range []struct {
+		graph core.Graph
+		opts  path.AStarOptions
+		want  path.AStarStrategy
+	}{
+		{plainGraph{g}, path.AStarOptions{TieBreak: path.TieBreakHighG}, path.StrategyBFS},
+		{plainGraph{g}, path.AStarOptions{TieBreak: path.TieBreakLowID}, path.StrategyBFS},
+		{plainGraph{g}, path.AStarOptions{Cost: core.UniformCost}, path.StrategyDijkstra},
+		{g, path.AStarOptions{TieBreak: path.TieBreakLowID}, path.StrategyDijkstra},
+		{g, path.AStarOptions{TieBreak: path.TieBreakFIFO}, path.StrategyAStar},
+		{tg, path.AStarOptions{}, path.StrategyAStar},
+	} {
+		stats := &path.AStarStats{}
+		c.opts.Stats = stats
+		if _, cost, _ := path.AStarWithOptions(core.GonumNode(0), core.GonumNode(39), c.graph, &c.opts); stats.Strategy != c.want || cost == 0 {
+			t.Errorf("%T with %+v ran %s, want %s", c.graph, c.opts, stats.Strategy, c.want)
+		}
+	}
+}
+
+func TestAStarStats(t *testing.T) {
+	tg := simple.NewTileGraph(10, 10, true)
+	start, goal := core.GonumNode(0), core.GonumNode(99)
+	null := func(core.Node, core.Node) float64 { return 0 }
+
+	// Every node the general search generates has its heuristic evaluated once, and the Manhattan distance steers it straight to the goal where no heuristic floods the grid
+	informed, blind := &path.AStarStats{}, &path.AStarStats{}
+	path.AStarWithOptions(start, goal, tg, &path.AStarOptions{TieBreak: path.TieBreakHighG, Stats: informed})
+	path.AStarWithOptions(start, goal, tg, &path.AStarOptions{HeuristicCost: null, TieBreak: path.TieBreakHighG, Stats: blind})
+	for _, stats := range []*path.AStarStats{informed, blind} {
+		if stats.Strategy != path.StrategyAStar || stats.HeuristicEvaluations != stats.NodesGenerated || stats.NodesGenerated < stats.NodesExpanded ||
+			stats.MaxOpenSize < 1 || stats.MaxOpenSize > stats.NodesGenerated || stats.Duration <= 0 {
+			t.Errorf("Inconsistent stats %+v", *stats)
+		}
+	}
+	if informed.NodesExpanded != 19 || blind.NodesExpanded <= informed.NodesExpanded || blind.HeuristicEvaluations <= informed.HeuristicEvaluations {
+		t.Errorf("Manhattan heuristic gave %+v, no heuristic gave %+v", *informed, *blind)
+	}
+
+	// The fast paths never evaluate the heuristic. The breadth first search generates each node once, and here reaches every node before expanding the far corner
+	stats := informed
+	path.AStarWithOptions(start, goal, plainGraph{tg}, &path.AStarOptions{Stats: stats})
+	if stats.Strategy != path.StrategyBFS || stats.HeuristicEvaluations != 0 || stats.NodesGenerated != 100 || stats.NodesExpanded != 100 || stats.MaxOpenSize > 10 {
+		t.Errorf("Breadth first search gave %+v", *stats)
+	}
+	path.AStarWithOptions(start, goal, plainGraph{tg}, &path.AStarOptions{Cost: core.UniformCost, Stats: stats})
+	if stats.Strategy != path.StrategyDijkstra || stats.HeuristicEvaluations != 0 || stats.NodesGenerated != 100 || stats.MaxOpenSize > stats.NodesGenerated {
+		t.Errorf("Dijkstra's Algorithm gave %+v", *stats)
+	}
+}
+
+func TestAStarMinimax(t *testing.T) {
+	// 0-1 is the cheapest route to 1 but a risky one; 0-2-3-1 costs more in total but none of its edges is as bad
+	g := simple.NewGonumGraph(false)
+	for id := 0; id < 4; id++ {
+		g.AddNode(core.GonumNode(id), nil)
+	}
+	for _, edge := range []struct {
+		head, tail int
+		cost       float64
+	}{{0, 1, 10}, {0, 2, 4}, {2, 3, 4}, {3, 1, 4}} {
+		e := core.GonumEdge{H: core.GonumNode(edge.head), T: core.GonumNode(edge.tail)}
+		g.AddEdge(e)
+		g.SetEdgeCost(e, edge.cost)
+	}
+	// A heuristic that would steer a summed search away from 2 is ignored
+	misleading := func(node, goal core.Node) float64 {
+		if node.ID() == 2 {
+			return 100
+		}
+		return 0
+	}
+
+	if route, cost, _ := path.AStarWithOptions(core.GonumNode(0), core.GonumNode(1), g, nil); len(route) != 2 || cost != 10 {
+		t.Errorf("Summed search found %v costing %v, want the direct edge", route, cost)
+	}
+	for _, c := range []struct {
+		opts path.AStarOptions
+		want path.AStarStrategy
+	}{
+		{path.AStarOptions{}, path.StrategyDijkstra},
+		{path.AStarOptions{TieBreak: path.TieBreakLowID}, path.StrategyDijkstra},
+		{path.AStarOptions{TieBreak: path.TieBreakFIFO}, path.StrategyAStar},
+		{path.AStarOptions{HeuristicCost: misleading}, path.StrategyDijkstra},
+	} {
+		stats := &path.AStarStats{}
+		c.opts.Stats, c.opts.Objective = stats, path.ObjectiveMinimax
+		route, cost, _ := path.AStarWithOptions(core.GonumNode(0), core.GonumNode(1), g, &c.opts)
+		if len(route) != 4 || route[1].ID() != 2 || cost != 4 || stats.Strategy != c.want {
+			t.Errorf("%v with %s found %v costing %v, want 0-2-3-1 costing 4 with %s", c.opts.Objective, stats.Strategy, route, cost, c.want)
+		}
+	}
+
+	// Without costs every path is as good as any other, and the start alone costs nothing
+	opts := &path.AStarOptions{Objective: path.ObjectiveMinimax}
+	if route, cost, _ := path.AStarWithOptions(core.GonumNode(0), core.GonumNode(1), plainGraph{g}, opts); !path.IsPath(route, g) || cost != 1 {
+		t.Errorf("Found %v costing %v with uniform costs", route, cost)
+	}
+	if route, cost, _ := path.AStarWithOptions(core.GonumNode(0), core.GonumNode(0), g, opts); len(route) != 1 || cost != 0 {
+		t.Errorf("Found %v costing %v from a node to itself", route, cost)
+	}
+}
+
+func TestIDAStar(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("    ▀   \n ▀▀ ▀ ▀ \n    ▀ ▀ \n▀▀▀ ▀   \n      ▀ ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetCost(2, 2, 4)
+
+	for _, goal := range []int{7, 16, 39} {
+		_, want, _ := path.AStar(core.GonumNode(0), core.GonumNode(goal), tg, nil, nil)
+		route, cost, _ := path.IDAStar(core.GonumNode(0), core.GonumNode(goal), tg, nil, nil)
+		if !path.IsShortestPath(route, tg, nil, 1e-9) || cost != want {
+			t.Errorf("Path to %d is %v costing %v, want cost %v", goal, route, cost, want)
+		}
+	}
+
+	if route, _, _ := path.IDAStar(core.GonumNode(0), core.GonumNode(4), tg, nil, nil); route != nil {
+		t.Errorf("Found path %v to a wall", route)
+	}
+}
+
+func TestBidirectionalAStar(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("    ▀   \n ▀▀ ▀ ▀ \n    ▀ ▀ \n▀▀▀ ▀   \n      ▀ ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetCost(2, 2, 4)
+
+	for _, goal := range []int{0, 7, 16, 39} {
+		for _, heuristic := range []func(core.Node, core.Node) float64{nil, core.NullHeuristic} {
+			_, want, _ := path.AStar(core.GonumNode(0), core.GonumNode(goal), tg, nil, nil)
+			route, cost, _ := path.BidirectionalAStar(core.GonumNode(0), core.GonumNode(goal), tg, nil, heuristic)
+			if !path.IsShortestPath(route, tg, nil, 1e-9) || route[0].ID() != 0 || route[len(route)-1].ID() != goal || cost != want {
+				t.Errorf("Path to %d is %v costing %v, want cost %v", goal, route, cost, want)
+			}
+		}
+	}
+	if route, _, _ := path.BidirectionalAStar(core.GonumNode(0), core.GonumNode(4), tg, nil, nil); route != nil {
+		t.Errorf("Found path %v to a wall", route)
+	}
+
+	// On an open grid without a heuristic, the two searches together expand far fewer nodes than one
+	open := simple.NewTileGraph(101, 101, true)
+	start, goal := open.CoordsToNode(50, 30), open.CoordsToNode(50, 70)
+	_, want, oneWay := path.DijkstraPath(start, goal, open, nil)
+	if route, cost, expanded := path.BidirectionalAStar(start, goal, open, nil, core.NullHeuristic); !path.IsPath(route, open) || cost != want || expanded >= oneWay*3/4 {
+		t.Errorf("Found cost %f expanding %d nodes, want cost %f with fewer than %d", cost, expanded, want, oneWay*3/4)
+	}
+
+	// A one-way shortcut, which the backward search has to take against its direction
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1, 3))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(4)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(0)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)}, 5)
+	if route, cost, _ := path.BidirectionalAStar(core.GonumNode(0), core.GonumNode(4), g, nil, nil); len(route) != 4 || route[2].ID() != 2 || cost != 3 {
+		t.Errorf("Found path %v costing %f, want 0 1 2 4 costing 3", route, cost)
+	}
+	if route, cost, _ := path.BidirectionalAStar(core.GonumNode(4), core.GonumNode(3), g, nil, nil); len(route) != 3 || cost != 2 {
+		t.Errorf("Found path %v costing %f, want 4 0 3 costing 2", route, cost)
+	}
+}
+
+func TestBoundedHopPath(t *testing.T) {
+	// The cheapest path from 0 to 4 is 0-1-2-3-4 costing 4; 0-5-4 costs 10 and 0-5-3-4 costs 7
+	g := simple.NewGonumGraph(true)
+	for id := 0; id < 6; id++ {
+		g.AddNode(core.GonumNode(id), nil)
+	}
+	for _, edge := range []struct {
+		head, tail int
+		cost       float64
+	}{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {0, 5, 5}, {5, 4, 5}, {5, 3, 1}} {
+		e := core.GonumEdge{H: core.GonumNode(edge.head), T: core.GonumNode(edge.tail)}
+		g.AddEdge(e)
+		g.SetEdgeCost(e, edge.cost)
+	}
+
+	for hops, want := range []float64{-1, -1, 10, 7, 4, 4} {
+		route, cost := path.BoundedHopPath(core.GonumNode(0), core.GonumNode(4), g, nil, hops)
+		if want == -1 {
+			if route != nil {
+				t.Errorf("%d hops: Got path %v, want none", hops, route)
+			}
+			continue
+		}
+		if cost != want || len(route)-1 > hops || !path.IsPath(route, g) || path.PathCost(route, g, nil) != cost {
+			t.Errorf("%d hops: Got path %v costing %v, want cost %v", hops, route, cost, want)
+		}
+	}
+	if route, cost := path.BoundedHopPath(core.GonumNode(0), core.GonumNode(0), g, nil, 0); len(route) != 1 || cost != 0 {
+		t.Errorf("Got path %v costing %v from a node to itself", route, cost)
+	}
+
+	// A negative cycle is taken as often as the hops allow
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(0)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(0)}, -5)
+	if route, cost := path.BoundedHopPath(core.GonumNode(0), core.GonumNode(4), g, nil, 9); cost != 3 || len(route) != 10 || !path.IsPath(route, g) {
+		t.Errorf("Got path %v costing %v around the negative cycle, want cost 3", route, cost)
+	}
+
+	// Without a bound that matters, it agrees with Dijkstra
+	tg := simple.NewTileGraph(6, 6, true)
+	tg.SetCost(2, 2, 4)
+	start := tg.CoordsToNode(0, 0)
+	want := path.DijkstraCosts(start, tg, nil)
+	for _, goal := range tg.NodeList() {
+		if _, cost := path.BoundedHopPath(start, goal, tg, nil, 36); cost != want[goal.ID()] {
+			t.Errorf("Cost to %d is %v, Dijkstra's is %v", goal.ID(), cost, want[goal.ID()])
+		}
+	}
+}
+
+func TestWidestPath(t *testing.T) {
+	// Links 0-1-3 carry 5 all the way, 0-2-3 only 2 despite its wide first hop, and 0-3 only 1
+	g := simple.NewGonumGraph(false)
+	for id := 0; id < 5; id++ {
+		g.AddNode(core.GonumNode(id), nil)
+	}
+	for _, edge := range []struct {
+		head, tail int
+		width      float64
+	}{{0, 1, 5}, {1, 3, 6}, {0, 2, 10}, {2, 3, 2}, {0, 3, 1}} {
+		e := core.GonumEdge{H: core.GonumNode(edge.head), T: core.GonumNode(edge.tail)}
+		g.AddEdge(e)
+		g.SetEdgeCost(e, edge.width)
+	}
+
+	route, width, _ := path.WidestPath(core.GonumNode(0), core.GonumNode(3), g, nil)
+	if len(route) != 3 || route[1].ID() != 1 || width != 5 {
+		t.Errorf("Got path %v of width %v, want 0-1-3 of width 5", route, width)
+	}
+	if route, width, _ := path.WidestPath(core.GonumNode(3), core.GonumNode(2), g, nil); len(route) != 4 || width != 5 {
+		t.Errorf("Got path %v of width %v, want 3-1-0-2 of width 5", route, width)
+	}
+	if route, width, _ := path.WidestPath(core.GonumNode(0), core.GonumNode(0), g, nil); len(route) != 1 || !math.IsInf(width, 1) {
+		t.Errorf("Got path %v of width %v from a node to itself", route, width)
+	}
+	if route, _, _ := path.WidestPath(core.GonumNode(0), core.GonumNode(4), g, nil); route != nil {
+		t.Errorf("Got path %v to an unreachable node", route)
+	}
+}
+
+func TestMostReliablePath(t *testing.T) {
+	// 0-1-3 succeeds with probability 0.9 * 0.5, 0-2-3 with 0.8 * 0.8, and 0-3 never
+	g := simple.NewGonumGraph(true)
+	for id := 0; id < 5; id++ {
+		g.AddNode(core.GonumNode(id), nil)
+	}
+	for _, edge := range []struct {
+		head, tail  int
+		probability float64
+	}{{0, 1, 0.9}, {1, 3, 0.5}, {0, 2, 0.8}, {2, 3, 0.8}, {0, 3, 0}, {4, 3, 1}} {
+		e := core.GonumEdge{H: core.GonumNode(edge.head), T: core.GonumNode(edge.tail)}
+		g.AddEdge(e)
+		g.SetEdgeCost(e, edge.probability)
+	}
+
+	route, p := path.MostReliablePath(core.GonumNode(0), core.GonumNode(3), g, nil)
+	if len(route) != 3 || route[1].ID() != 2 || math.Abs(p-0.64) > 1e-9 {
+		t.Errorf("Got path %v with probability %v, want 0-2-3 with 0.64", route, p)
+	}
+
+	g.RemoveNode(core.GonumNode(2))
+	g.RemoveNode(core.GonumNode(1))
+	if route, p := path.MostReliablePath(core.GonumNode(0), core.GonumNode(3), g, nil); route != nil || p != 0 {
+		t.Errorf("Got path %v with probability %v over an edge that always fails", route, p)
+	}
+	if route, p := path.MostReliablePath(core.GonumNode(4), core.GonumNode(3), g, nil); len(route) != 2 || p != 1 {
+		t.Errorf("Got path %v with probability %v over a certain edge", route, p)
+	}
+}
+
+func TestRouteVia(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("" +
+		"        \n" +
+		" ▀▀▀▀▀▀ \n" +
+		"      ▀ \n" +
+		" ▀▀▀▀ ▀ \n" +
+		"        ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, goal := tg.CoordsToNode(0, 0), tg.CoordsToNode(4, 0)
+	waypoints := []core.Node{tg.CoordsToNode(2, 5), tg.CoordsToNode(0, 7), tg.CoordsToNode(4, 7), tg.CoordsToNode(2, 0), tg.CoordsToNode(4, 4)}
+
+	// Every order, tried by brute force
+	nodes := append(append([]core.Node{start}, waypoints...), goal)
+	dist := path.DistanceMatrix(tg, nodes, nil)
+	want := math.Inf(1)
+	var permute func(order []int, used int)
+	permute = func(order []int, used int) {
+		if len(order) == len(waypoints) {
+			cost, prev := 0.0, 0
+			for _, i := range order {
+				cost += dist[prev][i+1]
+				prev = i + 1
+			}
+			want = math.Min(want, cost+dist[prev][len(nodes)-1])
+			return
+		}
+		for i := range waypoints {
+			if used&(1<<uint(i)) == 0 {
+				permute(append(order, i), used|1<<uint(i))
+			}
+		}
+	}
+	permute(nil, 0)
+
+	route, cost, order := path.RouteVia(start, waypoints, goal, tg, nil)
+	if cost != want || !path.IsPath(route, tg) || path.PathCost(route, tg, nil) != cost || route[0].ID() != start.ID() || route[len(route)-1].ID() != goal.ID() || len(order) != len(waypoints) {
+		t.Errorf("Got path %v costing %v in order %v, want cost %v", route, cost, order, want)
+	}
+	onPath := make(map[int]bool)
+	for _, node := range route {
+		onPath[node.ID()] = true
+	}
+	for _, waypoint := range waypoints {
+		if !onPath[waypoint.ID()] {
+			t.Errorf("The path %v misses waypoint %v", route, waypoint)
+		}
+	}
+
+	// Too many waypoints to try every order, scattered along a corridor
+	corridor := simple.NewTileGraph(1, 40, true)
+	waypoints = nil
+	for _, col := range []int{17, 3, 29, 8, 35, 22, 11, 38, 5, 26, 14, 31, 1, 19, 33, 9} {
+		waypoints = append(waypoints, corridor.CoordsToNode(0, col))
+	}
+	if route, cost, _ := path.RouteVia(corridor.CoordsToNode(0, 0), waypoints, corridor.CoordsToNode(0, 39), corridor, nil); len(route) != 40 || cost != 39 {
+		t.Errorf("Got path %v costing %v along the corridor, want cost 39", route, cost)
+	}
+
+	if route, _, _ := path.RouteVia(start, []core.Node{tg.CoordsToNode(1, 1)}, goal, tg, nil); route != nil {
+		t.Errorf("Got path %v through a wall", route)
+	}
+
+	// Each waypoint can be reached and can reach the goal, but neither can reach the other, so there's no route through both. The same goes for too many waypoints to order exactly
+	for _, k := range []int{2, 14} {
+		g := simple.NewGonumGraph(true)
+		g.AddNode(core.GonumNode(k+1), nil)
+		waypoints = nil
+		for id := 1; id <= k; id++ {
+			g.AddNode(core.GonumNode(id), []core.Node{core.GonumNode(k + 1)})
+			waypoints = append(waypoints, core.GonumNode(id))
+		}
+		g.AddNode(core.GonumNode(0), waypoints)
+		if route, cost, order := path.RouteVia(core.GonumNode(0), waypoints, core.GonumNode(k+1), g, nil); route != nil || order != nil {
+			t.Errorf("Got path %v costing %v in order %v through %d waypoints that can't all be visited", route, cost, order, k)
+		}
+	}
+	if route, cost, order := path.RouteVia(start, nil, goal, tg, nil); len(route) != 5 || cost != 4 || len(order) != 0 {
+		t.Errorf("Got path %v costing %v without waypoints", route, cost)
+	}
+}
+
+func TestIsochrone(t *testing.T) {
+	tg := simple.NewTileGraph(5, 5, true)
+	source := tg.CoordsToNode(2, 2)
+	distance := func(node core.Node) int {
+		row, col := tg.IDToCoords(node.ID())
+		return int(math.Abs(float64(row-2)) + math.Abs(float64(col-2)))
+	}
+
+	// Within 2 moves of the middle is a diamond of 13 tiles. The 8 tiles 3 moves away that are on the map each border two of them
+	for _, budget := range []float64{2, 2.5} {
+		reached, frontier := path.Isochrone(source, tg, nil, budget)
+		if len(reached) != 13 || reached[0] != source || len(frontier) != 16 {
+			t.Fatalf("Budget %v reached %d nodes with %d frontier edges, want 13 and 16", budget, len(reached), len(frontier))
+		}
+		for i, node := range reached {
+			if distance(node) > 2 || (i > 0 && distance(node) < distance(reached[i-1])) {
+				t.Errorf("Budget %v reached %v, which is %d moves away, out of order", budget, node, distance(node))
+			}
+		}
+		for _, edge := range frontier {
+			if distance(edge.Head()) != 2 || distance(edge.Tail()) != 3 {
+				t.Errorf("Frontier edge %v doesn't cross the budget", edge)
+			}
+		}
+	}
+
+	if reached, frontier := path.Isochrone(source, tg, nil, 0); len(reached) != 1 || len(frontier) != 4 {
+		t.Errorf("Budget 0 reached %v with frontier %v, want only the source", reached, frontier)
+	}
+	if reached, frontier := path.Isochrone(source, tg, nil, -1); reached != nil || frontier != nil {
+		t.Errorf("Negative budget reached %v with frontier %v", reached, frontier)
+	}
+
+	// Costs, not moves, bound the area: with every move into row 2 costing 3, a budget of 2 can't move along it
+	rowCost := func(a, b core.Node) float64 {
+		if row, _ := tg.IDToCoords(b.ID()); row == 2 {
+			return 3
+		}
+		return 1
+	}
+	if reached, _ := path.Isochrone(source, tg, rowCost, 2); len(reached) != 9 {
+		t.Errorf("Weighted budget reached %v, want the source and the 8 tiles within two moves of it off its row", reached)
+	}
+}
+
+func TestYenKSP(t *testing.T) {
+	// The example from Yen's paper as given on Wikipedia, with C D E F G H numbered 0 to 5
+	g := simple.NewGonumGraph(true)
+	for id := 0; id < 6; id++ {
+		g.AddNode(core.GonumNode(id), nil)
+	}
+	for _, edge := range []struct {
+		head, tail int
+		cost       float64
+	}{{0, 1, 3}, {0, 2, 2}, {1, 3, 4}, {2, 1, 1}, {2, 3, 2}, {2, 4, 3}, {3, 4, 2}, {3, 5, 1}, {4, 5, 2}} {
+		e := core.GonumEdge{H: core.GonumNode(edge.head), T: core.GonumNode(edge.tail)}
+		g.AddEdge(e)
+		g.SetEdgeCost(e, edge.cost)
+	}
+
+	paths, costs := path.YenKSP(core.GonumNode(0), core.GonumNode(5), g, nil, 3)
+	if len(paths) != 3 || costs[0] != 5 || costs[1] != 7 || costs[2] != 8 {
+		t.Fatalf("Got paths %v costing %v, want costs 5 7 8", paths, costs)
+	}
+	if want := []int{0, 2, 3, 5}; len(paths[0]) != len(want) || paths[0][1].ID() != 2 || paths[0][2].ID() != 3 {
+		t.Errorf("Got best path %v, want %v", paths[0], want)
+	}
+
+	// There are 7 loopless paths in all
+	paths, costs = path.YenKSP(core.GonumNode(0), core.GonumNode(5), g, nil, 20)
+	seen := make(map[string]bool)
+	for i, route := range paths {
+		onPath := make(map[int]bool)
+		for _, node := range route {
+			if onPath[node.ID()] {
+				t.Errorf("Path %v has a loop", route)
+			}
+			onPath[node.ID()] = true
+		}
+		if !path.IsPath(route, g) || path.PathCost(route, g, nil) != costs[i] || (i > 0 && costs[i] < costs[i-1]) || seen[fmt.Sprint(route)] {
+			t.Errorf("Path %d is %v costing %f", i, route, costs[i])
+		}
+		seen[fmt.Sprint(route)] = true
+	}
+	if len(paths) != 7 {
+		t.Errorf("Got %d paths, want 7", len(paths))
+	}
+
+	// Unit costs on a grid, where the paths are the routes around a wall
+	tg, err := simple.GenerateTileGraph("   \n ▀ \n   ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths, costs := path.YenKSP(core.GonumNode(0), core.GonumNode(8), tg, nil, 3); len(paths) != 2 || costs[0] != 4 || costs[1] != 4 {
+		t.Errorf("Got paths %v costing %v around the wall", paths, costs)
+	}
+	if paths, _ := path.YenKSP(core.GonumNode(0), core.GonumNode(4), tg, nil, 3); paths != nil {
+		t.Errorf("Found paths %v to a wall", paths)
+	}
+}
+
+func TestCostTolerance(t *testing.T) {
+	if !core.CostsEqual(0.1+0.2, 0.3, 1e-9) {
+		t.Error("0.1+0.2 and 0.3 not equal within tolerance")
+	}
+	if !core.CostsEqual(1e12, 1e12+1, 1e-9) {
+		t.Error("Large costs not compared relatively")
+	}
+	if core.CostsEqual(1, 1.01, 1e-9) || !core.CostsEqual(math.Inf(1), math.Inf(1), 0) {
+		t.Error("CostsEqual gives wrong answer for unequal or infinite costs")
+	}
+
+	tg := simple.NewTileGraph(20, 20, true)
+	route, _, _ := path.AStar(core.GonumNode(0), core.GonumNode(399), tg, nil, nil)
+	if !path.IsShortestPath(route, tg, nil, 1e-9) {
+		t.Error("A* path on open grid not reported as a shortest path")
+	}
+	detour := []core.Node{core.GonumNode(0), core.GonumNode(1), core.GonumNode(0), core.GonumNode(20)}
+	if path.IsShortestPath(detour, tg, nil, 1e-9) {
+		t.Error("Path with a detour reported as a shortest path")
+	}
+
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}, -1e-12)
+	if err := core.ValidateCosts(g, nil, 1e-9); err != nil {
+		t.Error("Cost within tolerance of zero rejected:", err)
+	}
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}, -1)
+	if err, ok := core.ValidateCosts(g, nil, 1e-9).(core.NegativeCostError); !ok || err.Cost != -1 {
+		t.Error("Negative cost not reported, got", err)
+	}
+}
+
+func TestDijkstraWithQueue(t *testing.T) {
+	tg := simple.NewTileGraph(20, 20, true)
+	tg.SetPassability(5, 0, false)
+	tg.SetPassability(5, 1, false)
+
+	_, want := path.Dijkstra(core.GonumNode(0), tg, nil)
+	for _, queue := range []container.PriorityQueue{container.NewPairingHeap(), container.NewBucketQueue(1)} {
+		_, costs := path.DijkstraWithQueue(core.GonumNode(0), tg, nil, queue)
+		if len(costs) != len(want) {
+			t.Fatalf("%T: reached %d nodes, want %d", queue, len(costs), len(want))
+		}
+		for id, cost := range want {
+			if costs[id] != cost {
+				t.Errorf("%T: cost to %d is %f, want %f", queue, id, costs[id], cost)
+			}
+		}
+	}
+
+	// Costs far apart in a queue with narrow buckets
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1), core.GonumNode(2)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, 1e15)
+	_, costs := path.DijkstraWithQueue(core.GonumNode(0), g, nil, container.NewBucketQueue(1))
+	if costs[1] != 1 || costs[2] != 1e15 {
+		t.Errorf("Got costs %v, want 1 to 1 and 1e15 to 2", costs)
+	}
+}
+
+func TestAStarDenseIDs(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀ ▀")
+	if err != nil {
+		t.Fatal("Couldn't generate tilegraph")
+	}
+
+	route, cost, expanded := path.AStarWithOptions(core.GonumNode(1), core.GonumNode(14), tg, &path.AStarOptions{DenseIDs: true})
+	_, wantCost, wantExpanded := path.AStar(core.GonumNode(1), core.GonumNode(14), tg, nil, nil)
+	if !path.IsPath(route, tg) || cost != wantCost || expanded != wantExpanded {
+		t.Errorf("Bitset closed set found cost %f expanding %d nodes, want %f expanding %d", cost, expanded, wantCost, wantExpanded)
+	}
+}
+
+func BenchmarkAStarMapClosedSet(b *testing.B) {
+	tg := simple.NewTileGraph(200, 200, true)
+	for i := 0; i < b.N; i++ {
+		path.AStarWithOptions(core.GonumNode(0), core.GonumNode(200*200-1), tg, nil)
+	}
+}
+
+func BenchmarkAStarBitSetClosedSet(b *testing.B) {
+	tg := simple.NewTileGraph(200, 200, true)
+	for i := 0; i < b.N; i++ {
+		path.AStarWithOptions(core.GonumNode(0), core.GonumNode(200*200-1), tg, &path.AStarOptions{DenseIDs: true})
+	}
+}
+
+func TestSearcherReuse(t *testing.T) {
+	searcher := path.NewSearcher()
+	tg := simple.NewTileGraph(10, 10, true)
+	tg.SetPassability(1, 0, false)
+	tg.SetPassability(1, 1, false)
+
+	for i, goal := range []int{99, 20, 9, 30, 55} {
+		opts := &path.AStarOptions{DenseIDs: i%2 == 0}
+		route, cost, _ := searcher.AStar(core.GonumNode(0), core.GonumNode(goal), tg, opts)
+		_, want, _ := path.AStar(core.GonumNode(0), core.GonumNode(goal), tg, nil, nil)
+		if !path.IsPath(route, tg) || route[len(route)-1].ID() != goal || cost != want {
+			t.Errorf("Search %d to %d with a reused Searcher found cost %f, want %f", i, goal, cost, want)
+		}
+	}
+}
+
+func TestAStarInstance(t *testing.T) {
+	tg := simple.NewTileGraph(15, 15, true)
+	for r := 0; r < 12; r++ {
+		tg.SetPassability(r, 7, false)
+	}
+
+	instance := path.NewAStarInstance(tg, nil)
+	for _, query := range [][2]int{{0, 14}, {0, 224}, {100, 3}, {14, 0}, {110, 110}} {
+		start, goal := core.GonumNode(query[0]), core.GonumNode(query[1])
+		route, cost, _ := instance.Search(start, goal, nil)
+		_, want, _ := path.AStar(start, goal, tg, nil, nil)
+		if !path.IsPath(route, tg) || route[0].ID() != query[0] || route[len(route)-1].ID() != query[1] || cost != want {
+			t.Errorf("Query %v found path %v with cost %f, want cost %f", query, route, cost, want)
+		}
+	}
+
+	if route, _, _ := instance.Search(core.GonumNode(0), core.GonumNode(7), nil); route != nil {
+		t.Error("Found a path to an impassable tile")
+	}
+}
+
+func TestDijkstraPath(t *testing.T) {
+	tg := simple.NewTileGraph(10, 10, true)
+	for r := 0; r < 9; r++ {
+		tg.SetPassability(r, 5, false)
+	}
+
+	costs := path.DijkstraCosts(core.GonumNode(0), tg, nil)
+	for _, goal := range []int{0, 9, 44, 99} {
+		route, cost, expanded := path.DijkstraPath(core.GonumNode(0), core.GonumNode(goal), tg, nil)
+		_, want, _ := path.AStar(core.GonumNode(0), core.GonumNode(goal), tg, nil, core.NullHeuristic)
+		if !path.IsPath(route, tg) || route[len(route)-1].ID() != goal || cost != want || costs[goal] != want {
+			t.Errorf("Path to %d is %v with cost %f (distance %f), want cost %f", goal, route, cost, costs[goal], want)
+		}
+		if expanded == 0 || expanded > len(costs) {
+			t.Errorf("Path to %d expanded %d nodes", goal, expanded)
+		}
+	}
+
+	if route, _, _ := path.DijkstraPath(core.GonumNode(0), core.GonumNode(5), tg, nil); route != nil {
+		t.Error("Found a path to an impassable tile")
+	}
+	if _, ok := costs[5]; ok || len(costs) != 91 {
+		t.Errorf("Got distances to %d nodes, want 91", len(costs))
+	}
+}
+
+func TestDistanceMatrix(t *testing.T) {
+	tg := simple.NewTileGraph(10, 10, true)
+	for r := 0; r < 9; r++ {
+		tg.SetPassability(r, 5, false)
+	}
+	tg.SetPassability(9, 9, false)
+
+	// Corners, a repeat, an impassable tile, and the far side of the wall
+	subset := []core.Node{core.GonumNode(0), core.GonumNode(9), core.GonumNode(90), core.GonumNode(0), core.GonumNode(99), core.GonumNode(6)}
+	for _, matrix := range [][][]float64{path.DistanceMatrix(tg, subset, nil), path.ParallelDistanceMatrix(tg, subset, nil, 4)} {
+		for i, from := range subset {
+			for j, to := range subset {
+				want := math.Inf(1)
+				if tg.NodeExists(from) && tg.NodeExists(to) {
+					_, want, _ = path.AStar(from, to, tg, nil, nil)
+				}
+				if matrix[i][j] != want {
+					t.Errorf("Distance from %d to %d is %f, want %f", from.ID(), to.ID(), matrix[i][j], want)
+				}
+			}
+		}
+	}
+}
+
+func TestAStarTrace(t *testing.T) {
+	tg := simple.NewTileGraph(3, 3, true)
+	trace := &path.AStarTrace{}
+	manhattan := func(a, b core.Node) float64 {
+		return math.Abs(float64(a.ID()/3-b.ID()/3)) + math.Abs(float64(a.ID()%3-b.ID()%3))
+	}
+	opts := &path.AStarOptions{HeuristicCost: manhattan, Trace: trace, TieBreak: path.TieBreakLowID}
+
+	_, _, expanded := path.AStarWithOptions(core.GonumNode(0), core.GonumNode(8), tg, opts)
+	if len(trace.Steps) != expanded || trace.Steps[0].Node.ID() != 0 || trace.Steps[0].Predecessor != nil || trace.Steps[expanded-1].Node.ID() != 8 {
+		t.Fatalf("Got trace %v for %d expansions", trace.Steps, expanded)
+	}
+	for _, step := range trace.Steps {
+		if step.F != step.G+step.H {
+			t.Errorf("Step %v has f != g + h", step)
+		}
+	}
+
+	// A second search through an instance replaces the trace
+	path.NewAStarInstance(tg, nil).Search(core.GonumNode(0), core.GonumNode(1), opts)
+	if len(trace.Steps) != 2 || trace.Steps[1].Predecessor.ID() != 0 || trace.Goal.ID() != 1 {
+		t.Errorf("Got trace %v after a second search", trace.Steps)
+	}
+
+	var buf bytes.Buffer
+	if err := trace.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"start":0,"goal":1,"steps":[{"id":0,"predecessor":null,"g":0,"h":1,"f":1},{"id":1,"predecessor":0,"g":1,"h":0,"f":1}]}` + "\n"; buf.String() != want {
+		t.Errorf("Got JSON %s, want %s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := trace.WriteDOT(&buf, tg, 1); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	if !strings.HasPrefix(dot, "digraph astar {") || !strings.Contains(dot, "fillcolor=orange") || strings.Contains(dot, "penwidth") {
+		t.Errorf("Got DOT for the first frame:\n%s", dot)
+	}
+}
+
+func TestBellmanFord(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	for _, e := range []struct {
+		h, t int
+		cost float64
+	}{{0, 1, 4}, {0, 2, 2}, {2, 1, -3}, {1, 3, 1}, {5, 6, -1}, {6, 5, -1}} {
+		g.AddNode(core.GonumNode(e.h), nil)
+		edge := core.GonumEdge{H: core.GonumNode(e.h), T: core.GonumNode(e.t)}
+		g.AddEdge(edge)
+		g.SetEdgeCost(edge, e.cost)
+	}
+	g.AddNode(core.GonumNode(4), nil)
+
+	// The negative cycle 5-6 can't be reached from 0, so it doesn't matter
+	paths, costs, aborted := path.BellmanFord(core.GonumNode(0), g, nil)
+	if aborted {
+		t.Fatal("Aborted on an unreachable negative cycle")
+	}
+	for id, want := range map[int]float64{0: 0, 1: -1, 2: 2, 3: 0} {
+		if got, ok := costs[id]; !ok || got != want {
+			t.Errorf("Node %d has cost %f, want %f", id, got, want)
+		}
+	}
+	if route := paths[3]; len(route) != 4 || route[1].ID() != 2 || !path.IsPath(route, g) {
+		t.Errorf("Path to 3 is %v, want through 2", route)
+	}
+	if _, ok := costs[4]; ok {
+		t.Error("Unreachable node has a cost")
+	}
+
+	// Make it reachable
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(5)})
+	_, costs, cycle := path.BellmanFordCycle(core.GonumNode(0), g, nil)
+	if costs != nil || len(cycle) != 3 || cycle[0].ID() != cycle[2].ID() || !path.IsPath(cycle, g) || path.PathCost(cycle, g, nil) >= 0 {
+		t.Errorf("Got negative cycle %v", cycle)
+	}
+}
+
+func TestAllPairsShortestPaths(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	for _, e := range []struct {
+		h, t int
+		cost float64
+	}{{0, 1, 4}, {0, 2, 2}, {2, 1, -3}, {1, 3, 1}, {3, 0, 5}} {
+		g.AddNode(core.GonumNode(e.h), nil)
+		edge := core.GonumEdge{H: core.GonumNode(e.h), T: core.GonumNode(e.t)}
+		g.AddEdge(edge)
+		g.SetEdgeCost(edge, e.cost)
+	}
+	g.AddNode(core.GonumNode(4), nil)
+
+	fw, aborted := path.FloydWarshall(g, nil)
+	if aborted {
+		t.Fatal("Floyd-Warshall aborted without a negative cycle")
+	}
+	paths, costs, aborted := path.Johnson(g, nil)
+	if aborted {
+		t.Fatal("Johnson aborted without a negative cycle")
+	}
+	if fwCosts := fw.Costs(); len(fwCosts[4]) != 1 || len(fwCosts[0]) != 4 {
+		t.Errorf("Got costs %v", fwCosts)
+	}
+
+	for u := 0; u < 5; u++ {
+		bf, bfCosts, _ := path.BellmanFord(core.GonumNode(u), g, nil)
+		for v := 0; v < 5; v++ {
+			want, ok := bfCosts[v]
+			if !ok {
+				want = math.Inf(1)
+			}
+			if got := fw.Cost(core.GonumNode(u), core.GonumNode(v)); got != want {
+				t.Errorf("Floyd-Warshall cost from %d to %d is %f, want %f", u, v, got, want)
+			}
+			if got, ok := costs[u][v]; ok != !math.IsInf(want, 1) || (ok && got != want) {
+				t.Errorf("Johnson cost from %d to %d is %f, want %f", u, v, got, want)
+			}
+
+			route := fw.Path(core.GonumNode(u), core.GonumNode(v))
+			if len(route) != len(bf[v]) || (route != nil && (path.PathCost(route, g, nil) != want || !path.IsPath(paths[u][v], g))) {
+				t.Errorf("Path from %d to %d is %v, want %v", u, v, route, bf[v])
+			}
+		}
+	}
+
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(0)}, -1)
+	if _, aborted := path.FloydWarshall(g, nil); !aborted {
+		t.Error("Floyd-Warshall didn't detect a negative cycle")
+	}
+}
@@ -0,0 +1,31 @@
+package path_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+)
+
+// Hides everything but the Graph methods
+type plainGraph struct {
+	core.Graph
+}
+
+// Builds an undirected path graph 0-1-2-...-(n-1)
+func pathGraph(n int) *simple.GonumGraph {
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nil)
+	for i := 1; i < n; i++ {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(i - 1), T: core.GonumNode(i)})
+	}
+
+	return g
+}
+
+func nodes(ids ...int) []core.Node {
+	nodes := make([]core.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = core.GonumNode(id)
+	}
+
+	return nodes
+}
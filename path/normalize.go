@@ -0,0 +1,104 @@
+package path
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"math"
+)
+
+/* Cost normalization. Imported datasets rarely come with costs that are ready to search on: they may be similarities rather than distances, or on wildly different scales. These helpers
+build a CostTransform from the graph's own statistics, which can then be applied as a view (TransformedCost gives a Cost function to pass to any algorithm) or in place (ApplyTransform).
+NodeWeightedCost is a view too, one that adds the cost of entering each node to the costs of the edges into it */
+
+// A CostTransform maps an edge's cost to a new one
+type CostTransform func(cost float64) float64
+
+// Returns a transform that linearly rescales the graph's current costs onto [lo, hi], so its cheapest edge maps to lo and its most expensive to hi. If every edge costs the same, they
+// all map to lo.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func MinMaxTransform(graph core.Graph, Cost func(core.Node, core.Node) float64, lo, hi float64) CostTransform {
+	stats := core.EdgeWeightStats(graph, Cost)
+	span := stats.Max - stats.Min
+
+	return func(cost float64) float64 {
+		if span == 0 {
+			return lo
+		}
+		return lo + (cost-stats.Min)/span*(hi-lo)
+	}
+}
+
+// Returns a transform that standardizes the graph's current costs to z-scores: (cost - mean) / standard deviation. If every edge costs the same, they all map to 0. Note that z-scores are
+// negative for below-average edges, which most shortest path algorithms in this package can't handle, so this is mostly useful as a step before further rescaling or for analysis.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func ZScoreTransform(graph core.Graph, Cost func(core.Node, core.Node) float64) CostTransform {
+	stats := core.EdgeWeightStats(graph, Cost)
+
+	return func(cost float64) float64 {
+		if stats.StdDev == 0 {
+			return 0
+		}
+		return (cost - stats.Mean) / stats.StdDev
+	}
+}
+
+// Turns similarities (or affinities, or strengths) into distances by taking their reciprocal, so strongly related nodes become close. This is the same relationship the clustering
+// algorithms assume between affinity and Cost. Zero similarities become +Inf.
+func ReciprocalTransform(cost float64) float64 {
+	return 1 / cost
+}
+
+// Turns probabilities (such as the reliability of a link, or the chance of crossing a stretch of ground unharmed) into costs by taking their negative logarithm, so that adding up the
+// costs of a path multiplies its probabilities and the cheapest path is the likeliest. A probability of 1 costs 0 and one of 0 costs +Inf; probabilities above 1 are taken as 1, so
+// no cost is negative.
+func NegLogTransform(probability float64) float64 {
+	return math.Max(0, -math.Log(probability))
+}
+
+// Returns a Cost function that applies the transform to the graph's costs on the fly, without changing the graph. Pass it as the Cost argument of any algorithm.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func TransformedCost(graph core.Graph, Cost func(core.Node, core.Node) float64, transform CostTransform) func(core.Node, core.Node) float64 {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	return func(node, succ core.Node) float64 {
+		return transform(Cost(node, succ))
+	}
+}
+
+// Returns a Cost function that adds the cost of entering a node to the cost of each edge into it, so that any search finds the paths that are cheapest counting both: a path costs its
+// edges plus its nodes, apart from the start, which the path doesn't enter. This models per-cell penalties on grid maps without repeating them on every edge into the cell. With
+// nonnegative node costs, a heuristic that's admissible for the edge costs alone still is.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost for the edge costs, and Argument > Interface > none for the node costs, so a nil
+// NodeCost uses the graph's NodeCost method if it's a NodeCoster and otherwise leaves the edge costs unchanged.
+func NodeWeightedCost(graph core.Graph, Cost func(core.Node, core.Node) float64, NodeCost func(core.Node) float64) func(core.Node, core.Node) float64 {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	if NodeCost == nil {
+		ngraph, ok := graph.(core.NodeCoster)
+		if !ok {
+			return Cost
+		}
+		NodeCost = ngraph.NodeCost
+	}
+
+	return func(node, succ core.Node) float64 {
+		return Cost(node, succ) + NodeCost(succ)
+	}
+}
+
+// Replaces the cost of every edge in the graph with its transformed cost. All the new costs are computed before any are set, so undirected edges (which EdgeList returns twice) are only
+// transformed once.
+func ApplyTransform(graph core.MutableGraph, transform CostTransform) {
+	edges := graph.EdgeList()
+	costs := make([]float64, len(edges))
+	for i, edge := range edges {
+		costs[i] = transform(graph.Cost(edge.Head(), edge.Tail()))
+	}
+
+	for i, edge := range edges {
+		graph.SetEdgeCost(edge, costs[i])
+	}
+}
@@ -0,0 +1,77 @@
+package path_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"testing"
+)
+
+func TestCostTransforms(t *testing.T) {
+	// Edges 0-1, 1-2 and 2-3 with costs 2, 4 and 6
+	g := pathGraph(4)
+	for i := 0; i < 3; i++ {
+		g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(i), T: core.GonumNode(i + 1)}, float64(2*i+2))
+	}
+
+	minMax := path.MinMaxTransform(g, nil, 0, 1)
+	if a, b, c := minMax(2), minMax(4), minMax(6); a != 0 || b != .5 || c != 1 {
+		t.Errorf("Min-max scaling gave %f, %f and %f, want 0, 0.5 and 1", a, b, c)
+	}
+
+	zScore := path.ZScoreTransform(g, nil)
+	if z := zScore(6); math.Abs(z-math.Sqrt(1.5)) > 1e-9 {
+		t.Errorf("The most expensive edge has z-score %f, want %f", z, math.Sqrt(1.5))
+	}
+
+	// As a view the graph is untouched
+	cost := path.TransformedCost(g, nil, path.ReciprocalTransform)
+	if c := cost(core.GonumNode(1), core.GonumNode(2)); c != .25 || g.Cost(core.GonumNode(1), core.GonumNode(2)) != 4 {
+		t.Errorf("Reciprocal view gave cost %f", c)
+	}
+
+	// In place, undirected edges are only transformed once
+	path.ApplyTransform(g, minMax)
+	if c := g.Cost(core.GonumNode(2), core.GonumNode(1)); c != .5 {
+		t.Errorf("Edge 2-1 has cost %f after min-max scaling, want 0.5", c)
+	}
+}
+
+// A graph whose nodes cost their ID to enter
+type nodeCostGraph struct {
+	core.Graph
+}
+
+func (g nodeCostGraph) NodeCost(node core.Node) float64 {
+	return float64(node.ID())
+}
+
+func TestNodeWeightedCost(t *testing.T) {
+	// Two routes from 0 to 3, through 1 or 2, whose edges cost the same
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1, 2))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}, 2)
+
+	// Entering 1 costs 5, which outweighs its cheaper edge
+	penalty := func(node core.Node) float64 {
+		if node.ID() == 1 {
+			return 5
+		}
+		return 0.5
+	}
+	route, cost, _ := path.DijkstraPath(core.GonumNode(0), core.GonumNode(3), g, path.NodeWeightedCost(g, nil, penalty))
+	if len(route) != 3 || route[1].ID() != 2 || cost != 3 {
+		t.Errorf("Got path %v costing %v, want 0-2-3 costing 3", route, cost)
+	}
+
+	// Without node costs the edge costs are unchanged; with a NodeCoster its costs are used
+	if cost := path.NodeWeightedCost(g, nil, nil)(core.GonumNode(0), core.GonumNode(1)); cost != 2 {
+		t.Errorf("Edge 0-1 costs %v without node costs, want 2", cost)
+	}
+	if cost := path.NodeWeightedCost(nodeCostGraph{g}, core.UniformCost, nil)(core.GonumNode(2), core.GonumNode(3)); cost != 4 {
+		t.Errorf("Edge 2-3 costs %v with node costs, want 4", cost)
+	}
+}
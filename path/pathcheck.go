@@ -0,0 +1,56 @@
+package path
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+)
+
+// Returns the total cost of following the given path. The path is assumed to be valid (see IsPath).
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func PathCost(path []core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64) float64 {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	cost := 0.0
+	for i := 0; i < len(path)-1; i++ {
+		cost += Cost(path[i], path[i+1])
+	}
+
+	return cost
+}
+
+// Returns true if, starting at path[0] and ending at path[len(path)-1], all nodes between are valid neighbors. That is, for each element path[i], path[i+1] is a valid successor
+//
+// Special case: a nil or zero length path is considered valid (true), a path of length 1 (only one node) is the trivial case, but only if the node listed in path exists.
+func IsPath(path []core.Node, graph core.Graph) bool {
+	if path == nil || len(path) == 0 {
+		return true
+	} else if len(path) == 1 {
+		return graph.NodeExists(path[0])
+	}
+
+	for i := 0; i < len(path)-1; i++ {
+		if !graph.IsSuccessor(path[i], path[i+1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns true if path is a valid path (see IsPath) and its cost is, to within epsilon (as in CostsEqual), the cost of the shortest path between its endpoints as found by Dijkstra's Algorithm.
+// This is mostly useful for checking the output of heuristic searches.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func IsShortestPath(path []core.Node, graph core.Graph, Cost func(core.Node, core.Node) float64, epsilon float64) bool {
+	if !IsPath(path, graph) {
+		return false
+	} else if len(path) < 2 {
+		return true
+	}
+
+	_, costs := Dijkstra(path[0], graph, Cost)
+	best, ok := costs[path[len(path)-1].ID()]
+
+	return ok && core.CostsEqual(PathCost(path, graph, Cost), best, epsilon)
+}
@@ -0,0 +1,66 @@
+package set
+
+// A BitSet is a set of non-negative integers stored as a bit array. For dense integer keys, such as the node IDs of a TileGraph, it is far smaller and faster than a Set since
+// adding an element is a bit operation rather than a map insertion. It grows as needed to hold the largest element added.
+type BitSet struct {
+	words []uint64
+}
+
+func NewBitSet(capacity int) *BitSet {
+	return &BitSet{words: make([]uint64, (capacity+63)/64)}
+}
+
+// Adds the element el to s. Panics if el is negative.
+func (s *BitSet) Add(el int) {
+	if el < 0 {
+		panic("Negative element added to BitSet")
+	}
+
+	word := el / 64
+	if word >= len(s.words) {
+		if word < cap(s.words) {
+			old := len(s.words)
+			s.words = s.words[:word+1]
+			for i := old; i < len(s.words); i++ {
+				s.words[i] = 0
+			}
+		} else {
+			grown := make([]uint64, word+1, 2*(word+1))
+			copy(grown, s.words)
+			s.words = grown
+		}
+	}
+	s.words[word] |= 1 << uint(el%64)
+}
+
+// Removes the element el from s
+func (s *BitSet) Remove(el int) {
+	if word := el / 64; el >= 0 && word < len(s.words) {
+		s.words[word] &^= 1 << uint(el%64)
+	}
+}
+
+// Returns true if el is an element of s
+func (s *BitSet) Contains(el int) bool {
+	word := el / 64
+	return el >= 0 && word < len(s.words) && s.words[word]&(1<<uint(el%64)) != 0
+}
+
+// Removes every element, keeping the allocated storage for reuse
+func (s *BitSet) Clear() {
+	for i := range s.words {
+		s.words[i] = 0
+	}
+}
+
+// Returns the number of elements in s
+func (s *BitSet) Cardinality() int {
+	count := 0
+	for _, word := range s.words {
+		for ; word != 0; word &= word - 1 {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,39 @@
+package set_test
+
+import (
+	"github.com/nathankerr/graph/set"
+	"testing"
+)
+
+func TestBitSet(t *testing.T) {
+	s := set.NewBitSet(10)
+	if s.Cardinality() != 0 || s.Contains(0) {
+		t.Fatal("New BitSet is not empty")
+	}
+
+	for _, el := range []int{0, 5, 63, 64, 1000} {
+		s.Add(el)
+	}
+	if s.Cardinality() != 5 {
+		t.Errorf("BitSet has cardinality %d after adding 5 elements", s.Cardinality())
+	}
+	for _, el := range []int{0, 5, 63, 64, 1000} {
+		if !s.Contains(el) {
+			t.Errorf("BitSet doesn't contain added element %d", el)
+		}
+	}
+	if s.Contains(1) || s.Contains(-1) || s.Contains(2000) {
+		t.Error("BitSet contains element that was never added")
+	}
+
+	s.Remove(64)
+	s.Remove(5000)
+	if s.Contains(64) || s.Cardinality() != 4 {
+		t.Error("BitSet Remove failed")
+	}
+
+	s.Clear()
+	if s.Cardinality() != 0 || s.Contains(1000) {
+		t.Error("BitSet not empty after Clear")
+	}
+}
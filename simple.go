@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"github.com/nathankerr/graph/simple"
+	"io"
+)
+
+// The types of package simple, under the names they had before the package was split
+type (
+	Side             = simple.Side
+	BipartiteGraph   = simple.BipartiteGraph
+	GonumGraph       = simple.GonumGraph
+	DenseGraph       = simple.DenseGraph
+	CostProvider     = simple.CostProvider
+	CostFunc         = simple.CostFunc
+	CostListener     = simple.CostListener
+	DynamicCostGraph = simple.DynamicCostGraph
+	FilteredGraph    = simple.FilteredGraph
+	Forest           = simple.Forest
+	FrozenGraph      = simple.FrozenGraph
+	HexTileGraph     = simple.HexTileGraph
+	HexDirection     = simple.HexDirection
+	Hypergraph       = simple.Hypergraph
+	ImplicitGraph    = simple.ImplicitGraph
+	IntGraph         = simple.IntGraph
+	Cursor           = simple.Cursor
+	Pager            = simple.Pager
+	ReversedGraph    = simple.ReversedGraph
+	ShardedGraph     = simple.ShardedGraph
+	LoggedGraph      = simple.LoggedGraph
+	Interval         = simple.Interval
+	SnapshotView     = simple.SnapshotView
+	TileGraph        = simple.TileGraph
+	TileAlphabet     = simple.TileAlphabet
+	TileCostModel    = simple.TileCostModel
+	DiagonalMovement = simple.DiagonalMovement
+	TileHeuristic    = simple.TileHeuristic
+	TileOverlay      = simple.TileOverlay
+	Direction        = simple.Direction
+)
+
+// The constants of package simple, under the names they had before the package was split
+const (
+	LeftSide              = simple.LeftSide
+	RightSide             = simple.RightSide
+	HexEast               = simple.HexEast
+	HexNorthEast          = simple.HexNorthEast
+	HexNorthWest          = simple.HexNorthWest
+	HexWest               = simple.HexWest
+	HexSouthWest          = simple.HexSouthWest
+	HexSouthEast          = simple.HexSouthEast
+	TileCostEnter         = simple.TileCostEnter
+	TileCostAverage       = simple.TileCostAverage
+	DiagonalNever         = simple.DiagonalNever
+	DiagonalAlways        = simple.DiagonalAlways
+	DiagonalAtMostOneWall = simple.DiagonalAtMostOneWall
+	DiagonalNoWalls       = simple.DiagonalNoWalls
+	HeuristicManhattan    = simple.HeuristicManhattan
+	HeuristicChebyshev    = simple.HeuristicChebyshev
+	HeuristicEuclidean    = simple.HeuristicEuclidean
+	HeuristicOctile       = simple.HeuristicOctile
+	North                 = simple.North
+	South                 = simple.South
+	West                  = simple.West
+	East                  = simple.East
+	NorthWest             = simple.NorthWest
+	NorthEast             = simple.NorthEast
+	SouthWest             = simple.SouthWest
+	SouthEast             = simple.SouthEast
+)
+
+// The variables of package simple, under the names they had before the package was split
+var (
+	ErrSameSide         = simple.ErrSameSide
+	ErrHasParent        = simple.ErrHasParent
+	ErrCycle            = simple.ErrCycle
+	ErrUndirected       = simple.ErrUndirected
+	HexDirections       = simple.HexDirections
+	DefaultTileAlphabet = simple.DefaultTileAlphabet
+	ASCIITileAlphabet   = simple.ASCIITileAlphabet
+	Directions          = simple.Directions
+	DiagonalDirections  = simple.DiagonalDirections
+)
+
+// See simple.NewBipartiteGraph
+func NewBipartiteGraph() *BipartiteGraph {
+	return simple.NewBipartiteGraph()
+}
+
+// See simple.Bipartition
+func Bipartition(graph Graph) (left, right []Node, ok bool) {
+	return simple.Bipartition(graph)
+}
+
+// See simple.HopcroftKarp
+func HopcroftKarp(graph Graph, left, right []Node) []Edge {
+	return simple.HopcroftKarp(graph, left, right)
+}
+
+// See simple.AsWeighted
+func AsWeighted(graph Graph) CostGraph {
+	return simple.AsWeighted(graph)
+}
+
+// See simple.AsDirected
+func AsDirected(graph Graph) Graph {
+	return simple.AsDirected(graph)
+}
+
+// See simple.AsMutable
+func AsMutable(graph Graph) (mutable MutableGraph, copied bool) {
+	return simple.AsMutable(graph)
+}
+
+// See simple.AsChecked
+func AsChecked(graph MutableGraph) CheckedMutableGraph {
+	return simple.AsChecked(graph)
+}
+
+// See simple.NewGonumGraph
+func NewGonumGraph(directed bool) *GonumGraph {
+	return simple.NewGonumGraph(directed)
+}
+
+// See simple.NewPreAllocatedGonumGraph
+func NewPreAllocatedGonumGraph(directed bool, numVertices int) *GonumGraph {
+	return simple.NewPreAllocatedGonumGraph(directed, numVertices)
+}
+
+// See simple.NewDenseGraph
+func NewDenseGraph(n int, directed bool) *DenseGraph {
+	return simple.NewDenseGraph(n, directed)
+}
+
+// See simple.NewDynamicCostGraph
+func NewDynamicCostGraph(graph Graph, provider CostProvider) *DynamicCostGraph {
+	return simple.NewDynamicCostGraph(graph, provider)
+}
+
+// See simple.NewFilteredGraph
+func NewFilteredGraph(graph Graph, node func(Node) bool, edge func(Edge) bool) *FilteredGraph {
+	return simple.NewFilteredGraph(graph, node, edge)
+}
+
+// See simple.Induce
+func Induce(graph Graph, nodes []Node) *FilteredGraph {
+	return simple.Induce(graph, nodes)
+}
+
+// See simple.NewForest
+func NewForest() *Forest {
+	return simple.NewForest()
+}
+
+// See simple.Frozen
+func Frozen(graph Graph) *FrozenGraph {
+	return simple.Frozen(graph)
+}
+
+// See simple.NewHexTileGraph
+func NewHexTileGraph(rows, cols int, passable bool) *HexTileGraph {
+	return simple.NewHexTileGraph(rows, cols, passable)
+}
+
+// See simple.HexDistance
+func HexDistance(q1, r1, q2, r2 int) int {
+	return simple.HexDistance(q1, r1, q2, r2)
+}
+
+// See simple.NewHypergraph
+func NewHypergraph() *Hypergraph {
+	return simple.NewHypergraph()
+}
+
+// See simple.NewImplicitGraph
+func NewImplicitGraph(successors func(Node) []Node, Cost, HeuristicCost func(Node, Node) float64) *ImplicitGraph {
+	return simple.NewImplicitGraph(successors, Cost, HeuristicCost)
+}
+
+// See simple.NewIntGraph
+func NewIntGraph(directed bool) *IntGraph {
+	return simple.NewIntGraph(directed)
+}
+
+// See simple.ParseCursor
+func ParseCursor(text string) (Cursor, error) {
+	return simple.ParseCursor(text)
+}
+
+// See simple.NodeListFrom
+func NodeListFrom(graph Graph, cursor Cursor, limit int) (nodes []Node, next Cursor) {
+	return simple.NodeListFrom(graph, cursor, limit)
+}
+
+// See simple.EdgeListFrom
+func EdgeListFrom(graph Graph, cursor Cursor, limit int) (edges []Edge, next Cursor) {
+	return simple.EdgeListFrom(graph, cursor, limit)
+}
+
+// See simple.Reverse
+func Reverse(graph Graph) Graph {
+	return simple.Reverse(graph)
+}
+
+// See simple.NewShardedGraph
+func NewShardedGraph(directed bool, shards int) *ShardedGraph {
+	return simple.NewShardedGraph(directed, shards)
+}
+
+// See simple.NewLoggedGraph
+func NewLoggedGraph(graph *GonumGraph, log io.Writer) *LoggedGraph {
+	return simple.NewLoggedGraph(graph, log)
+}
+
+// See simple.ReplayLog
+func ReplayLog(graph MutableGraph, log io.Reader) (mutations int, err error) {
+	return simple.ReplayLog(graph, log)
+}
+
+// See simple.Since
+func Since(start float64) Interval {
+	return simple.Since(start)
+}
+
+// See simple.NewTileGraph
+func NewTileGraph(dimX, dimY int, isPassable bool) *TileGraph {
+	return simple.NewTileGraph(dimX, dimY, isPassable)
+}
+
+// See simple.GenerateTileGraph
+func GenerateTileGraph(template string) (*TileGraph, error) {
+	return simple.GenerateTileGraph(template)
+}
+
+// See simple.GenerateTileGraphWithCosts
+func GenerateTileGraphWithCosts(template string, legend map[rune]float64) (*TileGraph, error) {
+	return simple.GenerateTileGraphWithCosts(template, legend)
+}
+
+// See simple.ParseTileGraph
+func ParseTileGraph(template string, alphabet TileAlphabet) (graph *TileGraph, start, goal Node, err error) {
+	return simple.ParseTileGraph(template, alphabet)
+}
+
+// See simple.LoadTileGraph
+func LoadTileGraph(r io.Reader) (*TileGraph, error) {
+	return simple.LoadTileGraph(r)
+}
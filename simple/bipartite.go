@@ -0,0 +1,288 @@
+package simple
+
+import (
+	"errors"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"sort"
+)
+
+// One of the two node sets of a BipartiteGraph
+type Side int
+
+const (
+	LeftSide Side = iota
+	RightSide
+)
+
+// Returned by BipartiteGraph.AddEdgeE for an edge between two nodes on the same side
+var ErrSameSide = errors.New("Edge joins two nodes on the same side of a bipartite graph")
+
+// A BipartiteGraph is an undirected GonumGraph whose nodes are split into a left and a right set, with edges only ever joining a left node to a right node; for example users and the items
+// they've rated, or authors and their papers. Besides the usual algorithms it supports one-mode projections, which relate the nodes of one side through the neighbors they share.
+//
+// Since AddNode takes a Side, a BipartiteGraph doesn't implement MutableGraph; use the embedded GonumGraph if you need to pass it to something that fills a MutableGraph (which won't respect
+// the sides).
+type BipartiteGraph struct {
+	*GonumGraph
+	sides map[int]Side
+}
+
+func NewBipartiteGraph() *BipartiteGraph {
+	return &BipartiteGraph{NewGonumGraph(false), make(map[int]Side)}
+}
+
+// Adds a node with no edges to the given side. Does nothing if the node already exists, even if it's on the other side.
+func (graph *BipartiteGraph) AddNode(node core.Node, side Side) {
+	if graph.NodeExists(node) {
+		return
+	}
+
+	graph.GonumGraph.AddNode(node, nil)
+	graph.sides[node.ID()] = side
+}
+
+// Like AddNode, but returns ErrNodeExists if the node is already in the graph, on either side
+func (graph *BipartiteGraph) AddNodeE(node core.Node, side Side) error {
+	if graph.NodeExists(node) {
+		return core.ErrNodeExists
+	}
+
+	graph.AddNode(node, side)
+	return nil
+}
+
+// Adds an edge between two existing nodes on opposite sides, in either order. Unlike GonumGraph.AddEdge, both nodes must already exist (since the side of a new node would be ambiguous), and
+// edges that don't meet these conditions are ignored; use AddEdgeE to find out why.
+func (graph *BipartiteGraph) AddEdge(e core.Edge) {
+	graph.AddEdgeE(e)
+}
+
+// Like AddEdge, but returns ErrNodeNotFound if either node doesn't exist, ErrSameSide if they're on the same side, or ErrEdgeExists if the edge is already in the graph.
+func (graph *BipartiteGraph) AddEdgeE(e core.Edge) error {
+	head, ok := graph.sides[e.Head().ID()]
+	if !ok {
+		return core.ErrNodeNotFound
+	}
+	tail, ok := graph.sides[e.Tail().ID()]
+	if !ok {
+		return core.ErrNodeNotFound
+	}
+	if head == tail {
+		return ErrSameSide
+	}
+
+	return graph.GonumGraph.AddEdgeE(e)
+}
+
+func (graph *BipartiteGraph) RemoveNode(node core.Node) {
+	graph.GonumGraph.RemoveNode(node)
+	delete(graph.sides, node.ID())
+}
+
+// Like RemoveNode, but returns ErrNodeNotFound if the node isn't in the graph
+func (graph *BipartiteGraph) RemoveNodeE(node core.Node) error {
+	if !graph.NodeExists(node) {
+		return core.ErrNodeNotFound
+	}
+
+	graph.RemoveNode(node)
+	return nil
+}
+
+func (graph *BipartiteGraph) EmptyGraph() {
+	graph.GonumGraph.EmptyGraph()
+	graph.sides = make(map[int]Side)
+}
+
+// Returns the side the node is on, and false if the node isn't in the graph
+func (graph *BipartiteGraph) Side(node core.Node) (side Side, ok bool) {
+	side, ok = graph.sides[node.ID()]
+	return side, ok
+}
+
+// Returns the nodes on one side, sorted by ID
+func (graph *BipartiteGraph) Nodes(side Side) []core.Node {
+	nodes := make(graphutil.NodeSorter, 0)
+	for id, s := range graph.sides {
+		if s == side {
+			nodes = append(nodes, graph.nodeMap[id])
+		}
+	}
+	sort.Sort(nodes)
+
+	return nodes
+}
+
+// Counts, for every pair of nodes on the given side, how many neighbors they have in common (e.g. how many items two users have both rated). Pairs without any shared neighbors are left
+// out. The keys are canonical undirected EdgeKeys, with the lower ID as the Head.
+func (graph *BipartiteGraph) SharedNeighborCounts(side Side) map[core.EdgeKey]int {
+	shared := make(map[core.EdgeKey]int)
+	for _, middle := range graph.Nodes(1 - side) {
+		neighbors := graphutil.NodeSorter(graph.Successors(middle))
+		sort.Sort(neighbors)
+		for i, a := range neighbors {
+			for _, b := range neighbors[i+1:] {
+				shared[core.EdgeKey{Head: a.ID(), Tail: b.ID()}]++
+			}
+		}
+	}
+
+	return shared
+}
+
+// Returns the weighted one-mode projection onto the given side: an undirected graph of that side's nodes, where two nodes are adjacent if they share at least one neighbor. As in
+// Hypergraph.CliqueExpansion, the cost of each edge is 1/(the number of shared neighbors), so nodes with more in common are closer, and their affinity in the clustering algorithms is
+// the shared count itself.
+func (graph *BipartiteGraph) Project(side Side) *GonumGraph {
+	return sharedCountGraph(graph.Nodes(side), graph.SharedNeighborCounts(side))
+}
+
+// Splits the nodes of a graph into two sides with no edges inside either, if that's possible, by two-coloring each connected component with a breadth first search. Edge directions are
+// ignored. The lowest ID node of each component goes on the left, and both sides are sorted by ID. Returns false, and nil sides, if the graph has an odd cycle (including a self loop), since
+// then it isn't bipartite.
+func Bipartition(graph core.Graph) (left, right []core.Node, ok bool) {
+	nodes := graphutil.NodeSorter(graph.NodeList())
+	sort.Sort(nodes)
+
+	sides := make(map[int]Side, len(nodes))
+	for _, root := range nodes {
+		if _, seen := sides[root.ID()]; seen {
+			continue
+		}
+
+		sides[root.ID()] = LeftSide
+		queue := []core.Node{root}
+		for k := 0; k < len(queue); k++ {
+			node := queue[k]
+			other := 1 - sides[node.ID()]
+			for _, neighbors := range [][]core.Node{graph.Successors(node), graph.Predecessors(node)} {
+				for _, neighbor := range neighbors {
+					side, seen := sides[neighbor.ID()]
+					if !seen {
+						sides[neighbor.ID()] = other
+						queue = append(queue, neighbor)
+					} else if side != other {
+						return nil, nil, false
+					}
+				}
+			}
+		}
+	}
+
+	left, right = make([]core.Node, 0), make([]core.Node, 0)
+	for _, node := range nodes {
+		if sides[node.ID()] == LeftSide {
+			left = append(left, node)
+		} else {
+			right = append(right, node)
+		}
+	}
+
+	return left, right, true
+}
+
+// Finds a maximum matching between the left and right nodes with the Hopcroft-Karp algorithm, in O(m sqrt(n)) time: the largest set of edges that pair left nodes with right nodes, with
+// no node in more than one pair. This solves assignment problems where every allowed pairing is equally good, such as workers to shifts they can cover; for weighted assignments use a
+// minimum cost flow instead.
+//
+// Any edge between a left node and a right node can be used, in either direction; edges within a side, and nodes that aren't in the graph, are ignored. The sides can come from a
+// BipartiteGraph's Nodes, or from Bipartition. Returns the matched pairs as edges from the left node to the right one, sorted by the left node's ID. Ties between maximum matchings are
+// always broken the same way for the same input.
+func HopcroftKarp(graph core.Graph, left, right []core.Node) []core.Edge {
+	lefts, rights := graphutil.NodeSorter(make([]core.Node, 0, len(left))), make(map[int]int, len(right))
+	seen := make(map[int]bool, len(left))
+	for _, node := range left {
+		if graph.NodeExists(node) && !seen[node.ID()] {
+			seen[node.ID()] = true
+			lefts = append(lefts, node)
+		}
+	}
+	sort.Sort(lefts)
+	rightNodes := make([]core.Node, 0, len(right))
+	for _, node := range right {
+		if _, ok := rights[node.ID()]; !ok && graph.NodeExists(node) && !seen[node.ID()] {
+			rights[node.ID()] = len(rightNodes)
+			rightNodes = append(rightNodes, node)
+		}
+	}
+
+	adjacent := make([][]int, len(lefts))
+	for i, node := range lefts {
+		linked := make(map[int]bool)
+		for _, neighbors := range [][]core.Node{graph.Successors(node), graph.Predecessors(node)} {
+			for _, neighbor := range neighbors {
+				if j, ok := rights[neighbor.ID()]; ok && !linked[j] {
+					linked[j] = true
+					adjacent[i] = append(adjacent[i], j)
+				}
+			}
+		}
+		sort.Ints(adjacent[i])
+	}
+
+	matchLeft := make([]int, len(lefts)) // The right index each left node is matched to, or -1
+	matchRight := make([]int, len(rightNodes))
+	for i := range matchLeft {
+		matchLeft[i] = -1
+	}
+	for j := range matchRight {
+		matchRight[j] = -1
+	}
+
+	// Each phase finds the shortest augmenting paths with a BFS from the free left nodes, then augments along a maximal set of disjoint ones with DFS
+	dist := make([]int, len(lefts))
+	var augment func(i int) bool
+	augment = func(i int) bool {
+		for _, j := range adjacent[i] {
+			if k := matchRight[j]; k == -1 || (dist[k] == dist[i]+1 && augment(k)) {
+				matchLeft[i], matchRight[j] = j, i
+				return true
+			}
+		}
+		dist[i] = -1 // Dead end for the rest of this phase
+
+		return false
+	}
+	for {
+		queue := make([]int, 0)
+		for i := range lefts {
+			if matchLeft[i] == -1 {
+				dist[i] = 0
+				queue = append(queue, i)
+			} else {
+				dist[i] = -1
+			}
+		}
+		found := false
+		for k := 0; k < len(queue); k++ {
+			i := queue[k]
+			for _, j := range adjacent[i] {
+				if next := matchRight[j]; next == -1 {
+					found = true
+				} else if dist[next] == -1 {
+					dist[next] = dist[i] + 1
+					queue = append(queue, next)
+				}
+			}
+		}
+		if !found {
+			break
+		}
+
+		for i := range lefts {
+			if matchLeft[i] == -1 {
+				augment(i)
+			}
+		}
+	}
+
+	matching := make([]core.Edge, 0)
+	for i, j := range matchLeft {
+		if j != -1 {
+			matching = append(matching, core.GonumEdge{H: lefts[i], T: rightNodes[j]})
+		}
+	}
+
+	return matching
+}
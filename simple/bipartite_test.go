@@ -0,0 +1,89 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func TestBipartiteGraph(t *testing.T) {
+	// Users 0, 1 and 2 rating items 10, 11 and 12
+	bg := simple.NewBipartiteGraph()
+	for _, user := range nodes(0, 1, 2) {
+		bg.AddNode(user, simple.LeftSide)
+	}
+	for _, item := range nodes(10, 11, 12) {
+		bg.AddNode(item, simple.RightSide)
+	}
+	for _, rating := range [][2]int{{0, 10}, {0, 11}, {1, 10}, {1, 11}, {2, 11}, {12, 2}} {
+		if err := bg.AddEdgeE(core.GonumEdge{H: core.GonumNode(rating[0]), T: core.GonumNode(rating[1])}); err != nil {
+			t.Fatalf("Adding rating %v: %v", rating, err)
+		}
+	}
+
+	if err := bg.AddEdgeE(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}); err != simple.ErrSameSide {
+		t.Errorf("Adding an edge within a side gave error %v", err)
+	}
+	if err := bg.AddEdgeE(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(13)}); err != core.ErrNodeNotFound {
+		t.Errorf("Adding an edge to a missing node gave error %v", err)
+	}
+
+	shared := bg.SharedNeighborCounts(simple.LeftSide)
+	if shared[core.EdgeKey{Head: 0, Tail: 1}] != 2 || shared[core.EdgeKey{Head: 0, Tail: 2}] != 1 || len(shared) != 3 {
+		t.Errorf("Got shared neighbor counts %v", shared)
+	}
+
+	users := bg.Project(simple.LeftSide)
+	if len(users.NodeList()) != 3 || users.Cost(core.GonumNode(0), core.GonumNode(1)) != .5 || users.Cost(core.GonumNode(1), core.GonumNode(2)) != 1 {
+		t.Errorf("Got user projection with edges %v", users.EdgeList())
+	}
+	items := bg.Project(simple.RightSide)
+	if items.IsAdjacent(core.GonumNode(10), core.GonumNode(12)) || !items.IsAdjacent(core.GonumNode(11), core.GonumNode(12)) {
+		t.Errorf("Got item projection with edges %v", items.EdgeList())
+	}
+
+	bg.RemoveNode(core.GonumNode(11))
+	if _, ok := bg.Side(core.GonumNode(11)); ok || len(bg.Nodes(simple.RightSide)) != 2 {
+		t.Error("Removed node is still on its side")
+	}
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	// Matching 0 to 3 first, as a greedy matching would, leaves 1 unmatched
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(3, 4))
+	g.AddNode(core.GonumNode(1), nodes(3))
+	g.AddNode(core.GonumNode(5), nodes(2)) // Backwards, which doesn't matter
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(4)})
+	g.AddNode(core.GonumNode(6), nil)
+
+	left, right, ok := simple.Bipartition(g)
+	if !ok || len(left) != 4 || len(right) != 3 {
+		t.Fatalf("Got bipartition %v | %v, %v", left, right, ok)
+	}
+
+	matching := simple.HopcroftKarp(g, nodes(0, 1, 2), nodes(3, 4, 5))
+	want := []core.EdgeKey{{Head: 0, Tail: 4}, {Head: 1, Tail: 3}, {Head: 2, Tail: 5}}
+	if len(matching) != len(want) {
+		t.Fatalf("Got matching %v, want %v", matching, want)
+	}
+	for i, e := range matching {
+		if e.Head().ID() != want[i].Head || e.Tail().ID() != want[i].Tail {
+			t.Errorf("Got matching %v, want %v", matching, want)
+		}
+	}
+
+	// A triangle is an odd cycle
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)})
+	if left, right, ok := simple.Bipartition(g); ok || left != nil || right != nil {
+		t.Errorf("Got bipartition %v | %v of a graph with a triangle", left, right)
+	}
+	// Which leaves the matching alone, since the new edge is within a side
+	if matching := simple.HopcroftKarp(g, nodes(0, 1, 2), nodes(3, 4, 5)); len(matching) != 3 {
+		t.Errorf("Got matching %v", matching)
+	}
+
+	if matching := simple.HopcroftKarp(g, nil, nodes(3, 4, 5)); len(matching) != 0 {
+		t.Errorf("Got matching %v with no left nodes", matching)
+	}
+}
@@ -0,0 +1,221 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+)
+
+// Every graph in this package implements Graph. This is which of the optional interfaces each implements as well:
+//
+//	                  Coster  HeuristicCoster  IntCoster  MutableGraph  SuccessorsAppender  DegreeCounter  MetadataHolder  EdgeIdentifier
+//	GonumGraph        yes                                 yes                               yes            yes             yes
+//	LoggedGraph       yes                                 yes
+//	IntGraph          yes                      yes                                          yes            yes             yes
+//	Forest            yes                                 yes                               yes            yes             yes
+//	BipartiteGraph    yes                                                                   yes            yes             yes
+//	TileGraph         yes     yes                                       yes                 yes
+//	HexTileGraph      yes     yes                                       yes                 yes
+//	SnapshotView      yes
+//	ImplicitGraph     yes     yes                                       yes
+//	DenseGraph        yes                                 yes                               yes
+//	FilteredGraph     yes     yes                                       yes
+//	ReversedGraph     yes     yes                                                           yes
+//	DynamicCostGraph  yes     yes                                       yes
+//	FrozenGraph       yes                                               yes                 yes            yes
+//
+// IntGraph and BipartiteGraph aren't MutableGraphs because their SetEdgeCost takes an int and their AddNode a Side. GonumGraph and Forest are also CheckedMutableGraphs. The
+// assertions below keep the table honest. Algorithms should take
+// the narrowest interface they can, usually Graph, and upgrade to a richer one when it's there, either with a type assertion or with AsWeighted, AsDirected, AsMutable and AsChecked,
+// which fall back to something that works when it isn't.
+var (
+	_ core.CheckedMutableGraph = (*GonumGraph)(nil)
+	_ core.CheckedMutableGraph = (*Forest)(nil)
+
+	_ core.MutableGraph   = (*GonumGraph)(nil)
+	_ core.DegreeCounter  = (*GonumGraph)(nil)
+	_ core.MetadataHolder = (*GonumGraph)(nil)
+	_ core.EdgeIdentifier = (*GonumGraph)(nil)
+	_ Pager               = (*GonumGraph)(nil)
+
+	_ core.MutableGraph = (*LoggedGraph)(nil)
+
+	_ core.CostGraph      = (*IntGraph)(nil)
+	_ core.IntCoster      = (*IntGraph)(nil)
+	_ core.DegreeCounter  = (*IntGraph)(nil)
+	_ core.MetadataHolder = (*IntGraph)(nil)
+	_ core.EdgeIdentifier = (*IntGraph)(nil)
+
+	_ core.MutableGraph = (*Forest)(nil)
+
+	_ core.CostGraph      = (*BipartiteGraph)(nil)
+	_ core.DegreeCounter  = (*BipartiteGraph)(nil)
+	_ core.MetadataHolder = (*BipartiteGraph)(nil)
+	_ core.EdgeIdentifier = (*BipartiteGraph)(nil)
+
+	_ core.HeuristicCoster    = (*TileGraph)(nil)
+	_ core.SuccessorsAppender = (*TileGraph)(nil)
+	_ core.DegreeCounter      = (*TileGraph)(nil)
+	_ core.Graph              = (*TileGraph)(nil)
+
+	_ core.HeuristicCoster    = (*HexTileGraph)(nil)
+	_ core.SuccessorsAppender = (*HexTileGraph)(nil)
+	_ core.DegreeCounter      = (*HexTileGraph)(nil)
+	_ core.Graph              = (*HexTileGraph)(nil)
+
+	_ core.CostGraph = (*SnapshotView)(nil)
+
+	_ core.HeuristicCoster    = (*ImplicitGraph)(nil)
+	_ core.SuccessorsAppender = (*ImplicitGraph)(nil)
+	_ core.Graph              = (*ImplicitGraph)(nil)
+
+	_ core.HeuristicCoster    = (*FilteredGraph)(nil)
+	_ core.SuccessorsAppender = (*FilteredGraph)(nil)
+	_ core.Graph              = (*FilteredGraph)(nil)
+
+	_ core.HeuristicCoster = (*ReversedGraph)(nil)
+	_ core.DegreeCounter   = (*ReversedGraph)(nil)
+
+	_ core.HeuristicCoster    = (*DynamicCostGraph)(nil)
+	_ core.SuccessorsAppender = (*DynamicCostGraph)(nil)
+	_ core.Graph              = (*DynamicCostGraph)(nil)
+
+	_ core.MutableGraph  = (*DenseGraph)(nil)
+	_ core.DegreeCounter = (*DenseGraph)(nil)
+	_ Pager              = (*DenseGraph)(nil)
+
+	_ core.CostGraph          = (*FrozenGraph)(nil)
+	_ core.SuccessorsAppender = (*FrozenGraph)(nil)
+	_ core.DegreeCounter      = (*FrozenGraph)(nil)
+	_ core.MetadataHolder     = (*FrozenGraph)(nil)
+	_ Pager                   = (*FrozenGraph)(nil)
+)
+
+// Returns the graph as a CostGraph: the graph itself if it's a Coster, or otherwise a view of it in which every edge costs 1, as UniformCost does. The view passes SuccessorsAppend
+// through to the graph.
+func AsWeighted(graph core.Graph) core.CostGraph {
+	if cgraph, ok := graph.(core.CostGraph); ok {
+		return cgraph
+	}
+
+	return graphView{graph, graph.IsDirected(), core.UniformCost}
+}
+
+// Returns the graph as a directed graph: the graph itself if it's directed, or otherwise a view of it in which every undirected edge is a pair of opposite arcs with the same cost.
+// Since an undirected graph already lists its edges in both directions, the view only changes what IsDirected reports, but that's enough for algorithms that treat the two kinds of
+// graph differently to see every arc: MarshalDOT writes both arcs rather than one undirected edge, and TopologicalSort finds each edge to be a cycle. The view is a Coster, with the
+// graph's costs if it has them and 1 otherwise, and passes SuccessorsAppend through.
+func AsDirected(graph core.Graph) core.Graph {
+	if graph.IsDirected() {
+		return graph
+	}
+
+	return graphView{graph, true, graphutil.DefaultCost(graph, nil)}
+}
+
+// Returns the graph as a MutableGraph: the graph itself if it's mutable, or otherwise a directed or undirected GonumGraph copy of it, with its costs and metadata (see CopyGraph). copied
+// reports which; changes to a copy don't reach the original graph. An undirected copy has one cost per edge, so costs that depend on the direction, like a TileGraph's after SetCost,
+// don't survive the copy.
+func AsMutable(graph core.Graph) (mutable core.MutableGraph, copied bool) {
+	if mgraph, ok := graph.(core.MutableGraph); ok {
+		return mgraph, false
+	}
+
+	gonum := NewGonumGraph(graph.IsDirected())
+	core.CopyGraph(gonum, graph)
+	return gonum, true
+}
+
+// Returns the graph as a CheckedMutableGraph: the graph itself if it is one, or otherwise a wrapper whose E methods check their arguments with NodeExists and IsSuccessor before calling
+// the graph's own unchecked methods, which the wrapper passes through unchanged. The wrapper can only check what any MutableGraph can be asked, so invariants of the graph's own (such as
+// a Forest's) still go unreported unless the graph implements the E methods itself.
+func AsChecked(graph core.MutableGraph) core.CheckedMutableGraph {
+	if cgraph, ok := graph.(core.CheckedMutableGraph); ok {
+		return cgraph
+	}
+
+	return checkedGraph{graph}
+}
+
+// The wrapper AsChecked returns for a MutableGraph without E methods
+type checkedGraph struct {
+	core.MutableGraph
+}
+
+func (graph checkedGraph) AddNodeE(node core.Node, successors []core.Node) error {
+	if graph.NodeExists(node) {
+		return core.ErrNodeExists
+	}
+
+	graph.AddNode(node, successors)
+	return nil
+}
+
+func (graph checkedGraph) AddEdgeE(e core.Edge) error {
+	if !graph.NodeExists(e.Head()) {
+		return core.ErrNodeNotFound
+	} else if graph.IsSuccessor(e.Head(), e.Tail()) {
+		return core.ErrEdgeExists
+	}
+
+	graph.AddEdge(e)
+	return nil
+}
+
+func (graph checkedGraph) SetEdgeCostE(e core.Edge, cost float64) error {
+	if !graph.NodeExists(e.Head()) {
+		return core.ErrNodeNotFound
+	} else if !graph.IsSuccessor(e.Head(), e.Tail()) {
+		return core.ErrEdgeNotFound
+	}
+
+	graph.SetEdgeCost(e, cost)
+	return nil
+}
+
+func (graph checkedGraph) RemoveNodeE(node core.Node) error {
+	if !graph.NodeExists(node) {
+		return core.ErrNodeNotFound
+	}
+
+	graph.RemoveNode(node)
+	return nil
+}
+
+func (graph checkedGraph) RemoveEdgeE(e core.Edge) error {
+	if !graph.NodeExists(e.Head()) || !graph.NodeExists(e.Tail()) {
+		return core.ErrNodeNotFound
+	} else if !graph.IsSuccessor(e.Head(), e.Tail()) {
+		return core.ErrEdgeNotFound
+	}
+
+	graph.RemoveEdge(e)
+	return nil
+}
+
+func (graph checkedGraph) SetDirectedE(directed bool) error {
+	if graph.IsDirected() != directed && len(graph.NodeList()) > 0 {
+		return core.ErrGraphNotEmpty
+	}
+
+	graph.SetDirected(directed)
+	return nil
+}
+
+// The view AsWeighted and AsDirected return when the graph itself won't do
+type graphView struct {
+	core.Graph
+	directed bool
+	cost     func(core.Node, core.Node) float64
+}
+
+func (view graphView) IsDirected() bool {
+	return view.directed
+}
+
+func (view graphView) Cost(node, succ core.Node) float64 {
+	return view.cost(node, succ)
+}
+
+func (view graphView) SuccessorsAppend(node core.Node, buf []core.Node) []core.Node {
+	return graphutil.SuccessorsAppend(view.Graph, node, buf)
+}
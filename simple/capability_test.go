@@ -0,0 +1,138 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"testing"
+)
+
+// Hides everything but the Graph methods
+type plainGraph struct {
+	core.Graph
+}
+
+func TestCapabilities(t *testing.T) {
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nodes(1))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, 3)
+	plain := plainGraph{g}
+
+	if weighted := simple.AsWeighted(g); weighted != core.CostGraph(g) {
+		t.Error("AsWeighted didn't return the GonumGraph itself")
+	}
+	weighted := simple.AsWeighted(plain)
+	if cost := weighted.Cost(core.GonumNode(1), core.GonumNode(2)); cost != 1 {
+		t.Errorf("Uniform view costs %v, want 1", cost)
+	}
+	if succs := weighted.(core.SuccessorsAppender).SuccessorsAppend(core.GonumNode(1), nil); len(succs) != 2 {
+		t.Errorf("Got successors %v", succs)
+	}
+
+	directed := simple.AsDirected(plain)
+	if !directed.IsDirected() || len(directed.EdgeList()) != 4 || directed.(core.Coster).Cost(core.GonumNode(2), core.GonumNode(1)) != 1 {
+		t.Errorf("Directed view of a plain graph is wrong, edges %v", directed.EdgeList())
+	}
+	if cost := simple.AsDirected(g).(core.Coster).Cost(core.GonumNode(2), core.GonumNode(1)); cost != 3 {
+		t.Errorf("Directed view costs %v, want 3", cost)
+	}
+	// Every undirected edge becomes a two-arc cycle
+	if _, err := topo.TopologicalSort(simple.AsDirected(g)); err == nil {
+		t.Error("Directed view has no cycles")
+	}
+	if d := simple.NewGonumGraph(true); simple.AsDirected(d) != core.Graph(d) {
+		t.Error("AsDirected didn't return a directed graph itself")
+	}
+
+	if mutable, copied := simple.AsMutable(g); copied || mutable != core.MutableGraph(g) {
+		t.Error("AsMutable copied a GonumGraph")
+	}
+	tg, err := simple.GenerateTileGraph("  \n ▀")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutable, copied := simple.AsMutable(tg)
+	if !copied || mutable.IsDirected() != tg.IsDirected() || len(mutable.NodeList()) != 3 || !mutable.IsSuccessor(core.GonumNode(1), core.GonumNode(0)) {
+		t.Errorf("Bad copy of a TileGraph, nodes %v and edges %v", mutable.NodeList(), mutable.EdgeList())
+	}
+}
+
+func TestCostFallback(t *testing.T) {
+	// The direct edge is the cheapest path by edge count, but not by cost
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1, 2))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, 10)
+	start, goal := core.GonumNode(0), core.GonumNode(2)
+
+	searches := map[string]func(core.Graph) float64{
+		"AStar": func(g core.Graph) float64 { _, cost, _ := path.AStar(start, goal, g, nil, nil); return cost },
+		"AStarInstance": func(g core.Graph) float64 {
+			_, cost, _ := path.NewAStarInstance(g, nil).Search(start, goal, nil)
+			return cost
+		},
+		"Dijkstra":          func(g core.Graph) float64 { _, costs := path.Dijkstra(start, g, nil); return costs[2] },
+		"DijkstraPath":      func(g core.Graph) float64 { _, cost, _ := path.DijkstraPath(start, goal, g, nil); return cost },
+		"DijkstraCosts":     func(g core.Graph) float64 { return path.DijkstraCosts(start, g, nil)[2] },
+		"DijkstraWithQueue": func(g core.Graph) float64 { _, costs := path.DijkstraWithQueue(start, g, nil, nil); return costs[2] },
+		"BellmanFord":       func(g core.Graph) float64 { _, costs, _ := path.BellmanFord(start, g, nil); return costs[2] },
+		"Johnson":           func(g core.Graph) float64 { _, costs, _ := path.Johnson(g, nil); return costs[0][2] },
+		"FloydWarshall":     func(g core.Graph) float64 { paths, _ := path.FloydWarshall(g, nil); return paths.Cost(start, goal) },
+		"DistanceMatrix":    func(g core.Graph) float64 { return path.DistanceMatrix(g, nodes(0, 2), nil)[0][1] },
+	}
+	for name, search := range searches {
+		if cost := search(g); cost != 2 {
+			t.Errorf("%s with a nil Cost on a Coster found a path costing %v, want 2", name, cost)
+		}
+		if cost := search(plainGraph{g}); cost != 1 {
+			t.Errorf("%s with a nil Cost on a plain graph found a path costing %v, want 1", name, cost)
+		}
+	}
+}
+
+// Hides everything but the MutableGraph methods, including the E methods
+type uncheckedGraph struct {
+	core.MutableGraph
+}
+
+func TestAsChecked(t *testing.T) {
+	g := simple.NewGonumGraph(false)
+	if checked := simple.AsChecked(g); checked != core.CheckedMutableGraph(g) {
+		t.Error("AsChecked wrapped a GonumGraph")
+	}
+	if err := simple.AsChecked(simple.NewForest()).SetDirectedE(false); err != simple.ErrUndirected {
+		t.Error("Making a forest undirected returned", err)
+	}
+
+	checked := simple.AsChecked(uncheckedGraph{g})
+	n0, n1 := core.GonumNode(0), core.GonumNode(1)
+	if err := checked.AddEdgeE(core.GonumEdge{H: n0, T: n1}); err != core.ErrNodeNotFound || g.NodeExists(n1) {
+		t.Error("Adding an edge from a missing node returned", err)
+	}
+	if err := checked.AddNodeE(n0, nodes(1)); err != nil {
+		t.Fatal("Adding a node returned", err)
+	}
+	if err := checked.AddNodeE(n1, nil); err != core.ErrNodeExists {
+		t.Error("Re-adding a node returned", err)
+	}
+	if err := checked.AddEdgeE(core.GonumEdge{H: n1, T: n0}); err != core.ErrEdgeExists {
+		t.Error("Adding the reverse of an undirected edge returned", err)
+	}
+	if err := checked.SetEdgeCostE(core.GonumEdge{H: n1, T: core.GonumNode(2)}, 3); err != core.ErrEdgeNotFound {
+		t.Error("Setting the cost of a missing edge returned", err)
+	}
+	if err := checked.SetDirectedE(true); err != core.ErrGraphNotEmpty || g.IsDirected() {
+		t.Error("Making a non-empty graph directed returned", err)
+	}
+	if err := checked.RemoveEdgeE(core.GonumEdge{H: n1, T: n0}); err != nil || g.IsAdjacent(n0, n1) {
+		t.Error("Removing an edge returned", err)
+	}
+	if err := checked.RemoveEdgeE(core.GonumEdge{H: n1, T: n0}); err != core.ErrEdgeNotFound {
+		t.Error("Removing a missing edge returned", err)
+	}
+	if err := checked.RemoveNodeE(core.GonumNode(5)); err != core.ErrNodeNotFound {
+		t.Error("Removing a missing node returned", err)
+	}
+}
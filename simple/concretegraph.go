@@ -0,0 +1,495 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"sort"
+)
+
+// A GonumGraph is a very generalized graph that can handle an arbitrary number of vertices and edges -- as well as act as either directed or undirected.
+//
+// Internally, it uses a map of successors AND predecessors, to speed up some operations (such as getting all successors/predecessors). It also speeds up thing like adding edges (assuming both edges exist).
+//
+// However, its generality is also its weakness (and partially a flaw in needing to satisfy MutableGraph). For most purposes, creating your own graph is probably better. For instance, see discrete.TileGraph for an example
+// of an immutable 2D grid of tiles that also implements the Graph interface, but would be more suitable if all you needed was a simple undirected 2D grid.
+type GonumGraph struct {
+	successors   map[int]map[int]float64
+	predecessors map[int]map[int]float64
+	nodeMap      map[int]core.Node
+	directed     bool
+	metadata     core.Metadata
+
+	// Edge IDs, see EdgeIdentifier. The maps are created on first use so that graphs built elsewhere in the package (such as by ShardedGraph) don't need to initialize them
+	edgeIDs    map[core.EdgeKey]int
+	edgeKeys   map[int]core.EdgeKey
+	nextEdgeID int
+
+	intervals map[core.EdgeKey][]Interval // Validity intervals, see SetEdgeIntervals. Edges without an entry are always valid.
+
+	ordered bool // Whether the listing methods sort their results, see SetOrdered
+}
+
+func NewGonumGraph(directed bool) *GonumGraph {
+	return &GonumGraph{
+		successors:   make(map[int]map[int]float64),
+		predecessors: make(map[int]map[int]float64),
+		nodeMap:      make(map[int]core.Node),
+		directed:     directed,
+	}
+}
+
+func NewPreAllocatedGonumGraph(directed bool, numVertices int) *GonumGraph {
+	return &GonumGraph{
+		successors:   make(map[int]map[int]float64, numVertices),
+		predecessors: make(map[int]map[int]float64, numVertices),
+		nodeMap:      make(map[int]core.Node, numVertices),
+		directed:     directed,
+	}
+}
+
+/* Mutable Graph implementation */
+
+func (graph *GonumGraph) NewNode(successors []core.Node) (node core.Node) {
+	node = core.GonumNode(graph.newNodeID())
+	graph.AddNode(node, successors)
+	return node
+}
+
+// Returns the ID NewNode gives the next node: the lowest non-negative ID that isn't in use
+func (graph *GonumGraph) newNodeID() int {
+	nodeList := graph.NodeList()
+	ids := make([]int, len(nodeList))
+	for i, node := range nodeList {
+		ids[i] = node.ID()
+	}
+
+	nodes := sort.IntSlice(ids)
+	sort.Sort(&nodes)
+	for i, node := range nodes {
+		if i != node {
+			return i
+		}
+	}
+
+	return len(nodes)
+}
+
+func (graph *GonumGraph) AddNode(node core.Node, successors []core.Node) {
+	id := node.ID()
+	if _, ok := graph.successors[id]; ok {
+		return
+	}
+
+	graph.nodeMap[id] = node
+
+	graph.successors[id] = make(map[int]float64, len(successors))
+	if !graph.directed {
+		graph.predecessors[id] = make(map[int]float64, len(successors))
+	} else {
+		graph.predecessors[id] = make(map[int]float64)
+	}
+	for _, successor := range successors {
+		succ := successor.ID()
+		graph.successors[id][succ] = 1.0
+		graph.identifyEdge(id, succ)
+
+		// Always add the reciprocal node to the graph
+		if _, ok := graph.successors[succ]; !ok {
+			graph.nodeMap[succ] = successor
+			graph.predecessors[succ] = make(map[int]float64)
+			graph.successors[succ] = make(map[int]float64)
+		}
+
+		graph.predecessors[succ][id] = 1.0
+
+		// But only add the reciprocal edge if we're undirected
+		if !graph.directed {
+			graph.successors[succ][id] = 1.0
+			graph.predecessors[id][succ] = 1.0
+		}
+	}
+}
+
+func (graph *GonumGraph) AddEdge(e core.Edge) {
+	id := e.Head().ID()
+	successor := e.Tail().ID()
+	if _, ok := graph.successors[id]; !ok {
+		return
+	}
+
+	if _, ok := graph.successors[successor]; !ok {
+		graph.nodeMap[successor] = e.Tail()
+		graph.successors[successor] = make(map[int]float64)
+		graph.predecessors[successor] = make(map[int]float64)
+	}
+
+	graph.successors[id][successor] = 1.0
+	graph.predecessors[successor][id] = 1.0
+	graph.identifyEdge(id, successor)
+
+	if !graph.directed {
+		graph.successors[successor][id] = 1.0
+		graph.predecessors[id][successor] = 1.0
+	}
+}
+
+func (graph *GonumGraph) SetEdgeCost(e core.Edge, cost float64) {
+	id := e.Head().ID()
+	successor := e.Tail().ID()
+	// Normally I'd use graph.vertices.Contains(id) as above, but this is equivalent and a bit easier to read here
+	if _, ok := graph.successors[id]; !ok {
+		return
+	} else if _, ok := graph.successors[id][successor]; !ok {
+		return
+	}
+	graph.successors[id][successor] = cost
+	graph.predecessors[successor][id] = cost
+
+	// By the spec, only the empty graph will be toggled between directed and undirected. Therefore we can be sure the reciprocal edge exists
+	if !graph.directed {
+		graph.successors[successor][id] = cost
+		graph.predecessors[id][successor] = cost
+	}
+}
+
+/* Checked mutations, these behave like their MutableGraph counterparts but report invalid input instead of ignoring it */
+
+// Like AddNode, but returns ErrNodeExists (and does nothing) if the node is already in the graph.
+func (graph *GonumGraph) AddNodeE(node core.Node, successors []core.Node) error {
+	if graph.NodeExists(node) {
+		return core.ErrNodeExists
+	}
+
+	graph.AddNode(node, successors)
+	return nil
+}
+
+// Like AddEdge, but returns ErrNodeNotFound if the head of the edge doesn't exist, or ErrEdgeExists if the edge is already in the graph.
+// As with AddEdge, a missing tail is created.
+func (graph *GonumGraph) AddEdgeE(e core.Edge) error {
+	if !graph.NodeExists(e.Head()) {
+		return core.ErrNodeNotFound
+	} else if graph.IsSuccessor(e.Head(), e.Tail()) {
+		return core.ErrEdgeExists
+	}
+
+	graph.AddEdge(e)
+	return nil
+}
+
+// Like SetEdgeCost, but returns ErrNodeNotFound if the head of the edge doesn't exist, or ErrEdgeNotFound if the edge doesn't.
+func (graph *GonumGraph) SetEdgeCostE(e core.Edge, cost float64) error {
+	if !graph.NodeExists(e.Head()) {
+		return core.ErrNodeNotFound
+	} else if !graph.IsSuccessor(e.Head(), e.Tail()) {
+		return core.ErrEdgeNotFound
+	}
+
+	graph.SetEdgeCost(e, cost)
+	return nil
+}
+
+// Like RemoveNode, but returns ErrNodeNotFound if the node isn't in the graph.
+func (graph *GonumGraph) RemoveNodeE(node core.Node) error {
+	if !graph.NodeExists(node) {
+		return core.ErrNodeNotFound
+	}
+
+	graph.RemoveNode(node)
+	return nil
+}
+
+// Like RemoveEdge, but returns ErrNodeNotFound if either end of the edge doesn't exist, or ErrEdgeNotFound if the edge doesn't.
+func (graph *GonumGraph) RemoveEdgeE(e core.Edge) error {
+	if !graph.NodeExists(e.Head()) || !graph.NodeExists(e.Tail()) {
+		return core.ErrNodeNotFound
+	} else if !graph.IsSuccessor(e.Head(), e.Tail()) {
+		return core.ErrEdgeNotFound
+	}
+
+	graph.RemoveEdge(e)
+	return nil
+}
+
+// Like SetDirected, but returns ErrGraphNotEmpty if the graph has nodes and isn't already directed (or undirected) as asked.
+func (graph *GonumGraph) SetDirectedE(directed bool) error {
+	if len(graph.successors) > 0 && graph.directed != directed {
+		return core.ErrGraphNotEmpty
+	}
+
+	graph.directed = directed
+	return nil
+}
+
+func (graph *GonumGraph) RemoveNode(node core.Node) {
+	id := node.ID()
+	if _, ok := graph.successors[id]; !ok {
+		return
+	}
+	delete(graph.nodeMap, id)
+
+	for succ, _ := range graph.successors[id] {
+		delete(graph.predecessors[succ], id)
+		graph.forgetEdge(id, succ)
+	}
+	delete(graph.successors, id)
+
+	for pred, _ := range graph.predecessors[id] {
+		delete(graph.successors[pred], id)
+		graph.forgetEdge(pred, id)
+	}
+	delete(graph.predecessors, id)
+
+}
+
+func (graph *GonumGraph) RemoveEdge(e core.Edge) {
+	id := e.Head().ID()
+	succ := e.Tail().ID()
+	if _, ok := graph.successors[id]; !ok {
+		return
+	} else if _, ok := graph.successors[succ]; !ok {
+		return
+	}
+
+	delete(graph.successors[id], succ)
+	delete(graph.predecessors[succ], id)
+	graph.forgetEdge(id, succ)
+	if !graph.directed {
+		delete(graph.predecessors[id], succ)
+		delete(graph.successors[succ], id)
+	}
+}
+
+// Returns the graph's metadata, which can be modified in place. EmptyGraph leaves the metadata alone.
+func (graph *GonumGraph) Metadata() *core.Metadata {
+	return &graph.metadata
+}
+
+func (graph *GonumGraph) EmptyGraph() {
+	if len(graph.successors) == 0 {
+		return
+	}
+	graph.successors = make(map[int]map[int]float64)
+	graph.predecessors = make(map[int]map[int]float64)
+	graph.nodeMap = make(map[int]core.Node)
+	graph.edgeIDs, graph.edgeKeys, graph.nextEdgeID = nil, nil, 0
+	graph.intervals = nil
+}
+
+func (graph *GonumGraph) SetDirected(directed bool) {
+	if len(graph.successors) > 0 {
+		return
+	}
+	graph.directed = directed
+}
+
+/* Edge identity */
+
+// Gives the edge head->tail the next unused ID, unless it already has one
+func (graph *GonumGraph) identifyEdge(head, tail int) {
+	if graph.edgeIDs == nil {
+		graph.edgeIDs = make(map[core.EdgeKey]int)
+		graph.edgeKeys = make(map[int]core.EdgeKey)
+	}
+
+	key := core.KeyOf(core.GonumEdge{H: core.GonumNode(head), T: core.GonumNode(tail)}, graph.directed)
+	if _, ok := graph.edgeIDs[key]; ok {
+		return
+	}
+	graph.edgeIDs[key] = graph.nextEdgeID
+	graph.edgeKeys[graph.nextEdgeID] = key
+	graph.nextEdgeID++
+}
+
+// Forgets everything stored about the edge head->tail outside of the adjacency maps, i.e. its ID and validity intervals
+func (graph *GonumGraph) forgetEdge(head, tail int) {
+	key := core.KeyOf(core.GonumEdge{H: core.GonumNode(head), T: core.GonumNode(tail)}, graph.directed)
+	if id, ok := graph.edgeIDs[key]; ok {
+		delete(graph.edgeIDs, key)
+		delete(graph.edgeKeys, id)
+	}
+	delete(graph.intervals, key)
+}
+
+// Gives IDs to every edge that doesn't have one yet, in order of their endpoints' IDs so the numbering is deterministic
+func (graph *GonumGraph) identifyAllEdges() {
+	keys := make(graphutil.EdgeKeySorter, 0)
+	for head, succs := range graph.successors {
+		for tail := range succs {
+			if key := core.KeyOf(core.GonumEdge{H: core.GonumNode(head), T: core.GonumNode(tail)}, graph.directed); key.Head == head && key.Tail == tail {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Sort(keys)
+
+	for _, key := range keys {
+		graph.identifyEdge(key.Head, key.Tail)
+	}
+}
+
+func (graph *GonumGraph) EdgeID(e core.Edge) (id int, ok bool) {
+	id, ok = graph.edgeIDs[core.KeyOf(e, graph.directed)]
+	return id, ok
+}
+
+func (graph *GonumGraph) EdgeByID(id int) (edge core.IdentifiedEdge, ok bool) {
+	key, ok := graph.edgeKeys[id]
+	if !ok {
+		return nil, false
+	}
+
+	return core.GonumIDEdge{H: graph.nodeMap[key.Head], T: graph.nodeMap[key.Tail], EID: id, W: graph.successors[key.Head][key.Tail]}, true
+}
+
+func (graph *GonumGraph) IdentifiedEdges() []core.IdentifiedEdge {
+	ids := make([]int, 0, len(graph.edgeKeys))
+	for id := range graph.edgeKeys {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	edges := make([]core.IdentifiedEdge, len(ids))
+	for i, id := range ids {
+		edges[i], _ = graph.EdgeByID(id)
+	}
+
+	return edges
+}
+
+// Sets whether NodeList, EdgeList, Successors and Predecessors return their results sorted by ID (EdgeList by head and then tail), as SortedNodeList does, rather than in map order.
+// Sorting costs O(k log k) per call, but makes everything run on the graph reproducible: the same calls give the same results on every run, which golden tests need, and AStar breaks
+// ties between equally good paths the same way every time, whatever its TieBreak. The setting survives EmptyGraph. Graphs are unordered by default.
+func (graph *GonumGraph) SetOrdered(ordered bool) {
+	graph.ordered = ordered
+}
+
+/* Graph implementation */
+
+func (graph *GonumGraph) Successors(node core.Node) []core.Node {
+	id := node.ID()
+	if _, ok := graph.successors[id]; !ok {
+		return nil
+	}
+
+	successors := make([]core.Node, 0, len(graph.successors[id]))
+	for succ, _ := range graph.successors[id] {
+		successors = append(successors, graph.nodeMap[succ])
+	}
+	if graph.ordered {
+		sort.Sort(graphutil.NodeSorter(successors))
+	}
+
+	return successors
+}
+
+func (graph *GonumGraph) IsSuccessor(node, successor core.Node) bool {
+	succ := successor.ID()
+	id := node.ID()
+	if _, ok := graph.successors[id]; !ok {
+		return false
+	}
+
+	_, ok := graph.successors[id][succ]
+
+	return ok
+}
+
+func (graph *GonumGraph) Predecessors(node core.Node) []core.Node {
+	id := node.ID()
+	if _, ok := graph.successors[id]; !ok {
+		return nil
+	}
+
+	predecessors := make([]core.Node, 0, len(graph.predecessors[id]))
+	for pred, _ := range graph.predecessors[id] {
+		predecessors = append(predecessors, graph.nodeMap[pred])
+	}
+	if graph.ordered {
+		sort.Sort(graphutil.NodeSorter(predecessors))
+	}
+
+	return predecessors
+}
+
+func (graph *GonumGraph) IsPredecessor(node, predecessor core.Node) bool {
+	id := node.ID()
+	pred := predecessor.ID()
+	if _, ok := graph.successors[id]; !ok {
+		return false
+	}
+
+	_, ok := graph.predecessors[id][pred]
+
+	return ok
+}
+
+func (graph *GonumGraph) IsAdjacent(node, neigh core.Node) bool {
+	id := node.ID()
+	neighbor := neigh.ID()
+	if _, ok := graph.successors[id]; !ok {
+		return false
+	}
+
+	_, succ := graph.successors[id][neighbor]
+	_, pred := graph.predecessors[id][neighbor]
+
+	return succ || pred
+}
+
+func (graph *GonumGraph) NodeExists(node core.Node) bool {
+	_, ok := graph.successors[node.ID()]
+
+	return ok
+}
+
+func (graph *GonumGraph) Degree(node core.Node) int {
+	id := node.ID()
+	if _, ok := graph.successors[id]; !ok {
+		return 0
+	}
+
+	return len(graph.successors[id]) + len(graph.predecessors[id])
+}
+
+func (graph *GonumGraph) InDegree(node core.Node) int {
+	return len(graph.predecessors[node.ID()])
+}
+
+func (graph *GonumGraph) OutDegree(node core.Node) int {
+	return len(graph.successors[node.ID()])
+}
+
+func (graph *GonumGraph) EdgeList() []core.Edge {
+	eList := make([]core.Edge, 0, len(graph.successors))
+	for id, succMap := range graph.successors {
+		for succ, cost := range succMap {
+			eList = append(eList, core.GonumCostEdge{H: graph.nodeMap[id], T: graph.nodeMap[succ], W: cost})
+		}
+	}
+	if graph.ordered {
+		sort.Sort(graphutil.EdgeListSorter(eList))
+	}
+
+	return eList
+}
+
+func (graph *GonumGraph) NodeList() []core.Node {
+	nodes := make([]core.Node, 0, len(graph.successors))
+	for _, node := range graph.nodeMap {
+		nodes = append(nodes, node)
+	}
+	if graph.ordered {
+		sort.Sort(graphutil.NodeSorter(nodes))
+	}
+
+	return nodes
+}
+
+func (graph *GonumGraph) IsDirected() bool {
+	return graph.directed
+}
+
+func (graph *GonumGraph) Cost(node, succ core.Node) float64 {
+	return graph.successors[node.ID()][succ.ID()]
+}
@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/encodingtest"
+	"testing"
+)
+
+func FuzzGonumGraphJSON(f *testing.F) {
+	encodingtest.FuzzDecoder(f, jsonCodec{},
+		[]byte(""),
+		[]byte("null"),
+		[]byte(`{"directed": false, "nodes": [1, 2], "edges": [{"from": 2, "to": 1, "cost": "NaN"}]}`),
+		[]byte(`{"directed": true, "created": "2014-03-01T12:00:00+01:00", "attributes": {}, "nodes": [-9223372036854775808]}`),
+	)
+}
@@ -0,0 +1,270 @@
+package simple_test
+
+import (
+	"encoding/json"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encodingtest"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestGonumGraphCheckedMutation(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	n0, n1, n2 := core.GonumNode(0), core.GonumNode(1), core.GonumNode(2)
+
+	if err := g.AddNodeE(n0, []core.Node{n1}); err != nil {
+		t.Fatal("Adding a new node failed:", err)
+	}
+	if err := g.AddNodeE(n0, nil); err != core.ErrNodeExists {
+		t.Error("Re-adding a node returned", err)
+	}
+
+	if err := g.AddEdgeE(core.GonumEdge{H: n2, T: n0}); err != core.ErrNodeNotFound {
+		t.Error("Adding an edge from a missing node returned", err)
+	} else if g.NodeExists(n2) {
+		t.Error("Failed AddEdgeE modified the graph")
+	}
+	if err := g.AddEdgeE(core.GonumEdge{H: n0, T: n1}); err != core.ErrEdgeExists {
+		t.Error("Adding an existing edge returned", err)
+	}
+	if err := g.AddEdgeE(core.GonumEdge{H: n1, T: n2}); err != nil {
+		t.Error("Adding a valid edge returned", err)
+	} else if !g.IsSuccessor(n1, n2) {
+		t.Error("AddEdgeE did not add the edge")
+	}
+
+	if err := g.SetEdgeCostE(core.GonumEdge{H: n2, T: n0}, 5); err != core.ErrEdgeNotFound {
+		t.Error("Setting the cost of a missing edge returned", err)
+	}
+	if err := g.SetEdgeCostE(core.GonumEdge{H: core.GonumNode(7), T: n0}, 5); err != core.ErrNodeNotFound {
+		t.Error("Setting the cost of an edge from a missing node returned", err)
+	}
+	if err := g.SetEdgeCostE(core.GonumEdge{H: n0, T: n1}, 5); err != nil {
+		t.Error("Setting the cost of a valid edge returned", err)
+	} else if g.Cost(n0, n1) != 5 {
+		t.Error("SetEdgeCostE did not set the cost")
+	}
+
+	if err := g.RemoveEdgeE(core.GonumEdge{H: n1, T: n0}); err != core.ErrEdgeNotFound {
+		t.Error("Removing a missing edge returned", err)
+	}
+	if err := g.RemoveEdgeE(core.GonumEdge{H: n0, T: core.GonumNode(7)}); err != core.ErrNodeNotFound {
+		t.Error("Removing an edge to a missing node returned", err)
+	}
+	if err := g.RemoveEdgeE(core.GonumEdge{H: n0, T: n1}); err != nil || g.IsSuccessor(n0, n1) {
+		t.Error("Removing a valid edge returned", err)
+	}
+	if err := g.SetDirectedE(false); err != core.ErrGraphNotEmpty || !g.IsDirected() {
+		t.Error("Making a non-empty graph undirected returned", err)
+	}
+	if err := g.SetDirectedE(true); err != nil {
+		t.Error("Keeping a non-empty graph directed returned", err)
+	}
+	if err := g.RemoveNodeE(n2); err != nil || g.NodeExists(n2) {
+		t.Error("Removing a node returned", err)
+	}
+	if err := g.RemoveNodeE(n2); err != core.ErrNodeNotFound {
+		t.Error("Removing a missing node returned", err)
+	}
+}
+
+func TestEdgeIdentity(t *testing.T) {
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1), core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+
+	id12, _ := g.EdgeID(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)})
+	if id21, ok := g.EdgeID(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(0)}); !ok || id21 != id12 {
+		t.Errorf("The two directions of an undirected edge have IDs %d and %d", id12, id21)
+	}
+	if edges := g.IdentifiedEdges(); len(edges) != 3 {
+		t.Errorf("Got %d identified edges, want 3", len(edges))
+	}
+
+	// Removing an edge doesn't renumber the others
+	id23, _ := g.EdgeID(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.RemoveEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)}, 4)
+	if edge, ok := g.EdgeByID(id23); !ok || edge.Head().ID()+edge.Tail().ID() != 5 || edge.Weight() != 4 {
+		t.Errorf("Edge %d is %v after removing another edge", id23, edge)
+	}
+	if _, ok := g.EdgeID(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}); ok {
+		t.Error("Removed edge still has an ID")
+	}
+
+	g.RemoveNode(core.GonumNode(2))
+	if edges := g.IdentifiedEdges(); len(edges) != 0 {
+		t.Errorf("Edges %v still have IDs after removing their node", edges)
+	}
+
+	sg := simple.NewShardedGraph(true, 2)
+	sg.AddEdge(core.GonumEdge{H: core.GonumNode(5), T: core.GonumNode(1)})
+	sg.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(5)})
+	if id, ok := sg.Freeze().EdgeID(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(5)}); !ok || id != 0 {
+		t.Errorf("Frozen ShardedGraph gave edge 1->5 ID %d", id)
+	}
+}
+
+func TestCostEdges(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1, 2))
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, 5)
+	for _, edge := range g.EdgeList() {
+		cedge, ok := edge.(core.CostEdge)
+		if !ok || cedge.Weight() != g.Cost(edge.Head(), edge.Tail()) {
+			t.Errorf("Edge %v doesn't carry its cost", edge)
+		}
+	}
+
+	tg, err := simple.GenerateTileGraph("  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetCost(0, 1, 3)
+	for _, edge := range tg.EdgeList() {
+		if cedge, ok := edge.(core.CostEdge); !ok || cedge.Weight() != tg.Cost(edge.Head(), edge.Tail()) {
+			t.Errorf("Tile edge %v doesn't carry its cost", edge)
+		}
+	}
+
+	// A Cost argument still takes precedence over the weights the edges carry
+	if stats := core.EdgeWeightStats(g, nil); stats.Total != 6 {
+		t.Errorf("Edge weights total %v, want 6", stats.Total)
+	}
+	if stats := core.EdgeWeightStats(g, core.UniformCost); stats.Total != 2 {
+		t.Errorf("Uniform costs total %v, want 2", stats.Total)
+	}
+	if _, costs, _ := path.BellmanFord(core.GonumNode(0), g, nil); costs[2] != 5 {
+		t.Errorf("BellmanFord found cost %v, want 5", costs[2])
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(w io.Writer, g core.Graph) error {
+	gonum, ok := g.(*simple.GonumGraph)
+	if !ok {
+		gonum = simple.NewGonumGraph(g.IsDirected())
+		core.CopyGraph(gonum, g)
+	}
+	out, err := json.Marshal(gonum)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(out, '\n'))
+	return err
+}
+
+func (jsonCodec) Decode(r io.Reader) (core.Graph, error) {
+	g := simple.NewGonumGraph(false)
+	if err := json.NewDecoder(r).Decode(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func TestGonumGraphJSON(t *testing.T) {
+	for name, g := range encodingtest.Fixtures() {
+		encodingtest.RoundTrip(t, g, jsonCodec{})
+		encodingtest.Golden(t, "json_"+name, g, jsonCodec{})
+	}
+
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(1), nodes(2))
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, math.Inf(1))
+	out, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"directed":true,"nodes":[1,2],"edges":[{"from":1,"to":2,"cost":"+Inf"}]}`; string(out) != want {
+		t.Errorf("Got %s, want %s", out, want)
+	}
+
+	// A graph embedded in a config file, with costs left out and an unknown field
+	var config struct {
+		Roads *simple.GonumGraph `json:"roads"`
+	}
+	if err := json.Unmarshal([]byte(`{"roads": {"directed": false, "nodes": [3, 4, 5], "edges": [{"from": 4, "to": 3}, {"from": 4, "to": 5, "cost": -2}], "comment": "ignored"}}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if roads := config.Roads; roads.IsDirected() || len(roads.EdgeList()) != 4 || roads.Cost(core.GonumNode(3), core.GonumNode(4)) != 1 || roads.Cost(core.GonumNode(5), core.GonumNode(4)) != -2 {
+		t.Errorf("Got edges %v", roads.EdgeList())
+	}
+
+	for _, bad := range []string{
+		`{"nodes": [1]}`,
+		`{"directed": true, "nodes": [1, 1]}`,
+		`{"directed": true, "nodes": [1], "edges": [{"from": 1, "to": 2}]}`,
+		`{"directed": false, "nodes": [1, 2], "edges": [{"from": 1, "to": 2}, {"from": 2, "to": 1}]}`,
+		`{"directed": true, "nodes": [1.5]}`,
+		`{"directed": true, "nodes": [1], "edges": [{"from": 1, "to": 1, "cost": "1"}]}`,
+		`[]`,
+	} {
+		if err := json.Unmarshal([]byte(bad), g); err == nil {
+			t.Errorf("No error for %s", bad)
+		}
+	}
+	if len(g.NodeList()) != 2 || !g.IsDirected() {
+		t.Error("A failed decode changed the graph")
+	}
+}
+
+func TestOrderedGraph(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	for _, id := range []int{9, 7, 5, 3, 1} {
+		g.AddNode(core.GonumNode(id), []core.Node{core.GonumNode(id + 2), core.GonumNode(id + 1)})
+	}
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(6), T: core.GonumNode(7)})
+
+	sorted := func(nodes []core.Node) bool {
+		for i := 1; i < len(nodes); i++ {
+			if nodes[i-1].ID() >= nodes[i].ID() {
+				return false
+			}
+		}
+		return true
+	}
+	edgesSorted := func(edges []core.Edge) bool {
+		for i := 1; i < len(edges); i++ {
+			a, b := edges[i-1], edges[i]
+			if a.Head().ID() > b.Head().ID() || (a.Head().ID() == b.Head().ID() && a.Tail().ID() >= b.Tail().ID()) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if nodes := core.SortedNodeList(g); len(nodes) != 11 || !sorted(nodes) {
+		t.Errorf("SortedNodeList returned %v", nodes)
+	}
+	if edges := core.SortedEdgeList(g); len(edges) != 11 || !edgesSorted(edges) {
+		t.Errorf("SortedEdgeList returned %v", edges)
+	}
+	if succs := core.SortedSuccessors(g, core.GonumNode(5)); len(succs) != 2 || succs[0].ID() != 6 || succs[1].ID() != 7 {
+		t.Errorf("SortedSuccessors returned %v, want [6 7]", succs)
+	}
+
+	g.SetOrdered(true)
+	for i := 0; i < 10; i++ {
+		if !sorted(g.NodeList()) || !edgesSorted(g.EdgeList()) || !sorted(g.Successors(core.GonumNode(5))) || !sorted(g.Predecessors(core.GonumNode(7))) {
+			t.Fatal("An ordered graph listed nodes or edges out of order")
+		}
+	}
+
+	// A diamond with two equally short paths: an ordered graph always finds the same one, whatever the tie break
+	diamond := simple.NewGonumGraph(true)
+	diamond.SetOrdered(true)
+	diamond.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(2), core.GonumNode(1)})
+	diamond.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(3)})
+	diamond.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	first, _, _ := path.AStar(core.GonumNode(0), core.GonumNode(3), diamond, nil, nil)
+	for i := 0; i < 20; i++ {
+		route, _, _ := path.AStar(core.GonumNode(0), core.GonumNode(3), diamond, nil, nil)
+		if len(route) != 3 || route[1].ID() != first[1].ID() {
+			t.Fatalf("AStar on an ordered graph found %v, then %v", first, route)
+		}
+	}
+}
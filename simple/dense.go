@@ -0,0 +1,284 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"math"
+)
+
+// A DenseGraph is a mutable graph stored as an adjacency matrix: one flat slice of costs, row by row, with NaN where there's no edge. Finding or costing an edge is a single index,
+// and Successors is a scan of one row, so it's faster and smaller than a GonumGraph once a graph has a fair fraction of all possible edges, and much larger when it has few of them.
+// Use it for small dense graphs, such as distance tables and the inputs of FloydWarshall, which reads the matrix directly.
+//
+// The nodes are the IDs 0 to n-1 for some n, and are always returned as GonumNodes, whatever Node type they were added as. Adding a node beyond the matrix grows it, doubling its size
+// at least, so growing a graph one node at a time stays cheap; nodes with negative IDs are ignored. Costs may be anything but NaN.
+type DenseGraph struct {
+	directed bool
+	size     int       // The number of rows and columns of the matrix, which may include IDs that aren't nodes
+	exists   []bool    // Whether each ID is a node
+	costs    []float64 // The cost of the edge from i to j is costs[i*size+j], or NaN if there isn't one
+}
+
+// Returns an empty DenseGraph with room for nodes 0 to n-1 before it needs to grow
+func NewDenseGraph(n int, directed bool) *DenseGraph {
+	graph := &DenseGraph{directed: directed}
+	graph.grow(n)
+	return graph
+}
+
+// Makes room for the IDs 0 to n-1
+func (graph *DenseGraph) grow(n int) {
+	if n <= graph.size {
+		return
+	}
+	if n < 2*graph.size {
+		n = 2 * graph.size
+	}
+
+	costs := make([]float64, n*n)
+	for i := range costs {
+		costs[i] = math.NaN()
+	}
+	for i := 0; i < graph.size; i++ {
+		copy(costs[i*n:i*n+graph.size], graph.costs[i*graph.size:(i+1)*graph.size])
+	}
+	exists := make([]bool, n)
+	copy(exists, graph.exists)
+
+	graph.size, graph.exists, graph.costs = n, exists, costs
+}
+
+func (graph *DenseGraph) has(id int) bool {
+	return id >= 0 && id < graph.size && graph.exists[id]
+}
+
+func (graph *DenseGraph) hasEdge(i, j int) bool {
+	return graph.has(i) && graph.has(j) && !math.IsNaN(graph.costs[i*graph.size+j])
+}
+
+// Adds the node if it isn't in the graph, growing the matrix to fit it
+func (graph *DenseGraph) addID(id int) bool {
+	if id < 0 {
+		return false
+	}
+	graph.grow(id + 1)
+	graph.exists[id] = true
+	return true
+}
+
+func (graph *DenseGraph) setCost(i, j int, cost float64) {
+	graph.costs[i*graph.size+j] = cost
+	if !graph.directed {
+		graph.costs[j*graph.size+i] = cost
+	}
+}
+
+/* Graph implementation */
+
+func (graph *DenseGraph) Successors(node core.Node) []core.Node {
+	i := node.ID()
+	if !graph.has(i) {
+		return nil
+	}
+
+	succs := make([]core.Node, 0)
+	for j, cost := range graph.costs[i*graph.size : (i+1)*graph.size] {
+		if !math.IsNaN(cost) {
+			succs = append(succs, core.GonumNode(j))
+		}
+	}
+
+	return succs
+}
+
+func (graph *DenseGraph) IsSuccessor(node, successor core.Node) bool {
+	return graph.hasEdge(node.ID(), successor.ID())
+}
+
+func (graph *DenseGraph) Predecessors(node core.Node) []core.Node {
+	j := node.ID()
+	if !graph.has(j) {
+		return nil
+	}
+
+	preds := make([]core.Node, 0)
+	for i := 0; i < graph.size; i++ {
+		if !math.IsNaN(graph.costs[i*graph.size+j]) {
+			preds = append(preds, core.GonumNode(i))
+		}
+	}
+
+	return preds
+}
+
+func (graph *DenseGraph) IsPredecessor(node, predecessor core.Node) bool {
+	return graph.hasEdge(predecessor.ID(), node.ID())
+}
+
+func (graph *DenseGraph) IsAdjacent(node, neighbor core.Node) bool {
+	return graph.IsSuccessor(node, neighbor) || graph.IsPredecessor(node, neighbor)
+}
+
+func (graph *DenseGraph) NodeExists(node core.Node) bool {
+	return graph.has(node.ID())
+}
+
+func (graph *DenseGraph) Degree(node core.Node) int {
+	return graph.InDegree(node) + graph.OutDegree(node)
+}
+
+func (graph *DenseGraph) InDegree(node core.Node) int {
+	j := node.ID()
+	if !graph.has(j) {
+		return 0
+	}
+
+	degree := 0
+	for i := 0; i < graph.size; i++ {
+		if !math.IsNaN(graph.costs[i*graph.size+j]) {
+			degree++
+		}
+	}
+
+	return degree
+}
+
+func (graph *DenseGraph) OutDegree(node core.Node) int {
+	i := node.ID()
+	if !graph.has(i) {
+		return 0
+	}
+
+	degree := 0
+	for _, cost := range graph.costs[i*graph.size : (i+1)*graph.size] {
+		if !math.IsNaN(cost) {
+			degree++
+		}
+	}
+
+	return degree
+}
+
+// Returns the edges in order of head and then tail ID, as GonumCostEdges
+func (graph *DenseGraph) EdgeList() []core.Edge {
+	edges := make([]core.Edge, 0)
+	for i := 0; i < graph.size; i++ {
+		for j, cost := range graph.costs[i*graph.size : (i+1)*graph.size] {
+			if !math.IsNaN(cost) {
+				edges = append(edges, core.GonumCostEdge{H: core.GonumNode(i), T: core.GonumNode(j), W: cost})
+			}
+		}
+	}
+
+	return edges
+}
+
+// Returns the nodes in order of ID
+func (graph *DenseGraph) NodeList() []core.Node {
+	nodes := make([]core.Node, 0, graph.size)
+	for id, ok := range graph.exists {
+		if ok {
+			nodes = append(nodes, core.GonumNode(id))
+		}
+	}
+
+	return nodes
+}
+
+func (graph *DenseGraph) IsDirected() bool {
+	return graph.directed
+}
+
+/* Coster implementation */
+
+// Returns the cost of the edge, or +Inf if there's no such edge
+func (graph *DenseGraph) Cost(node, succ core.Node) float64 {
+	if !graph.hasEdge(node.ID(), succ.ID()) {
+		return math.Inf(1)
+	}
+
+	return graph.costs[node.ID()*graph.size+succ.ID()]
+}
+
+/* MutableGraph implementation */
+
+// Adds a node with the lowest unused ID and the given successors
+func (graph *DenseGraph) NewNode(successors []core.Node) core.Node {
+	id := 0
+	for id < graph.size && graph.exists[id] {
+		id++
+	}
+	graph.AddNode(core.GonumNode(id), successors)
+
+	return core.GonumNode(id)
+}
+
+// Adds the node with edges costing 1 to its successors, which are added as well if they aren't in the graph. Does nothing if the node is already in the graph.
+func (graph *DenseGraph) AddNode(node core.Node, successors []core.Node) {
+	id := node.ID()
+	if graph.has(id) || !graph.addID(id) {
+		return
+	}
+	for _, succ := range successors {
+		if graph.addID(succ.ID()) {
+			graph.setCost(id, succ.ID(), 1)
+		}
+	}
+}
+
+// Adds an edge costing 1, adding its tail if it isn't in the graph. Does nothing if the head isn't in the graph.
+func (graph *DenseGraph) AddEdge(e core.Edge) {
+	head, tail := e.Head().ID(), e.Tail().ID()
+	if !graph.has(head) || !graph.addID(tail) {
+		return
+	}
+	graph.setCost(head, tail, 1)
+}
+
+func (graph *DenseGraph) SetEdgeCost(e core.Edge, cost float64) {
+	head, tail := e.Head().ID(), e.Tail().ID()
+	if !graph.hasEdge(head, tail) {
+		return
+	}
+	graph.setCost(head, tail, cost)
+}
+
+func (graph *DenseGraph) RemoveNode(node core.Node) {
+	id := node.ID()
+	if !graph.has(id) {
+		return
+	}
+
+	graph.exists[id] = false
+	for other := 0; other < graph.size; other++ {
+		graph.costs[id*graph.size+other] = math.NaN()
+		graph.costs[other*graph.size+id] = math.NaN()
+	}
+}
+
+func (graph *DenseGraph) RemoveEdge(e core.Edge) {
+	head, tail := e.Head().ID(), e.Tail().ID()
+	if !graph.hasEdge(head, tail) {
+		return
+	}
+	graph.setCost(head, tail, math.NaN())
+}
+
+// Removes every node and edge, keeping the matrix's size
+func (graph *DenseGraph) EmptyGraph() {
+	for i := range graph.costs {
+		graph.costs[i] = math.NaN()
+	}
+	for i := range graph.exists {
+		graph.exists[i] = false
+	}
+}
+
+// Does nothing unless the graph is empty
+func (graph *DenseGraph) SetDirected(directed bool) {
+	for _, ok := range graph.exists {
+		if ok {
+			return
+		}
+	}
+	graph.directed = directed
+}
@@ -0,0 +1,83 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"testing"
+)
+
+func TestDenseGraph(t *testing.T) {
+	g := simple.NewDenseGraph(2, true)
+	g.AddNode(core.GonumNode(0), nodes(1, 5))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(5), T: core.GonumNode(1)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(9), T: core.GonumNode(0)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(5)}, -2)
+
+	// Node 5 grew the matrix, and the missing head of the last edge kept it from being added
+	if len(g.NodeList()) != 3 || len(g.EdgeList()) != 3 || g.NodeExists(core.GonumNode(9)) || g.NodeExists(core.GonumNode(2)) {
+		t.Errorf("Got nodes %v and edges %v", g.NodeList(), g.EdgeList())
+	}
+	if g.Cost(core.GonumNode(0), core.GonumNode(5)) != -2 || g.Cost(core.GonumNode(5), core.GonumNode(1)) != 1 || !math.IsInf(g.Cost(core.GonumNode(1), core.GonumNode(0)), 1) {
+		t.Error("Got the wrong costs")
+	}
+	if preds := g.Predecessors(core.GonumNode(1)); len(preds) != 2 || g.InDegree(core.GonumNode(1)) != 2 || g.Degree(core.GonumNode(0)) != 2 || !g.IsPredecessor(core.GonumNode(1), core.GonumNode(5)) {
+		t.Errorf("Got predecessors %v of 1", preds)
+	}
+	if node := g.NewNode(nodes(0)); node.ID() != 2 || !g.IsSuccessor(node, core.GonumNode(0)) {
+		t.Errorf("NewNode added %v", node)
+	}
+
+	g.RemoveNode(core.GonumNode(1))
+	g.RemoveEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(5)})
+	if len(g.NodeList()) != 3 || len(g.EdgeList()) != 1 || g.IsSuccessor(core.GonumNode(5), core.GonumNode(1)) {
+		t.Errorf("After removals got nodes %v and edges %v", g.NodeList(), g.EdgeList())
+	}
+
+	undirected := simple.NewDenseGraph(0, true)
+	undirected.SetDirected(false)
+	undirected.AddNode(core.GonumNode(3), nodes(4))
+	undirected.SetEdgeCost(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(3)}, 7)
+	if undirected.IsDirected() || undirected.Cost(core.GonumNode(3), core.GonumNode(4)) != 7 || len(undirected.Successors(core.GonumNode(4))) != 1 {
+		t.Errorf("Got undirected edges %v", undirected.EdgeList())
+	}
+	undirected.EmptyGraph()
+	if len(undirected.NodeList()) != 0 || len(undirected.EdgeList()) != 0 {
+		t.Error("EmptyGraph left nodes or edges behind")
+	}
+}
+
+func TestDenseFloydWarshall(t *testing.T) {
+	gonum := simple.NewGonumGraph(true)
+	dense := simple.NewDenseGraph(6, true)
+	for _, e := range []struct {
+		h, t int
+		cost float64
+	}{{0, 1, 4}, {0, 2, 1}, {2, 1, 2}, {1, 3, 1}, {2, 3, 5}, {3, 4, 3}, {4, 0, -1}} {
+		for _, g := range []core.MutableGraph{gonum, dense} {
+			g.AddNode(core.GonumNode(e.h), nil)
+			g.AddEdge(core.GonumEdge{H: core.GonumNode(e.h), T: core.GonumNode(e.t)})
+			g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(e.h), T: core.GonumNode(e.t)}, e.cost)
+		}
+	}
+	if !core.Equal(gonum, dense, 0) {
+		t.Fatal("The dense graph differs from the GonumGraph")
+	}
+
+	want, _ := path.FloydWarshall(gonum, nil)
+	got, aborted := path.FloydWarshall(dense, nil)
+	if aborted {
+		t.Fatal("Found a negative cycle")
+	}
+	for _, u := range gonum.NodeList() {
+		for _, v := range gonum.NodeList() {
+			if got.Cost(u, v) != want.Cost(u, v) {
+				t.Errorf("Got cost %f from %v to %v, want %f", got.Cost(u, v), u, v, want.Cost(u, v))
+			}
+		}
+	}
+	if uniform, _ := path.FloydWarshall(dense, core.UniformCost); uniform.Cost(core.GonumNode(0), core.GonumNode(4)) != 3 {
+		t.Errorf("Got cost %f with uniform costs, want 3", uniform.Cost(core.GonumNode(0), core.GonumNode(4)))
+	}
+}
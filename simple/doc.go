@@ -0,0 +1,24 @@
+// Package simple has the concrete graphs, the views that change how another graph looks without copying it, and the encodings that belong to a single graph type (GonumGraph's JSON and
+// snapshots, TileGraph's text format). Every graph here implements core.Graph; capability.go lists which of the optional interfaces each implements as well.
+//
+//	capability.go     which optional interfaces each graph implements, and AsWeighted, AsDirected, AsMutable and AsChecked for upgrading to them
+//	page.go           paging through a graph's nodes and edges with cursors, for services that can't send the whole graph at once
+//	concretegraph.go  GonumGraph, a general purpose mutable graph
+//	gonumjson.go      GonumGraph's JSON encoding, with a stable schema
+//	snapshot.go       binary snapshots of a GonumGraph, and LoggedGraph, which writes a log of its mutations for replaying after a crash
+//	temporal.go       edge validity intervals on GonumGraph, and the snapshot views they give
+//	intgraph.go       IntGraph, a GonumGraph restricted to integer costs
+//	forest.go         Forest, a directed GonumGraph that's kept a set of rooted trees
+//	bipartite.go      BipartiteGraph, a GonumGraph split into two node sets, its one-mode projections, bipartition testing and Hopcroft-Karp matching
+//	shardedgraph.go   ShardedGraph, for loading a GonumGraph from many goroutines at once
+//	tilegraph.go      TileGraph, a 2D grid of passable and impassable tiles
+//	tilegraphio.go    saving and loading TileGraphs, with their costs and portals, as text
+//	hextilegraph.go   HexTileGraph, a map of hexagonal tiles addressed by axial coordinates
+//	dense.go          DenseGraph, a mutable graph stored as an adjacency matrix, for small dense graphs
+//	frozen.go         FrozenGraph, an immutable compressed sparse row copy of a graph for read-heavy workloads
+//	filter.go         FilteredGraph, a view of a graph with nodes and edges hidden by predicates, and Induce for induced subgraphs
+//	reverse.go        ReversedGraph, a view of a directed graph with its edges reversed, for searching backwards
+//	dynamiccost.go    DynamicCostGraph, a view of a graph with swappable cost providers that notifies incremental planners of changed costs
+//	implicit.go       ImplicitGraph, for searching state spaces given by a successor function
+//	hypergraph.go     Hypergraph, whose hyperedges join sets of nodes, and its conversions to ordinary graphs
+package simple
@@ -0,0 +1,193 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"sync"
+)
+
+// A CostProvider supplies the costs of a graph's edges from outside the graph, such as a live traffic feed giving travel times on a road network whose roads don't change. Any Coster is
+// one, including another graph, and CostFunc turns a cost function into one.
+type CostProvider interface {
+	Cost(node, succ core.Node) float64
+}
+
+// A CostFunc is a cost function used as a CostProvider.
+type CostFunc func(node, succ core.Node) float64
+
+func (f CostFunc) Cost(node, succ core.Node) float64 {
+	return f(node, succ)
+}
+
+// A CostListener is told which edges' costs have changed, so that it can repair what it worked out from the old costs instead of starting over. A DStarInstance is one, and repairs its
+// path on its next Step or Path.
+type CostListener interface {
+	UpdateCost(edge core.Edge)
+}
+
+// A DynamicCostGraph is a view of a graph with its costs taken from a CostProvider, which can be swapped for another while the view is in use, such as when a new traffic estimate
+// arrives. Searches and planners are run on the view as on any other Coster, and read the current provider's costs as they go. Planners that keep results between searches subscribe to
+// be told which edges changed, so that only the affected part of their search is redone.
+//
+// The view isn't a copy, so later changes to the graph's nodes and edges show up in it. Its heuristic is the graph's (the null heuristic if the graph has none), which must stay
+// admissible for every provider swapped in: a provider that makes edges cheaper than the graph's own costs can make it overestimate.
+//
+// The provider may be swapped from one goroutine while others search the view. Listeners are called on the goroutine that reports the change, once the new costs are in place, so a
+// listener that isn't safe for concurrent use (a DStarInstance isn't) must only be used on that goroutine or be guarded by the caller.
+type DynamicCostGraph struct {
+	graph         core.Graph
+	heuristicCost func(core.Node, core.Node) float64
+	mu            sync.RWMutex // Guards provider and listeners
+	provider      CostProvider
+	listeners     []CostListener
+}
+
+// Returns a view of the graph with its costs taken from the provider. If provider is nil, the graph's own costs are used until another provider is set (uniform costs if it has none).
+func NewDynamicCostGraph(graph core.Graph, provider CostProvider) *DynamicCostGraph {
+	if provider == nil {
+		provider = CostFunc(graphutil.DefaultCost(graph, nil))
+	}
+
+	view := &DynamicCostGraph{graph: graph, heuristicCost: core.NullHeuristic, provider: provider}
+	if hgraph, ok := graph.(core.HeuristicCoster); ok {
+		view.heuristicCost = hgraph.HeuristicCost
+	}
+
+	return view
+}
+
+// Returns the provider the view's costs currently come from
+func (view *DynamicCostGraph) CostProvider() CostProvider {
+	view.mu.RLock()
+	defer view.mu.RUnlock()
+
+	return view.provider
+}
+
+// Swaps in a new provider, and returns the edges whose costs it changes, which the listeners are told about. They're found by comparing the old and new costs of every edge in the
+// graph's EdgeList, so a swap takes time in proportion to the size of the graph; a provider that changes its own costs in place can report them with CostsChanged instead.
+func (view *DynamicCostGraph) SetCostProvider(provider CostProvider) (changed []core.Edge) {
+	if provider == nil {
+		provider = CostFunc(graphutil.DefaultCost(view.graph, nil))
+	}
+
+	view.mu.Lock()
+	old := view.provider
+	view.provider = provider
+	view.mu.Unlock()
+
+	for _, edge := range view.graph.EdgeList() {
+		if old.Cost(edge.Head(), edge.Tail()) != provider.Cost(edge.Head(), edge.Tail()) {
+			changed = append(changed, edge)
+		}
+	}
+	view.CostsChanged(changed...)
+
+	return changed
+}
+
+// Tells the listeners that the costs of the edges have changed, for a provider whose costs change without being swapped, such as one that reads a table the traffic feed updates. For
+// an undirected graph either direction of an edge will do.
+func (view *DynamicCostGraph) CostsChanged(edges ...core.Edge) {
+	if len(edges) == 0 {
+		return
+	}
+
+	view.mu.RLock()
+	listeners := make([]CostListener, len(view.listeners))
+	copy(listeners, view.listeners)
+	view.mu.RUnlock()
+
+	for _, listener := range listeners {
+		for _, edge := range edges {
+			listener.UpdateCost(edge)
+		}
+	}
+}
+
+// Adds a listener to be told about every change of costs from now on
+func (view *DynamicCostGraph) Subscribe(listener CostListener) {
+	view.mu.Lock()
+	defer view.mu.Unlock()
+
+	view.listeners = append(view.listeners, listener)
+}
+
+// Removes a listener added with Subscribe. Listeners are compared with ==, so they should be pointers (as a DStarInstance is) or some other comparable type.
+func (view *DynamicCostGraph) Unsubscribe(listener CostListener) {
+	view.mu.Lock()
+	defer view.mu.Unlock()
+
+	for i, l := range view.listeners {
+		if l == listener {
+			view.listeners = append(view.listeners[:i], view.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+/* Graph implementation */
+
+func (view *DynamicCostGraph) Successors(node core.Node) []core.Node {
+	return view.graph.Successors(node)
+}
+
+func (view *DynamicCostGraph) SuccessorsAppend(node core.Node, buf []core.Node) []core.Node {
+	return graphutil.SuccessorsAppend(view.graph, node, buf)
+}
+
+func (view *DynamicCostGraph) IsSuccessor(node, successor core.Node) bool {
+	return view.graph.IsSuccessor(node, successor)
+}
+
+func (view *DynamicCostGraph) Predecessors(node core.Node) []core.Node {
+	return view.graph.Predecessors(node)
+}
+
+func (view *DynamicCostGraph) IsPredecessor(node, predecessor core.Node) bool {
+	return view.graph.IsPredecessor(node, predecessor)
+}
+
+func (view *DynamicCostGraph) IsAdjacent(node, neighbor core.Node) bool {
+	return view.graph.IsAdjacent(node, neighbor)
+}
+
+func (view *DynamicCostGraph) NodeExists(node core.Node) bool {
+	return view.graph.NodeExists(node)
+}
+
+func (view *DynamicCostGraph) Degree(node core.Node) int {
+	return view.graph.Degree(node)
+}
+
+// Returns the graph's edges weighted with the current provider's costs, so that algorithms reading the weights off the edges see the same costs as those calling Cost
+func (view *DynamicCostGraph) EdgeList() []core.Edge {
+	provider := view.CostProvider()
+	edges := view.graph.EdgeList()
+	weighted := make([]core.Edge, len(edges))
+	for i, edge := range edges {
+		weighted[i] = core.GonumCostEdge{H: edge.Head(), T: edge.Tail(), W: provider.Cost(edge.Head(), edge.Tail())}
+	}
+
+	return weighted
+}
+
+func (view *DynamicCostGraph) NodeList() []core.Node {
+	return view.graph.NodeList()
+}
+
+func (view *DynamicCostGraph) IsDirected() bool {
+	return view.graph.IsDirected()
+}
+
+/* Coster implementation */
+
+func (view *DynamicCostGraph) Cost(node, succ core.Node) float64 {
+	return view.CostProvider().Cost(node, succ)
+}
+
+/* HeuristicCoster implementation */
+
+func (view *DynamicCostGraph) HeuristicCost(node, goal core.Node) float64 {
+	return view.heuristicCost(node, goal)
+}
@@ -0,0 +1,108 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"testing"
+)
+
+// Counts the edges it's told about
+type countingListener struct {
+	updates int
+}
+
+func (l *countingListener) UpdateCost(edge core.Edge) {
+	l.updates++
+}
+
+func TestDynamicCostGraph(t *testing.T) {
+	// Two routes from 0 to 3, through 1 or through 2, with every edge costing 1
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nodes(1, 2))
+	g.AddNode(core.GonumNode(3), nodes(1, 2))
+	start, goal := core.GonumNode(0), core.GonumNode(3)
+	through := func(route []core.Node) int {
+		if len(route) != 3 {
+			return -1
+		}
+		return route[1].ID()
+	}
+
+	// The traffic feed adds a delay for entering or leaving a jammed node
+	delay := make(map[int]float64)
+	traffic := simple.CostFunc(func(node, succ core.Node) float64 {
+		return 1 + delay[node.ID()] + delay[succ.ID()]
+	})
+
+	view := simple.NewDynamicCostGraph(g, nil)
+	planner := path.InitDStar(start, goal, view, nil, nil)
+	counter := &countingListener{}
+	view.Subscribe(planner)
+	view.Subscribe(counter)
+	if _, cost := planner.Path(); cost != 2 {
+		t.Fatalf("Planned a path costing %v before any traffic, want 2", cost)
+	}
+
+	// Until something is jammed the feed agrees with the graph, so swapping it in changes nothing
+	if changed := view.SetCostProvider(traffic); len(changed) != 0 || counter.updates != 0 {
+		t.Errorf("Swapping in an equal provider changed %v", changed)
+	}
+
+	delay[1] = 9
+	view.CostsChanged(core.GonumEdge{H: start, T: core.GonumNode(1)}, core.GonumEdge{H: core.GonumNode(1), T: goal})
+	if route, cost := planner.Path(); through(route) != 2 || cost != 2 || counter.updates != 2 {
+		t.Errorf("With 1 jammed planned %v costing %v after %d updates, want the route through 2", route, cost, counter.updates)
+	}
+
+	delay[2] = 20
+	view.CostsChanged(core.GonumEdge{H: core.GonumNode(2), T: start}, core.GonumEdge{H: goal, T: core.GonumNode(2)})
+	if route, cost := planner.Path(); through(route) != 1 || cost != 20 {
+		t.Errorf("With both jammed planned %v costing %v, want the route through 1", route, cost)
+	}
+	if route, cost, _ := path.AStar(start, goal, view, nil, nil); through(route) != 1 || cost != 20 {
+		t.Errorf("With both jammed AStar found %v costing %v, want the route through 1", route, cost)
+	}
+
+	// Going back to the graph's costs changes every edge, and an unsubscribed listener isn't told
+	view.Unsubscribe(counter)
+	if changed := view.SetCostProvider(nil); len(changed) != len(g.EdgeList()) || counter.updates != 4 {
+		t.Errorf("Swapping back changed %v, with %d updates", changed, counter.updates)
+	}
+	if _, cost := planner.Path(); cost != 2 {
+		t.Errorf("Planned a path costing %v after the traffic cleared, want 2", cost)
+	}
+	if _, ok := view.CostProvider().(simple.CostFunc); !ok {
+		t.Errorf("Got provider %T, want the graph's costs as a CostFunc", view.CostProvider())
+	}
+}
+
+func TestDynamicCostGraphEdgeList(t *testing.T) {
+	// A triangle whose edges cost 5, 1 and 3 in the graph, with the 5 made the cheapest by the provider
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nodes(1, 2))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}, 5)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, 1)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, 3)
+	view := simple.NewDynamicCostGraph(g, nil)
+	view.SetCostProvider(simple.CostFunc(func(node, succ core.Node) float64 {
+		if node.ID()+succ.ID() == 1 {
+			return 2
+		}
+		return g.Cost(node, succ)
+	}))
+
+	for _, edge := range view.EdgeList() {
+		if cedge, ok := edge.(core.CostEdge); !ok || cedge.Weight() != view.Cost(edge.Head(), edge.Tail()) {
+			t.Errorf("Got edge %v from the view, want it weighted %v", edge, view.Cost(edge.Head(), edge.Tail()))
+		}
+	}
+	if _, costs, _ := path.BellmanFord(core.GonumNode(0), view, nil); costs[1] != 2 || costs[2] != 3 {
+		t.Errorf("BellmanFord on the view found costs %v, want 2 to 1 and 3 to 2", costs)
+	}
+	if _, weight := topo.Kruskal(view, nil); weight != 3 {
+		t.Errorf("Kruskal on the view found a tree weighing %v, want 3", weight)
+	}
+}
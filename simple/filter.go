@@ -0,0 +1,149 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+)
+
+// A FilteredGraph is a read-only view of a graph with some of its nodes and edges hidden, as chosen by a node and an edge predicate, such as a road network minus its closed roads or
+// a grid minus its blocked cells. The view isn't a copy: it reads through to the graph and asks the predicates on every query, so it costs nothing to make, and later changes to the
+// graph or to whatever the predicates consult show up in it. An edge is visible when the predicates keep it and both of its ends.
+//
+// The view is a HeuristicCoster with the graph's costs and heuristic (uniform costs and the null heuristic if the graph has none), so AStar on it is guided just as on the graph
+// itself, and it passes SuccessorsAppend through.
+type FilteredGraph struct {
+	graph         core.Graph
+	node          func(core.Node) bool
+	edge          func(core.Edge) bool
+	cost          func(core.Node, core.Node) float64
+	heuristicCost func(core.Node, core.Node) float64
+}
+
+// Returns a view of the graph with only the nodes for which node returns true and the edges for which edge does. Either predicate may be nil to keep everything. The edge predicate is
+// given edges as GonumEdges in the direction they're followed, so for an undirected graph it should give the same answer both ways.
+func NewFilteredGraph(graph core.Graph, node func(core.Node) bool, edge func(core.Edge) bool) *FilteredGraph {
+	view := &FilteredGraph{graph: graph, node: node, edge: edge, cost: graphutil.DefaultCost(graph, nil), heuristicCost: core.NullHeuristic}
+	if hgraph, ok := graph.(core.HeuristicCoster); ok {
+		view.heuristicCost = hgraph.HeuristicCost
+	}
+
+	return view
+}
+
+// Returns the subgraph induced by the nodes: a view of the graph with only those nodes and the edges between them. Nodes that aren't in the graph are ignored.
+func Induce(graph core.Graph, nodes []core.Node) *FilteredGraph {
+	keep := make(map[int]bool, len(nodes))
+	for _, node := range nodes {
+		keep[node.ID()] = true
+	}
+
+	return NewFilteredGraph(graph, func(node core.Node) bool { return keep[node.ID()] }, nil)
+}
+
+func (view *FilteredGraph) hasNode(node core.Node) bool {
+	return view.node == nil || view.node(node)
+}
+
+func (view *FilteredGraph) hasEdge(head, tail core.Node) bool {
+	return view.hasNode(head) && view.hasNode(tail) && (view.edge == nil || view.edge(core.GonumEdge{H: head, T: tail}))
+}
+
+/* Graph implementation */
+
+func (view *FilteredGraph) Successors(node core.Node) []core.Node {
+	if !view.hasNode(node) {
+		return nil
+	}
+
+	return view.SuccessorsAppend(node, make([]core.Node, 0))
+}
+
+func (view *FilteredGraph) SuccessorsAppend(node core.Node, buf []core.Node) []core.Node {
+	if !view.hasNode(node) {
+		return buf
+	}
+
+	start := len(buf)
+	buf = graphutil.SuccessorsAppend(view.graph, node, buf)
+	kept := buf[:start]
+	for _, succ := range buf[start:] {
+		if view.hasEdge(node, succ) {
+			kept = append(kept, succ)
+		}
+	}
+
+	return kept
+}
+
+func (view *FilteredGraph) IsSuccessor(node, successor core.Node) bool {
+	return view.graph.IsSuccessor(node, successor) && view.hasEdge(node, successor)
+}
+
+func (view *FilteredGraph) Predecessors(node core.Node) []core.Node {
+	if !view.hasNode(node) {
+		return nil
+	}
+
+	preds := make([]core.Node, 0)
+	for _, pred := range view.graph.Predecessors(node) {
+		if view.hasEdge(pred, node) {
+			preds = append(preds, pred)
+		}
+	}
+
+	return preds
+}
+
+func (view *FilteredGraph) IsPredecessor(node, predecessor core.Node) bool {
+	return view.IsSuccessor(predecessor, node)
+}
+
+func (view *FilteredGraph) IsAdjacent(node, neighbor core.Node) bool {
+	return view.IsSuccessor(node, neighbor) || view.IsSuccessor(neighbor, node)
+}
+
+func (view *FilteredGraph) NodeExists(node core.Node) bool {
+	return view.graph.NodeExists(node) && view.hasNode(node)
+}
+
+func (view *FilteredGraph) Degree(node core.Node) int {
+	return len(view.Successors(node)) + len(view.Predecessors(node))
+}
+
+func (view *FilteredGraph) EdgeList() []core.Edge {
+	edges := make([]core.Edge, 0)
+	for _, edge := range view.graph.EdgeList() {
+		if view.hasEdge(edge.Head(), edge.Tail()) {
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges
+}
+
+func (view *FilteredGraph) NodeList() []core.Node {
+	nodes := make([]core.Node, 0)
+	for _, node := range view.graph.NodeList() {
+		if view.hasNode(node) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+func (view *FilteredGraph) IsDirected() bool {
+	return view.graph.IsDirected()
+}
+
+/* Coster implementation */
+
+func (view *FilteredGraph) Cost(node, succ core.Node) float64 {
+	return view.cost(node, succ)
+}
+
+/* HeuristicCoster implementation */
+
+func (view *FilteredGraph) HeuristicCost(node, goal core.Node) float64 {
+	return view.heuristicCost(node, goal)
+}
@@ -0,0 +1,69 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func TestFilteredGraph(t *testing.T) {
+	tg := simple.NewTileGraph(5, 5, true)
+	start, goal := tg.CoordsToNode(2, 0), tg.CoordsToNode(2, 4)
+
+	// A wall across the middle column, with a gap at the bottom
+	blocked := map[int]bool{}
+	for row := 0; row < 4; row++ {
+		blocked[tg.CoordsToNode(row, 2).ID()] = true
+	}
+	view := simple.NewFilteredGraph(tg, func(node core.Node) bool { return !blocked[node.ID()] }, nil)
+
+	route, cost, _ := path.AStar(start, goal, view, nil, nil)
+	if !path.IsPath(route, view) || cost != 8 {
+		t.Errorf("Found path %v costing %f around the wall, want cost 8", route, cost)
+	}
+	if _, cost, _ := path.AStar(start, goal, tg, nil, nil); cost != 4 {
+		t.Errorf("Filtering changed the graph, found cost %f through it", cost)
+	}
+	if len(view.NodeList()) != 21 || view.NodeExists(tg.CoordsToNode(0, 2)) || len(view.Successors(tg.CoordsToNode(1, 1))) != 3 || view.Successors(tg.CoordsToNode(1, 2)) != nil {
+		t.Errorf("Got %d nodes, and successors %v of a node next to the wall", len(view.NodeList()), view.Successors(tg.CoordsToNode(1, 1)))
+	}
+
+	// The predicates are asked on every query, so unblocking a cell opens it up
+	delete(blocked, tg.CoordsToNode(2, 2).ID())
+	if _, cost, _ := path.AStar(start, goal, view, nil, nil); cost != 4 {
+		t.Errorf("Found cost %f through the gap, want 4", cost)
+	}
+
+	// Hiding one direction of an edge
+	oneWay := simple.NewFilteredGraph(tg, nil, func(e core.Edge) bool {
+		return e.Head().ID() != tg.CoordsToNode(0, 0).ID() || e.Tail().ID() != tg.CoordsToNode(0, 1).ID()
+	})
+	if oneWay.IsSuccessor(tg.CoordsToNode(0, 0), tg.CoordsToNode(0, 1)) || !oneWay.IsSuccessor(tg.CoordsToNode(0, 1), tg.CoordsToNode(0, 0)) || len(oneWay.EdgeList()) != len(tg.EdgeList())-1 {
+		t.Error("Got the hidden edge")
+	}
+}
+
+func TestInduce(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1, 2, 3))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(1)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}, 5)
+
+	sub := simple.Induce(g, nodes(0, 1, 2, 9))
+	if len(sub.NodeList()) != 3 || len(sub.EdgeList()) != 3 || sub.IsSuccessor(core.GonumNode(0), core.GonumNode(3)) || sub.Cost(core.GonumNode(0), core.GonumNode(1)) != 5 {
+		t.Errorf("Got nodes %v and edges %v", sub.NodeList(), sub.EdgeList())
+	}
+	if preds := sub.Predecessors(core.GonumNode(1)); len(preds) != 1 || sub.Degree(core.GonumNode(1)) != 2 || sub.NodeExists(core.GonumNode(9)) {
+		t.Errorf("Got predecessors %v of 1", preds)
+	}
+
+	want := simple.NewGonumGraph(true)
+	want.AddNode(core.GonumNode(0), nodes(1, 2))
+	want.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	want.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}, 5)
+	if !core.Equal(sub, want, 0) {
+		t.Errorf("The induced subgraph differs from the expected one")
+	}
+}
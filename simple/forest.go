@@ -0,0 +1,184 @@
+package simple
+
+import (
+	"errors"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"sort"
+)
+
+// Errors returned by the checked mutations of a Forest
+var (
+	ErrHasParent  = errors.New("Node already has a parent")
+	ErrCycle      = errors.New("Edge would create a cycle")
+	ErrUndirected = errors.New("Forests are always directed")
+)
+
+// A Forest is a directed GonumGraph that's guaranteed to be a set of rooted trees: every edge points from a parent to its child, every node has at most one parent, and there are no cycles.
+// Nodes without a parent are roots. The invariants are checked on every insertion, so code building tree-structured data doesn't have to police them itself.
+//
+// A Forest implements MutableGraph, so it can be passed anywhere a MutableGraph is filled in, but the unchecked MutableGraph methods silently ignore insertions that would break the
+// invariants. Use the E-suffixed methods to find out why an insertion was refused. Removing a node or edge can't break the invariants; the removed node's children simply become roots.
+type Forest struct {
+	*GonumGraph
+}
+
+func NewForest() *Forest {
+	return &Forest{NewGonumGraph(true)}
+}
+
+// Forests are always directed, so this does nothing
+func (f *Forest) SetDirected(directed bool) {
+}
+
+// Returns ErrUndirected if directed is false, since forests are always directed
+func (f *Forest) SetDirectedE(directed bool) error {
+	if !directed {
+		return ErrUndirected
+	}
+
+	return nil
+}
+
+// Adds a new root with the given children, see AddNodeE
+func (f *Forest) AddNode(node core.Node, children []core.Node) {
+	f.AddNodeE(node, children)
+}
+
+// Adds a new root with the given children, which are created if they don't exist. Returns ErrNodeExists if the node is already in the forest, or ErrHasParent if any of the children
+// already has a parent (or is listed twice), or ErrCycle if the node is its own child. Nothing is added if an error is returned.
+func (f *Forest) AddNodeE(node core.Node, children []core.Node) error {
+	if f.NodeExists(node) {
+		return core.ErrNodeExists
+	}
+
+	seen := make(map[int]bool, len(children))
+	for _, child := range children {
+		if child.ID() == node.ID() {
+			return ErrCycle
+		} else if _, ok := f.Parent(child); ok || seen[child.ID()] {
+			return ErrHasParent
+		}
+		seen[child.ID()] = true
+	}
+
+	f.GonumGraph.AddNode(node, children)
+	return nil
+}
+
+// Adds a root with the lowest unused ID and the given children, see GonumGraph.NewNode. Children that already have parents are ignored.
+func (f *Forest) NewNode(children []core.Node) core.Node {
+	orphans := make([]core.Node, 0, len(children))
+	for _, child := range children {
+		if _, ok := f.Parent(child); !ok {
+			orphans = append(orphans, child)
+		}
+	}
+
+	return f.GonumGraph.NewNode(orphans)
+}
+
+// Makes the edge's tail a child of its head, see AddEdgeE
+func (f *Forest) AddEdge(e core.Edge) {
+	f.AddEdgeE(e)
+}
+
+// Makes the edge's tail a child of its head. As with GonumGraph, the head must exist and a missing tail is created; an existing tail must be a root, and grafting it moves its whole tree
+// under the head. Returns ErrNodeNotFound if the head doesn't exist, ErrEdgeExists if the edge is already there, ErrHasParent if the tail already has a different parent, or ErrCycle if
+// the tail is the head or one of its ancestors.
+func (f *Forest) AddEdgeE(e core.Edge) error {
+	head, tail := e.Head(), e.Tail()
+	if !f.NodeExists(head) {
+		return core.ErrNodeNotFound
+	} else if f.IsSuccessor(head, tail) {
+		return core.ErrEdgeExists
+	} else if _, ok := f.Parent(tail); ok {
+		return ErrHasParent
+	} else if f.IsAncestor(tail, head) {
+		return ErrCycle
+	}
+
+	f.GonumGraph.AddEdge(e)
+	return nil
+}
+
+// Returns the node's parent, and false if it's a root or isn't in the forest
+func (f *Forest) Parent(node core.Node) (parent core.Node, ok bool) {
+	for pred := range f.predecessors[node.ID()] {
+		return f.nodeMap[pred], true
+	}
+
+	return nil, false
+}
+
+// Returns the node's children sorted by ID
+func (f *Forest) Children(node core.Node) []core.Node {
+	children := graphutil.NodeSorter(f.Successors(node))
+	sort.Sort(children)
+
+	return children
+}
+
+// Returns all the roots sorted by ID
+func (f *Forest) Roots() []core.Node {
+	roots := make(graphutil.NodeSorter, 0)
+	for id, preds := range f.predecessors {
+		if len(preds) == 0 {
+			roots = append(roots, f.nodeMap[id])
+		}
+	}
+	sort.Sort(roots)
+
+	return roots
+}
+
+// Returns the root of the tree containing the node, or nil if the node isn't in the forest
+func (f *Forest) Root(node core.Node) core.Node {
+	if !f.NodeExists(node) {
+		return nil
+	}
+
+	root := f.nodeMap[node.ID()]
+	for parent, ok := f.Parent(root); ok; parent, ok = f.Parent(root) {
+		root = parent
+	}
+
+	return root
+}
+
+// Returns the number of edges between the node and its root, or -1 if the node isn't in the forest
+func (f *Forest) Depth(node core.Node) int {
+	if !f.NodeExists(node) {
+		return -1
+	}
+
+	depth := 0
+	for parent, ok := f.Parent(node); ok; parent, ok = f.Parent(parent) {
+		depth++
+	}
+
+	return depth
+}
+
+// Returns whether ancestor is node itself, or is reached by following parents up from node
+func (f *Forest) IsAncestor(ancestor, node core.Node) bool {
+	if !f.NodeExists(ancestor) || !f.NodeExists(node) {
+		return false
+	}
+
+	for curr, ok := node, true; ok; curr, ok = f.Parent(curr) {
+		if curr.ID() == ancestor.ID() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Removes the node and all of its descendants
+func (f *Forest) RemoveSubtree(node core.Node) {
+	for _, child := range f.Successors(node) {
+		f.RemoveSubtree(child)
+	}
+	f.RemoveNode(node)
+}
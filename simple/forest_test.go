@@ -0,0 +1,62 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+var _ core.MutableGraph = simple.NewForest()
+
+func TestForest(t *testing.T) {
+	// 0 -> {1, 2}, 1 -> 3, and a separate root 4
+	f := simple.NewForest()
+	if err := f.AddNodeE(core.GonumNode(0), nodes(1, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddEdgeE(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(3)}); err != nil {
+		t.Fatal(err)
+	}
+	f.AddNode(core.GonumNode(4), nil)
+
+	for _, test := range []struct {
+		h, t int
+		err  error
+	}{
+		{5, 4, core.ErrNodeNotFound},
+		{0, 1, core.ErrEdgeExists},
+		{2, 3, simple.ErrHasParent},
+		{3, 0, simple.ErrCycle},
+		{4, 4, simple.ErrCycle},
+	} {
+		if err := f.AddEdgeE(core.GonumEdge{H: core.GonumNode(test.h), T: core.GonumNode(test.t)}); err != test.err {
+			t.Errorf("Adding edge %d->%d gave error %v, want %v", test.h, test.t, err, test.err)
+		}
+	}
+	if err := f.AddNodeE(core.GonumNode(6), nodes(3)); err != simple.ErrHasParent || f.NodeExists(core.GonumNode(6)) {
+		t.Errorf("Adding a root with an adopted child gave error %v", err)
+	}
+
+	// Grafting the separate root under 3
+	if err := f.AddEdgeE(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)}); err != nil {
+		t.Fatal(err)
+	}
+	if roots := f.Roots(); len(roots) != 1 || roots[0].ID() != 0 {
+		t.Errorf("Roots are %v, want [0]", roots)
+	}
+	if depth, root := f.Depth(core.GonumNode(4)), f.Root(core.GonumNode(4)); depth != 3 || root.ID() != 0 {
+		t.Errorf("Node 4 has depth %d and root %v, want 3 and 0", depth, root)
+	}
+	if !f.IsAncestor(core.GonumNode(1), core.GonumNode(4)) || f.IsAncestor(core.GonumNode(2), core.GonumNode(4)) {
+		t.Error("IsAncestor is wrong for node 4")
+	}
+
+	f.RemoveNode(core.GonumNode(1))
+	if roots := f.Roots(); len(roots) != 2 || roots[1].ID() != 3 {
+		t.Errorf("After removing node 1 the roots are %v, want [0 3]", roots)
+	}
+	f.RemoveSubtree(core.GonumNode(3))
+	if len(f.NodeList()) != 2 {
+		t.Errorf("After removing the subtree at 3 the nodes are %v, want [0 2]", f.NodeList())
+	}
+}
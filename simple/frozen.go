@@ -0,0 +1,264 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"math"
+	"sort"
+)
+
+// A FrozenGraph is an immutable copy of a graph in compressed sparse row (CSR) form: the nodes are numbered by their position in ID order, and every node's successors, as those
+// numbers, and the costs of the edges to them are stored side by side in two flat slices, sorted, with an offset into them per node. Looking up a successor or a cost is a binary
+// search over a few contiguous values rather than two map lookups, and the whole graph takes a fraction of a GonumGraph's memory, which suits graphs that are built once and then
+// searched many times. A directed graph stores its predecessors the same way; an undirected one uses its successors for both.
+//
+// When the node IDs are exactly 0 to n-1, a node's number is its ID and no map is needed at all, which is the layout to aim for with graphs big enough for this to matter.
+type FrozenGraph struct {
+	directed bool
+	nodes    []core.Node // In ID order
+	index    map[int]int // The position of each node by ID, or nil if every node's ID is its position
+
+	succOffsets []int // The successors of nodes[i] are succs[succOffsets[i]:succOffsets[i+1]]
+	succs       []int
+	costs       []float64 // The cost of the edge to each entry of succs
+
+	predOffsets []int // Like succOffsets, for directed graphs only
+	preds       []int
+
+	metadata core.Metadata
+}
+
+// Returns a FrozenGraph with the graph's nodes, edges and costs (as resolved by defaultCost: uniform if the graph isn't a Coster), along with its metadata if it's a MetadataHolder.
+// Later changes to the graph don't affect the copy.
+func Frozen(graph core.Graph) *FrozenGraph {
+	nodes := core.SortedNodeList(graph)
+	frozen := &FrozenGraph{directed: graph.IsDirected(), nodes: nodes}
+	for i, node := range nodes {
+		if node.ID() != i {
+			frozen.index = make(map[int]int, len(nodes))
+			break
+		}
+	}
+	if frozen.index != nil {
+		for i, node := range nodes {
+			frozen.index[node.ID()] = i
+		}
+	}
+
+	if mgraph, ok := graph.(core.MetadataHolder); ok {
+		frozen.metadata = mgraph.Metadata().Clone()
+	}
+
+	Cost := graphutil.DefaultCost(graph, nil)
+	frozen.succOffsets = make([]int, len(nodes)+1)
+	for i, node := range nodes {
+		succs := graph.Successors(node)
+		row := make(adjacencyRow, 0, len(succs))
+		for _, succ := range succs {
+			if j, ok := frozen.indexOf(succ.ID()); ok {
+				row = append(row, adjacencyEntry{j, Cost(node, succ)})
+			}
+		}
+		sort.Sort(row)
+		for _, entry := range row {
+			frozen.succs = append(frozen.succs, entry.node)
+			frozen.costs = append(frozen.costs, entry.cost)
+		}
+		frozen.succOffsets[i+1] = len(frozen.succs)
+	}
+
+	if frozen.directed {
+		frozen.predOffsets, frozen.preds = transposeCSR(frozen.succOffsets, frozen.succs)
+	}
+
+	return frozen
+}
+
+// Returns the transpose of a CSR adjacency structure, with each row sorted, by counting the entries in every column first
+func transposeCSR(offsets, targets []int) (tOffsets, tTargets []int) {
+	n := len(offsets) - 1
+	tOffsets = make([]int, n+1)
+	for _, target := range targets {
+		tOffsets[target+1]++
+	}
+	for i := 0; i < n; i++ {
+		tOffsets[i+1] += tOffsets[i]
+	}
+
+	// Filling the rows in order of source keeps each of them sorted
+	tTargets = make([]int, len(targets))
+	next := append([]int(nil), tOffsets[:n]...)
+	for source := 0; source < n; source++ {
+		for _, target := range targets[offsets[source]:offsets[source+1]] {
+			tTargets[next[target]] = source
+			next[target]++
+		}
+	}
+
+	return tOffsets, tTargets
+}
+
+func (graph *FrozenGraph) indexOf(id int) (int, bool) {
+	if graph.index == nil {
+		return id, id >= 0 && id < len(graph.nodes)
+	}
+	i, ok := graph.index[id]
+	return i, ok
+}
+
+// Returns the position in succs of the edge from node i to node j, or -1 if there's no such edge
+func (graph *FrozenGraph) edgeIndex(i, j int) int {
+	lo, hi := graph.succOffsets[i], graph.succOffsets[i+1]
+	k := lo + sort.SearchInts(graph.succs[lo:hi], j)
+	if k < hi && graph.succs[k] == j {
+		return k
+	}
+
+	return -1
+}
+
+func (graph *FrozenGraph) predecessorRow(i int) []int {
+	if !graph.directed {
+		return graph.succs[graph.succOffsets[i]:graph.succOffsets[i+1]]
+	}
+
+	return graph.preds[graph.predOffsets[i]:graph.predOffsets[i+1]]
+}
+
+func (graph *FrozenGraph) Metadata() *core.Metadata {
+	return &graph.metadata
+}
+
+/* Graph implementation */
+
+func (graph *FrozenGraph) Successors(node core.Node) []core.Node {
+	return graph.SuccessorsAppend(node, nil)
+}
+
+func (graph *FrozenGraph) SuccessorsAppend(node core.Node, buf []core.Node) []core.Node {
+	i, ok := graph.indexOf(node.ID())
+	if !ok {
+		return buf
+	}
+	for _, j := range graph.succs[graph.succOffsets[i]:graph.succOffsets[i+1]] {
+		buf = append(buf, graph.nodes[j])
+	}
+
+	return buf
+}
+
+func (graph *FrozenGraph) IsSuccessor(node, successor core.Node) bool {
+	i, ok := graph.indexOf(node.ID())
+	if !ok {
+		return false
+	}
+	j, ok := graph.indexOf(successor.ID())
+	return ok && graph.edgeIndex(i, j) != -1
+}
+
+func (graph *FrozenGraph) Predecessors(node core.Node) []core.Node {
+	i, ok := graph.indexOf(node.ID())
+	if !ok {
+		return nil
+	}
+	row := graph.predecessorRow(i)
+	preds := make([]core.Node, len(row))
+	for k, j := range row {
+		preds[k] = graph.nodes[j]
+	}
+
+	return preds
+}
+
+func (graph *FrozenGraph) IsPredecessor(node, predecessor core.Node) bool {
+	return graph.IsSuccessor(predecessor, node)
+}
+
+func (graph *FrozenGraph) IsAdjacent(node, neighbor core.Node) bool {
+	return graph.IsSuccessor(node, neighbor) || graph.IsSuccessor(neighbor, node)
+}
+
+func (graph *FrozenGraph) NodeExists(node core.Node) bool {
+	_, ok := graph.indexOf(node.ID())
+	return ok
+}
+
+func (graph *FrozenGraph) Degree(node core.Node) int {
+	return graph.InDegree(node) + graph.OutDegree(node)
+}
+
+func (graph *FrozenGraph) InDegree(node core.Node) int {
+	i, ok := graph.indexOf(node.ID())
+	if !ok {
+		return 0
+	}
+
+	return len(graph.predecessorRow(i))
+}
+
+func (graph *FrozenGraph) OutDegree(node core.Node) int {
+	i, ok := graph.indexOf(node.ID())
+	if !ok {
+		return 0
+	}
+
+	return graph.succOffsets[i+1] - graph.succOffsets[i]
+}
+
+// Returns the edges in order of head and then tail ID, as GonumCostEdges
+func (graph *FrozenGraph) EdgeList() []core.Edge {
+	edges := make([]core.Edge, 0, len(graph.succs))
+	for i, node := range graph.nodes {
+		for k := graph.succOffsets[i]; k < graph.succOffsets[i+1]; k++ {
+			edges = append(edges, core.GonumCostEdge{H: node, T: graph.nodes[graph.succs[k]], W: graph.costs[k]})
+		}
+	}
+
+	return edges
+}
+
+// Returns the nodes in order of ID
+func (graph *FrozenGraph) NodeList() []core.Node {
+	return append([]core.Node(nil), graph.nodes...)
+}
+
+func (graph *FrozenGraph) IsDirected() bool {
+	return graph.directed
+}
+
+/* Coster implementation */
+
+// Returns the cost of the edge from node1 to node2, or +Inf if there's no such edge
+func (graph *FrozenGraph) Cost(node1, node2 core.Node) float64 {
+	i, iok := graph.indexOf(node1.ID())
+	j, jok := graph.indexOf(node2.ID())
+	if !iok || !jok {
+		return math.Inf(1)
+	}
+	if k := graph.edgeIndex(i, j); k != -1 {
+		return graph.costs[k]
+	}
+
+	return math.Inf(1)
+}
+
+/** Sorts a node's successors by position, keeping their costs with them **/
+
+type adjacencyEntry struct {
+	node int
+	cost float64
+}
+
+type adjacencyRow []adjacencyEntry
+
+func (row adjacencyRow) Len() int {
+	return len(row)
+}
+
+func (row adjacencyRow) Less(i, j int) bool {
+	return row[i].node < row[j].node
+}
+
+func (row adjacencyRow) Swap(i, j int) {
+	row[i], row[j] = row[j], row[i]
+}
@@ -0,0 +1,56 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encodingtest"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"testing"
+)
+
+func TestFrozen(t *testing.T) {
+	for name, g := range encodingtest.Fixtures() {
+		frozen := simple.Frozen(g)
+		if !core.Equal(g, frozen, 0) {
+			t.Errorf("%s: The frozen graph differs: %s", name, encodingtest.Diff(g, frozen))
+			continue
+		}
+		if frozen.Metadata().Name != g.Metadata().Name {
+			t.Errorf("%s: Got metadata %+v", name, *frozen.Metadata())
+		}
+		for _, node := range g.NodeList() {
+			if frozen.Degree(node) != g.Degree(node) || frozen.InDegree(node) != g.InDegree(node) || len(frozen.Predecessors(node)) != len(g.Predecessors(node)) {
+				t.Errorf("%s: Got degree %d and predecessors %v of %v, want %d and %v", name, frozen.Degree(node), frozen.Predecessors(node), node, g.Degree(node), g.Predecessors(node))
+			}
+			for _, pred := range g.Predecessors(node) {
+				if !frozen.IsPredecessor(node, pred) {
+					t.Errorf("%s: %v isn't a predecessor of %v", name, pred, node)
+				}
+			}
+		}
+	}
+
+	// Dense IDs are used as positions directly, and costs come along
+	tg, err := simple.GenerateTileGraph("    ▀   \n ▀▀ ▀ ▀ \n    ▀ ▀ \n▀▀▀ ▀   \n      ▀ ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetCost(2, 2, 5)
+	frozen := simple.Frozen(tg)
+	_, want, _ := path.AStar(tg.CoordsToNode(0, 0), tg.CoordsToNode(4, 7), tg, nil, nil)
+	if route, cost, _ := path.AStar(tg.CoordsToNode(0, 0), tg.CoordsToNode(4, 7), frozen, nil, nil); !path.IsPath(route, frozen) || cost != want {
+		t.Errorf("Found cost %f on the frozen graph, want %f", cost, want)
+	}
+
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(-3), []core.Node{core.GonumNode(40)})
+	frozen = simple.Frozen(g)
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(40), T: core.GonumNode(-3)})
+	if frozen.IsSuccessor(core.GonumNode(40), core.GonumNode(-3)) || !frozen.IsSuccessor(core.GonumNode(-3), core.GonumNode(40)) {
+		t.Error("The frozen graph changed with the original")
+	}
+	if frozen.NodeExists(core.GonumNode(0)) || frozen.Successors(core.GonumNode(0)) != nil || !math.IsInf(frozen.Cost(core.GonumNode(40), core.GonumNode(-3)), 1) {
+		t.Error("Got a node or edge that isn't in the frozen graph")
+	}
+}
@@ -0,0 +1,122 @@
+package simple
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"sort"
+	"time"
+)
+
+// The JSON form of a GonumGraph, see MarshalJSON
+type jsonGraph struct {
+	Directed   *bool                `json:"directed"`
+	Name       string               `json:"name,omitempty"`
+	Creator    string               `json:"creator,omitempty"`
+	Created    *time.Time           `json:"created,omitempty"`
+	Attributes map[string]string    `json:"attributes,omitempty"`
+	Nodes      []int                `json:"nodes"`
+	Edges      []graphutil.JSONEdge `json:"edges"`
+}
+
+// Encodes the graph as JSON, in a schema that will stay stable:
+//
+//	{
+//		"directed": true,
+//		"name": "roads",
+//		"creator": "importer",
+//		"created": "2014-03-01T12:00:00Z",
+//		"attributes": {"source": "survey"},
+//		"nodes": [0, 1, 2],
+//		"edges": [{"from": 0, "to": 1, "cost": 2.5}, {"from": 1, "to": 2, "cost": "+Inf"}]
+//	}
+//
+// directed, nodes and edges are always present. The metadata fields (name, creator, created and attributes) are left out when they're empty. Nodes are written as their IDs in
+// ascending order, and edges in order of from and then to, with each edge of an undirected graph written once, from the lower ID. Costs are JSON numbers, except for infinities and
+// NaN, which are the strings "+Inf", "-Inf" and "NaN". The output is the same for the same graph, however it was built.
+//
+// Only what's in the schema is written: edge IDs (see EdgeIdentifier) and validity intervals (see SetEdgeIntervals) aren't, and nodes come back as GonumNodes.
+func (graph *GonumGraph) MarshalJSON() ([]byte, error) {
+	jg := jsonGraph{
+		Directed:   &graph.directed,
+		Name:       graph.metadata.Name,
+		Creator:    graph.metadata.Creator,
+		Attributes: graph.metadata.Attributes,
+		Nodes:      make([]int, 0, len(graph.successors)),
+		Edges:      make([]graphutil.JSONEdge, 0),
+	}
+	if !graph.metadata.Created.IsZero() {
+		jg.Created = &graph.metadata.Created
+	}
+
+	for id := range graph.successors {
+		jg.Nodes = append(jg.Nodes, id)
+	}
+	sort.Ints(jg.Nodes)
+	for _, id := range jg.Nodes {
+		succs := make([]int, 0, len(graph.successors[id]))
+		for succ := range graph.successors[id] {
+			if graph.directed || id <= succ {
+				succs = append(succs, succ)
+			}
+		}
+		sort.Ints(succs)
+		for _, succ := range succs {
+			cost := graphutil.JSONCost(graph.successors[id][succ])
+			jg.Edges = append(jg.Edges, graphutil.JSONEdge{From: id, To: succ, Cost: &cost})
+		}
+	}
+
+	return json.Marshal(jg)
+}
+
+// Replaces the graph with one decoded from JSON in the schema MarshalJSON writes, metadata included. An edge's cost may be left out, in which case it's 1, and unknown fields are
+// ignored. Returns an error, leaving the graph as it was, if directed is missing, a node is listed twice, an edge is listed twice (in either direction, for an undirected graph), or
+// an edge's endpoints aren't both in the node list. As encoding/json expects, decoding null leaves the graph alone.
+func (graph *GonumGraph) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+
+	var jg jsonGraph
+	if err := json.Unmarshal(data, &jg); err != nil {
+		return err
+	}
+	if jg.Directed == nil {
+		return errors.New("Missing \"directed\"")
+	}
+
+	decoded := NewGonumGraph(*jg.Directed)
+	for _, id := range jg.Nodes {
+		if err := decoded.AddNodeE(core.GonumNode(id), nil); err != nil {
+			return fmt.Errorf("Node %d is listed twice", id)
+		}
+	}
+	for _, edge := range jg.Edges {
+		e := core.GonumEdge{H: core.GonumNode(edge.From), T: core.GonumNode(edge.To)}
+		if !decoded.NodeExists(e.H) || !decoded.NodeExists(e.T) {
+			return fmt.Errorf("Edge %d->%d has an endpoint that isn't in the node list", edge.From, edge.To)
+		}
+		if decoded.IsSuccessor(e.H, e.T) {
+			return fmt.Errorf("Edge %d->%d is listed twice", edge.From, edge.To)
+		}
+		decoded.AddEdge(e)
+		if edge.Cost != nil {
+			decoded.SetEdgeCost(e, float64(*edge.Cost))
+		}
+	}
+
+	decoded.metadata = core.Metadata{Name: jg.Name, Directed: *jg.Directed, Creator: jg.Creator, Attributes: jg.Attributes}
+	if jg.Created != nil {
+		decoded.metadata.Created = *jg.Created
+	}
+	if len(decoded.metadata.Attributes) == 0 {
+		decoded.metadata.Attributes = nil
+	}
+
+	*graph = *decoded
+	return nil
+}
@@ -0,0 +1,32 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"math/rand"
+)
+
+// Builds an undirected path graph 0-1-2-...-(n-1)
+func pathGraph(n int) *simple.GonumGraph {
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nil)
+	for i := 1; i < n; i++ {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(i - 1), T: core.GonumNode(i)})
+	}
+
+	return g
+}
+
+// Builds a random graph with n nodes and about n*degree edges, dense enough that ParallelBFS switches to bottom-up steps
+func randomGraph(n, degree int, directed bool, seed int64) *simple.GonumGraph {
+	rnd := rand.New(rand.NewSource(seed))
+	g := simple.NewGonumGraph(directed)
+	for i := 0; i < n; i++ {
+		g.AddNode(core.GonumNode(i), nil)
+	}
+	for i := 0; i < n*degree; i++ {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(rnd.Intn(n)), T: core.GonumNode(rnd.Intn(n))})
+	}
+
+	return g
+}
@@ -0,0 +1,311 @@
+package simple
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+)
+
+// A HexTileGraph is a map of hexagonal tiles, each passable or not, as many strategy games use. Each tile has six neighbors, so moves in every direction cost the same and paths don't
+// zigzag the way they do on a square TileGraph.
+//
+// The map is laid out as a rectangle of rows of pointy-topped hexes, with the odd rows shifted half a tile to the right, which is how String draws it. Tiles are addressed by axial
+// coordinates (q, r): r is the row, and q increases to the East along a row but, unlike a column, also shifts by one every two rows, so that the six neighbors of any tile are the same
+// offsets from it (see HexDirection). Node IDs count the tiles row by row, as in a TileGraph. Every move costs 1.
+type HexTileGraph struct {
+	tiles            []bool
+	nodes            []core.Node // nodes[id] == GonumNode(id), boxed once up front so that SuccessorsAppend doesn't allocate
+	numRows, numCols int
+}
+
+// Returns a map of hexes with the given number of rows and tiles per row, all passable or all impassable.
+func NewHexTileGraph(rows, cols int, passable bool) *HexTileGraph {
+	tiles := make([]bool, rows*cols)
+	if passable {
+		for i := range tiles {
+			tiles[i] = true
+		}
+	}
+
+	return &HexTileGraph{
+		tiles:   tiles,
+		nodes:   tileNodes(len(tiles)),
+		numRows: rows,
+		numCols: cols,
+	}
+}
+
+// Sets whether the tile at the given axial coordinates is passable. Coordinates outside the graph are ignored.
+func (graph *HexTileGraph) SetPassability(q, r int, passable bool) {
+	if id := graph.CoordsToID(q, r); id != -1 {
+		graph.tiles[id] = passable
+	}
+}
+
+// Returns the number of rows and of tiles per row
+func (graph *HexTileGraph) Dimensions() (rows, cols int) {
+	return graph.numRows, graph.numCols
+}
+
+// Returns the axial coordinates of the tile with the given ID
+func (graph *HexTileGraph) IDToCoords(id int) (q, r int) {
+	r = id / graph.numCols
+	return id%graph.numCols - r/2, r
+}
+
+// Returns the ID of the tile at the given axial coordinates, or -1 if they're outside the graph
+func (graph *HexTileGraph) CoordsToID(q, r int) (id int) {
+	col := q + r/2
+	if r < 0 || r >= graph.numRows || col < 0 || col >= graph.numCols {
+		return -1
+	}
+
+	return r*graph.numCols + col
+}
+
+// Returns the tile at the given axial coordinates, or nil if they're outside the graph
+func (graph *HexTileGraph) CoordsToNode(q, r int) core.Node {
+	id := graph.CoordsToID(q, r)
+	if id == -1 {
+		return nil
+	}
+
+	return graph.nodes[id]
+}
+
+// Returns the number of moves between two tiles on an open map, whether or not they're in the graph
+func HexDistance(q1, r1, q2, r2 int) int {
+	dq, dr := q1-q2, r1-r2
+	ds := dq + dr
+	if dq < 0 {
+		dq = -dq
+	}
+	if dr < 0 {
+		dr = -dr
+	}
+	if ds < 0 {
+		ds = -ds
+	}
+
+	return (dq + dr + ds) / 2
+}
+
+// A HexDirection is one of the six moves between neighboring tiles of a HexTileGraph, in counterclockwise order from East.
+type HexDirection int
+
+const (
+	HexEast HexDirection = iota
+	HexNorthEast
+	HexNorthWest
+	HexWest
+	HexSouthWest
+	HexSouthEast
+)
+
+// The six directions, in the order SuccessorsAppend lists a tile's neighbors in.
+var HexDirections = []HexDirection{HexEast, HexNorthEast, HexNorthWest, HexWest, HexSouthWest, HexSouthEast}
+
+func (dir HexDirection) String() string {
+	switch dir {
+	case HexEast:
+		return "East"
+	case HexNorthEast:
+		return "NorthEast"
+	case HexNorthWest:
+		return "NorthWest"
+	case HexWest:
+		return "West"
+	case HexSouthWest:
+		return "SouthWest"
+	case HexSouthEast:
+		return "SouthEast"
+	}
+
+	return fmt.Sprintf("HexDirection(%d)", int(dir))
+}
+
+// Returns the direction that undoes a move in this one.
+func (dir HexDirection) Opposite() HexDirection {
+	if dir < HexEast || dir > HexSouthEast {
+		return dir
+	}
+
+	return (dir + 3) % 6
+}
+
+// Returns the change in axial coordinates a move in this direction makes.
+func (dir HexDirection) Offset() (dq, dr int) {
+	switch dir {
+	case HexEast:
+		return 1, 0
+	case HexNorthEast:
+		return 1, -1
+	case HexNorthWest:
+		return 0, -1
+	case HexWest:
+		return -1, 0
+	case HexSouthWest:
+		return -1, 1
+	case HexSouthEast:
+		return 0, 1
+	}
+
+	return 0, 0
+}
+
+// Returns the tile next to node in the given direction, and whether it's possible to move there: ok is false, and the neighbor nil, if either tile is impassable or the move would
+// leave the graph.
+func (graph *HexTileGraph) Neighbor(node core.Node, dir HexDirection) (neighbor core.Node, ok bool) {
+	if !graph.NodeExists(node) {
+		return nil, false
+	}
+
+	q, r := graph.IDToCoords(node.ID())
+	dq, dr := dir.Offset()
+	id := graph.CoordsToID(q+dq, r+dr)
+	if (dq == 0 && dr == 0) || id == -1 || !graph.tiles[id] {
+		return nil, false
+	}
+
+	return graph.nodes[id], true
+}
+
+// Draws the map with '⬢' for impassable tiles and '⬡' for passable ones, separated by spaces, with the odd rows indented by one space so that each row sits between its neighbors.
+func (graph *HexTileGraph) String() string {
+	var buf bytes.Buffer
+	for r := 0; r < graph.numRows; r++ {
+		if r > 0 {
+			buf.WriteByte('\n')
+		}
+		if r%2 == 1 {
+			buf.WriteByte(' ')
+		}
+
+		for c := 0; c < graph.numCols; c++ {
+			if c > 0 {
+				buf.WriteByte(' ')
+			}
+			if graph.tiles[r*graph.numCols+c] {
+				buf.WriteString("⬡") // White hexagon
+			} else {
+				buf.WriteString("⬢") // Black hexagon
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+// Returns 1, the cost of every move. Doesn't check that the tiles are adjacent.
+func (graph *HexTileGraph) Cost(node, succ core.Node) float64 {
+	return 1
+}
+
+// Returns the number of moves between the tiles on an open map (see HexDistance), which is a lower bound on the cost of the cheapest path between them, so AStar searches the graph
+// with this heuristic when it isn't given one.
+func (graph *HexTileGraph) HeuristicCost(node, goal core.Node) float64 {
+	from, to := node.ID(), goal.ID()
+	if from < 0 || from >= len(graph.tiles) || to < 0 || to >= len(graph.tiles) {
+		return 0
+	}
+
+	q1, r1 := graph.IDToCoords(from)
+	q2, r2 := graph.IDToCoords(to)
+	return float64(HexDistance(q1, r1, q2, r2))
+}
+
+func (graph *HexTileGraph) Successors(node core.Node) []core.Node {
+	if !graph.NodeExists(node) {
+		return nil
+	}
+
+	return graph.SuccessorsAppend(node, make([]core.Node, 0, 6))
+}
+
+// Appends the successors of node to buf, in the order of HexDirections, and returns the extended slice. Doesn't allocate as long as buf has room for six more nodes.
+func (graph *HexTileGraph) SuccessorsAppend(node core.Node, buf []core.Node) []core.Node {
+	if !graph.NodeExists(node) {
+		return buf
+	}
+
+	q, r := graph.IDToCoords(node.ID())
+	for _, dir := range HexDirections {
+		dq, dr := dir.Offset()
+		if id := graph.CoordsToID(q+dq, r+dr); id != -1 && graph.tiles[id] {
+			buf = append(buf, graph.nodes[id])
+		}
+	}
+
+	return buf
+}
+
+func (graph *HexTileGraph) IsSuccessor(node, successor core.Node) bool {
+	if !graph.NodeExists(node) || !graph.NodeExists(successor) {
+		return false
+	}
+
+	q1, r1 := graph.IDToCoords(node.ID())
+	q2, r2 := graph.IDToCoords(successor.ID())
+	return HexDistance(q1, r1, q2, r2) == 1
+}
+
+func (graph *HexTileGraph) Predecessors(node core.Node) []core.Node {
+	return graph.Successors(node)
+}
+
+func (graph *HexTileGraph) IsPredecessor(node, pred core.Node) bool {
+	return graph.IsSuccessor(node, pred)
+}
+
+func (graph *HexTileGraph) IsAdjacent(node, neighbor core.Node) bool {
+	return graph.IsSuccessor(node, neighbor)
+}
+
+func (graph *HexTileGraph) NodeExists(node core.Node) bool {
+	id := node.ID()
+	return id >= 0 && id < len(graph.tiles) && graph.tiles[id]
+}
+
+func (graph *HexTileGraph) Degree(node core.Node) int {
+	return graph.OutDegree(node) * 2
+}
+
+func (graph *HexTileGraph) InDegree(node core.Node) int {
+	return graph.OutDegree(node)
+}
+
+func (graph *HexTileGraph) OutDegree(node core.Node) int {
+	var buf [6]core.Node
+	return len(graph.SuccessorsAppend(node, buf[:0]))
+}
+
+func (graph *HexTileGraph) EdgeList() []core.Edge {
+	edges := make([]core.Edge, 0)
+	var buf [6]core.Node
+	for id, passable := range graph.tiles {
+		if !passable {
+			continue
+		}
+
+		for _, succ := range graph.SuccessorsAppend(graph.nodes[id], buf[:0]) {
+			edges = append(edges, core.GonumEdge{H: graph.nodes[id], T: succ})
+		}
+	}
+
+	return edges
+}
+
+func (graph *HexTileGraph) NodeList() []core.Node {
+	nodes := make([]core.Node, 0)
+	for id, passable := range graph.tiles {
+		if passable {
+			nodes = append(nodes, graph.nodes[id])
+		}
+	}
+
+	return nodes
+}
+
+func (graph *HexTileGraph) IsDirected() bool {
+	return false
+}
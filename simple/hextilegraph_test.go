@@ -0,0 +1,59 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func TestHexTileGraph(t *testing.T) {
+	hg := simple.NewHexTileGraph(4, 5, true)
+	for id := 0; id < 20; id++ {
+		q, r := hg.IDToCoords(id)
+		if hg.CoordsToID(q, r) != id {
+			t.Errorf("Tile %d has coordinates (%d, %d), which map back to %d", id, q, r, hg.CoordsToID(q, r))
+		}
+	}
+	if hg.CoordsToID(-1, 0) != -1 || hg.CoordsToID(5, 1) != -1 || hg.CoordsToNode(0, 4) != nil {
+		t.Error("Got a tile outside the graph")
+	}
+
+	// An inner tile has six neighbors, which are its neighbors' neighbors in the opposite directions
+	center := hg.CoordsToNode(1, 2)
+	if succ := hg.Successors(center); len(succ) != 6 {
+		t.Errorf("Got successors %v, want six", succ)
+	}
+	for _, dir := range simple.HexDirections {
+		neighbor, ok := hg.Neighbor(center, dir)
+		if back, _ := hg.Neighbor(neighbor, dir.Opposite()); !ok || back.ID() != center.ID() || !hg.IsSuccessor(center, neighbor) {
+			t.Errorf("Moving %v from %v reached %v, and back %v", dir, center, neighbor, back)
+		}
+	}
+	if corner := hg.CoordsToNode(0, 0); hg.OutDegree(corner) != 2 || hg.IsSuccessor(corner, hg.CoordsToNode(2, 0)) {
+		t.Errorf("The corner has successors %v", hg.Successors(corner))
+	}
+
+	// A wall across the map with one gap
+	for q := 0; q < 4; q++ {
+		hg.SetPassability(q-1, 2, false)
+	}
+	want := "" +
+		"⬡ ⬡ ⬡ ⬡ ⬡\n" +
+		" ⬡ ⬡ ⬡ ⬡ ⬡\n" +
+		"⬢ ⬢ ⬢ ⬢ ⬡\n" +
+		" ⬡ ⬡ ⬡ ⬡ ⬡"
+	if hg.String() != want {
+		t.Errorf("Drew\n%s\nwant\n%s", hg.String(), want)
+	}
+
+	// Three moves apart, but the path has to go around through the gap
+	start, goal := hg.CoordsToNode(0, 0), hg.CoordsToNode(-1, 3)
+	route, cost, _ := path.AStar(start, goal, hg, nil, nil)
+	if !path.IsPath(route, hg) || cost != 9 || hg.HeuristicCost(start, goal) != 3 {
+		t.Errorf("Got path %v costing %v, want cost 9", route, cost)
+	}
+	// 4 edges along each of the three open rows, 9 between the first two, and 2 either side of the gap, each listed both ways
+	if len(hg.EdgeList()) != 2*(3*4+9+2+2) {
+		t.Errorf("Got %d edges", len(hg.EdgeList()))
+	}
+}
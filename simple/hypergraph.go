@@ -0,0 +1,201 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"sort"
+)
+
+// A Hypergraph is a set of nodes and hyperedges, where each hyperedge joins an arbitrary set of nodes rather than just two. They model things like co-authorship (each paper is a hyperedge
+// joining its authors) or any other system of sets. Hyperedges are identified by the int ID that AddHyperedge returns.
+//
+// A Hypergraph doesn't implement Graph; instead, convert it with IncidenceGraph or CliqueExpansion to run the algorithms on it.
+type Hypergraph struct {
+	nodeMap    map[int]core.Node
+	hyperedges map[int][]core.Node  // Sorted by ID
+	incidence  map[int]map[int]bool // Node ID -> the IDs of the hyperedges containing it
+	nextID     int
+}
+
+func NewHypergraph() *Hypergraph {
+	return &Hypergraph{
+		nodeMap:    make(map[int]core.Node),
+		hyperedges: make(map[int][]core.Node),
+		incidence:  make(map[int]map[int]bool),
+	}
+}
+
+// Adds a node that isn't in any hyperedge (yet). Does nothing if the node already exists.
+func (h *Hypergraph) AddNode(node core.Node) {
+	if _, ok := h.nodeMap[node.ID()]; ok {
+		return
+	}
+
+	h.nodeMap[node.ID()] = node
+	h.incidence[node.ID()] = make(map[int]bool)
+}
+
+// Adds a hyperedge joining the given nodes, adding any nodes that don't exist yet, and returns the hyperedge's ID. Duplicate nodes are only counted once. The same set of nodes may be joined
+// by several hyperedges (two papers by the same authors are still two papers).
+func (h *Hypergraph) AddHyperedge(nodes []core.Node) (id int) {
+	id = h.nextID
+	h.nextID++
+
+	members := make(graphutil.NodeSorter, 0, len(nodes))
+	for _, node := range nodes {
+		h.AddNode(node)
+		if !h.incidence[node.ID()][id] {
+			h.incidence[node.ID()][id] = true
+			members = append(members, h.nodeMap[node.ID()])
+		}
+	}
+	sort.Sort(members)
+	h.hyperedges[id] = members
+
+	return id
+}
+
+// Removes a hyperedge, leaving its nodes in place
+func (h *Hypergraph) RemoveHyperedge(id int) {
+	for _, node := range h.hyperedges[id] {
+		delete(h.incidence[node.ID()], id)
+	}
+	delete(h.hyperedges, id)
+}
+
+// Removes a node from the hypergraph and from every hyperedge containing it. Hyperedges that are left empty are removed as well.
+func (h *Hypergraph) RemoveNode(node core.Node) {
+	id := node.ID()
+	for e := range h.incidence[id] {
+		members := h.hyperedges[e]
+		for i, member := range members {
+			if member.ID() == id {
+				members = append(members[:i], members[i+1:]...)
+				break
+			}
+		}
+
+		if len(members) == 0 {
+			delete(h.hyperedges, e)
+		} else {
+			h.hyperedges[e] = members
+		}
+	}
+
+	delete(h.incidence, id)
+	delete(h.nodeMap, id)
+}
+
+// Returns the nodes joined by a hyperedge sorted by ID, or nil if there's no hyperedge with that ID
+func (h *Hypergraph) Hyperedge(id int) []core.Node {
+	members, ok := h.hyperedges[id]
+	if !ok {
+		return nil
+	}
+
+	return append([]core.Node(nil), members...)
+}
+
+// Returns the IDs of all the hyperedges, in increasing order
+func (h *Hypergraph) Hyperedges() []int {
+	ids := make([]int, 0, len(h.hyperedges))
+	for id := range h.hyperedges {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// Returns the IDs of the hyperedges containing the node, in increasing order
+func (h *Hypergraph) IncidentHyperedges(node core.Node) []int {
+	ids := make([]int, 0, len(h.incidence[node.ID()]))
+	for id := range h.incidence[node.ID()] {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// Returns the number of hyperedges containing the node
+func (h *Hypergraph) Degree(node core.Node) int {
+	return len(h.incidence[node.ID()])
+}
+
+func (h *Hypergraph) NodeExists(node core.Node) bool {
+	_, ok := h.nodeMap[node.ID()]
+	return ok
+}
+
+// Returns all the nodes in no particular order
+func (h *Hypergraph) NodeList() []core.Node {
+	nodes := make([]core.Node, 0, len(h.nodeMap))
+	for _, node := range h.nodeMap {
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// Returns the bipartite incidence graph (also called the Levi graph, or star expansion) of the hypergraph: an undirected graph containing every node, plus one new node for every
+// hyperedge that's joined to each of the hyperedge's members. The hyperedge nodes are given IDs above the largest node ID, and the returned map goes from hyperedge ID to the node
+// standing in for it.
+func (h *Hypergraph) IncidenceGraph() (graph *GonumGraph, hyperedgeNodes map[int]core.Node) {
+	graph = NewGonumGraph(false)
+	nodes := graphutil.NodeSorter(h.NodeList())
+	sort.Sort(nodes)
+	maxID := -1
+	for _, node := range nodes {
+		graph.AddNode(node, nil)
+		maxID = node.ID()
+	}
+
+	hyperedgeNodes = make(map[int]core.Node, len(h.hyperedges))
+	for _, e := range h.Hyperedges() {
+		maxID++
+		hyperedgeNode := core.GonumNode(maxID)
+		graph.AddNode(hyperedgeNode, h.hyperedges[e])
+		hyperedgeNodes[e] = hyperedgeNode
+	}
+
+	return graph, hyperedgeNodes
+}
+
+// Returns the clique expansion of the hypergraph: an undirected graph with the same nodes, where two nodes are adjacent if they share at least one hyperedge. The more hyperedges two nodes
+// share, the more closely tied they are, so the cost of each edge is 1/(the number of shared hyperedges). That makes the affinities used by the clustering algorithms (which are 1/Cost)
+// equal to the shared counts.
+func (h *Hypergraph) CliqueExpansion() *GonumGraph {
+	shared := make(map[core.EdgeKey]int)
+	for _, members := range h.hyperedges {
+		for i, a := range members {
+			for _, b := range members[i+1:] {
+				shared[core.EdgeKey{Head: a.ID(), Tail: b.ID()}]++ // Members are sorted, so this is already the canonical undirected key
+			}
+		}
+	}
+
+	return sharedCountGraph(h.NodeList(), shared)
+}
+
+// Builds an undirected graph of the given nodes, with an edge of cost 1/count for every pair of nodes in shared. Edges are added in key order so that their IDs are deterministic.
+func sharedCountGraph(nodes []core.Node, shared map[core.EdgeKey]int) *GonumGraph {
+	graph := NewGonumGraph(false)
+	for _, node := range nodes {
+		graph.AddNode(node, nil)
+	}
+
+	keys := make(graphutil.EdgeKeySorter, 0, len(shared))
+	for key := range shared {
+		keys = append(keys, key)
+	}
+	sort.Sort(keys)
+
+	for _, key := range keys {
+		edge := core.GonumEdge{H: graph.nodeMap[key.Head], T: graph.nodeMap[key.Tail]}
+		graph.AddEdge(edge)
+		graph.SetEdgeCost(edge, 1/float64(shared[key]))
+	}
+
+	return graph
+}
@@ -0,0 +1,55 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func nodes(ids ...int) []core.Node {
+	nodes := make([]core.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = core.GonumNode(id)
+	}
+
+	return nodes
+}
+
+func TestHypergraph(t *testing.T) {
+	// Three papers: {0, 1, 2}, {1, 2} and {2, 3}
+	h := simple.NewHypergraph()
+	h.AddHyperedge(nodes(2, 0, 1, 1))
+	second := h.AddHyperedge(nodes(1, 2))
+	h.AddHyperedge(nodes(2, 3))
+
+	if members := h.Hyperedge(0); len(members) != 3 || members[0].ID() != 0 || members[2].ID() != 2 {
+		t.Errorf("Hyperedge 0 is %v, want [0 1 2]", members)
+	}
+	if degree := h.Degree(core.GonumNode(2)); degree != 3 {
+		t.Errorf("Node 2 is in %d hyperedges, want 3", degree)
+	}
+
+	clique := h.CliqueExpansion()
+	for _, test := range []struct {
+		a, b int
+		cost float64
+	}{{0, 1, 1}, {1, 2, .5}, {2, 3, 1}} {
+		if cost := clique.Cost(core.GonumNode(test.a), core.GonumNode(test.b)); cost != test.cost {
+			t.Errorf("Clique expansion edge %d-%d has cost %f, want %f", test.a, test.b, cost, test.cost)
+		}
+	}
+	if clique.IsAdjacent(core.GonumNode(0), core.GonumNode(3)) {
+		t.Error("Nodes 0 and 3 share no hyperedge but are adjacent in the clique expansion")
+	}
+
+	incidence, hyperedgeNodes := h.IncidenceGraph()
+	if len(incidence.NodeList()) != 7 || hyperedgeNodes[0].ID() != 4 || len(incidence.Successors(hyperedgeNodes[second])) != 2 {
+		t.Errorf("Got incidence graph with %d nodes and hyperedge nodes %v", len(incidence.NodeList()), hyperedgeNodes)
+	}
+
+	h.RemoveNode(core.GonumNode(3))
+	h.RemoveHyperedge(second)
+	if ids := h.Hyperedges(); len(ids) != 2 || len(h.Hyperedge(2)) != 1 {
+		t.Errorf("After removals hyperedges are %v, hyperedge 2 is %v", ids, h.Hyperedge(2))
+	}
+}
@@ -0,0 +1,97 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+)
+
+// An ImplicitGraph is a directed graph given by a successor function rather than stored node and edge lists, such as the state space of a puzzle or a planner, where the nodes are
+// states and the successors of a state are the states one move away. The graph is explored as a search asks for it, so it may be far too large to materialize, or infinite.
+//
+// Every node must have an ID that's unique to its state, since the searches tell states apart by ID alone; encoding the state into an int (say, a puzzle's tile positions in base 16)
+// is the usual way to get one. A successor function that returns new Nodes for the same state each time is fine, as long as their IDs match.
+//
+// Only the successor side of the graph is known: Predecessors returns nil, and NodeList and EdgeList return nil as there's no way to enumerate the graph. That's enough for AStar,
+// AStarWithOptions, a Searcher and DijkstraPath, which only ever ask for the successors of nodes they've reached, but not for algorithms that need the whole graph.
+type ImplicitGraph struct {
+	successors    func(core.Node) []core.Node
+	cost          func(core.Node, core.Node) float64
+	heuristicCost func(core.Node, core.Node) float64
+}
+
+// Returns an ImplicitGraph with the given successor function. Cost gives the cost of a move, and is UniformCost if nil; HeuristicCost estimates the cost from a state to the goal,
+// and is NullHeuristic if nil. AStar uses them when it's passed nil functions. Since the graph is a HeuristicCoster either way, AStar always runs its general search on it, never
+// the breadth first or Dijkstra fast paths.
+func NewImplicitGraph(successors func(core.Node) []core.Node, Cost, HeuristicCost func(core.Node, core.Node) float64) *ImplicitGraph {
+	if Cost == nil {
+		Cost = core.UniformCost
+	}
+	if HeuristicCost == nil {
+		HeuristicCost = core.NullHeuristic
+	}
+
+	return &ImplicitGraph{successors: successors, cost: Cost, heuristicCost: HeuristicCost}
+}
+
+func (graph *ImplicitGraph) Successors(node core.Node) []core.Node {
+	return graph.successors(node)
+}
+
+func (graph *ImplicitGraph) SuccessorsAppend(node core.Node, buf []core.Node) []core.Node {
+	return append(buf, graph.successors(node)...)
+}
+
+func (graph *ImplicitGraph) IsSuccessor(node, successor core.Node) bool {
+	for _, succ := range graph.successors(node) {
+		if succ.ID() == successor.ID() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Always returns nil, since the successor function can't be run backwards
+func (graph *ImplicitGraph) Predecessors(node core.Node) []core.Node {
+	return nil
+}
+
+// Returns whether node is a successor of predecessor, which unlike Predecessors can be answered
+func (graph *ImplicitGraph) IsPredecessor(node, predecessor core.Node) bool {
+	return graph.IsSuccessor(predecessor, node)
+}
+
+func (graph *ImplicitGraph) IsAdjacent(node, neighbor core.Node) bool {
+	return graph.IsSuccessor(node, neighbor) || graph.IsSuccessor(neighbor, node)
+}
+
+// Always returns true, every state the caller can name is a node of the graph
+func (graph *ImplicitGraph) NodeExists(node core.Node) bool {
+	return true
+}
+
+// Returns the number of successors, since the predecessors aren't known
+func (graph *ImplicitGraph) Degree(node core.Node) int {
+	return len(graph.successors(node))
+}
+
+// Always returns nil, the graph can't be enumerated
+func (graph *ImplicitGraph) EdgeList() []core.Edge {
+	return nil
+}
+
+// Always returns nil, the graph can't be enumerated
+func (graph *ImplicitGraph) NodeList() []core.Node {
+	return nil
+}
+
+func (graph *ImplicitGraph) IsDirected() bool {
+	return true
+}
+
+func (graph *ImplicitGraph) Cost(node, succ core.Node) float64 {
+	return graph.cost(node, succ)
+}
+
+func (graph *ImplicitGraph) HeuristicCost(node, goal core.Node) float64 {
+	return graph.heuristicCost(node, goal)
+}
@@ -0,0 +1,64 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"testing"
+)
+
+func TestImplicitGraph(t *testing.T) {
+	// The states are the integers up to 200, and the moves from n are to n+1 and 2n
+	successors := func(node core.Node) []core.Node {
+		var succs []core.Node
+		for _, next := range []int{node.ID() + 1, node.ID() * 2} {
+			if next <= 200 {
+				succs = append(succs, core.GonumNode(next))
+			}
+		}
+		return succs
+	}
+	// No move more than doubles the state, so this never overestimates
+	doublings := func(node, goal core.Node) float64 {
+		if node.ID() >= goal.ID() {
+			return 0
+		}
+		return math.Ceil(math.Log2(float64(goal.ID()) / float64(node.ID())))
+	}
+	start, goal := core.GonumNode(1), core.GonumNode(100)
+
+	g := simple.NewImplicitGraph(successors, nil, nil)
+	route, cost, wantExpanded := path.AStar(start, goal, g, nil, nil)
+	// 1 2 3 6 12 24 25 50 100, a doubling for every bit of 100 after the first and an increment for every 1 among them
+	if !path.IsPath(route, g) || cost != 8 {
+		t.Errorf("Found path %v costing %v, want cost 8", route, cost)
+	}
+	if _, cost, _ := path.DijkstraPath(start, goal, g, nil); cost != 8 {
+		t.Errorf("DijkstraPath found cost %v, want 8", cost)
+	}
+
+	guided := simple.NewImplicitGraph(successors, nil, doublings)
+	route, cost, expanded := path.AStar(start, goal, guided, nil, nil)
+	if !path.IsPath(route, guided) || cost != 8 || expanded >= wantExpanded {
+		t.Errorf("Heuristic search found cost %v expanding %d nodes, without the heuristic %d were expanded", cost, expanded, wantExpanded)
+	}
+
+	// Doublings cost 10, so five increments are cheaper than one
+	weighted := simple.NewImplicitGraph(successors, func(a, b core.Node) float64 {
+		if b.ID() == a.ID()+1 {
+			return 1
+		}
+		return 10
+	}, nil)
+	if route, cost, _ := path.AStar(core.GonumNode(5), core.GonumNode(10), weighted, nil, nil); len(route) != 6 || cost != 5 {
+		t.Errorf("Weighted search found path %v costing %v, want cost 5", route, cost)
+	}
+
+	if !g.IsSuccessor(core.GonumNode(7), core.GonumNode(14)) || g.IsSuccessor(core.GonumNode(7), core.GonumNode(9)) || !g.IsPredecessor(core.GonumNode(14), core.GonumNode(7)) {
+		t.Error("Got the wrong successors of 7")
+	}
+	if g.Degree(core.GonumNode(150)) != 1 || g.NodeList() != nil || g.Predecessors(goal) != nil {
+		t.Error("Got a degree, node list or predecessors that the successor function doesn't give")
+	}
+}
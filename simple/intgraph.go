@@ -0,0 +1,32 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+)
+
+// An IntGraph is a GonumGraph whose edge costs are restricted to integers. It's meant for use with DijkstraInt and other integer specialized algorithms, where costs are small
+// integers and accumulated floating point rounding is unacceptable.
+//
+// The costs are stored as float64s by the underlying GonumGraph, which represents every integer up to 2^53 exactly, so Cost and IntCost always agree. Since SetEdgeCost takes an int,
+// an IntGraph doesn't implement MutableGraph; use the embedded GonumGraph if you need to pass it to something that fills a MutableGraph.
+type IntGraph struct {
+	*GonumGraph
+}
+
+func NewIntGraph(directed bool) *IntGraph {
+	return &IntGraph{NewGonumGraph(directed)}
+}
+
+// Sets the integer cost of an existing edge, see GonumGraph.SetEdgeCost
+func (graph *IntGraph) SetEdgeCost(e core.Edge, cost int) {
+	graph.GonumGraph.SetEdgeCost(e, float64(cost))
+}
+
+// Like SetEdgeCost, but returns an error instead of ignoring invalid edges, see GonumGraph.SetEdgeCostE
+func (graph *IntGraph) SetEdgeCostE(e core.Edge, cost int) error {
+	return graph.GonumGraph.SetEdgeCostE(e, float64(cost))
+}
+
+func (graph *IntGraph) IntCost(node, succ core.Node) int {
+	return int(graph.GonumGraph.Cost(node, succ))
+}
@@ -0,0 +1,296 @@
+package simple
+
+import (
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Cursor is a position in a paged listing of a graph's nodes or edges, as returned by NodeListFrom and EdgeListFrom: the key of the last item of the previous page. Pages are in
+// order of ID (edges by head and then tail), and each starts after its cursor rather than at an offset, so nodes and edges added or removed between requests don't shift the pages
+// after them. The zero Cursor is the start of the listing, and is also returned once the listing is exhausted.
+//
+// Cursors are meant to be handed to clients between requests, so String and ParseCursor turn them into text and back.
+type Cursor struct {
+	head, tail int
+	set        bool
+}
+
+// Returns the cursor as text, "" for the zero Cursor
+func (cursor Cursor) String() string {
+	if !cursor.set {
+		return ""
+	}
+
+	return fmt.Sprintf("%d,%d", cursor.head, cursor.tail)
+}
+
+// Parses a cursor written by Cursor.String. Returns an error if the text isn't one, since it will usually have come from a client.
+func ParseCursor(text string) (Cursor, error) {
+	if text == "" {
+		return Cursor{}, nil
+	}
+
+	fields := strings.Split(text, ",")
+	if len(fields) != 2 {
+		return Cursor{}, fmt.Errorf("Bad cursor %q", text)
+	}
+	head, herr := strconv.Atoi(fields[0])
+	tail, terr := strconv.Atoi(fields[1])
+	if herr != nil || terr != nil {
+		return Cursor{}, fmt.Errorf("Bad cursor %q", text)
+	}
+
+	return Cursor{head, tail, true}, nil
+}
+
+// Returns whether the edge key comes after the cursor
+func (cursor Cursor) before(key core.EdgeKey) bool {
+	return !cursor.set || key.Head > cursor.head || (key.Head == cursor.head && key.Tail > cursor.tail)
+}
+
+// A graph that implements Pager can list its nodes and edges a page at a time without building the whole list, so a service can page through a huge graph cheaply on every request.
+// NodeListFrom and EdgeListFrom use it when it's available. The methods behave like the functions.
+type Pager interface {
+	NodeListFrom(cursor Cursor, limit int) (nodes []core.Node, next Cursor)
+	EdgeListFrom(cursor Cursor, limit int) (edges []core.Edge, next Cursor)
+}
+
+// Returns up to limit of the graph's nodes after the cursor, in order of ID, and the cursor of the next page, which is the zero Cursor if there are no more nodes. Start from the zero
+// Cursor, and keep passing back the cursor returned until it's the zero Cursor again. A limit <= 0 returns all the remaining nodes.
+//
+// Graphs that implement Pager (GonumGraph, FrozenGraph and DenseGraph) list a page in time proportional to the page, or for GonumGraph to the number of nodes, without the memory of a
+// full NodeList; other graphs are listed in full and then paged.
+func NodeListFrom(graph core.Graph, cursor Cursor, limit int) (nodes []core.Node, next Cursor) {
+	if pager, ok := graph.(Pager); ok {
+		return pager.NodeListFrom(cursor, limit)
+	}
+
+	byID := make(map[int]core.Node)
+	page := newPageSelector(cursor, limit)
+	for _, node := range graph.NodeList() {
+		if page.add(core.EdgeKey{Head: node.ID(), Tail: 0}) {
+			byID[node.ID()] = node
+		}
+	}
+
+	return page.nodes(func(key core.EdgeKey) core.Node { return byID[key.Head] })
+}
+
+// Returns up to limit of the graph's edges after the cursor, in order of their heads' IDs and then their tails', and the cursor of the next page, as NodeListFrom does. As with
+// EdgeList, an undirected graph lists each edge in both directions.
+//
+// Graphs that implement Pager (GonumGraph, FrozenGraph and DenseGraph) list a page in time proportional to the page, or for GonumGraph to the number of edges, without the memory of
+// a full EdgeList; other graphs are listed in full and then paged.
+func EdgeListFrom(graph core.Graph, cursor Cursor, limit int) (edges []core.Edge, next Cursor) {
+	if pager, ok := graph.(Pager); ok {
+		return pager.EdgeListFrom(cursor, limit)
+	}
+
+	byKey := make(map[core.EdgeKey]core.Edge)
+	page := newPageSelector(cursor, limit)
+	for _, edge := range graph.EdgeList() {
+		key := core.EdgeKey{Head: edge.Head().ID(), Tail: edge.Tail().ID()}
+		if page.add(key) {
+			byKey[key] = edge
+		}
+	}
+
+	return page.edges(func(key core.EdgeKey) core.Edge { return byKey[key] })
+}
+
+// A pageSelector keeps the smallest keys after a cursor out of keys given in any order, holding at most a few pages' worth of them at once
+type pageSelector struct {
+	cursor Cursor
+	limit  int // One more than the page, to find out whether there's another
+	keys   graphutil.EdgeKeySorter
+}
+
+func newPageSelector(cursor Cursor, limit int) *pageSelector {
+	if limit <= 0 {
+		return &pageSelector{cursor: cursor}
+	}
+
+	return &pageSelector{cursor: cursor, limit: limit + 1}
+}
+
+// Offers a key, returning whether it's after the cursor (it may still be dropped later for a smaller one)
+func (page *pageSelector) add(key core.EdgeKey) bool {
+	if !page.cursor.before(key) {
+		return false
+	}
+	page.keys = append(page.keys, key)
+	if page.limit > 0 && len(page.keys) >= 4*page.limit {
+		page.trim()
+	}
+
+	return true
+}
+
+func (page *pageSelector) trim() {
+	sort.Sort(page.keys)
+	if page.limit > 0 && len(page.keys) > page.limit {
+		page.keys = page.keys[:page.limit]
+	}
+}
+
+// Returns the page's keys and the cursor of the next page
+func (page *pageSelector) finish() (keys []core.EdgeKey, next Cursor) {
+	page.trim()
+	keys = page.keys
+	if page.limit > 0 && len(keys) == page.limit {
+		keys = keys[:len(keys)-1]
+		last := keys[len(keys)-1]
+		next = Cursor{last.Head, last.Tail, true}
+	}
+
+	return keys, next
+}
+
+func (page *pageSelector) nodes(node func(core.EdgeKey) core.Node) ([]core.Node, Cursor) {
+	keys, next := page.finish()
+	nodes := make([]core.Node, len(keys))
+	for i, key := range keys {
+		nodes[i] = node(key)
+	}
+
+	return nodes, next
+}
+
+func (page *pageSelector) edges(edge func(core.EdgeKey) core.Edge) ([]core.Edge, Cursor) {
+	keys, next := page.finish()
+	edges := make([]core.Edge, len(keys))
+	for i, key := range keys {
+		edges[i] = edge(key)
+	}
+
+	return edges, next
+}
+
+/* Pager implementations */
+
+func (graph *GonumGraph) NodeListFrom(cursor Cursor, limit int) ([]core.Node, Cursor) {
+	page := newPageSelector(cursor, limit)
+	for id := range graph.nodeMap {
+		page.add(core.EdgeKey{Head: id, Tail: 0})
+	}
+
+	return page.nodes(func(key core.EdgeKey) core.Node { return graph.nodeMap[key.Head] })
+}
+
+func (graph *GonumGraph) EdgeListFrom(cursor Cursor, limit int) ([]core.Edge, Cursor) {
+	page := newPageSelector(cursor, limit)
+	for id, succs := range graph.successors {
+		if cursor.set && id < cursor.head {
+			continue
+		}
+		for succ := range succs {
+			page.add(core.EdgeKey{Head: id, Tail: succ})
+		}
+	}
+
+	return page.edges(func(key core.EdgeKey) core.Edge {
+		return core.GonumCostEdge{H: graph.nodeMap[key.Head], T: graph.nodeMap[key.Tail], W: graph.successors[key.Head][key.Tail]}
+	})
+}
+
+func (graph *FrozenGraph) NodeListFrom(cursor Cursor, limit int) ([]core.Node, Cursor) {
+	start := 0
+	if cursor.set {
+		start = sort.Search(len(graph.nodes), func(i int) bool { return graph.nodes[i].ID() > cursor.head })
+	}
+	end := len(graph.nodes)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	nodes := append([]core.Node(nil), graph.nodes[start:end]...)
+	if end == len(graph.nodes) {
+		return nodes, Cursor{}
+	}
+	return nodes, Cursor{graph.nodes[end-1].ID(), 0, true}
+}
+
+func (graph *FrozenGraph) EdgeListFrom(cursor Cursor, limit int) ([]core.Edge, Cursor) {
+	i, k := 0, 0
+	if cursor.set {
+		i = sort.Search(len(graph.nodes), func(i int) bool { return graph.nodes[i].ID() >= cursor.head })
+		if i < len(graph.nodes) {
+			k = graph.succOffsets[i]
+			if graph.nodes[i].ID() == cursor.head {
+				row := graph.succs[graph.succOffsets[i]:graph.succOffsets[i+1]]
+				k += sort.Search(len(row), func(j int) bool { return graph.nodes[row[j]].ID() > cursor.tail })
+			}
+		} else {
+			k = len(graph.succs)
+		}
+	}
+
+	edges := make([]core.Edge, 0)
+	for ; k < len(graph.succs); k++ {
+		for k >= graph.succOffsets[i+1] {
+			i++
+		}
+		if limit > 0 && len(edges) == limit {
+			last := edges[len(edges)-1]
+			return edges, Cursor{last.Head().ID(), last.Tail().ID(), true}
+		}
+		edges = append(edges, core.GonumCostEdge{H: graph.nodes[i], T: graph.nodes[graph.succs[k]], W: graph.costs[k]})
+	}
+
+	return edges, Cursor{}
+}
+
+func (graph *DenseGraph) NodeListFrom(cursor Cursor, limit int) ([]core.Node, Cursor) {
+	nodes := make([]core.Node, 0)
+	for id := graph.pageStart(cursor.head, cursor.set); id < graph.size; id++ {
+		if !graph.exists[id] {
+			continue
+		}
+		if limit > 0 && len(nodes) == limit {
+			return nodes, Cursor{nodes[len(nodes)-1].ID(), 0, true}
+		}
+		nodes = append(nodes, core.GonumNode(id))
+	}
+
+	return nodes, Cursor{}
+}
+
+func (graph *DenseGraph) EdgeListFrom(cursor Cursor, limit int) ([]core.Edge, Cursor) {
+	edges := make([]core.Edge, 0)
+	start := 0
+	if cursor.set && cursor.head >= 0 {
+		// Resume in the cursor's row, just after its tail
+		start = cursor.head*graph.size + graph.pageStart(cursor.tail, true)
+		if cursor.head >= graph.size {
+			start = len(graph.costs)
+		}
+	}
+	for k := start; k < len(graph.costs); k++ {
+		if cost := graph.costs[k]; !math.IsNaN(cost) {
+			if limit > 0 && len(edges) == limit {
+				last := edges[len(edges)-1]
+				return edges, Cursor{last.Head().ID(), last.Tail().ID(), true}
+			}
+			edges = append(edges, core.GonumCostEdge{H: core.GonumNode(k / graph.size), T: core.GonumNode(k % graph.size), W: cost})
+		}
+	}
+
+	return edges, Cursor{}
+}
+
+// Returns the first ID after the given one, or 0 if there's no cursor, clamped to the matrix
+func (graph *DenseGraph) pageStart(after int, set bool) int {
+	if !set || after < 0 {
+		return 0
+	}
+	if after >= graph.size {
+		return graph.size
+	}
+
+	return after + 1
+}
@@ -0,0 +1,85 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func TestListFrom(t *testing.T) {
+	g := simple.NewGonumGraph(true)
+	for id := 0; id < 12; id++ {
+		g.AddNode(core.GonumNode(id*3%11), nodes((id+1)%7, id%5))
+	}
+	dense := simple.NewDenseGraph(0, true)
+	core.CopyGraph(dense, g)
+
+	for name, paged := range map[string]core.Graph{"GonumGraph": g, "FrozenGraph": simple.Frozen(g), "DenseGraph": dense, "Graph": plainGraph{g}} {
+		for _, limit := range []int{1, 3, 5, 0} {
+			var got []core.Node
+			for cursor, pages := (simple.Cursor{}), 0; ; pages++ {
+				if pages > 20 {
+					t.Fatalf("%s: Paging nodes by %d never finished", name, limit)
+				}
+				// The cursor goes through a client between requests
+				cursor, _ = simple.ParseCursor(cursor.String())
+				var page []core.Node
+				page, cursor = simple.NodeListFrom(paged, cursor, limit)
+				if limit > 0 && len(page) > limit {
+					t.Errorf("%s: Got a page of %d nodes with a limit of %d", name, len(page), limit)
+				}
+				got = append(got, page...)
+				if cursor == (simple.Cursor{}) {
+					break
+				}
+			}
+			want := core.SortedNodeList(g)
+			if len(got) != len(want) {
+				t.Fatalf("%s: Paging by %d gave nodes %v, want %v", name, limit, got, want)
+			}
+			for i := range want {
+				if got[i].ID() != want[i].ID() {
+					t.Fatalf("%s: Paging by %d gave nodes %v, want %v", name, limit, got, want)
+				}
+			}
+
+			var gotEdges []core.Edge
+			for cursor, pages := (simple.Cursor{}), 0; ; pages++ {
+				if pages > 40 {
+					t.Fatalf("%s: Paging edges by %d never finished", name, limit)
+				}
+				var page []core.Edge
+				page, cursor = simple.EdgeListFrom(paged, cursor, limit)
+				gotEdges = append(gotEdges, page...)
+				if cursor == (simple.Cursor{}) {
+					break
+				}
+			}
+			wantEdges := core.SortedEdgeList(g)
+			if len(gotEdges) != len(wantEdges) {
+				t.Fatalf("%s: Paging by %d gave edges %v, want %v", name, limit, gotEdges, wantEdges)
+			}
+			for i := range wantEdges {
+				if gotEdges[i].Head().ID() != wantEdges[i].Head().ID() || gotEdges[i].Tail().ID() != wantEdges[i].Tail().ID() {
+					t.Fatalf("%s: Paging by %d gave edges %v, want %v", name, limit, gotEdges, wantEdges)
+				}
+			}
+		}
+	}
+
+	// Pages start after the cursor, so a node added before it doesn't shift the next page
+	page, cursor := simple.NodeListFrom(g, simple.Cursor{}, 4)
+	g.AddNode(core.GonumNode(-1), nil)
+	if next, _ := simple.NodeListFrom(g, cursor, 1); len(next) != 1 || next[0].ID() != page[3].ID()+1 {
+		t.Errorf("After %v the next page is %v", page, next)
+	}
+
+	if cursor, err := simple.ParseCursor("-3,12"); err != nil || cursor.String() != "-3,12" {
+		t.Errorf("Parsed cursor %v with error %v", cursor, err)
+	}
+	for _, bad := range []string{"3", "a,b", "1,2,3", ","} {
+		if _, err := simple.ParseCursor(bad); err == nil {
+			t.Errorf("No error for cursor %q", bad)
+		}
+	}
+}
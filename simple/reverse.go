@@ -0,0 +1,106 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+)
+
+// A ReversedGraph is a read-only view of a directed graph with every edge turned around, as returned by Reverse: the graph's predecessors are the view's successors and the other way
+// round. The view isn't a copy, so it costs nothing to make and later changes to the graph show up in it. It's what searching backwards from a node needs, such as finding everything
+// that can reach it, the second pass of Kosaraju's algorithm or the backward half of a bidirectional search.
+//
+// The view is a HeuristicCoster: the cost of an edge is the graph's cost of the edge the other way (uniform costs if the graph has none), and the heuristic from a node to a goal is the
+// graph's heuristic from the goal to the node, since a path in the view is a path in the graph run backwards. It also counts degrees with the graph's DegreeCounter when there is one.
+type ReversedGraph struct {
+	graph core.Graph
+}
+
+// Returns a view of the graph with every edge reversed. An undirected graph is its own reverse, so it's returned as it is, and so is the graph a ReversedGraph is a view of.
+func Reverse(graph core.Graph) core.Graph {
+	if !graph.IsDirected() {
+		return graph
+	}
+	if rgraph, ok := graph.(*ReversedGraph); ok {
+		return rgraph.graph
+	}
+
+	return &ReversedGraph{graph}
+}
+
+/* Graph implementation */
+
+func (view *ReversedGraph) Successors(node core.Node) []core.Node {
+	return view.graph.Predecessors(node)
+}
+
+func (view *ReversedGraph) IsSuccessor(node, successor core.Node) bool {
+	return view.graph.IsPredecessor(node, successor)
+}
+
+func (view *ReversedGraph) Predecessors(node core.Node) []core.Node {
+	return view.graph.Successors(node)
+}
+
+func (view *ReversedGraph) IsPredecessor(node, predecessor core.Node) bool {
+	return view.graph.IsSuccessor(node, predecessor)
+}
+
+func (view *ReversedGraph) IsAdjacent(node, neighbor core.Node) bool {
+	return view.graph.IsAdjacent(node, neighbor)
+}
+
+func (view *ReversedGraph) NodeExists(node core.Node) bool {
+	return view.graph.NodeExists(node)
+}
+
+func (view *ReversedGraph) Degree(node core.Node) int {
+	return view.graph.Degree(node)
+}
+
+func (view *ReversedGraph) EdgeList() []core.Edge {
+	edges := view.graph.EdgeList()
+	reversed := make([]core.Edge, len(edges))
+	for i, edge := range edges {
+		if cedge, ok := edge.(core.CostEdge); ok {
+			reversed[i] = core.GonumCostEdge{H: edge.Tail(), T: edge.Head(), W: cedge.Weight()}
+		} else {
+			reversed[i] = core.GonumEdge{H: edge.Tail(), T: edge.Head()}
+		}
+	}
+
+	return reversed
+}
+
+func (view *ReversedGraph) NodeList() []core.Node {
+	return view.graph.NodeList()
+}
+
+func (view *ReversedGraph) IsDirected() bool {
+	return true
+}
+
+/* Coster implementation */
+
+func (view *ReversedGraph) Cost(node, succ core.Node) float64 {
+	return graphutil.DefaultCost(view.graph, nil)(succ, node)
+}
+
+/* HeuristicCoster implementation */
+
+func (view *ReversedGraph) HeuristicCost(node, goal core.Node) float64 {
+	if hgraph, ok := view.graph.(core.HeuristicCoster); ok {
+		return hgraph.HeuristicCost(goal, node)
+	}
+
+	return core.NullHeuristic(node, goal)
+}
+
+/* DegreeCounter implementation */
+
+func (view *ReversedGraph) InDegree(node core.Node) int {
+	return core.OutDegree(view.graph, node)
+}
+
+func (view *ReversedGraph) OutDegree(node core.Node) int {
+	return core.InDegree(view.graph, node)
+}
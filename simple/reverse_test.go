@@ -0,0 +1,56 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	// 0 -> 1 -> 2 -> 3, with a shortcut 0 -> 2 and 4 -> 3
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1))
+	g.AddNode(core.GonumNode(4), nodes(3))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, 5)
+
+	reversed := simple.Reverse(g)
+	if succs := reversed.Successors(core.GonumNode(2)); len(succs) != 2 || !reversed.IsSuccessor(core.GonumNode(3), core.GonumNode(4)) || reversed.IsSuccessor(core.GonumNode(0), core.GonumNode(1)) {
+		t.Errorf("Got successors %v of 2", succs)
+	}
+	if core.InDegree(reversed, core.GonumNode(0)) != 2 || core.OutDegree(reversed, core.GonumNode(0)) != 0 || reversed.Degree(core.GonumNode(2)) != 3 {
+		t.Errorf("Got degrees %d in and %d out of 0", core.InDegree(reversed, core.GonumNode(0)), core.OutDegree(reversed, core.GonumNode(0)))
+	}
+
+	// Searching the reverse from 3 finds the costs of reaching 3 in the graph
+	costs := path.DijkstraCosts(core.GonumNode(3), reversed, nil)
+	if len(costs) != 5 || costs[0] != 3 || costs[4] != 1 {
+		t.Errorf("Got costs %v to 3", costs)
+	}
+	if route, cost, _ := path.AStar(core.GonumNode(3), core.GonumNode(0), reversed, nil, nil); len(route) != 4 || cost != 3 || !path.IsPath(route, reversed) {
+		t.Errorf("Found path %v costing %f", route, cost)
+	}
+
+	want := simple.NewGonumGraph(true)
+	for _, node := range g.NodeList() {
+		want.AddNode(node, nil)
+	}
+	for _, edge := range g.EdgeList() {
+		want.AddEdge(core.GonumEdge{H: edge.Tail(), T: edge.Head()})
+		want.SetEdgeCost(core.GonumEdge{H: edge.Tail(), T: edge.Head()}, g.Cost(edge.Head(), edge.Tail()))
+	}
+	if !core.Equal(reversed, want, 0) || len(reversed.EdgeList()) != 5 {
+		t.Errorf("The reverse differs from the graph built backwards: %v", reversed.EdgeList())
+	}
+
+	if simple.Reverse(reversed) != core.Graph(g) {
+		t.Error("Reversing twice didn't give back the graph")
+	}
+	undirected := simple.NewGonumGraph(false)
+	if simple.Reverse(undirected) != core.Graph(undirected) {
+		t.Error("Reversing an undirected graph didn't give back the graph")
+	}
+}
@@ -0,0 +1,138 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"runtime"
+	"sync"
+)
+
+// A ShardedGraph is a write-only graph builder that can be filled from many goroutines at once. Nodes are hash-partitioned by ID into shards that each have their own lock, so concurrent
+// AddEdge calls only contend when they touch the same shard. Once loading is done, Freeze turns it into an ordinary GonumGraph for running algorithms on.
+//
+// This is meant for bulk ingest, such as parsing a large edge list with one goroutine per file chunk. The zero value is not usable; use NewShardedGraph.
+type ShardedGraph struct {
+	shards   []graphShard
+	directed bool
+}
+
+type graphShard struct {
+	sync.Mutex
+	successors   map[int]map[int]float64
+	predecessors map[int]map[int]float64
+	nodeMap      map[int]core.Node
+}
+
+// Creates an empty ShardedGraph with the given number of shards. If shards <= 0, four shards per available CPU (runtime.GOMAXPROCS(0)) are used, which keeps contention low without
+// making Freeze noticeably slower.
+func NewShardedGraph(directed bool, shards int) *ShardedGraph {
+	if shards <= 0 {
+		shards = 4 * runtime.GOMAXPROCS(0)
+	}
+
+	sg := &ShardedGraph{shards: make([]graphShard, shards), directed: directed}
+	for i := range sg.shards {
+		sg.shards[i].successors = make(map[int]map[int]float64)
+		sg.shards[i].predecessors = make(map[int]map[int]float64)
+		sg.shards[i].nodeMap = make(map[int]core.Node)
+	}
+
+	return sg
+}
+
+func (sg *ShardedGraph) shard(id int) *graphShard {
+	// Fibonacci hashing, so IDs that are allocated with a fixed stride still spread over all the shards
+	h := uint64(id) * 0x9E3779B97F4A7C15
+	return &sg.shards[(h>>32)%uint64(len(sg.shards))]
+}
+
+// Adds the node to its shard, creating its adjacency maps. Must be called with the shard locked. Freeze takes the shard's maps away, so their absence means the graph was frozen.
+func (s *graphShard) addNode(node core.Node) {
+	if s.nodeMap == nil {
+		s.Unlock()
+		panic("ShardedGraph modified after Freeze")
+	}
+
+	id := node.ID()
+	if _, ok := s.successors[id]; ok {
+		return
+	}
+
+	s.nodeMap[id] = node
+	s.successors[id] = make(map[int]float64)
+	s.predecessors[id] = make(map[int]float64)
+}
+
+// Adds a node with no edges, if it isn't already present. Safe to call concurrently, but panics after Freeze.
+func (sg *ShardedGraph) AddNode(node core.Node) {
+	s := sg.shard(node.ID())
+	s.Lock()
+	s.addNode(node)
+	s.Unlock()
+}
+
+// Adds the edge with a cost of 1, and reciprocal edge if the graph is undirected. Unlike GonumGraph.AddEdge, missing heads are created as well as missing tails, since the order edges
+// arrive in during a concurrent load isn't predictable. Safe to call concurrently, but panics after Freeze.
+func (sg *ShardedGraph) AddEdge(e core.Edge) {
+	sg.SetEdgeCost(e, 1.0)
+}
+
+// Adds the edge if needed, as AddEdge does, and sets its cost (and its reciprocal's, if the graph is undirected). Safe to call concurrently, but panics after Freeze.
+func (sg *ShardedGraph) SetEdgeCost(e core.Edge, cost float64) {
+	head, tail := e.Head(), e.Tail()
+	h, t := head.ID(), tail.ID()
+
+	// Each endpoint's half of the edge is written under its own shard's lock, and the locks are never held together, so there's no lock ordering to get wrong
+	s := sg.shard(h)
+	s.Lock()
+	s.addNode(head)
+	s.successors[h][t] = cost
+	if !sg.directed {
+		s.predecessors[h][t] = cost
+	}
+	s.Unlock()
+
+	s = sg.shard(t)
+	s.Lock()
+	s.addNode(tail)
+	s.predecessors[t][h] = cost
+	if !sg.directed {
+		s.successors[t][h] = cost
+	}
+	s.Unlock()
+}
+
+// Merges the shards into a GonumGraph and returns it. The adjacency maps are moved rather than copied, so this is cheap, but it means the ShardedGraph can't be used afterwards: any further
+// mutation panics, as does freezing it twice. Callers must make sure all their loading goroutines are done first; an edge that's being added while Freeze runs may end up half added.
+func (sg *ShardedGraph) Freeze() *GonumGraph {
+	size := 0
+	for i := range sg.shards {
+		sg.shards[i].Lock()
+		if sg.shards[i].nodeMap == nil {
+			sg.shards[i].Unlock()
+			panic("ShardedGraph frozen twice")
+		}
+		size += len(sg.shards[i].nodeMap)
+		sg.shards[i].Unlock()
+	}
+
+	graph := &GonumGraph{
+		successors:   make(map[int]map[int]float64, size),
+		predecessors: make(map[int]map[int]float64, size),
+		nodeMap:      make(map[int]core.Node, size),
+		directed:     sg.directed,
+	}
+	for i := range sg.shards {
+		s := &sg.shards[i]
+		s.Lock()
+		for id, node := range s.nodeMap {
+			graph.nodeMap[id] = node
+			graph.successors[id] = s.successors[id]
+			graph.predecessors[id] = s.predecessors[id]
+		}
+		s.successors, s.predecessors, s.nodeMap = nil, nil, nil
+		s.Unlock()
+	}
+	graph.identifyAllEdges()
+
+	return graph
+}
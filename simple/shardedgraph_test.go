@@ -0,0 +1,56 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"sync"
+	"testing"
+)
+
+func TestShardedGraph(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		want := randomGraph(500, 4, directed, 4)
+		edges := want.EdgeList()
+
+		sg := simple.NewShardedGraph(directed, 0)
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := w; i < len(edges); i += 8 {
+					sg.AddEdge(edges[i])
+				}
+			}(w)
+		}
+		wg.Wait()
+		for _, node := range want.NodeList() {
+			sg.AddNode(node)
+		}
+
+		got := sg.Freeze()
+		if len(got.NodeList()) != len(want.NodeList()) || len(got.EdgeList()) != len(edges) {
+			t.Errorf("Directed %t: frozen graph has %d nodes and %d edges, want %d and %d", directed, len(got.NodeList()), len(got.EdgeList()), len(want.NodeList()), len(edges))
+		}
+		for _, edge := range edges {
+			if !got.IsSuccessor(edge.Head(), edge.Tail()) || !got.IsPredecessor(edge.Tail(), edge.Head()) {
+				t.Fatalf("Directed %t: frozen graph is missing edge %v", directed, edge)
+			}
+		}
+	}
+}
+
+func TestShardedGraphFrozen(t *testing.T) {
+	sg := simple.NewShardedGraph(true, 2)
+	sg.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, 5)
+	if cost := sg.Freeze().Cost(core.GonumNode(1), core.GonumNode(2)); cost != 5 {
+		t.Errorf("Edge has cost %f, want 5", cost)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("No panic when adding to a frozen graph")
+		}
+	}()
+	sg.AddNode(core.GonumNode(3))
+}
@@ -0,0 +1,516 @@
+package simple
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+)
+
+/* Snapshots and write-ahead logs. A long-running service that keeps a GonumGraph in memory can make it durable by writing a Snapshot now and then and logging every mutation made
+since through a LoggedGraph; after a crash, Restore the last snapshot and ReplayLog the log written after it. Both are in a compact binary format, which unlike the text formats
+keeps every cost exactly */
+
+// The format's name and version. Version 1 had no edge IDs or validity intervals; Restore still reads it, numbering the edges in order.
+const (
+	snapshotMagic   = "GGSNAP"
+	snapshotVersion = 2
+)
+
+// Returns the graph in a compact binary format that Restore reads back: its directedness, metadata, nodes (as IDs) and edges with their costs, IDs (see EdgeIdentifier) and validity
+// intervals (see SetEdgeIntervals). Costs and interval bounds are kept bit for bit, NaNs and infinities included, and so is the ID the next new edge gets, so a log replayed onto the
+// restored graph numbers its edges as the original did. Nodes come back as GonumNodes. The same graph always gives the same bytes.
+func (graph *GonumGraph) Snapshot() []byte {
+	var w snapshotWriter
+	w.WriteString(snapshotMagic)
+	w.WriteByte(snapshotVersion)
+	w.bool(graph.directed)
+
+	w.string(graph.metadata.Name)
+	w.string(graph.metadata.Creator)
+	var created []byte
+	if !graph.metadata.Created.IsZero() {
+		created, _ = graph.metadata.Created.MarshalBinary()
+	}
+	w.string(string(created))
+	keys := graph.metadata.Keys()
+	w.uvarint(uint64(len(keys)))
+	for _, key := range keys {
+		w.string(key)
+		w.string(graph.metadata.Attributes[key])
+	}
+
+	ids := make([]int, 0, len(graph.successors))
+	for id := range graph.successors {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	w.uvarint(uint64(len(ids)))
+	for _, id := range ids {
+		w.varint(id)
+	}
+
+	edges := make(graphutil.EdgeKeySorter, 0)
+	for id, succs := range graph.successors {
+		for succ := range succs {
+			if graph.directed || id <= succ {
+				edges = append(edges, core.EdgeKey{Head: id, Tail: succ})
+			}
+		}
+	}
+	sort.Sort(edges)
+	w.varint(graph.nextEdgeID)
+	w.uvarint(uint64(len(edges)))
+	for _, key := range edges {
+		w.varint(key.Head)
+		w.varint(key.Tail)
+		w.float(graph.successors[key.Head][key.Tail])
+
+		e := core.GonumEdge{H: core.GonumNode(key.Head), T: core.GonumNode(key.Tail)}
+		if id, ok := graph.EdgeID(e); ok {
+			w.varint(id)
+		} else {
+			w.varint(-1)
+		}
+		intervals := graph.intervals[core.KeyOf(e, graph.directed)]
+		w.uvarint(uint64(len(intervals)))
+		for _, iv := range intervals {
+			w.float(iv.Start)
+			w.float(iv.End)
+		}
+	}
+
+	return w.Bytes()
+}
+
+// Replaces the graph with one read from a Snapshot. Returns an error, leaving the graph as it was, if the data isn't a snapshot, is cut short, lists a node or edge twice or an edge
+// whose ends aren't both nodes, or gives two edges the same ID or one an ID that isn't below the next edge's.
+func (graph *GonumGraph) Restore(data []byte) error {
+	r := snapshotReader{bytes.NewReader(data), nil}
+	if magic := r.bytes(len(snapshotMagic)); r.err != nil || string(magic) != snapshotMagic {
+		return errors.New("Not a graph snapshot")
+	}
+	version := r.bytes(1)
+	if r.err != nil || version[0] < 1 || version[0] > snapshotVersion {
+		return errors.New("Not a graph snapshot")
+	}
+
+	decoded := NewGonumGraph(r.bool())
+	decoded.metadata.Name = r.string()
+	decoded.metadata.Creator = r.string()
+	if created := r.string(); created != "" && r.err == nil {
+		if err := decoded.metadata.Created.UnmarshalBinary([]byte(created)); err != nil {
+			return err
+		}
+	}
+	for i, n := 0, r.count(); i < n; i++ {
+		key, value := r.string(), r.string()
+		decoded.metadata.Set(key, value)
+	}
+	decoded.metadata.Directed = decoded.directed
+
+	for i, n := 0, r.count(); i < n; i++ {
+		id := r.varint()
+		if r.err == nil && decoded.AddNodeE(core.GonumNode(id), nil) != nil {
+			return fmt.Errorf("Node %d is listed twice", id)
+		}
+	}
+	// Version 1 snapshots leave the edges to be numbered as they're added
+	nextEdgeID := -1
+	if version[0] >= 2 {
+		if nextEdgeID = r.varint(); nextEdgeID < 0 && r.err == nil {
+			return fmt.Errorf("Bad next edge ID %d", nextEdgeID)
+		}
+	}
+	edgeKeys := make(map[int]core.EdgeKey)
+	for i, n := 0, r.count(); i < n; i++ {
+		head, tail, cost := r.varint(), r.varint(), r.float()
+		if r.err != nil {
+			break
+		}
+		e := core.GonumEdge{H: core.GonumNode(head), T: core.GonumNode(tail)}
+		if !decoded.NodeExists(e.H) || !decoded.NodeExists(e.T) {
+			return fmt.Errorf("Edge %d->%d has an endpoint that isn't in the node list", head, tail)
+		}
+		if decoded.IsSuccessor(e.H, e.T) {
+			return fmt.Errorf("Edge %d->%d is listed twice", head, tail)
+		}
+		decoded.AddEdge(e)
+		decoded.SetEdgeCost(e, cost)
+		if nextEdgeID < 0 {
+			continue
+		}
+
+		if id := r.varint(); r.err == nil && id != -1 {
+			if _, ok := edgeKeys[id]; ok || id < 0 || id >= nextEdgeID {
+				return fmt.Errorf("Edge %d->%d has ID %d, which is taken or not below the next edge's", head, tail, id)
+			}
+			edgeKeys[id] = core.KeyOf(e, decoded.directed)
+		}
+		var intervals []Interval
+		for j, m := 0, r.count(); j < m; j++ {
+			intervals = append(intervals, Interval{r.float(), r.float()})
+		}
+		decoded.SetEdgeIntervals(e, intervals...)
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+	if r.Len() > 0 {
+		return errors.New("Trailing data after the snapshot")
+	}
+
+	if nextEdgeID >= 0 {
+		decoded.edgeIDs = make(map[core.EdgeKey]int, len(edgeKeys))
+		decoded.edgeKeys = edgeKeys
+		decoded.nextEdgeID = nextEdgeID
+		for id, key := range edgeKeys {
+			decoded.edgeIDs[key] = id
+		}
+	}
+
+	*graph = *decoded
+	return nil
+}
+
+// A LoggedGraph is a GonumGraph that writes each mutation to a log before making it, so that the graph can be rebuilt after a crash by replaying the log with ReplayLog (onto the
+// Snapshot taken before the log was started, if there is one). Each mutation is one Write of a checksummed record, so the log is only as durable as the writer: to survive a power
+// failure, write to a file and Sync it as often as the service can afford.
+//
+// If a write fails, the mutation isn't made, so the graph never gets ahead of its log, and Err returns the error; later mutations are refused too. The graph's own methods aren't
+// logged, so make all changes through the LoggedGraph, including checked ones through AsChecked.
+type LoggedGraph struct {
+	core.CostGraph
+	graph *GonumGraph
+	log   io.Writer
+	err   error
+}
+
+// Returns a view of the graph that logs its mutations to log before making them
+func NewLoggedGraph(graph *GonumGraph, log io.Writer) *LoggedGraph {
+	return &LoggedGraph{graph, graph, log, nil}
+}
+
+// Returns the first error writing the log, after which the graph refuses all mutations
+func (lg *LoggedGraph) Err() error {
+	return lg.err
+}
+
+// The operations a log records
+const (
+	logAddNode byte = iota + 1
+	logAddEdge
+	logSetEdgeCost
+	logRemoveNode
+	logRemoveEdge
+	logEmptyGraph
+	logSetDirected
+)
+
+// Writes a record, returning whether the mutation may go ahead
+func (lg *LoggedGraph) write(record *snapshotWriter) bool {
+	if lg.err != nil {
+		return false
+	}
+
+	payload := record.Bytes()
+	var frame snapshotWriter
+	frame.uvarint(uint64(len(payload)))
+	frame.Write(payload)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(payload))
+	frame.Write(sum[:])
+
+	if _, err := lg.log.Write(frame.Bytes()); err != nil {
+		lg.err = err
+		return false
+	}
+	return true
+}
+
+func (lg *LoggedGraph) edgeRecord(op byte, e core.Edge) *snapshotWriter {
+	record := &snapshotWriter{}
+	record.WriteByte(op)
+	record.varint(e.Head().ID())
+	record.varint(e.Tail().ID())
+	return record
+}
+
+/* MutableGraph implementation */
+
+// Adds a node with the ID GonumGraph.NewNode would give it, and logs it as an AddNode of that ID. Returns nil if the log can't be written.
+func (lg *LoggedGraph) NewNode(successors []core.Node) core.Node {
+	node := core.GonumNode(lg.graph.newNodeID())
+	if !lg.addNode(node, successors) {
+		return nil
+	}
+
+	return node
+}
+
+func (lg *LoggedGraph) AddNode(node core.Node, successors []core.Node) {
+	lg.addNode(node, successors)
+}
+
+// AddNode, returning whether the mutation was logged and made
+func (lg *LoggedGraph) addNode(node core.Node, successors []core.Node) bool {
+	record := &snapshotWriter{}
+	record.WriteByte(logAddNode)
+	record.varint(node.ID())
+	record.uvarint(uint64(len(successors)))
+	for _, succ := range successors {
+		record.varint(succ.ID())
+	}
+	if !lg.write(record) {
+		return false
+	}
+
+	lg.graph.AddNode(node, successors)
+	return true
+}
+
+func (lg *LoggedGraph) AddEdge(e core.Edge) {
+	if lg.write(lg.edgeRecord(logAddEdge, e)) {
+		lg.graph.AddEdge(e)
+	}
+}
+
+func (lg *LoggedGraph) SetEdgeCost(e core.Edge, cost float64) {
+	record := lg.edgeRecord(logSetEdgeCost, e)
+	record.float(cost)
+	if lg.write(record) {
+		lg.graph.SetEdgeCost(e, cost)
+	}
+}
+
+func (lg *LoggedGraph) RemoveNode(node core.Node) {
+	record := &snapshotWriter{}
+	record.WriteByte(logRemoveNode)
+	record.varint(node.ID())
+	if lg.write(record) {
+		lg.graph.RemoveNode(node)
+	}
+}
+
+func (lg *LoggedGraph) RemoveEdge(e core.Edge) {
+	if lg.write(lg.edgeRecord(logRemoveEdge, e)) {
+		lg.graph.RemoveEdge(e)
+	}
+}
+
+func (lg *LoggedGraph) EmptyGraph() {
+	record := &snapshotWriter{}
+	record.WriteByte(logEmptyGraph)
+	if lg.write(record) {
+		lg.graph.EmptyGraph()
+	}
+}
+
+func (lg *LoggedGraph) SetDirected(directed bool) {
+	record := &snapshotWriter{}
+	record.WriteByte(logSetDirected)
+	record.bool(directed)
+	if lg.write(record) {
+		lg.graph.SetDirected(directed)
+	}
+}
+
+// Makes the mutations recorded in a LoggedGraph's log on the graph, in order; nodes are added as GonumNodes. A record that's cut off at the end of the log is ignored, since its
+// mutation was never made, but a record that fails its checksum or can't be decoded anywhere else is an error, returned along with the number of mutations made before it.
+func ReplayLog(graph core.MutableGraph, log io.Reader) (mutations int, err error) {
+	in := bufio.NewReader(log)
+	for {
+		size, err := binary.ReadUvarint(in)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return mutations, nil
+		} else if err != nil {
+			return mutations, err
+		}
+		if size > math.MaxInt32 {
+			return mutations, fmt.Errorf("Record %d: Bad length %d", mutations+1, size)
+		}
+
+		frame := make([]byte, size+4)
+		if _, err := io.ReadFull(in, frame); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return mutations, nil
+		} else if err != nil {
+			return mutations, err
+		}
+		payload := frame[:size]
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(frame[size:]) {
+			return mutations, fmt.Errorf("Record %d: Bad checksum", mutations+1)
+		}
+
+		if err := replayRecord(graph, payload); err != nil {
+			return mutations, fmt.Errorf("Record %d: %v", mutations+1, err)
+		}
+		mutations++
+	}
+}
+
+func replayRecord(graph core.MutableGraph, payload []byte) error {
+	r := snapshotReader{bytes.NewReader(payload), nil}
+	op := r.bytes(1)
+	if r.err != nil {
+		return r.err
+	}
+
+	switch op[0] {
+	case logAddNode:
+		id := r.varint()
+		successors := make([]core.Node, r.count())
+		for i := range successors {
+			successors[i] = core.GonumNode(r.varint())
+		}
+		if r.err == nil {
+			graph.AddNode(core.GonumNode(id), successors)
+		}
+	case logAddEdge, logSetEdgeCost, logRemoveEdge:
+		e := core.GonumEdge{H: core.GonumNode(r.varint()), T: core.GonumNode(r.varint())}
+		switch {
+		case r.err != nil:
+		case op[0] == logAddEdge:
+			graph.AddEdge(e)
+		case op[0] == logRemoveEdge:
+			graph.RemoveEdge(e)
+		default:
+			if cost := r.float(); r.err == nil {
+				graph.SetEdgeCost(e, cost)
+			}
+		}
+	case logRemoveNode:
+		if id := r.varint(); r.err == nil {
+			graph.RemoveNode(core.GonumNode(id))
+		}
+	case logEmptyGraph:
+		graph.EmptyGraph()
+	case logSetDirected:
+		if directed := r.bool(); r.err == nil {
+			graph.SetDirected(directed)
+		}
+	default:
+		return fmt.Errorf("Unknown operation %d", op[0])
+	}
+
+	return r.err
+}
+
+// A snapshotWriter appends the binary format's fields to a buffer
+type snapshotWriter struct {
+	bytes.Buffer
+}
+
+func (w *snapshotWriter) uvarint(x uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutUvarint(buf[:], x)])
+}
+
+func (w *snapshotWriter) varint(x int) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutVarint(buf[:], int64(x))])
+}
+
+func (w *snapshotWriter) float(f float64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+	w.Write(buf[:])
+}
+
+func (w *snapshotWriter) bool(b bool) {
+	if b {
+		w.WriteByte(1)
+	} else {
+		w.WriteByte(0)
+	}
+}
+
+func (w *snapshotWriter) string(s string) {
+	w.uvarint(uint64(len(s)))
+	w.WriteString(s)
+}
+
+// A snapshotReader reads the binary format's fields, remembering the first error so that a whole record can be read before checking; after an error every field reads as zero
+type snapshotReader struct {
+	*bytes.Reader
+	err error
+}
+
+func (r *snapshotReader) fail(err error) {
+	if r.err == nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		r.err = err
+	}
+}
+
+func (r *snapshotReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > r.Len() {
+		r.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+
+	buf := make([]byte, n)
+	io.ReadFull(r.Reader, buf)
+	return buf
+}
+
+func (r *snapshotReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	x, err := binary.ReadUvarint(r.Reader)
+	if err != nil {
+		r.fail(err)
+	}
+	return x
+}
+
+// Reads a length, which can't be more than the bytes left, so corrupt data can't ask for a huge allocation
+func (r *snapshotReader) count() int {
+	n := r.uvarint()
+	if n > uint64(r.Len()) {
+		r.fail(errors.New("Bad length"))
+		return 0
+	}
+	return int(n)
+}
+
+func (r *snapshotReader) varint() int {
+	if r.err != nil {
+		return 0
+	}
+	x, err := binary.ReadVarint(r.Reader)
+	if err != nil {
+		r.fail(err)
+	}
+	return int(x)
+}
+
+func (r *snapshotReader) float() float64 {
+	buf := r.bytes(8)
+	if buf == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(buf))
+}
+
+func (r *snapshotReader) bool() bool {
+	buf := r.bytes(1)
+	return buf != nil && buf[0] != 0
+}
+
+func (r *snapshotReader) string() string {
+	return string(r.bytes(r.count()))
+}
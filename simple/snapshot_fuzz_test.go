@@ -0,0 +1,19 @@
+//go:build go1.18
+// +build go1.18
+
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/encodingtest"
+	"testing"
+)
+
+func FuzzRestore(f *testing.F) {
+	encodingtest.FuzzDecoder(f, snapshotCodec{},
+		[]byte(""),
+		[]byte("GGSNAP\x01"),
+		[]byte("GGSNAP\x01\x01\x00\x00\x00\x00\xff\xff\xff\xff\x0f"),
+		[]byte("GGSNAP\x01\x00\x00\x00\x00\x00\x02\x02\x02\x01\x02\x02\x00\x00\x00\x00\x00\x00\xf8\x7f"),
+		[]byte("GGSNAP\x02\x00\x00\x00\x00\x00\x03\x00\x02\x04\x04\x01\x00\x04\x00\x00\x00\x00\x00\x00\xf0?\x02\x02\x00\x00\x00\x00\x00\x00\xf0\xff\x00\x00\x00\x00\x00\x00\xf0\x7f\x00\x00\x00\x00\x00\x00\b@\x00\x00\x00\x00\x00\x00\xf0?"),
+	)
+}
@@ -0,0 +1,198 @@
+package simple_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encodingtest"
+	"github.com/nathankerr/graph/simple"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type snapshotCodec struct{}
+
+func (snapshotCodec) Encode(w io.Writer, g core.Graph) error {
+	gg, ok := g.(*simple.GonumGraph)
+	if !ok {
+		return errors.New("Only a GonumGraph can be snapshotted")
+	}
+	_, err := w.Write(gg.Snapshot())
+	return err
+}
+
+func (snapshotCodec) Decode(r io.Reader) (core.Graph, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	g := simple.NewGonumGraph(true)
+	if err := g.Restore(data); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Compares the edge IDs and validity intervals, which snapshots keep but encodingtest.Diff doesn't look at. The ID the next edge gets isn't visible without adding one, but RoundTrip
+// still checks it, as the re-encoded snapshot must match the first byte for byte.
+func (snapshotCodec) Diff(want, got core.Graph) string {
+	w, g := want.(*simple.GonumGraph), got.(*simple.GonumGraph)
+	var diffs []string
+	if wids, gids := fmt.Sprint(w.IdentifiedEdges()), fmt.Sprint(g.IdentifiedEdges()); wids != gids {
+		diffs = append(diffs, fmt.Sprintf("edges are identified as %s, want %s", gids, wids))
+	}
+	for _, edge := range w.EdgeList() {
+		if wiv, giv := fmt.Sprint(w.EdgeIntervals(edge)), fmt.Sprint(g.EdgeIntervals(edge)); wiv != giv {
+			diffs = append(diffs, fmt.Sprintf("edge %v is valid during %s, want %s", edge, giv, wiv))
+		}
+	}
+
+	return strings.Join(diffs, "\n")
+}
+
+func TestSnapshotFixtures(t *testing.T) {
+	for name, g := range encodingtest.Fixtures() {
+		encodingtest.RoundTrip(t, g, snapshotCodec{})
+		encodingtest.Golden(t, "snapshot_"+name, g, snapshotCodec{})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		g := simple.NewGonumGraph(directed)
+		g.AddNode(core.GonumNode(-3), nodes(1, 2))
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+		g.AddNode(core.GonumNode(7), nil)
+		g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(-3), T: core.GonumNode(1)}, math.Inf(1))
+		g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, 0.1)
+		g.Metadata().Name = "roads"
+		g.Metadata().Created = time.Date(2014, 3, 1, 12, 0, 0, 0, time.UTC)
+		g.Metadata().Set("source", "survey")
+		g.SetEdgeIntervals(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(1)}, simple.Interval{Start: 0, End: 5}, simple.Since(math.NaN()))
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(7), T: core.GonumNode(7)})
+		g.RemoveEdge(core.GonumEdge{H: core.GonumNode(7), T: core.GonumNode(7)})
+
+		data := g.Snapshot()
+		restored := simple.NewGonumGraph(!directed)
+		if err := restored.Restore(data); err != nil {
+			t.Fatal(err)
+		}
+		md := restored.Metadata()
+		if !core.Equal(restored, g, 0) || md.Name != "roads" || !md.Created.Equal(g.Metadata().Created) || md.Attributes["source"] != "survey" {
+			t.Errorf("Directed %v: Restored %v with metadata %+v", directed, restored.EdgeList(), md)
+		}
+		if !bytes.Equal(restored.Snapshot(), data) {
+			t.Errorf("Directed %v: The restored graph's snapshot differs", directed)
+		}
+		if diff := (snapshotCodec{}).Diff(g, restored); diff != "" {
+			t.Errorf("Directed %v: %s", directed, diff)
+		}
+
+		// Edges added after the restore are numbered as they would have been on the original
+		added := core.GonumEdge{H: core.GonumNode(7), T: core.GonumNode(-3)}
+		g.AddEdge(added)
+		restored.AddEdge(added)
+		if want, _ := g.EdgeID(added); (snapshotCodec{}).Diff(g, restored) != "" || want != 4 {
+			t.Errorf("Directed %v: Added edges got IDs %v on the restored graph, want %v", directed, restored.IdentifiedEdges(), g.IdentifiedEdges())
+		}
+		g.RemoveEdge(added)
+		restored.Restore(data)
+
+		// Every shorter prefix is an error that leaves the graph alone
+		for n := 0; n < len(data); n++ {
+			if err := restored.Restore(data[:n]); err == nil || !core.Equal(restored, g, 0) {
+				t.Fatalf("Directed %v: Restoring %d of %d bytes gave error %v", directed, n, len(data), err)
+			}
+		}
+		if err := restored.Restore(append(data, 0)); err == nil {
+			t.Errorf("Directed %v: No error for trailing data", directed)
+		}
+	}
+
+	if err := simple.NewGonumGraph(true).Restore(simple.NewGonumGraph(true).Snapshot()); err != nil {
+		t.Errorf("Couldn't restore an empty graph: %v", err)
+	}
+}
+
+func TestSnapshotVersion1(t *testing.T) {
+	// Version 1 snapshots have no edge IDs or intervals, so the edges are numbered in the order they're listed
+	v1 := []byte("GGSNAP\x01\x01\x00\x00\x00\x00\x03\x00\x02\x04\x02\x02\x04\x00\x00\x00\x00\x00\x00\x00\x40\x00\x02\x00\x00\x00\x00\x00\x00\xf0\x3f")
+	g := simple.NewGonumGraph(false)
+	if err := g.Restore(v1); err != nil {
+		t.Fatal(err)
+	}
+	if edges := fmt.Sprint(g.IdentifiedEdges()); !g.IsDirected() || len(g.NodeList()) != 3 || edges != "[{1 2 0 2} {0 1 1 1}]" || g.EdgeIntervals(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}) != nil {
+		t.Errorf("Restored a version 1 snapshot with edges %s", edges)
+	}
+
+	if err := g.Restore([]byte("GGSNAP\x03\x01\x00\x00\x00\x00\x00\x00")); err == nil {
+		t.Error("No error restoring a snapshot from a later version")
+	}
+}
+
+type failingWriter struct {
+	failAfter int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.failAfter == 0 {
+		return 0, errors.New("disk full")
+	}
+	w.failAfter--
+	return len(p), nil
+}
+
+func TestLoggedGraph(t *testing.T) {
+	// A snapshot, and then the mutations made since it
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1))
+	snapshot := g.Snapshot()
+
+	var log bytes.Buffer
+	logged := simple.NewLoggedGraph(g, &log)
+	node := logged.NewNode(nodes(0))
+	logged.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(5)})
+	logged.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(5)}, math.NaN())
+	logged.SetEdgeCost(core.GonumEdge{H: node, T: core.GonumNode(0)}, 4)
+	logged.RemoveEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)})
+	logged.AddNode(core.GonumNode(9), nodes(1))
+	logged.RemoveNode(core.GonumNode(9))
+	if node.ID() != 2 || !g.IsSuccessor(node, core.GonumNode(0)) || logged.Cost(node, core.GonumNode(0)) != 4 || logged.Err() != nil {
+		t.Fatalf("The logged graph has edges %v", g.EdgeList())
+	}
+
+	recovered := simple.NewGonumGraph(true)
+	if err := recovered.Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := simple.ReplayLog(recovered, bytes.NewReader(log.Bytes())); err != nil || n != 7 || !bytes.Equal(recovered.Snapshot(), g.Snapshot()) {
+		t.Errorf("Replayed %d mutations with error %v, giving %v", n, err, recovered.EdgeList())
+	}
+
+	// A crash in the middle of writing the last record loses only that record
+	for cut := 1; cut < 4; cut++ {
+		torn := simple.NewGonumGraph(true)
+		if n, err := simple.ReplayLog(torn, bytes.NewReader(log.Bytes()[:log.Len()-cut])); err != nil || n != 6 {
+			t.Errorf("Replayed %d mutations of a torn log with error %v", n, err)
+		}
+	}
+	corrupt := append([]byte(nil), log.Bytes()...)
+	corrupt[3] ^= 0xff
+	if _, err := simple.ReplayLog(simple.NewGonumGraph(true), bytes.NewReader(corrupt)); err == nil {
+		t.Error("No error replaying a corrupt log")
+	}
+
+	// Once the log can't be written, the graph stops changing
+	g = simple.NewGonumGraph(false)
+	logged = simple.NewLoggedGraph(g, &failingWriter{1})
+	logged.AddNode(core.GonumNode(0), nil)
+	logged.AddNode(core.GonumNode(1), nodes(0))
+	if logged.NewNode(nil) != nil || len(g.NodeList()) != 1 || logged.Err() == nil {
+		t.Errorf("Got nodes %v and error %v after the log failed", g.NodeList(), logged.Err())
+	}
+}
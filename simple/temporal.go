@@ -0,0 +1,160 @@
+package simple
+
+import (
+	"github.com/nathankerr/graph/core"
+	"math"
+)
+
+// An Interval is a half-open span of time [Start, End). Times are plain float64s in whatever unit suits the data, such as Unix seconds or simulation steps; use math.Inf(1) as the End of
+// an interval that hasn't finished.
+type Interval struct {
+	Start, End float64
+}
+
+// Returns whether t falls in the interval
+func (iv Interval) Contains(t float64) bool {
+	return iv.Start <= t && t < iv.End
+}
+
+// Returns an interval that starts at the given time and never ends
+func Since(start float64) Interval {
+	return Interval{start, math.Inf(1)}
+}
+
+// Sets the times at which an existing edge is valid (the edge is valid at t if any of the intervals contains t), replacing any intervals it had before. An edge without intervals is valid
+// at all times, which is the default; passing no intervals restores that. In an undirected graph both directions share the intervals. Does nothing if the edge doesn't exist.
+//
+// The intervals only affect SnapshotAt, every other method (and any algorithm run directly on the graph) sees all the edges regardless of time.
+func (graph *GonumGraph) SetEdgeIntervals(e core.Edge, intervals ...Interval) {
+	if !graph.IsSuccessor(e.Head(), e.Tail()) {
+		return
+	}
+
+	key := core.KeyOf(e, graph.directed)
+	if len(intervals) == 0 {
+		delete(graph.intervals, key)
+		return
+	}
+
+	if graph.intervals == nil {
+		graph.intervals = make(map[core.EdgeKey][]Interval)
+	}
+	graph.intervals[key] = append([]Interval(nil), intervals...)
+}
+
+// Returns the validity intervals of the edge, or nil if it's valid at all times (or doesn't exist)
+func (graph *GonumGraph) EdgeIntervals(e core.Edge) []Interval {
+	intervals, ok := graph.intervals[core.KeyOf(e, graph.directed)]
+	if !ok {
+		return nil
+	}
+
+	return append([]Interval(nil), intervals...)
+}
+
+func (graph *GonumGraph) validAt(head, tail int, t float64) bool {
+	intervals, ok := graph.intervals[core.KeyOf(core.GonumEdge{H: core.GonumNode(head), T: core.GonumNode(tail)}, graph.directed)]
+	if !ok {
+		return true
+	}
+
+	for _, iv := range intervals {
+		if iv.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns a read-only view of the graph containing every node, but only the edges valid at time t. The view isn't a copy: it reads through to the graph, so later changes to the graph (or
+// to its intervals) show up in it, and it's cheap to take many snapshots. Each query costs an extra interval check per edge compared to querying the graph itself.
+func (graph *GonumGraph) SnapshotAt(t float64) *SnapshotView {
+	return &SnapshotView{graph, t}
+}
+
+// A SnapshotView is the graph seen at one moment in time, as returned by GonumGraph.SnapshotAt. It implements Graph and Coster.
+type SnapshotView struct {
+	graph *GonumGraph
+	t     float64
+}
+
+// Returns the time the view was taken at
+func (view *SnapshotView) Time() float64 {
+	return view.t
+}
+
+func (view *SnapshotView) Successors(node core.Node) []core.Node {
+	id := node.ID()
+	if !view.graph.NodeExists(node) {
+		return nil
+	}
+
+	successors := make([]core.Node, 0, len(view.graph.successors[id]))
+	for succ := range view.graph.successors[id] {
+		if view.graph.validAt(id, succ, view.t) {
+			successors = append(successors, view.graph.nodeMap[succ])
+		}
+	}
+
+	return successors
+}
+
+func (view *SnapshotView) IsSuccessor(node, successor core.Node) bool {
+	return view.graph.IsSuccessor(node, successor) && view.graph.validAt(node.ID(), successor.ID(), view.t)
+}
+
+func (view *SnapshotView) Predecessors(node core.Node) []core.Node {
+	id := node.ID()
+	if !view.graph.NodeExists(node) {
+		return nil
+	}
+
+	predecessors := make([]core.Node, 0, len(view.graph.predecessors[id]))
+	for pred := range view.graph.predecessors[id] {
+		if view.graph.validAt(pred, id, view.t) {
+			predecessors = append(predecessors, view.graph.nodeMap[pred])
+		}
+	}
+
+	return predecessors
+}
+
+func (view *SnapshotView) IsPredecessor(node, predecessor core.Node) bool {
+	return view.graph.IsPredecessor(node, predecessor) && view.graph.validAt(predecessor.ID(), node.ID(), view.t)
+}
+
+func (view *SnapshotView) IsAdjacent(node, neighbor core.Node) bool {
+	return view.IsSuccessor(node, neighbor) || view.IsPredecessor(node, neighbor)
+}
+
+func (view *SnapshotView) NodeExists(node core.Node) bool {
+	return view.graph.NodeExists(node)
+}
+
+func (view *SnapshotView) Degree(node core.Node) int {
+	return len(view.Successors(node)) + len(view.Predecessors(node))
+}
+
+func (view *SnapshotView) EdgeList() []core.Edge {
+	edges := make([]core.Edge, 0)
+	for _, edge := range view.graph.EdgeList() {
+		if view.graph.validAt(edge.Head().ID(), edge.Tail().ID(), view.t) {
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges
+}
+
+func (view *SnapshotView) NodeList() []core.Node {
+	return view.graph.NodeList()
+}
+
+func (view *SnapshotView) IsDirected() bool {
+	return view.graph.IsDirected()
+}
+
+func (view *SnapshotView) Cost(node, succ core.Node) float64 {
+	return view.graph.Cost(node, succ)
+}
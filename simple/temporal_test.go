@@ -0,0 +1,40 @@
+package simple_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"testing"
+)
+
+func TestSnapshotAt(t *testing.T) {
+	// 0-1 always, 1-2 during [0, 10), 0-2 during [5, 6) and from 20 on
+	g := pathGraph(3)
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)})
+	g.SetEdgeIntervals(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(1)}, simple.Interval{Start: 0, End: 10})
+	g.SetEdgeIntervals(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, simple.Interval{Start: 5, End: 6}, simple.Since(20))
+
+	for _, test := range []struct {
+		t     float64
+		edges int
+	}{{-1, 1}, {0, 2}, {5, 3}, {6, 2}, {10, 1}, {20, 2}} {
+		view := g.SnapshotAt(test.t)
+		if edges := len(view.EdgeList()) / 2; edges != test.edges {
+			t.Errorf("At time %f there are %d edges, want %d", test.t, edges, test.edges)
+		}
+	}
+
+	view := g.SnapshotAt(12)
+	if view.IsAdjacent(core.GonumNode(1), core.GonumNode(2)) || len(view.Successors(core.GonumNode(2))) != 0 || !view.NodeExists(core.GonumNode(2)) {
+		t.Error("Edge 1-2 is still in the view after it expired")
+	}
+	if _, cost, _ := path.AStar(core.GonumNode(0), core.GonumNode(2), g.SnapshotAt(3), nil, nil); cost != 2 {
+		t.Errorf("Shortest path at time 3 has cost %f, want 2", cost)
+	}
+
+	g.RemoveEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)})
+	if intervals := g.EdgeIntervals(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(0)}); intervals != nil {
+		t.Errorf("Re-added edge kept the intervals %v", intervals)
+	}
+}
@@ -0,0 +1,1128 @@
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+type TileGraph struct {
+	tiles            []bool
+	costs            []float64 // The cost of entering each tile, or nil if they're all 1
+	minCost          float64   // The lowest of the costs, if there are any, kept up to date for HeuristicCost
+	portals          map[int][]int
+	blocked          []bool // The transient obstacles, or nil if there are none
+	diagonal         DiagonalMovement
+	costModel        TileCostModel
+	nodes            []core.Node // nodes[id] == GonumNode(id), boxed once up front so that SuccessorsAppend doesn't allocate
+	numRows, numCols int
+}
+
+func tileNodes(n int) []core.Node {
+	nodes := make([]core.Node, n)
+	for id := range nodes {
+		nodes[id] = core.GonumNode(id)
+	}
+
+	return nodes
+}
+
+func NewTileGraph(dimX, dimY int, isPassable bool) *TileGraph {
+	tiles := make([]bool, dimX*dimY)
+	if isPassable {
+		for i, _ := range tiles {
+			tiles[i] = true
+		}
+	}
+
+	return &TileGraph{
+		tiles:   tiles,
+		nodes:   tileNodes(len(tiles)),
+		numRows: dimX,
+		numCols: dimY,
+	}
+}
+
+// A TileAlphabet says which characters of a template stand for which tiles, so that ParseTileGraph can read mazes drawn by other tools. Characters that aren't in the alphabet are an
+// error. The start and goal markers are passable tiles that ParseTileGraph also reports the positions of; leave them 0 to not recognize any.
+type TileAlphabet struct {
+	Walls, Floors string // Every character of Walls is an impassable tile, and every character of Floors a passable one
+	Start, Goal   rune
+	CostDigits    bool             // Whether '1' to '9' are passable tiles that cost that much to enter
+	Costs         map[rune]float64 // A legend of passable terrain, such as '~' for swamp costing 3 and '=' for road costing 0.5, checked after Walls and Floors
+}
+
+// The alphabet of GenerateTileGraph and String: '\u2580' (▀) for walls and ' ' for floors, with no markers or costs.
+var DefaultTileAlphabet = TileAlphabet{Walls: "\u2580", Floors: " "}
+
+// The alphabet most ASCII maze files use: '#' for walls, ' ' and '.' for floors, 'S' and 'G' for the start and goal, and digits for tiles that are more costly to enter.
+var ASCIITileAlphabet = TileAlphabet{Walls: "#", Floors: " .", Start: 'S', Goal: 'G', CostDigits: true}
+
+// Parses a TileGraph from a template in DefaultTileAlphabet. See ParseTileGraph for the format.
+func GenerateTileGraph(template string) (*TileGraph, error) {
+	graph, _, _, err := ParseTileGraph(template, DefaultTileAlphabet)
+	return graph, err
+}
+
+// Parses a TileGraph from a template in DefaultTileAlphabet extended with a legend of terrain: every character in the legend is a passable tile with that cost (see SetCost). For
+// example, with the legend {'~': 3, '=': 0.5} a '~' is swamp and a '=' is road.
+func GenerateTileGraphWithCosts(template string, legend map[rune]float64) (*TileGraph, error) {
+	alphabet := DefaultTileAlphabet
+	alphabet.Costs = legend
+	graph, _, _, err := ParseTileGraph(template, alphabet)
+	return graph, err
+}
+
+// Parses a TileGraph from a template where every line is a row and every character a tile, as given by the alphabet. Blank lines before and after the grid are ignored, as are Windows
+// line endings, but spaces never are, since they're usually tiles. Returns the start and goal marked in the template, or nil for ones that aren't. If any tile has a cost digit or a
+// character from the alphabet's Costs, the graph is given a cost for each tile (see SetCost), otherwise all of its costs are 1.
+//
+// Returns an error if the template has no rows, contains a character not in the alphabet (or invalid UTF-8), marks more than one start or goal, or if its rows aren't all the same length.
+// The template may come from an untrusted source.
+func ParseTileGraph(template string, alphabet TileAlphabet) (graph *TileGraph, start, goal core.Node, err error) {
+	rows := strings.Split(template, "\n")
+	for i, row := range rows {
+		rows[i] = strings.TrimSuffix(row, "\r")
+	}
+	for len(rows) > 0 && rows[0] == "" {
+		rows = rows[1:]
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 {
+		return nil, nil, nil, errors.New("Empty template, cannot generate graph.")
+	}
+
+	tiles := make([]bool, 0)
+	costs := make([]float64, 0)
+	hasCosts := false
+
+	colCheck := -1
+	for _, colString := range rows {
+		colCount := 0
+		cols := strings.NewReader(colString)
+		for cols.Len() != 0 {
+			colCount += 1
+			ch, _, err := cols.ReadRune()
+			if err != nil {
+				return nil, nil, nil, errors.New("Error while reading rune from input string")
+			}
+
+			id := len(tiles)
+			cost := 1.0
+			switch {
+			case ch == utf8.RuneError:
+				return nil, nil, nil, errors.New("Invalid UTF-8 in input string")
+			case strings.ContainsRune(alphabet.Walls, ch):
+				tiles = append(tiles, false)
+			case strings.ContainsRune(alphabet.Floors, ch):
+				tiles = append(tiles, true)
+			case ch != 0 && ch == alphabet.Start:
+				if start != nil {
+					return nil, nil, nil, errors.New("More than one start marked")
+				}
+				start = core.GonumNode(id)
+				tiles = append(tiles, true)
+			case ch != 0 && ch == alphabet.Goal:
+				if goal != nil {
+					return nil, nil, nil, errors.New("More than one goal marked")
+				}
+				goal = core.GonumNode(id)
+				tiles = append(tiles, true)
+			case alphabet.Costs != nil && hasRune(alphabet.Costs, ch):
+				tiles = append(tiles, true)
+				cost = alphabet.Costs[ch]
+				hasCosts = true
+			case alphabet.CostDigits && ch >= '1' && ch <= '9':
+				tiles = append(tiles, true)
+				cost = float64(ch - '0')
+				hasCosts = true
+			default:
+				return nil, nil, nil, errors.New("Unrecognized character while reading input string")
+			}
+			costs = append(costs, cost)
+		}
+
+		if colCheck == -1 {
+			colCheck = colCount
+		} else if colCheck != colCount {
+			return nil, nil, nil, errors.New("Jagged rows, cannot generate graph.")
+		}
+	}
+
+	if !hasCosts {
+		costs = nil
+	}
+
+	graph = &TileGraph{
+		tiles:   tiles,
+		costs:   costs,
+		nodes:   tileNodes(len(tiles)),
+		numRows: len(rows),
+		numCols: colCheck,
+	}
+	graph.updateMinCost()
+
+	return graph, start, goal, nil
+}
+
+func hasRune(legend map[rune]float64, ch rune) bool {
+	_, ok := legend[ch]
+	return ok
+}
+
+func (graph *TileGraph) SetPassability(row, col int, passability bool) {
+	loc := row*graph.numCols + col
+	if loc >= len(graph.tiles) || row < 0 || col < 0 {
+		return
+	}
+
+	graph.tiles[loc] = passability
+}
+
+// Sets the cost of the tile at the given coordinates, its terrain weight, which is the cost of entering it unless the cost model says otherwise (see SetCostModel). Coordinates outside
+// the graph are ignored.
+func (graph *TileGraph) SetCost(row, col int, cost float64) {
+	loc := graph.CoordsToID(row, col)
+	if loc == -1 {
+		return
+	}
+
+	if graph.costs == nil {
+		graph.costs = make([]float64, len(graph.tiles))
+		for i := range graph.costs {
+			graph.costs[i] = 1
+		}
+		graph.minCost = 1
+	}
+	old := graph.costs[loc]
+	graph.costs[loc] = cost
+	if cost < graph.minCost {
+		graph.minCost = cost
+	} else if old == graph.minCost && cost != old {
+		graph.updateMinCost()
+	}
+}
+
+// Returns the cost of the tile at the given coordinates, as set with SetCost or given in the template, or 0 if they're outside the graph
+func (graph *TileGraph) TileCost(row, col int) float64 {
+	id := graph.CoordsToID(row, col)
+	if id == -1 {
+		return 0
+	}
+
+	return graph.tileCost(id)
+}
+
+func (graph *TileGraph) tileCost(id int) float64 {
+	if graph.costs == nil || id < 0 || id >= len(graph.costs) {
+		return 1
+	}
+
+	return graph.costs[id]
+}
+
+// A TileCostModel says how a TileGraph combines the costs of two tiles into the cost of moving between them
+type TileCostModel int
+
+const (
+	TileCostEnter   TileCostModel = iota // A move costs as much as the tile it enters, the default
+	TileCostAverage                      // A move costs the average of the two tiles, as if half of it were spent crossing each, so leaving a swamp is as slow as entering one
+)
+
+func (model TileCostModel) String() string {
+	switch model {
+	case TileCostEnter:
+		return "Enter"
+	case TileCostAverage:
+		return "Average"
+	}
+
+	return fmt.Sprintf("TileCostModel(%d)", int(model))
+}
+
+// Sets how the cost of a move is made from the costs of the two tiles. Either way a move costs at least the lowest tile cost, so HeuristicCost stays a lower bound.
+func (graph *TileGraph) SetCostModel(model TileCostModel) {
+	graph.costModel = model
+}
+
+// Returns how the cost of a move is made from the costs of the two tiles
+func (graph *TileGraph) CostModel() TileCostModel {
+	return graph.costModel
+}
+
+// DiagonalMovement says whether a TileGraph joins diagonal neighbors as well as orthogonal ones, and which corners a diagonal move may cut: the two tiles it passes between, which
+// are orthogonal neighbors of both ends. Diagonal moves make paths on open ground look natural rather than staircased.
+type DiagonalMovement int
+
+const (
+	DiagonalNever         DiagonalMovement = iota // Only orthogonal moves, the default
+	DiagonalAlways                                // Diagonal moves between any two passable tiles, even squeezing between two walls that meet at a corner
+	DiagonalAtMostOneWall                         // Diagonal moves that cut past at most one wall, so not between two, as LineOfSight sees them
+	DiagonalNoWalls                               // Diagonal moves only across open corners, so a path keeps clear of every wall it turns around
+)
+
+func (mode DiagonalMovement) String() string {
+	switch mode {
+	case DiagonalNever:
+		return "Never"
+	case DiagonalAlways:
+		return "Always"
+	case DiagonalAtMostOneWall:
+		return "AtMostOneWall"
+	case DiagonalNoWalls:
+		return "NoWalls"
+	}
+
+	return fmt.Sprintf("DiagonalMovement(%d)", int(mode))
+}
+
+// Sets whether the graph joins diagonal neighbors, and which corners their moves may cut. A diagonal move costs √2 times the cost of entering the tile it moves to, which is its length
+// next to an orthogonal move, and the heuristic becomes the octile distance, which allows for diagonal moves, so AStar still finds shortest paths.
+func (graph *TileGraph) SetDiagonalMovement(mode DiagonalMovement) {
+	graph.diagonal = mode
+}
+
+// Returns whether the graph joins diagonal neighbors, and which corners their moves may cut
+func (graph *TileGraph) DiagonalMovement() DiagonalMovement {
+	return graph.diagonal
+}
+
+// Returns whether the diagonal move from the tile at row, col by dRow, dCol (both -1 or 1) is allowed, and stays inside the graph. Doesn't check the tile moved from.
+func (graph *TileGraph) diagonalOpen(row, col, dRow, dCol int) bool {
+	to := graph.CoordsToID(row+dRow, col+dCol)
+	if graph.diagonal == DiagonalNever || to == -1 || !graph.open(to) {
+		return false
+	}
+
+	walls := 0
+	if !graph.open((row+dRow)*graph.numCols + col) {
+		walls++
+	}
+	if !graph.open(row*graph.numCols + col + dCol) {
+		walls++
+	}
+	switch graph.diagonal {
+	case DiagonalAtMostOneWall:
+		return walls <= 1
+	case DiagonalNoWalls:
+		return walls == 0
+	}
+
+	return true
+}
+
+func (graph *TileGraph) updateMinCost() {
+	if len(graph.costs) == 0 {
+		return
+	}
+	graph.minCost = graph.costs[0]
+	for _, cost := range graph.costs {
+		if cost < graph.minCost {
+			graph.minCost = cost
+		}
+	}
+}
+
+// Returns the cost of entering succ, which is 1 unless it was given a cost in the template or set with SetCost, times √2 if it's a diagonal neighbor of node (see
+// SetDiagonalMovement). This means that, although the graph is undirected, the cost of a move depends on its direction, unless the cost model is TileCostAverage, in which case it's
+// the average of the two tiles' costs instead of succ's alone. Doesn't check that the tiles are adjacent.
+func (graph *TileGraph) Cost(node, succ core.Node) float64 {
+	id := succ.ID()
+	cost := graph.tileCost(id)
+	if graph.costModel == TileCostAverage {
+		cost = (graph.tileCost(node.ID()) + cost) / 2
+	}
+	if graph.diagonal != DiagonalNever && graph.isDiagonal(node.ID(), id) {
+		cost *= math.Sqrt2
+	}
+
+	return cost
+}
+
+// Returns whether two tiles are diagonal neighbors
+func (graph *TileGraph) isDiagonal(a, b int) bool {
+	if a < 0 || a >= len(graph.tiles) || b < 0 || b >= len(graph.tiles) {
+		return false
+	}
+
+	ar, ac := graph.IDToCoords(a)
+	br, bc := graph.IDToCoords(b)
+	return (ar-br == 1 || br-ar == 1) && (ac-bc == 1 || bc-ac == 1)
+}
+
+// Returns a lower bound on the cost of the cheapest path from node to goal, which makes TileGraph a HeuristicCoster, so AStar searches it with this heuristic rather than as Dijkstra
+// when it isn't given one. The bound is the Manhattan distance between the tiles (or the octile distance, with diagonal movement) times the lowest cost of entering any tile. Portals
+// can make a path shorter than that, by walking to a portal, jumping and walking on from another portal, so when there are any the bound is the lesser of the distance and the
+// distance from each end to its nearest portal plus one for the jump, and each call takes time proportional to the number of portals. See Heuristic for other distances.
+//
+// The heuristic is admissible and consistent (walls and obstacles only make paths longer than it says), so AStar still finds shortest paths with it. If any tile costs less than
+// nothing it returns 0, as NullHeuristic does, since no distance bounds the cost of a path then.
+func (graph *TileGraph) HeuristicCost(node, goal core.Node) float64 {
+	kind := HeuristicManhattan
+	if graph.diagonal != DiagonalNever {
+		kind = HeuristicOctile
+	}
+
+	return graph.heuristic(kind, node, goal)
+}
+
+// A TileHeuristic is a distance between two tiles on open ground, which TileGraph.Heuristic turns into a heuristic for AStar
+type TileHeuristic int
+
+const (
+	HeuristicManhattan TileHeuristic = iota // The rows plus the columns between the tiles, the tightest bound for orthogonal moves, but too high (so not admissible) with diagonal ones
+	HeuristicChebyshev                      // The greater of the rows and the columns between the tiles, admissible with or without diagonal moves, but loose
+	HeuristicEuclidean                      // The straight line distance between the tiles' centers, admissible with or without diagonal moves
+	HeuristicOctile                         // The length of the shortest path of orthogonal and diagonal moves, the tightest bound with diagonal moves, and admissible without them
+)
+
+func (kind TileHeuristic) String() string {
+	switch kind {
+	case HeuristicManhattan:
+		return "Manhattan"
+	case HeuristicChebyshev:
+		return "Chebyshev"
+	case HeuristicEuclidean:
+		return "Euclidean"
+	case HeuristicOctile:
+		return "Octile"
+	}
+
+	return fmt.Sprintf("TileHeuristic(%d)", int(kind))
+}
+
+// Returns a heuristic for AStar (or any search that takes a HeuristicCost) that bounds the cost of a path by the given distance between its ends, so callers can pick the distance
+// that suits how their agents move instead of passing nil and searching as Dijkstra does. The distance is scaled and allows for portals as HeuristicCost does, so each kind is
+// admissible whenever its distance never exceeds the number of moves on open ground (see TileHeuristic): all of them are without diagonal movement, and all but Manhattan are with it.
+// A heuristic that isn't admissible still finds paths, just not necessarily the cheapest.
+func (graph *TileGraph) Heuristic(kind TileHeuristic) func(core.Node, core.Node) float64 {
+	return func(node, goal core.Node) float64 {
+		return graph.heuristic(kind, node, goal)
+	}
+}
+
+func (graph *TileGraph) heuristic(kind TileHeuristic, node, goal core.Node) float64 {
+	from, to := node.ID(), goal.ID()
+	if from < 0 || from >= len(graph.tiles) || to < 0 || to >= len(graph.tiles) {
+		return 0
+	}
+	minCost := 1.0
+	if graph.costs != nil {
+		minCost = graph.minCost
+	}
+	if !(minCost > 0) {
+		return 0
+	}
+
+	steps := graph.distance(kind, from, to)
+	if len(graph.portals) > 0 {
+		fromPortal, toPortal := math.Inf(1), math.Inf(1)
+		for portal := range graph.portals {
+			fromPortal = math.Min(fromPortal, graph.distance(kind, from, portal))
+			toPortal = math.Min(toPortal, graph.distance(kind, portal, to))
+		}
+		steps = math.Min(steps, fromPortal+1+toPortal)
+	}
+
+	return steps * minCost
+}
+
+// Returns the given distance between two tiles
+func (graph *TileGraph) distance(kind TileHeuristic, a, b int) float64 {
+	ar, ac := graph.IDToCoords(a)
+	br, bc := graph.IDToCoords(b)
+	dr, dc := ar-br, ac-bc
+	if dr < 0 {
+		dr = -dr
+	}
+	if dc < 0 {
+		dc = -dc
+	}
+	if dc < dr {
+		dr, dc = dc, dr
+	}
+
+	switch kind {
+	case HeuristicChebyshev:
+		return float64(dc)
+	case HeuristicEuclidean:
+		return math.Hypot(float64(dr), float64(dc))
+	case HeuristicOctile:
+		return float64(dc-dr) + float64(dr)*math.Sqrt2
+	}
+	return float64(dr + dc)
+}
+
+// Returns whether the tile can be entered right now: it's passable in the map, and not blocked by an obstacle
+func (graph *TileGraph) open(id int) bool {
+	return graph.tiles[id] && (graph.blocked == nil || !graph.blocked[id])
+}
+
+// Blocks the tile at the given coordinates with a transient obstacle, such as another agent or a closed door, without changing the map underneath. While it's blocked the tile is
+// treated as impassable by everything that reads the graph, searches included (except an AStarInstance built before the change, which has its own copy), but String and Save still
+// show the map, and SetPassability changes the map under the obstacle. Coordinates outside the graph are ignored.
+func (graph *TileGraph) Block(row, col int) {
+	id := graph.CoordsToID(row, col)
+	if id == -1 {
+		return
+	}
+
+	if graph.blocked == nil {
+		graph.blocked = make([]bool, len(graph.tiles))
+	}
+	graph.blocked[id] = true
+}
+
+// Removes the obstacle from the tile at the given coordinates, if there is one, leaving it as the map has it.
+func (graph *TileGraph) Unblock(row, col int) {
+	if id := graph.CoordsToID(row, col); id != -1 && graph.blocked != nil {
+		graph.blocked[id] = false
+	}
+}
+
+// Returns whether the tile at the given coordinates is blocked by an obstacle (whatever the map has there).
+func (graph *TileGraph) IsBlocked(row, col int) bool {
+	id := graph.CoordsToID(row, col)
+	return id != -1 && graph.blocked != nil && graph.blocked[id]
+}
+
+// Removes every obstacle at once, e.g. before placing this frame's moving agents.
+func (graph *TileGraph) ClearObstacles() {
+	graph.blocked = nil
+}
+
+// Joins two tiles with a portal, so that each is a successor of the other as long as both are passable. Portals between tiles that are already adjacent, from a tile to itself, or
+// that already exist are ignored, as are nodes outside the graph. Moving through a portal costs the same as moving to any other tile: the cost of entering it.
+func (graph *TileGraph) AddPortal(a, b core.Node) {
+	from, to := a.ID(), b.ID()
+	if from < 0 || from >= len(graph.tiles) || to < 0 || to >= len(graph.tiles) || from == to {
+		return
+	}
+	if _, adjacent := graph.DirectionTo(a, b); adjacent {
+		return
+	}
+	for _, existing := range graph.portals[from] {
+		if existing == to {
+			return
+		}
+	}
+
+	if graph.portals == nil {
+		graph.portals = make(map[int][]int)
+	}
+	graph.portals[from] = append(graph.portals[from], to)
+	graph.portals[to] = append(graph.portals[to], from)
+}
+
+func (graph *TileGraph) String() string {
+	if graph.numRows == 0 {
+		return ""
+	}
+
+	var outString string
+	for r := 0; r < graph.numRows; r++ {
+		for c := 0; c < graph.numCols; c++ {
+			if graph.tiles[r*graph.numCols+c] == false {
+				outString += "\u2580" // Black square
+			} else {
+				outString += " " // Space
+			}
+		}
+
+		outString += "\n"
+	}
+
+	return outString[:len(outString)-1] // Kill final newline
+}
+
+// Draws the graph like String, with the first node of the path drawn as 's', the last as 'g' and the ones in between as '♥'.
+func (graph *TileGraph) PathString(path []core.Node) string {
+	if path == nil || len(path) == 0 {
+		return graph.String()
+	}
+
+	return graph.OverlayString(
+		TileOverlay{Nodes: path[1 : len(path)-1], Glyph: '♥'},
+		TileOverlay{Nodes: path[len(path)-1:], Glyph: 'g'},
+		TileOverlay{Nodes: path[:1], Glyph: 's'},
+	)
+}
+
+// A TileOverlay marks a set of tiles, such as a path or the nodes a search has visited, when drawing a TileGraph with OverlayString. Color is the parameter of an ANSI escape sequence
+// (SGR) to draw the glyph with, such as "31" for red or "1;32" for bold green, or "" to leave it uncolored.
+type TileOverlay struct {
+	Nodes []core.Node
+	Glyph rune
+	Color string
+}
+
+// Draws the graph like String, with each overlay's tiles drawn in its glyph. Later overlays are drawn over earlier ones, so pass them in increasing order of importance, e.g. the visited
+// set, then the frontier, then the path. Walls are always drawn as walls, and nodes outside the graph are ignored. If any overlay has a Color the result is only readable in a terminal.
+func (graph *TileGraph) OverlayString(overlays ...TileOverlay) string {
+	if graph.numRows == 0 {
+		return ""
+	}
+
+	glyphs := make(map[int]*TileOverlay)
+	for i := range overlays {
+		for _, node := range overlays[i].Nodes {
+			glyphs[node.ID()] = &overlays[i]
+		}
+	}
+
+	var buf bytes.Buffer
+	for r := 0; r < graph.numRows; r++ {
+		if r > 0 {
+			buf.WriteByte('\n')
+		}
+
+		for c := 0; c < graph.numCols; c++ {
+			id := r*graph.numCols + c
+			overlay, ok := glyphs[id]
+			switch {
+			case graph.tiles[id] == false:
+				buf.WriteString("\u2580") // Black square
+			case !ok:
+				buf.WriteByte(' ')
+			case overlay.Color == "":
+				buf.WriteRune(overlay.Glyph)
+			default:
+				fmt.Fprintf(&buf, "\x1b[%sm%c\x1b[0m", overlay.Color, overlay.Glyph)
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+func (graph *TileGraph) Dimensions() (rows, cols int) {
+	return graph.numRows, graph.numCols
+}
+
+func (graph *TileGraph) IDToCoords(id int) (row, col int) {
+	col = (id % graph.numCols)
+	row = (id - col) / graph.numCols
+
+	return row, col
+}
+
+func (graph *TileGraph) CoordsToID(row, col int) (id int) {
+	if row < 0 || row >= graph.numRows || col < 0 || col >= graph.numCols {
+		return -1
+	}
+	id = row*graph.numCols + col
+
+	return id
+}
+
+func (graph *TileGraph) CoordsToNode(row, col int) (node core.Node) {
+	id := graph.CoordsToID(row, col)
+	if id == -1 {
+		return nil
+	} else {
+		return core.GonumNode(id)
+	}
+}
+
+// A Direction is one of the moves between adjacent tiles of a TileGraph: the four orthogonal ones, and the four diagonal ones if the graph allows them (see SetDiagonalMovement). Rows
+// increase to the South and columns to the East, as in String.
+type Direction int
+
+const (
+	North Direction = iota
+	South
+	West
+	East
+	NorthWest
+	NorthEast
+	SouthWest
+	SouthEast
+)
+
+// The four orthogonal directions, in the order SuccessorsAppend lists a tile's neighbors in.
+var Directions = []Direction{North, South, West, East}
+
+// The four diagonal directions, in the order SuccessorsAppend lists a tile's diagonal neighbors in, after the orthogonal ones.
+var DiagonalDirections = []Direction{NorthWest, NorthEast, SouthWest, SouthEast}
+
+func (dir Direction) String() string {
+	switch dir {
+	case North:
+		return "North"
+	case South:
+		return "South"
+	case West:
+		return "West"
+	case East:
+		return "East"
+	case NorthWest:
+		return "NorthWest"
+	case NorthEast:
+		return "NorthEast"
+	case SouthWest:
+		return "SouthWest"
+	case SouthEast:
+		return "SouthEast"
+	}
+
+	return fmt.Sprintf("Direction(%d)", int(dir))
+}
+
+// Returns the direction that undoes a move in this one.
+func (dir Direction) Opposite() Direction {
+	switch dir {
+	case North:
+		return South
+	case South:
+		return North
+	case West:
+		return East
+	case East:
+		return West
+	case NorthWest:
+		return SouthEast
+	case NorthEast:
+		return SouthWest
+	case SouthWest:
+		return NorthEast
+	case SouthEast:
+		return NorthWest
+	}
+
+	return dir
+}
+
+// Returns the change in row and column a move in this direction makes.
+func (dir Direction) Offset() (dRow, dCol int) {
+	switch dir {
+	case North:
+		return -1, 0
+	case South:
+		return 1, 0
+	case West:
+		return 0, -1
+	case East:
+		return 0, 1
+	case NorthWest:
+		return -1, -1
+	case NorthEast:
+		return -1, 1
+	case SouthWest:
+		return 1, -1
+	case SouthEast:
+		return 1, 1
+	}
+
+	return 0, 0
+}
+
+// Returns the tile next to node in the given direction, and whether it's possible to move there: ok is false, and the neighbor nil, if either tile is impassable, the move would
+// leave the graph, or it's a diagonal move the graph doesn't allow. Portals have no direction, so they're never returned.
+func (graph *TileGraph) Neighbor(node core.Node, dir Direction) (neighbor core.Node, ok bool) {
+	if !graph.NodeExists(node) {
+		return nil, false
+	}
+
+	row, col := graph.IDToCoords(node.ID())
+	dRow, dCol := dir.Offset()
+	id := graph.CoordsToID(row+dRow, col+dCol)
+	if (dRow == 0 && dCol == 0) || id == -1 || !graph.open(id) {
+		return nil, false
+	}
+	if dRow != 0 && dCol != 0 && !graph.diagonalOpen(row, col, dRow, dCol) {
+		return nil, false
+	}
+
+	return graph.nodes[id], true
+}
+
+// Returns the direction of the move from one tile to an adjacent one, or false if they aren't adjacent in the grid (or either is outside the graph). Diagonal neighbors are only
+// adjacent if the graph allows diagonal moves. Passability isn't checked.
+func (graph *TileGraph) DirectionTo(from, to core.Node) (dir Direction, ok bool) {
+	if from.ID() < 0 || from.ID() >= len(graph.tiles) || to.ID() < 0 || to.ID() >= len(graph.tiles) {
+		return 0, false
+	}
+
+	fromRow, fromCol := graph.IDToCoords(from.ID())
+	toRow, toCol := graph.IDToCoords(to.ID())
+	for _, dir := range Directions {
+		if dRow, dCol := dir.Offset(); fromRow+dRow == toRow && fromCol+dCol == toCol {
+			return dir, true
+		}
+	}
+	if graph.diagonal != DiagonalNever {
+		for _, dir := range DiagonalDirections {
+			if dRow, dCol := dir.Offset(); fromRow+dRow == toRow && fromCol+dCol == toCol {
+				return dir, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// Returns whether there's a straight, unobstructed line between the centers of two passable tiles. See Raycast.
+func (graph *TileGraph) LineOfSight(from, to core.Node) bool {
+	_, clear := graph.Raycast(from, to)
+	return clear
+}
+
+// Walks the straight line from one tile towards another, and returns the last passable tile on it before the first obstruction, along with whether the line reached the target
+// unobstructed. The line is drawn with Bresenham's algorithm, in integer arithmetic, and a diagonal step between two walls that meet at a corner counts as obstructed, so sight can't
+// squeeze through gaps that a path couldn't. If from is impassable, or either node is outside the graph, it returns nil and false.
+//
+// The line between two tiles is the same whichever end it's drawn from, so LineOfSight is symmetric, as any-angle searches such as Theta* and path smoothing need.
+func (graph *TileGraph) Raycast(from, to core.Node) (last core.Node, clear bool) {
+	if !graph.NodeExists(from) || to.ID() < 0 || to.ID() >= len(graph.tiles) {
+		return nil, false
+	}
+
+	line := graph.bresenham(from.ID(), to.ID())
+	last = from
+	for i := 1; i < len(line); i++ {
+		prevRow, prevCol := graph.IDToCoords(line[i-1])
+		row, col := graph.IDToCoords(line[i])
+		squeezed := prevRow != row && prevCol != col && !graph.open(prevRow*graph.numCols+col) && !graph.open(row*graph.numCols+prevCol)
+		if !graph.open(line[i]) || squeezed {
+			return last, false
+		}
+		last = graph.nodes[line[i]]
+	}
+
+	return last, true
+}
+
+// Returns the IDs of the tiles on the line between two tiles, in order from a to b. The line is always drawn from the lower ID, so that a line and its reverse cross the same tiles.
+func (graph *TileGraph) bresenham(a, b int) []int {
+	if b < a {
+		line := graph.bresenham(b, a)
+		for i, j := 0, len(line)-1; i < j; i, j = i+1, j-1 {
+			line[i], line[j] = line[j], line[i]
+		}
+		return line
+	}
+
+	row, col := graph.IDToCoords(a)
+	toRow, toCol := graph.IDToCoords(b)
+	dRow, dCol := toRow-row, toCol-col
+	stepRow, stepCol := 1, 1
+	if dRow < 0 {
+		dRow, stepRow = -dRow, -1
+	}
+	if dCol < 0 {
+		dCol, stepCol = -dCol, -1
+	}
+
+	line := []int{a}
+	err := dCol - dRow
+	for row != toRow || col != toCol {
+		e2 := 2 * err
+		if e2 > -dRow {
+			err -= dRow
+			col += stepCol
+		}
+		if e2 < dCol {
+			err += dCol
+			row += stepRow
+		}
+		line = append(line, row*graph.numCols+col)
+	}
+
+	return line
+}
+
+// Returns, for every tile, the exact Euclidean distance from its center to the center of the nearest impassable (or blocked) tile, indexed by node ID. The area outside the graph counts as
+// impassable, so tiles along the border are at most 1 from an obstacle, and walls themselves are at 0. An agent with radius r (in tiles) fits on any tile whose distance is greater than
+// r, which makes this the basis of clearance-aware pathfinding: pass a Cost (or a filtered graph) that rules out the tiles that are too tight.
+//
+// This uses Felzenszwalb and Huttenlocher's distance transform, one pass over the columns and one over the rows, so it takes time linear in the number of tiles.
+func (graph *TileGraph) DistanceField() []float64 {
+	// Pad the grid with a ring of walls, so the border is an obstacle without any special cases
+	rows, cols := graph.numRows+2, graph.numCols+2
+	far := float64(rows*rows + cols*cols) // More than any squared distance in the grid, and finite so the envelope arithmetic stays exact
+	squared := make([]float64, rows*cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if r > 0 && r < rows-1 && c > 0 && c < cols-1 && graph.open((r-1)*graph.numCols+c-1) {
+				squared[r*cols+c] = far
+			}
+		}
+	}
+
+	n := rows
+	if cols > n {
+		n = cols
+	}
+	f, d, v, z := make([]float64, n), make([]float64, n), make([]int, n), make([]float64, n+1)
+	for c := 0; c < cols; c++ {
+		for r := 0; r < rows; r++ {
+			f[r] = squared[r*cols+c]
+		}
+		distanceTransform(f[:rows], d[:rows], v, z)
+		for r := 0; r < rows; r++ {
+			squared[r*cols+c] = d[r]
+		}
+	}
+	for r := 0; r < rows; r++ {
+		copy(f, squared[r*cols:(r+1)*cols])
+		distanceTransform(f[:cols], d[:cols], v, z)
+		copy(squared[r*cols:(r+1)*cols], d[:cols])
+	}
+
+	field := make([]float64, len(graph.tiles))
+	for id := range field {
+		row, col := graph.IDToCoords(id)
+		field[id] = math.Sqrt(squared[(row+1)*cols+col+1])
+	}
+
+	return field
+}
+
+// The one dimensional squared distance transform: sets d[q] to the minimum over p of (q-p)^2 + f[p], by building the lower envelope of the parabolas rooted at each p. v and z are
+// scratch space for the envelope, of at least len(f) and len(f)+1.
+func distanceTransform(f, d []float64, v []int, z []float64) {
+	k := 0
+	v[0] = 0
+	z[0], z[1] = math.Inf(-1), math.Inf(1)
+	intersect := func(q, p int) float64 {
+		return ((f[q] + float64(q*q)) - (f[p] + float64(p*p))) / float64(2*q-2*p)
+	}
+	for q := 1; q < len(f); q++ {
+		// z[0] is -Inf and f is finite, so this always stops at the first parabola
+		s := intersect(q, v[k])
+		for s <= z[k] {
+			k--
+			s = intersect(q, v[k])
+		}
+		k++
+		v[k] = q
+		z[k], z[k+1] = s, math.Inf(1)
+	}
+
+	k = 0
+	for q := range f {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		dq := q - v[k]
+		d[q] = float64(dq*dq) + f[v[k]]
+	}
+}
+
+// Returns the graph as a round agent of the given radius (in tiles, measured from the center of the tile it's on) sees it: a copy where only the tiles whose DistanceField is greater
+// than the radius are passable, so the agent never overlaps a wall. Searching the copy with AStar, or anything else, gives paths the agent fits along. A radius below 0.5 leaves the
+// graph as it is, while a radius of 1 keeps the agent off every tile next to a wall, which closes 1-tile gaps (and 2-tile ones, since a round agent of radius 1 is 2 tiles wide wherever
+// it stands; use FootprintGraph for square units that fit gaps exactly their size).
+//
+// The costs and portals are copied, and the copy is independent of the original. Tiles blocked by obstacles (see Block) count as walls, and are walls in the copy.
+func (graph *TileGraph) ClearanceGraph(radius float64) *TileGraph {
+	field := graph.DistanceField()
+	tiles := make([]bool, len(graph.tiles))
+	for id := range tiles {
+		tiles[id] = graph.open(id) && field[id] > radius
+	}
+
+	return graph.withTiles(tiles)
+}
+
+// Returns the graph as a square unit covering size by size tiles sees it, with the unit's position given by its top left tile: a copy where a tile is only passable if every tile of the
+// footprint anchored there is passable and inside the graph. A 2x2 unit can then pass through 2-tile gaps, but not 1-tile ones. A size of 1 or less leaves the graph as it is.
+//
+// This is the "true clearance" of each tile, the largest all-passable square anchored at it, which is a distance transform like DistanceField but measured towards the bottom right in
+// the Chebyshev metric. The costs and portals are copied, and the copy is independent of the original. Tiles blocked by obstacles (see Block) count as walls, and are walls in the copy.
+func (graph *TileGraph) FootprintGraph(size int) *TileGraph {
+	clearance := make([]int, len(graph.tiles))
+	for r := graph.numRows - 1; r >= 0; r-- {
+		for c := graph.numCols - 1; c >= 0; c-- {
+			id := r*graph.numCols + c
+			if !graph.open(id) {
+				continue
+			}
+			if r == graph.numRows-1 || c == graph.numCols-1 {
+				clearance[id] = 1
+				continue
+			}
+
+			below, right, diagonal := clearance[id+graph.numCols], clearance[id+1], clearance[id+graph.numCols+1]
+			smallest := below
+			if right < smallest {
+				smallest = right
+			}
+			if diagonal < smallest {
+				smallest = diagonal
+			}
+			clearance[id] = smallest + 1
+		}
+	}
+
+	tiles := make([]bool, len(graph.tiles))
+	for id := range tiles {
+		tiles[id] = graph.open(id) && clearance[id] >= size
+	}
+
+	return graph.withTiles(tiles)
+}
+
+// Returns a copy of the graph with the given passability, and the same costs, cost model, portals and diagonal movement
+func (graph *TileGraph) withTiles(tiles []bool) *TileGraph {
+	clone := &TileGraph{
+		tiles:     tiles,
+		nodes:     graph.nodes, // Never modified, so it can be shared
+		diagonal:  graph.diagonal,
+		costModel: graph.costModel,
+		numRows:   graph.numRows,
+		numCols:   graph.numCols,
+	}
+	if graph.costs != nil {
+		clone.costs = append([]float64(nil), graph.costs...)
+		clone.minCost = graph.minCost
+	}
+	if graph.portals != nil {
+		clone.portals = make(map[int][]int, len(graph.portals))
+		for from, tos := range graph.portals {
+			clone.portals[from] = append([]int(nil), tos...)
+		}
+	}
+
+	return clone
+}
+
+func (graph *TileGraph) Successors(node core.Node) []core.Node {
+	if !graph.NodeExists(node) {
+		return nil
+	}
+
+	return graph.SuccessorsAppend(node, make([]core.Node, 0, 4))
+}
+
+// Appends the successors of node to buf, in the same order as Successors, and returns the extended slice: the orthogonal neighbors, then any diagonal ones, then the portals. Unlike
+// Successors this doesn't allocate as long as buf has room for four more nodes (eight with diagonal movement, plus one for each of its portals), so it's the better choice in tight
+// loops; the searches in package path use it automatically through the SuccessorsAppender interface.
+func (graph *TileGraph) SuccessorsAppend(node core.Node, buf []core.Node) []core.Node {
+	id := node.ID()
+	if id < 0 || id >= len(graph.tiles) || !graph.open(id) {
+		return buf
+	}
+
+	row, col := graph.IDToCoords(id)
+	if row > 0 && graph.open(id-graph.numCols) {
+		buf = append(buf, graph.nodes[id-graph.numCols])
+	}
+	if row < graph.numRows-1 && graph.open(id+graph.numCols) {
+		buf = append(buf, graph.nodes[id+graph.numCols])
+	}
+	if col > 0 && graph.open(id-1) {
+		buf = append(buf, graph.nodes[id-1])
+	}
+	if col < graph.numCols-1 && graph.open(id+1) {
+		buf = append(buf, graph.nodes[id+1])
+	}
+	if graph.diagonal != DiagonalNever {
+		for _, dir := range DiagonalDirections {
+			if dRow, dCol := dir.Offset(); graph.diagonalOpen(row, col, dRow, dCol) {
+				buf = append(buf, graph.nodes[id+dRow*graph.numCols+dCol])
+			}
+		}
+	}
+	for _, to := range graph.portals[id] {
+		if graph.open(to) {
+			buf = append(buf, graph.nodes[to])
+		}
+	}
+
+	return buf
+}
+
+// Returns whether successor is one of the tiles SuccessorsAppend would give for node: a passable orthogonal neighbor, a diagonal neighbor the graph's diagonal movement allows, or
+// the other end of a portal, with node passable too
+func (graph *TileGraph) IsSuccessor(node, successor core.Node) bool {
+	id, succ := node.ID(), successor.ID()
+	if id < 0 || id >= len(graph.tiles) || !graph.open(id) || succ < 0 || succ >= len(graph.tiles) || !graph.open(succ) {
+		return false
+	}
+
+	row, col := graph.IDToCoords(id)
+	succRow, succCol := graph.IDToCoords(succ)
+	dRow, dCol := succRow-row, succCol-col
+	if (dRow == 0 && (dCol == 1 || dCol == -1)) || (dCol == 0 && (dRow == 1 || dRow == -1)) {
+		return true
+	}
+	if (dRow == 1 || dRow == -1) && (dCol == 1 || dCol == -1) && graph.diagonalOpen(row, col, dRow, dCol) {
+		return true
+	}
+	for _, to := range graph.portals[id] {
+		if to == succ {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (graph *TileGraph) Predecessors(node core.Node) []core.Node {
+	return graph.Successors(node)
+}
+
+func (graph *TileGraph) IsPredecessor(node, pred core.Node) bool {
+	return graph.IsSuccessor(node, pred)
+}
+
+func (graph *TileGraph) IsAdjacent(id, neighbor core.Node) bool {
+	return graph.IsSuccessor(id, neighbor)
+}
+
+func (graph *TileGraph) NodeExists(node core.Node) bool {
+	id := node.ID()
+	return id >= 0 && id < len(graph.tiles) && graph.open(id)
+}
+
+func (graph *TileGraph) Degree(node core.Node) int {
+	return graph.OutDegree(node) * 2
+}
+
+func (graph *TileGraph) InDegree(node core.Node) int {
+	return graph.OutDegree(node)
+}
+
+func (graph *TileGraph) OutDegree(node core.Node) int {
+	var buf [8]core.Node
+	return len(graph.SuccessorsAppend(node, buf[:0]))
+}
+
+func (graph *TileGraph) EdgeList() []core.Edge {
+	edges := make([]core.Edge, 0)
+	for id := range graph.tiles {
+		if !graph.open(id) {
+			continue
+		}
+
+		for _, succ := range graph.Successors(core.GonumNode(id)) {
+			edges = append(edges, core.GonumCostEdge{H: core.GonumNode(id), T: succ, W: graph.Cost(core.GonumNode(id), succ)})
+		}
+	}
+
+	return edges
+}
+
+func (graph *TileGraph) NodeList() []core.Node {
+	nodes := make([]core.Node, 0)
+	for id := range graph.tiles {
+		if !graph.open(id) {
+			continue
+		}
+
+		nodes = append(nodes, core.GonumNode(id))
+	}
+
+	return nodes
+}
+
+func (graph *TileGraph) IsDirected() bool {
+	return false
+}
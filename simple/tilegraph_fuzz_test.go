@@ -0,0 +1,108 @@
+//go:build go1.18
+// +build go1.18
+
+package simple_test
+
+import (
+	"bytes"
+	"github.com/nathankerr/graph/encodingtest"
+	"github.com/nathankerr/graph/simple"
+	"strings"
+	"testing"
+)
+
+func FuzzGenerateTileGraph(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		" ",
+		"▀",
+		"▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀ ▀",
+		"\n  \n▀▀\n\n",
+		" ▀\r\n▀ \r\n",
+		"▀ \n▀",
+		"▀\n\n▀",
+		"x",
+		"\xff\xfe",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, template string) {
+		tg, err := simple.GenerateTileGraph(template)
+		if err != nil {
+			if tg != nil {
+				t.Errorf("Got a graph along with error %v", err)
+			}
+			return
+		}
+
+		rows, cols := tg.Dimensions()
+		if rows == 0 || cols == 0 {
+			t.Fatalf("Template %q parsed to a %dx%d graph", template, rows, cols)
+		}
+
+		// The output must parse back to itself
+		out := tg.String()
+		if strings.Count(out, "\n") != rows-1 {
+			t.Errorf("String of a %dx%d graph has %d rows: %q", rows, cols, strings.Count(out, "\n")+1, out)
+		}
+		if again, err := simple.GenerateTileGraph(out); err != nil {
+			t.Errorf("Reparsing %q failed: %v", out, err)
+		} else if again.String() != out {
+			t.Errorf("Reparsing %q gave %q", out, again.String())
+		}
+
+		if len(tg.NodeList()) > rows*cols {
+			t.Errorf("%dx%d graph has %d nodes", rows, cols, len(tg.NodeList()))
+		}
+		for _, node := range tg.NodeList() {
+			if !tg.NodeExists(node) {
+				t.Errorf("Listed node %d doesn't exist", node.ID())
+			}
+			for _, succ := range tg.Successors(node) {
+				if succ.ID() < 0 || succ.ID() >= rows*cols || !tg.NodeExists(succ) {
+					t.Errorf("Node %d has successor %d outside the passable tiles", node.ID(), succ.ID())
+				}
+			}
+		}
+	})
+}
+
+func FuzzLoadTileGraph(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"tilegraph 0 0\n",
+		"tilegraph 3 4\n#...\n#.#.\n....\ncost 2 0 2.5\nportal 0 1 2 3\n",
+		"tilegraph 1 3\r\n...\r\ncost 0 0 NaN\r\nportal 0 0 0 0\r\n",
+		"tilegraph 2 1\n.\n",
+		"tilegraph -1 1\n",
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tg, err := simple.LoadTileGraph(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		// Whatever was accepted must save and load back the same
+		var first, second bytes.Buffer
+		if err := tg.Save(&first); err != nil {
+			t.Fatal(err)
+		}
+		again, err := simple.LoadTileGraph(bytes.NewReader(first.Bytes()))
+		if err != nil {
+			t.Fatalf("Loading saved graph failed: %v\n%s", err, first.String())
+		}
+		if diff := encodingtest.Diff(tg, again); diff != "" {
+			t.Errorf("Saving and loading changed the graph:\n%s", diff)
+		}
+		if err := again.Save(&second); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Errorf("Saves differ:\n%s\n%s", first.String(), second.String())
+		}
+	})
+}
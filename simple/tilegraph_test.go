@@ -0,0 +1,702 @@
+package simple_test
+
+import (
+	"bytes"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/encodingtest"
+	"github.com/nathankerr/graph/path"
+	"github.com/nathankerr/graph/simple"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTileGraph(t *testing.T) {
+	tg := simple.NewTileGraph(4, 4, false)
+	if tg == nil || tg.String() != "▀▀▀▀\n▀▀▀▀\n▀▀▀▀\n▀▀▀▀" {
+		t.Fatal("Tile graph not generated correctly")
+	}
+
+	tg.SetPassability(0, 1, true)
+	if tg == nil || tg.String() != "▀ ▀▀\n▀▀▀▀\n▀▀▀▀\n▀▀▀▀" {
+		t.Fatal("Passability set incorrectly")
+	}
+
+	tg.SetPassability(0, 1, false)
+	if tg == nil || tg.String() != "▀▀▀▀\n▀▀▀▀\n▀▀▀▀\n▀▀▀▀" {
+		t.Fatal("Passability set incorrectly")
+	}
+
+	tg.SetPassability(0, 1, true)
+	if tg == nil || tg.String() != "▀ ▀▀\n▀▀▀▀\n▀▀▀▀\n▀▀▀▀" {
+		t.Fatal("Passability set incorrectly")
+	}
+
+	tg.SetPassability(0, 2, true)
+	if tg == nil || tg.String() != "▀  ▀\n▀▀▀▀\n▀▀▀▀\n▀▀▀▀" {
+		t.Fatal("Passability set incorrectly")
+	}
+
+	tg.SetPassability(1, 2, true)
+	if tg == nil || tg.String() != "▀  ▀\n▀▀ ▀\n▀▀▀▀\n▀▀▀▀" {
+		t.Fatal("Passability set incorrectly")
+	}
+
+	tg.SetPassability(2, 2, true)
+	if tg == nil || tg.String() != "▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀▀▀" {
+		t.Fatal("Passability set incorrectly")
+	}
+
+	tg.SetPassability(3, 2, true)
+	if tg == nil || tg.String() != "▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀ ▀" {
+		t.Fatal("Passability set incorrectly")
+	}
+
+	if tg2, err := simple.GenerateTileGraph("▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀ ▀"); err != nil {
+		t.Error("Tile graph errored on interpreting valid template string\n▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀ ▀")
+	} else if tg2.String() != "▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀ ▀" {
+		t.Error("Tile graph failed to generate properly with input string\n▀  ▀\n▀▀ ▀\n▀▀ ▀\n▀▀ ▀")
+	}
+
+	if tg.CoordsToID(0, 0) != 0 {
+		t.Error("Coords to ID fails on 0,0")
+	} else if tg.CoordsToID(3, 3) != 15 {
+		t.Error("Coords to ID fails on 3,3")
+	} else if tg.CoordsToID(0, 3) != 3 {
+		t.Error("Coords to ID fails on 0,3")
+	} else if tg.CoordsToID(3, 0) != 12 {
+		t.Error("Coords to ID fails on 3,0")
+	}
+
+	if r, c := tg.IDToCoords(0); r != 0 || c != 0 {
+		t.Error("ID to Coords fails on 0,0")
+	} else if r, c := tg.IDToCoords(15); r != 3 || c != 3 {
+		t.Error("ID to Coords fails on 3,3")
+	} else if r, c := tg.IDToCoords(3); r != 0 || c != 3 {
+		t.Error("ID to Coords fails on 0,3")
+	} else if r, c := tg.IDToCoords(12); r != 3 || c != 0 {
+		t.Error("ID to Coords fails on 3,0")
+	}
+
+	if succ := tg.Successors(core.GonumNode(0)); succ != nil || len(succ) != 0 {
+		t.Error("Successors for impassable tile not 0")
+	}
+
+	if succ := tg.Successors(core.GonumNode(2)); succ == nil || len(succ) != 2 {
+		t.Error("Incorrect number of successors for (0,2)")
+	} else {
+		for _, s := range succ {
+			if s.ID() != 1 && s.ID() != 6 {
+				t.Error("Successor for (0,2) neither (0,1) nor (1,2)")
+			}
+		}
+	}
+
+	if tg.Degree(core.GonumNode(2)) != 4 {
+		t.Error("Degree returns incorrect number for (0,2)")
+	}
+	if tg.Degree(core.GonumNode(1)) != 2 {
+		t.Error("Degree returns incorrect number for (0,2)")
+	}
+	if tg.Degree(core.GonumNode(0)) != 0 {
+		t.Error("Degree returns incorrect number for impassable tile (0,0)")
+	}
+
+}
+
+func TestTileGraphSuccessorsAppend(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("" +
+		"▀  \n" +
+		"   \n" +
+		" ▀▀")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]core.Node, 0, 4)
+	for id := -1; id < 10; id++ {
+		want := tg.Successors(core.GonumNode(id))
+		buf = tg.SuccessorsAppend(core.GonumNode(id), buf[:0])
+		if len(buf) != len(want) {
+			t.Fatalf("Node %d: SuccessorsAppend gave %v, Successors gave %v", id, buf, want)
+		}
+		for i := range want {
+			if buf[i].ID() != want[i].ID() {
+				t.Fatalf("Node %d: SuccessorsAppend gave %v, Successors gave %v", id, buf, want)
+			}
+		}
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { buf = tg.SuccessorsAppend(core.GonumNode(4), buf[:0]) }); allocs != 0 {
+		t.Errorf("SuccessorsAppend made %f allocations per call, want 0", allocs)
+	}
+}
+
+func TestTileGraphIsSuccessor(t *testing.T) {
+	tg, _, _, err := simple.ParseTileGraph(" # \n#  \n   ", simple.ASCIITileAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.AddPortal(tg.CoordsToNode(0, 0), tg.CoordsToNode(2, 2))
+
+	// IsSuccessor, IsPredecessor and IsAdjacent hold exactly for the tiles Successors lists, whatever diagonal moves are allowed
+	for _, mode := range []simple.DiagonalMovement{simple.DiagonalNever, simple.DiagonalAlways, simple.DiagonalAtMostOneWall, simple.DiagonalNoWalls} {
+		tg.SetDiagonalMovement(mode)
+		for id := -1; id <= 9; id++ {
+			succs := make(map[int]bool)
+			for _, succ := range tg.Successors(core.GonumNode(id)) {
+				succs[succ.ID()] = true
+			}
+			for other := -1; other <= 9; other++ {
+				node, succ := core.GonumNode(id), core.GonumNode(other)
+				if tg.IsSuccessor(node, succ) != succs[other] || tg.IsPredecessor(node, succ) != succs[other] || tg.IsAdjacent(node, succ) != succs[other] {
+					t.Errorf("Diagonal movement %d: IsSuccessor(%d, %d) is %v, want %v", mode, id, other, tg.IsSuccessor(node, succ), succs[other])
+				}
+			}
+		}
+	}
+
+	tg.SetDiagonalMovement(simple.DiagonalNever)
+	if path.IsPath([]core.Node{tg.CoordsToNode(1, 1), tg.CoordsToNode(2, 0)}, tg) {
+		t.Error("A diagonal step is a path without diagonal movement")
+	}
+	if path.IsPath([]core.Node{tg.CoordsToNode(2, 0), tg.CoordsToNode(1, 2)}, tg) {
+		t.Error("A jump across the map is a path")
+	}
+	if !path.IsPath([]core.Node{tg.CoordsToNode(2, 0), tg.CoordsToNode(2, 1), tg.CoordsToNode(2, 2), tg.CoordsToNode(0, 0)}, tg) {
+		t.Error("A walk through a portal isn't a path")
+	}
+}
+
+func TestTileGraphNeighbor(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("▀  \n   \n ▀▀")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	center := tg.CoordsToNode(1, 1)
+	for _, test := range []struct {
+		dir simple.Direction
+		id  int
+		ok  bool
+	}{{simple.North, 1, true}, {simple.South, -1, false}, {simple.West, 3, true}, {simple.East, 5, true}} {
+		neighbor, ok := tg.Neighbor(center, test.dir)
+		if ok != test.ok || (ok && neighbor.ID() != test.id) || (!ok && neighbor != nil) {
+			t.Errorf("%v of the center is %v, %v; want %d, %v", test.dir, neighbor, ok, test.id, test.ok)
+		}
+		if ok {
+			if dir, _ := tg.DirectionTo(neighbor, center); dir != test.dir.Opposite() {
+				t.Errorf("Direction back from %v is %v, want %v", test.dir, dir, test.dir.Opposite())
+			}
+		}
+	}
+
+	if _, ok := tg.Neighbor(tg.CoordsToNode(0, 2), simple.East); ok {
+		t.Error("Moved off the edge of the graph")
+	}
+	if _, ok := tg.Neighbor(tg.CoordsToNode(0, 0), simple.East); ok {
+		t.Error("Moved out of a wall")
+	}
+	if _, ok := tg.DirectionTo(tg.CoordsToNode(0, 2), tg.CoordsToNode(1, 0)); ok {
+		t.Error("Wrapping around a row counts as adjacent")
+	}
+	if simple.East.String() != "East" {
+		t.Errorf("Got %v", simple.East)
+	}
+}
+
+func TestLineOfSight(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("" +
+		"     \n" +
+		"  ▀  \n" +
+		"     ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !tg.LineOfSight(tg.CoordsToNode(0, 0), tg.CoordsToNode(0, 4)) || !tg.LineOfSight(tg.CoordsToNode(0, 0), tg.CoordsToNode(1, 1)) {
+		t.Error("Open lines are blocked")
+	}
+	if tg.LineOfSight(tg.CoordsToNode(0, 0), tg.CoordsToNode(2, 4)) {
+		t.Error("Line through the wall is clear")
+	}
+	if last, clear := tg.Raycast(tg.CoordsToNode(1, 4), tg.CoordsToNode(1, 0)); clear || last.ID() != tg.CoordsToID(1, 3) {
+		t.Errorf("Ray stopped at %v, clear %v; want stopped at 8", last, clear)
+	}
+	if last, clear := tg.Raycast(tg.CoordsToNode(1, 2), tg.CoordsToNode(0, 0)); clear || last != nil {
+		t.Errorf("Ray from a wall got to %v", last)
+	}
+
+	corner, err := simple.GenerateTileGraph(" ▀\n▀ ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corner.LineOfSight(core.GonumNode(0), core.GonumNode(3)) || corner.LineOfSight(core.GonumNode(3), core.GonumNode(0)) {
+		t.Error("Sight squeezes between walls meeting at a corner")
+	}
+
+	// Lines must be the same both ways round
+	for a := 0; a < 15; a++ {
+		for b := 0; b < 15; b++ {
+			if tg.NodeExists(core.GonumNode(a)) && tg.NodeExists(core.GonumNode(b)) && tg.LineOfSight(core.GonumNode(a), core.GonumNode(b)) != tg.LineOfSight(core.GonumNode(b), core.GonumNode(a)) {
+				t.Errorf("Line of sight between %d and %d isn't symmetric", a, b)
+			}
+		}
+	}
+}
+
+func TestDistanceField(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("" +
+		"▀      \n" +
+		"       \n" +
+		"       \n" +
+		"       \n" +
+		"      ▀")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field := tg.DistanceField()
+	rows, cols := tg.Dimensions()
+	// Compare with brute force over the walls and the ring of tiles just outside the graph
+	for id, got := range field {
+		r, c := tg.IDToCoords(id)
+		want := math.Inf(1)
+		for wr := -1; wr <= rows; wr++ {
+			for wc := -1; wc <= cols; wc++ {
+				if wr >= 0 && wr < rows && wc >= 0 && wc < cols && tg.NodeExists(tg.CoordsToNode(wr, wc)) {
+					continue
+				}
+				want = math.Min(want, math.Hypot(float64(r-wr), float64(c-wc)))
+			}
+		}
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("Tile (%d, %d) is %f from a wall, want %f", r, c, got, want)
+		}
+	}
+	if field[0] != 0 || field[tg.CoordsToID(2, 3)] != 3 {
+		t.Errorf("Got %f for the wall and %f for the center", field[0], field[tg.CoordsToID(2, 3)])
+	}
+}
+
+func TestClearanceGraphs(t *testing.T) {
+	// A room on the left, joined to one on the right by a 1-tile gap (row 1) and a 2-tile gap (rows 4 and 5)
+	tg, err := simple.GenerateTileGraph("" +
+		"   ▀   \n" +
+		"       \n" +
+		"   ▀   \n" +
+		"   ▀   \n" +
+		"       \n" +
+		"       \n" +
+		"   ▀   ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetCost(5, 5, 3)
+
+	start, goal := tg.CoordsToNode(0, 0), tg.CoordsToNode(0, 5)
+	if _, cost, _ := path.AStar(start, goal, tg, nil, nil); cost != 7 {
+		t.Errorf("Small agent's path costs %f, want 7 through the narrow gap", cost)
+	}
+
+	big := tg.FootprintGraph(2)
+	route, cost, _ := path.AStar(start, goal, big, nil, nil)
+	if route == nil || cost != 13 {
+		t.Fatalf("2x2 agent's path is %v costing %f, want one through the wide gap costing 13\n%s", route, cost, big.PathString(route))
+	}
+	for _, node := range route {
+		r, c := big.IDToCoords(node.ID())
+		for _, tile := range [][2]int{{r, c}, {r + 1, c}, {r, c + 1}, {r + 1, c + 1}} {
+			if !tg.NodeExists(tg.CoordsToNode(tile[0], tile[1])) {
+				t.Errorf("2x2 agent at (%d, %d) overlaps the wall at %v", r, c, tile)
+			}
+		}
+	}
+	if big.Cost(core.GonumNode(0), tg.CoordsToNode(5, 5)) != 3 || tg.FootprintGraph(4).NodeExists(start) {
+		t.Error("Footprint graph lost the costs, or a 4x4 agent fits over a wall")
+	}
+
+	// A round agent of radius 1 needs every tile around it clear, so neither gap is wide enough
+	if route, _, _ := path.AStar(tg.CoordsToNode(2, 1), tg.CoordsToNode(2, 5), tg.ClearanceGraph(1), nil, nil); route != nil {
+		t.Errorf("Radius 1 agent found path\n%s", tg.PathString(route))
+	}
+	if route, _, _ := path.AStar(start, goal, tg.ClearanceGraph(0.5), nil, nil); route == nil {
+		t.Error("Radius 0.5 agent can't find a path")
+	}
+	if tg.ClearanceGraph(1).NodeExists(tg.CoordsToNode(1, 3)) || !tg.NodeExists(tg.CoordsToNode(1, 3)) {
+		t.Error("Clearance graph isn't an independent copy")
+	}
+}
+
+func TestTileGraphObstacles(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("" +
+		"   \n" +
+		" ▀ \n" +
+		"   ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, goal := tg.CoordsToNode(1, 0), tg.CoordsToNode(1, 2)
+
+	tg.Block(0, 1)
+	if !tg.IsBlocked(0, 1) || tg.NodeExists(tg.CoordsToNode(0, 1)) || len(tg.NodeList()) != 7 {
+		t.Fatal("Blocked tile is still passable")
+	}
+	if tg.String() != "   \n ▀ \n   " {
+		t.Errorf("Obstacle changed the map:\n%s", tg.String())
+	}
+	if route, _, _ := path.AStar(start, goal, tg, nil, nil); len(route) != 5 || route[2].ID() != tg.CoordsToID(2, 1) {
+		t.Errorf("Got path %v, want the one along the bottom", route)
+	}
+
+	tg.Block(2, 1)
+	tg.Block(7, 7)
+	if route, _, _ := path.AStar(start, goal, tg, nil, nil); route != nil {
+		t.Errorf("Got path %v through the obstacles", route)
+	}
+	if tg.LineOfSight(tg.CoordsToNode(0, 0), tg.CoordsToNode(0, 2)) {
+		t.Error("Sight passes through an obstacle")
+	}
+
+	tg.Unblock(2, 1)
+	if route, _, _ := path.AStar(start, goal, tg, nil, nil); len(route) != 5 {
+		t.Errorf("Got path %v after unblocking", route)
+	}
+	tg.ClearObstacles()
+	if tg.IsBlocked(0, 1) || len(tg.NodeList()) != 8 {
+		t.Error("Obstacles weren't cleared")
+	}
+}
+
+func TestTileGraphHeuristic(t *testing.T) {
+	tg := simple.NewTileGraph(10, 10, true)
+	start, goal := tg.CoordsToNode(0, 0), tg.CoordsToNode(9, 9)
+	_, cost, expanded := path.AStar(start, goal, tg, nil, nil)
+	_, want, dijkstraExpanded := path.DijkstraPath(start, goal, tg, nil)
+	if cost != want || expanded >= dijkstraExpanded {
+		t.Errorf("AStar found cost %v expanding %d nodes, Dijkstra found %v expanding %d", cost, expanded, want, dijkstraExpanded)
+	}
+
+	// Costs below 1 and a portal both make paths cheaper than the plain Manhattan distance, and the heuristic has to allow for them
+	tg, _, _, err := simple.ParseTileGraph(""+
+		"  2  #  \n"+
+		" ### # 9\n"+
+		"  3    9\n"+
+		"#### ## \n"+
+		"        ", simple.ASCIITileAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetCost(4, 3, 0.5)
+	tg.AddPortal(tg.CoordsToNode(0, 0), tg.CoordsToNode(4, 7))
+	for _, from := range tg.NodeList() {
+		costs := path.DijkstraCosts(from, tg, nil)
+		for _, to := range tg.NodeList() {
+			h := tg.HeuristicCost(from, to)
+			if h > costs[to.ID()] {
+				t.Errorf("Heuristic from %d to %d is %v, more than the cost %v", from.ID(), to.ID(), h, costs[to.ID()])
+			}
+			for _, succ := range tg.Successors(from) {
+				if next := tg.HeuristicCost(succ, to); h > tg.Cost(from, succ)+next {
+					t.Errorf("Heuristic to %d isn't consistent between %d (%v) and %d (%v)", to.ID(), from.ID(), h, succ.ID(), next)
+				}
+			}
+		}
+	}
+
+	tg.SetCost(4, 3, -1)
+	if h := tg.HeuristicCost(tg.CoordsToNode(0, 0), tg.CoordsToNode(0, 4)); h != 0 {
+		t.Errorf("Heuristic with a negative cost is %v, want 0", h)
+	}
+	tg.SetCost(4, 3, 1)
+	if h := tg.HeuristicCost(tg.CoordsToNode(0, 0), tg.CoordsToNode(0, 4)); h != 4 {
+		t.Errorf("Heuristic after restoring the cost is %v, want 4", h)
+	}
+}
+
+func TestParseTileGraph(t *testing.T) {
+	tg, start, goal, err := simple.ParseTileGraph(""+
+		"#####\r\n"+
+		"#S.9#\r\n"+
+		"#.#.#\r\n"+
+		"#..G#\r\n"+
+		"#####\r\n", simple.ASCIITileAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start == nil || start.ID() != 6 || goal == nil || goal.ID() != 18 {
+		t.Fatalf("Got start %v and goal %v, want 6 and 18", start, goal)
+	}
+	if tg.String() != "▀▀▀▀▀\n▀   ▀\n▀ ▀ ▀\n▀   ▀\n▀▀▀▀▀" {
+		t.Errorf("Parsed graph is\n%s", tg.String())
+	}
+	if tg.Cost(core.GonumNode(7), core.GonumNode(8)) != 9 || tg.Cost(core.GonumNode(8), core.GonumNode(7)) != 1 {
+		t.Error("Cost isn't the cost of entering the tile")
+	}
+
+	// The expensive tile makes the way round the bottom cheaper
+	route, cost, _ := path.AStar(start, goal, tg, nil, nil)
+	if cost != 4 || len(route) != 5 || route[2].ID() != 16 {
+		t.Errorf("Got path %v costing %v, want the one through 16 costing 4", route, cost)
+	}
+
+	tg.SetCost(1, 3, 1)
+	if tg.Cost(core.GonumNode(7), core.GonumNode(8)) != 1 {
+		t.Error("SetCost didn't change the cost")
+	}
+
+	for _, bad := range []string{"", "\n\n", "#S#S", "#G\nG#", "#x", "#\n##", "\xff"} {
+		if _, _, _, err := simple.ParseTileGraph(bad, simple.ASCIITileAlphabet); err == nil {
+			t.Errorf("Template %q parsed without error", bad)
+		}
+	}
+
+	// Markers are just unrecognized characters in an alphabet without them
+	if _, _, _, err := simple.ParseTileGraph("S", simple.DefaultTileAlphabet); err == nil {
+		t.Error("Default alphabet accepts a start marker")
+	}
+	if _, start, goal, err := simple.ParseTileGraph("▀ ", simple.DefaultTileAlphabet); err != nil || start != nil || goal != nil {
+		t.Errorf("Got start %v, goal %v and error %v from an unmarked template", start, goal, err)
+	}
+}
+
+func TestOverlayString(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("▀   \n▀ ▀ \n    ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	route := []core.Node{core.GonumNode(1), core.GonumNode(5), core.GonumNode(9), core.GonumNode(10)}
+	if got := tg.PathString(route); got != "▀s  \n▀♥▀ \n ♥g " {
+		t.Errorf("Got path string\n%s", got)
+	}
+
+	visited := simple.TileOverlay{Nodes: []core.Node{core.GonumNode(0), core.GonumNode(1), core.GonumNode(2), core.GonumNode(5)}, Glyph: '.'}
+	frontier := simple.TileOverlay{Nodes: []core.Node{core.GonumNode(3), core.GonumNode(9), core.GonumNode(99)}, Glyph: 'o', Color: "33"}
+	current := simple.TileOverlay{Nodes: []core.Node{core.GonumNode(2)}, Glyph: '@', Color: "1;31"}
+	if got := tg.OverlayString(visited, frontier, current); got != "▀.\x1b[1;31m@\x1b[0m\x1b[33mo\x1b[0m\n▀.▀ \n \x1b[33mo\x1b[0m  " {
+		t.Errorf("Got overlay string %q", got)
+	}
+
+	if simple.NewTileGraph(0, 0, true).OverlayString(visited) != "" {
+		t.Error("Empty graph isn't drawn as an empty string")
+	}
+}
+
+func TestTileGraphHeuristicKinds(t *testing.T) {
+	tg, _, _, err := simple.ParseTileGraph(""+
+		"  2  #  \n"+
+		" ### # 9\n"+
+		"  3    9\n"+
+		"#### ## \n"+
+		"        ", simple.ASCIITileAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.AddPortal(tg.CoordsToNode(0, 0), tg.CoordsToNode(4, 7))
+	kinds := []simple.TileHeuristic{simple.HeuristicManhattan, simple.HeuristicChebyshev, simple.HeuristicEuclidean, simple.HeuristicOctile}
+
+	for _, diagonal := range []simple.DiagonalMovement{simple.DiagonalNever, simple.DiagonalAlways} {
+		tg.SetDiagonalMovement(diagonal)
+		overestimated := false
+		for _, kind := range kinds {
+			h := tg.Heuristic(kind)
+			admissible := diagonal == simple.DiagonalNever || kind != simple.HeuristicManhattan
+			for _, from := range tg.NodeList() {
+				costs := path.DijkstraCosts(from, tg, nil)
+				for _, to := range tg.NodeList() {
+					if h(from, to) > costs[to.ID()]+1e-9 {
+						overestimated = true
+						if admissible {
+							t.Errorf("%v with diagonal movement %v: Heuristic from %d to %d is %v, more than the cost %v", kind, diagonal, from.ID(), to.ID(), h(from, to), costs[to.ID()])
+						}
+					}
+				}
+				if _, cost, _ := path.AStar(from, tg.CoordsToNode(4, 0), tg, nil, h); admissible && math.Abs(cost-costs[tg.CoordsToID(4, 0)]) > 1e-9 {
+					t.Errorf("%v with diagonal movement %v: AStar from %d cost %v", kind, diagonal, from.ID(), cost)
+				}
+			}
+		}
+		if overestimated != (diagonal != simple.DiagonalNever) {
+			t.Errorf("With diagonal movement %v Manhattan overestimated: %v", diagonal, overestimated)
+		}
+	}
+
+	// The tighter the bound, the fewer nodes AStar expands
+	open := simple.NewTileGraph(30, 30, true)
+	open.SetDiagonalMovement(simple.DiagonalAlways)
+	start, goal := open.CoordsToNode(0, 0), open.CoordsToNode(29, 15)
+	_, _, octile := path.AStar(start, goal, open, nil, open.Heuristic(simple.HeuristicOctile))
+	_, _, chebyshev := path.AStar(start, goal, open, nil, open.Heuristic(simple.HeuristicChebyshev))
+	if octile >= chebyshev || open.Heuristic(simple.HeuristicOctile)(start, goal) != open.HeuristicCost(start, goal) {
+		t.Errorf("Octile expanded %d nodes, Chebyshev %d", octile, chebyshev)
+	}
+}
+
+func TestTileGraphDiagonal(t *testing.T) {
+	tg := simple.NewTileGraph(10, 10, true)
+	tg.SetDiagonalMovement(simple.DiagonalNoWalls)
+	start, goal := tg.CoordsToNode(0, 0), tg.CoordsToNode(9, 9)
+	route, cost, _ := path.AStar(start, goal, tg, nil, nil)
+	if len(route) != 10 || math.Abs(cost-9*math.Sqrt2) > 1e-9 || math.Abs(tg.HeuristicCost(start, goal)-cost) > 1e-9 {
+		t.Errorf("Got path %v costing %v, want the diagonal", route, cost)
+	}
+	if n := len(tg.Successors(tg.CoordsToNode(5, 5))); n != 8 {
+		t.Errorf("Open tile has %d successors, want 8", n)
+	}
+
+	// From the middle tile, NorthWest squeezes between two walls and NorthEast cuts the corner of one
+	tg, _, _, err := simple.ParseTileGraph(" # \n#  \n   ", simple.ASCIITileAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := tg.CoordsToNode(1, 1)
+	for _, test := range []struct {
+		mode          simple.DiagonalMovement
+		squeeze, edge bool
+	}{
+		{simple.DiagonalNever, false, false},
+		{simple.DiagonalAlways, true, true},
+		{simple.DiagonalAtMostOneWall, false, true},
+		{simple.DiagonalNoWalls, false, false},
+	} {
+		tg.SetDiagonalMovement(test.mode)
+		if _, ok := tg.Neighbor(from, simple.NorthWest); ok != test.squeeze {
+			t.Errorf("%v: Squeezing between two walls is %v, want %v", test.mode, ok, test.squeeze)
+		}
+		if _, ok := tg.Neighbor(from, simple.NorthEast); ok != test.edge {
+			t.Errorf("%v: Cutting the corner of one wall is %v, want %v", test.mode, ok, test.edge)
+		}
+		if _, ok := tg.Neighbor(from, simple.SouthEast); ok != (test.mode != simple.DiagonalNever) {
+			t.Errorf("%v: Moving diagonally across open ground is %v", test.mode, ok)
+		}
+	}
+
+	// With walls and costs, AStar with the octile heuristic still finds the cheapest paths
+	tg, _, _, err = simple.ParseTileGraph(""+
+		"  2  #  \n"+
+		" ### # 9\n"+
+		"  3    9\n"+
+		"#### ## \n"+
+		"        ", simple.ASCIITileAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetDiagonalMovement(simple.DiagonalAtMostOneWall)
+	for _, from := range tg.NodeList() {
+		costs := path.DijkstraCosts(from, tg, nil)
+		for _, to := range tg.NodeList() {
+			if h := tg.HeuristicCost(from, to); h > costs[to.ID()]+1e-9 {
+				t.Errorf("Heuristic from %d to %d is %v, more than the cost %v", from.ID(), to.ID(), h, costs[to.ID()])
+			}
+			if _, cost, _ := path.AStar(from, to, tg, nil, nil); math.Abs(cost-costs[to.ID()]) > 1e-9 && !math.IsInf(costs[to.ID()], 1) {
+				t.Errorf("AStar from %d to %d cost %v, Dijkstra %v", from.ID(), to.ID(), cost, costs[to.ID()])
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	tg.Save(&buf)
+	loaded, err := simple.LoadTileGraph(&buf)
+	if err != nil || loaded.DiagonalMovement() != simple.DiagonalAtMostOneWall {
+		t.Errorf("Loaded diagonal movement %v with error %v", loaded.DiagonalMovement(), err)
+	}
+	if _, err := simple.LoadTileGraph(strings.NewReader("tilegraph 1 1\n.\ndiagonal Sometimes\n")); err == nil {
+		t.Error("Loaded an unknown diagonal movement without error")
+	}
+}
+
+func TestTileGraphTerrain(t *testing.T) {
+	// A swamp between the start and goal, with a road around it
+	tg, err := simple.GenerateTileGraphWithCosts(""+
+		"=====\n"+
+		"=~~~=\n"+
+		" ~~~ ", map[rune]float64{'~': 3, '=': 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tg.TileCost(1, 1) != 3 || tg.TileCost(0, 0) != 0.5 || tg.TileCost(2, 0) != 1 || tg.TileCost(5, 5) != 0 {
+		t.Errorf("Got tile costs %v, %v and %v", tg.TileCost(1, 1), tg.TileCost(0, 0), tg.TileCost(2, 0))
+	}
+
+	start, goal := tg.CoordsToNode(2, 0), tg.CoordsToNode(2, 4)
+	route, cost, _ := path.AStar(start, goal, tg, nil, nil)
+	if len(route) != 9 || cost != 4.5 {
+		t.Errorf("Got path %v costing %v, want the road costing 4.5", route, cost)
+	}
+
+	// Averaged, leaving the swamp costs as much as entering it, and the cost is the same both ways
+	swamp, road := tg.CoordsToNode(1, 1), tg.CoordsToNode(0, 1)
+	tg.SetCostModel(simple.TileCostAverage)
+	if tg.Cost(swamp, road) != 1.75 || tg.Cost(road, swamp) != 1.75 {
+		t.Errorf("Moving between swamp and road costs %v and %v, want 1.75", tg.Cost(swamp, road), tg.Cost(road, swamp))
+	}
+	route, cost, _ = path.AStar(start, goal, tg, nil, nil)
+	if _, want, _ := path.DijkstraPath(start, goal, tg, nil); cost != want || path.PathCost(route, tg, nil) != cost {
+		t.Errorf("Got path %v costing %v, Dijkstra found %v", route, cost, want)
+	}
+
+	var buf bytes.Buffer
+	tg.Save(&buf)
+	loaded, err := simple.LoadTileGraph(&buf)
+	if err != nil || loaded.CostModel() != simple.TileCostAverage || loaded.Cost(swamp, road) != 1.75 {
+		t.Errorf("Loaded cost model %v with error %v", loaded.CostModel(), err)
+	}
+	if _, err := simple.LoadTileGraph(strings.NewReader("tilegraph 1 1\n.\ncostmodel Maximum\n")); err == nil {
+		t.Error("Loaded an unknown cost model without error")
+	}
+	if _, err := simple.GenerateTileGraphWithCosts("~?", map[rune]float64{'~': 3}); err == nil {
+		t.Error("Generated a graph with a character outside the legend")
+	}
+}
+
+func TestTileGraphSaveLoad(t *testing.T) {
+	tg, err := simple.GenerateTileGraph("▀   \n▀ ▀ \n    ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tg.SetCost(2, 0, 2.5)
+	tg.AddPortal(tg.CoordsToNode(2, 3), tg.CoordsToNode(0, 1))
+	tg.AddPortal(tg.CoordsToNode(0, 1), tg.CoordsToNode(2, 3))
+	tg.AddPortal(tg.CoordsToNode(0, 1), tg.CoordsToNode(0, 2)) // Already adjacent
+	if !tg.IsAdjacent(core.GonumNode(1), core.GonumNode(11)) || len(tg.Successors(core.GonumNode(11))) != 3 {
+		t.Fatalf("Portal not added, successors of 11 are %v", tg.Successors(core.GonumNode(11)))
+	}
+
+	var buf bytes.Buffer
+	if err := tg.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "tilegraph 3 4\n#...\n#.#.\n....\ncost 2 0 2.5\nportal 0 1 2 3\n"
+	if buf.String() != want {
+		t.Fatalf("Saved\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	loaded, err := simple.LoadTileGraph(strings.NewReader(strings.Replace(want, "\n", "\r\n", -1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := encodingtest.Diff(tg, loaded); diff != "" {
+		t.Errorf("Loaded graph differs:\n%s", diff)
+	}
+	if route, cost, _ := path.AStar(core.GonumNode(1), core.GonumNode(11), loaded, nil, nil); len(route) != 2 || cost != 1 {
+		t.Errorf("Got path %v costing %v, want the portal", route, cost)
+	}
+
+	for _, bad := range []string{
+		"",
+		"tilegraph 1\n.",
+		"tilegraph 2 2\n..\n",
+		"tilegraph 1 2\n.x\n",
+		"tilegraph 1 2\n...\n",
+		"tilegraph 1 2\n..\ncost 0 2 1\n",
+		"tilegraph 1 2\n..\ncost 0 1 cheap\n",
+		"tilegraph 1 2\n..\nportal 0 0 -1 0\n",
+		"tilegraph 1 2\n..\nteleporter 0 0 0 1\n",
+		"tilegraph 99999999999 1\n.\n",
+	} {
+		if _, err := simple.LoadTileGraph(strings.NewReader(bad)); err == nil {
+			t.Errorf("Loaded %q without error", bad)
+		}
+	}
+}
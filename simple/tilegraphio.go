@@ -0,0 +1,217 @@
+package simple
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Writes the graph in a line-based text format that's meant to be checked in and diffed. The first line is "tilegraph <rows> <cols>", followed by one line per row with '#' for walls
+// and '.' for floors (rather than String's glyphs, so that editors don't strip trailing floors as whitespace). Then come "diagonal <mode>" if the graph allows diagonal moves, with
+// the mode as DiagonalMovement.String gives it, "costmodel <model>" if the cost model isn't TileCostEnter, "cost <row> <col> <cost>" for every tile that doesn't cost 1, and
+// "portal <row> <col> <row> <col>" for every portal, each in order of position. For example:
+//
+//	tilegraph 2 3
+//	#..
+//	...
+//	diagonal NoWalls
+//	costmodel Average
+//	cost 1 2 2.5
+//	portal 0 1 1 2
+//
+// The output is the same for the same graph, however it was built. Read it back with LoadTileGraph.
+func (graph *TileGraph) Save(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "tilegraph %d %d\n", graph.numRows, graph.numCols)
+
+	row := make([]byte, graph.numCols)
+	for r := 0; r < graph.numRows; r++ {
+		for c := range row {
+			if graph.tiles[r*graph.numCols+c] {
+				row[c] = '.'
+			} else {
+				row[c] = '#'
+			}
+		}
+		buf.Write(row)
+		buf.WriteByte('\n')
+	}
+
+	if graph.diagonal != DiagonalNever {
+		fmt.Fprintf(buf, "diagonal %v\n", graph.diagonal)
+	}
+	if graph.costModel != TileCostEnter {
+		fmt.Fprintf(buf, "costmodel %v\n", graph.costModel)
+	}
+	for id, cost := range graph.costs {
+		if cost != 1 {
+			r, c := graph.IDToCoords(id)
+			fmt.Fprintf(buf, "cost %d %d %s\n", r, c, strconv.FormatFloat(cost, 'g', -1, 64))
+		}
+	}
+
+	portals := make(graphutil.EdgeKeySorter, 0)
+	for from, tos := range graph.portals {
+		for _, to := range tos {
+			if from < to {
+				portals = append(portals, core.EdgeKey{Head: from, Tail: to})
+			}
+		}
+	}
+	sort.Sort(portals)
+	for _, portal := range portals {
+		fromRow, fromCol := graph.IDToCoords(portal.Head)
+		toRow, toCol := graph.IDToCoords(portal.Tail)
+		fmt.Fprintf(buf, "portal %d %d %d %d\n", fromRow, fromCol, toRow, toCol)
+	}
+
+	return buf.Flush()
+}
+
+// Reads a graph written by Save. Windows line endings are accepted. Returns an error, with the line number, for anything that doesn't match the format: a bad header, the wrong number
+// or length of rows, unknown lines, diagonal modes or cost models, or costs and portals with coordinates outside the graph. The input may come from an untrusted source; no more is
+// allocated than the input's size justifies.
+func LoadTileGraph(r io.Reader) (*TileGraph, error) {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	next := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		line++
+		return strings.TrimSuffix(scanner.Text(), "\r"), true
+	}
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("Line %d: %s", line, fmt.Sprintf(format, args...))
+	}
+
+	header, ok := next()
+	if !ok {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("Empty input, expected a tilegraph header")
+	}
+	fields := strings.Fields(header)
+	if len(fields) != 3 || fields[0] != "tilegraph" {
+		return nil, fail("Expected \"tilegraph <rows> <cols>\", got %q", header)
+	}
+	rows, rowErr := strconv.Atoi(fields[1])
+	cols, colErr := strconv.Atoi(fields[2])
+	if rowErr != nil || colErr != nil || rows < 0 || cols < 0 || (rows == 0) != (cols == 0) {
+		return nil, fail("Bad dimensions %q", header)
+	}
+
+	// The tiles are appended row by row rather than allocated up front, so a header claiming a huge graph can't exhaust memory
+	tiles := make([]bool, 0)
+	for r := 0; r < rows; r++ {
+		text, ok := next()
+		if !ok {
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fail("Expected %d rows, got %d", rows, r)
+		}
+		if len(text) != cols {
+			return nil, fail("Row %d has %d tiles, want %d", r, len(text), cols)
+		}
+		for i := 0; i < len(text); i++ {
+			switch text[i] {
+			case '#':
+				tiles = append(tiles, false)
+			case '.':
+				tiles = append(tiles, true)
+			default:
+				return nil, fail("Unrecognized tile %q", text[i])
+			}
+		}
+	}
+
+	graph := &TileGraph{
+		tiles:   tiles,
+		nodes:   tileNodes(len(tiles)),
+		numRows: rows,
+		numCols: cols,
+	}
+
+	for {
+		text, ok := next()
+		if !ok {
+			break
+		}
+
+		fields := strings.Fields(text)
+		switch {
+		case len(fields) == 0:
+			continue
+		case fields[0] == "diagonal" && len(fields) == 2:
+			mode := DiagonalNever
+			for mode <= DiagonalNoWalls && mode.String() != fields[1] {
+				mode++
+			}
+			if mode > DiagonalNoWalls {
+				return nil, fail("Unknown diagonal movement %q", fields[1])
+			}
+			graph.SetDiagonalMovement(mode)
+		case fields[0] == "costmodel" && len(fields) == 2:
+			model := TileCostEnter
+			for model <= TileCostAverage && model.String() != fields[1] {
+				model++
+			}
+			if model > TileCostAverage {
+				return nil, fail("Unknown cost model %q", fields[1])
+			}
+			graph.SetCostModel(model)
+		case fields[0] == "cost" && len(fields) == 4:
+			r, c, err := graph.parseCoords(fields[1], fields[2])
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			cost, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil {
+				return nil, fail("Bad cost %q", fields[3])
+			}
+			graph.SetCost(r, c, cost)
+		case fields[0] == "portal" && len(fields) == 5:
+			fromRow, fromCol, err := graph.parseCoords(fields[1], fields[2])
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			toRow, toCol, err := graph.parseCoords(fields[3], fields[4])
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			graph.AddPortal(graph.nodes[graph.CoordsToID(fromRow, fromCol)], graph.nodes[graph.CoordsToID(toRow, toCol)])
+		default:
+			return nil, fail("Unrecognized line %q", text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// Parses a row and column, which must be inside the graph
+func (graph *TileGraph) parseCoords(row, col string) (int, int, error) {
+	r, err := strconv.Atoi(row)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Bad row %q", row)
+	}
+	c, err := strconv.Atoi(col)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Bad column %q", col)
+	}
+	if graph.CoordsToID(r, c) == -1 {
+		return 0, 0, fmt.Errorf("Tile (%d, %d) is outside the %dx%d graph", r, c, graph.numRows, graph.numCols)
+	}
+
+	return r, c, nil
+}
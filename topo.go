@@ -0,0 +1,240 @@
+package graph
+
+import (
+	"github.com/nathankerr/graph/set"
+	"github.com/nathankerr/graph/topo"
+	"math/rand"
+)
+
+// The types of package topo, under the names they had before the package was split
+type (
+	Linkage             = topo.Linkage
+	Dendrogram          = topo.Dendrogram
+	ClusteringWeighting = topo.ClusteringWeighting
+	CycleError          = topo.CycleError
+	EdgeReplacement     = topo.EdgeReplacement
+	Report              = topo.Report
+	Reservoir           = topo.Reservoir
+	WeightedReservoir   = topo.WeightedReservoir
+	EdgeStream          = topo.EdgeStream
+	EdgeFile            = topo.EdgeFile
+	GraphEdgeStream     = topo.GraphEdgeStream
+	Visitor             = topo.Visitor
+	VertexProgram       = topo.VertexProgram
+	Vertex              = topo.Vertex
+)
+
+// The constants of package topo, under the names they had before the package was split
+const (
+	SingleLinkage        = topo.SingleLinkage
+	CompleteLinkage      = topo.CompleteLinkage
+	AverageLinkage       = topo.AverageLinkage
+	UnweightedClustering = topo.UnweightedClustering
+	BarratClustering     = topo.BarratClustering
+	OnnelaClustering     = topo.OnnelaClustering
+)
+
+// See topo.SpectralRadius
+func SpectralRadius(graph Graph, Cost func(Node, Node) float64) float64 {
+	return topo.SpectralRadius(graph, Cost)
+}
+
+// See topo.KatzCentrality
+func KatzCentrality(graph Graph, alpha, beta float64, Cost func(Node, Node) float64) map[int]float64 {
+	return topo.KatzCentrality(graph, alpha, beta, Cost)
+}
+
+// See topo.SubgraphCentrality
+func SubgraphCentrality(graph Graph, Cost func(Node, Node) float64) map[int]float64 {
+	return topo.SubgraphCentrality(graph, Cost)
+}
+
+// See topo.Communicability
+func Communicability(graph Graph, Cost func(Node, Node) float64) map[int]map[int]float64 {
+	return topo.Communicability(graph, Cost)
+}
+
+// See topo.CurrentFlowBetweenness
+func CurrentFlowBetweenness(graph Graph, Cost func(Node, Node) float64) map[int]float64 {
+	return topo.CurrentFlowBetweenness(graph, Cost)
+}
+
+// See topo.CurrentFlowCloseness
+func CurrentFlowCloseness(graph Graph, Cost func(Node, Node) float64) map[int]float64 {
+	return topo.CurrentFlowCloseness(graph, Cost)
+}
+
+// See topo.EffectiveResistance
+func EffectiveResistance(graph Graph, u, v Node, Cost func(Node, Node) float64) float64 {
+	return topo.EffectiveResistance(graph, u, v, Cost)
+}
+
+// See topo.ResistanceDistances
+func ResistanceDistances(graph Graph, Cost func(Node, Node) float64) map[int]map[int]float64 {
+	return topo.ResistanceDistances(graph, Cost)
+}
+
+// See topo.HarmonicLabels
+func HarmonicLabels(graph Graph, labels map[int]int, Cost func(Node, Node) float64, maxIterations int) map[int]map[int]float64 {
+	return topo.HarmonicLabels(graph, labels, Cost, maxIterations)
+}
+
+// See topo.SpectralClustering
+func SpectralClustering(graph Graph, k int, Cost func(Node, Node) float64) [][]Node {
+	return topo.SpectralClustering(graph, k, Cost)
+}
+
+// See topo.Agglomerate
+func Agglomerate(nodes []Node, distance func(Node, Node) float64, linkage Linkage) *Dendrogram {
+	return topo.Agglomerate(nodes, distance, linkage)
+}
+
+// See topo.HierarchicalClustering
+func HierarchicalClustering(graph Graph, Cost func(Node, Node) float64, linkage Linkage) *Dendrogram {
+	return topo.HierarchicalClustering(graph, Cost, linkage)
+}
+
+// See topo.ClusteringCoefficients
+func ClusteringCoefficients(graph Graph, Cost func(Node, Node) float64, weighting ClusteringWeighting) map[int]float64 {
+	return topo.ClusteringCoefficients(graph, Cost, weighting)
+}
+
+// See topo.GirvanNewman
+func GirvanNewman(graph Graph, Cost func(Node, Node) float64) *Dendrogram {
+	return topo.GirvanNewman(graph, Cost)
+}
+
+// See topo.Tarjan
+func Tarjan(graph Graph) (sccs [][]Node) {
+	return topo.Tarjan(graph)
+}
+
+// See topo.TopologicalSort
+func TopologicalSort(graph Graph) ([]Node, error) {
+	return topo.TopologicalSort(graph)
+}
+
+// See topo.Prim
+func Prim(graph Graph, Cost func(Node, Node) float64) (forest []Edge, weight float64) {
+	return topo.Prim(graph, Cost)
+}
+
+// See topo.Kruskal
+func Kruskal(graph Graph, Cost func(Node, Node) float64) (forest []Edge, weight float64) {
+	return topo.Kruskal(graph, Cost)
+}
+
+// See topo.MSTSensitivity
+func MSTSensitivity(graph Graph, Cost func(Node, Node) float64) (tree []WeightedEdge, replacements []EdgeReplacement) {
+	return topo.MSTSensitivity(graph, Cost)
+}
+
+// See topo.Dominators
+func Dominators(start Node, graph Graph) map[int]*set.Set {
+	return topo.Dominators(start, graph)
+}
+
+// See topo.PostDominators
+func PostDominators(end Node, graph Graph) map[int]*set.Set {
+	return topo.PostDominators(end, graph)
+}
+
+// See topo.ParallelBFS
+func ParallelBFS(source Node, graph Graph, workers int) map[int]int {
+	return topo.ParallelBFS(source, graph, workers)
+}
+
+// See topo.ParallelConnectedComponents
+func ParallelConnectedComponents(graph Graph, workers int) [][]Node {
+	return topo.ParallelConnectedComponents(graph, workers)
+}
+
+// See topo.NewReport
+func NewReport(graph Graph, Cost func(Node, Node) float64) *Report {
+	return topo.NewReport(graph, Cost)
+}
+
+// See topo.NewReservoir
+func NewReservoir(k int, rnd *rand.Rand) *Reservoir {
+	return topo.NewReservoir(k, rnd)
+}
+
+// See topo.NewWeightedReservoir
+func NewWeightedReservoir(k int, rnd *rand.Rand) *WeightedReservoir {
+	return topo.NewWeightedReservoir(k, rnd)
+}
+
+// See topo.SampleNodes
+func SampleNodes(graph Graph, k int, rnd *rand.Rand) []Node {
+	return topo.SampleNodes(graph, k, rnd)
+}
+
+// See topo.SampleEdges
+func SampleEdges(graph Graph, k int, weight func(Edge) float64, rnd *rand.Rand) []Edge {
+	return topo.SampleEdges(graph, k, weight, rnd)
+}
+
+// See topo.StreamSampleEdges
+func StreamSampleEdges(stream EdgeStream, k int, weight func(head, tail int) float64, rnd *rand.Rand) ([]EdgeKey, error) {
+	return topo.StreamSampleEdges(stream, k, weight, rnd)
+}
+
+// See topo.StreamDegrees
+func StreamDegrees(stream EdgeStream) (outDegrees, inDegrees map[int]int, err error) {
+	return topo.StreamDegrees(stream)
+}
+
+// See topo.StreamConnectedComponents
+func StreamConnectedComponents(stream EdgeStream) (map[int]int, error) {
+	return topo.StreamConnectedComponents(stream)
+}
+
+// See topo.StreamPageRank
+func StreamPageRank(stream EdgeStream, damping float64, iterations int) (map[int]float64, error) {
+	return topo.StreamPageRank(stream, damping, iterations)
+}
+
+// See topo.BFS
+func BFS(start Node, graph Graph, visit func(Node) bool) Node {
+	return topo.BFS(start, graph, visit)
+}
+
+// See topo.DFS
+func DFS(start Node, graph Graph, visit func(Node) bool) Node {
+	return topo.DFS(start, graph, visit)
+}
+
+// See topo.BreadthFirst
+func BreadthFirst(start Node, graph Graph, visitor *Visitor) Node {
+	return topo.BreadthFirst(start, graph, visitor)
+}
+
+// See topo.DepthFirst
+func DepthFirst(start Node, graph Graph, visitor *Visitor) Node {
+	return topo.DepthFirst(start, graph, visitor)
+}
+
+// See topo.BFSTree
+func BFSTree(start Node, graph Graph) *GonumGraph {
+	return topo.BFSTree(start, graph)
+}
+
+// See topo.DFSTree
+func DFSTree(start Node, graph Graph) *GonumGraph {
+	return topo.DFSTree(start, graph)
+}
+
+// See topo.RunVertexProgram
+func RunVertexProgram(graph Graph, program VertexProgram, iterations int) map[int]interface{} {
+	return topo.RunVertexProgram(graph, program, iterations)
+}
+
+// See topo.PageRank
+func PageRank(graph Graph, damping float64, iterations int) map[int]float64 {
+	return topo.PageRank(graph, damping, iterations)
+}
+
+// See topo.LabelPropagation
+func LabelPropagation(graph Graph, iterations int) map[int]int {
+	return topo.LabelPropagation(graph, iterations)
+}
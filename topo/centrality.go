@@ -0,0 +1,486 @@
+package topo
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/internal/linalg"
+	"math"
+)
+
+// Builds a directed adjacency list over the node indices, where the weight of each edge is its affinity, 1/Cost. Undirected graphs list each edge in both directions.
+func weightedAdjacency(graph core.Graph, nodes []core.Node, indices map[int]int, Cost func(core.Node, core.Node) float64) (targets [][]int, weights [][]float64) {
+	targets = make([][]int, len(nodes))
+	weights = make([][]float64, len(nodes))
+	for i, node := range nodes {
+		for _, succ := range graph.Successors(node) {
+			if j, ok := indices[succ.ID()]; ok {
+				targets[i] = append(targets[i], j)
+				weights[i] = append(weights[i], 1/Cost(node, succ))
+			}
+		}
+	}
+
+	return targets, weights
+}
+
+// Estimates the spectral radius (the largest absolute eigenvalue) of the graph's weighted adjacency matrix, where the weight of an edge is its affinity, 1/Cost. The spectral radius bounds how
+// fast walks multiply as they get longer, so it's what decides which attenuation factors KatzCentrality converges for.
+//
+// The estimate comes from power iteration on A+I, which has the same dominant eigenvector as the adjacency matrix A (the weights are never negative) but converges even for periodic graphs
+// such as bipartite ones. It's accurate to about 1e-9 for most graphs, though it converges slowly if the two largest eigenvalues are close.
+// Returns 0 for a graph without cycles, since its walks can't get longer than the graph itself.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func SpectralRadius(graph core.Graph, Cost func(core.Node, core.Node) float64) float64 {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	nodes, indices := graphutil.IndexNodes(graph)
+	targets, weights := weightedAdjacency(graph, nodes, indices, Cost)
+
+	// Without any cycles there are no arbitrarily long walks, so the adjacency matrix is nilpotent. Power iteration would only creep towards 0, so check for that directly (Kahn's algorithm).
+	inDegrees := make([]int, len(nodes))
+	for i := range targets {
+		for _, j := range targets[i] {
+			inDegrees[j]++
+		}
+	}
+	sources := make([]int, 0, len(nodes))
+	for i, degree := range inDegrees {
+		if degree == 0 {
+			sources = append(sources, i)
+		}
+	}
+	for k := 0; k < len(sources); k++ {
+		for _, j := range targets[sources[k]] {
+			if inDegrees[j]--; inDegrees[j] == 0 {
+				sources = append(sources, j)
+			}
+		}
+	}
+	if len(sources) == len(nodes) {
+		return 0
+	}
+
+	x := make([]float64, len(nodes))
+	next := make([]float64, len(nodes))
+	for i := range x {
+		x[i] = 1 / math.Sqrt(float64(len(x)))
+	}
+
+	radius := 0.0
+	for iteration := 0; iteration < 10000; iteration++ {
+		copy(next, x)
+		for i := range targets {
+			for k, j := range targets[i] {
+				next[j] += weights[i][k] * x[i]
+			}
+		}
+
+		norm := 0.0
+		for _, v := range next {
+			norm += v * v
+		}
+		norm = math.Sqrt(norm)
+		for i := range next {
+			next[i] /= norm
+		}
+		x, next = next, x
+
+		// x is a unit vector, so norm is the eigenvalue of A+I once it's converged
+		if math.Abs(norm-1-radius) < 1e-12*math.Max(1, radius) {
+			radius = norm - 1
+			break
+		}
+		radius = norm - 1
+	}
+
+	return math.Max(radius, 0)
+}
+
+// KatzCentrality scores each node by the number of walks that end at it, with walks of length k discounted by alpha^k, plus a baseline of beta that every node gets. This is the solution to
+// x = alpha * A^T x + beta, where the weight of each edge in A is its affinity, 1/Cost; in a directed graph the walks follow the edges, so a node's centrality comes from its predecessors.
+// Unlike eigenvector centrality, nodes outside strongly connected cores (including those with no incoming edges at all) still get meaningful scores.
+//
+// The sum only converges if alpha is less than 1/SpectralRadius(graph, Cost). If alpha <= 0, 0.9 times that bound is used, which weighs long walks heavily while staying safely convergent;
+// smaller values make the result closer to (in-)degree centrality. Returns nil if the given alpha is too large to converge. The scores are not normalized, and nodes are keyed by ID.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func KatzCentrality(graph core.Graph, alpha, beta float64, Cost func(core.Node, core.Node) float64) map[int]float64 {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	nodes, indices := graphutil.IndexNodes(graph)
+	targets, weights := weightedAdjacency(graph, nodes, indices, Cost)
+
+	radius := SpectralRadius(graph, Cost)
+	if alpha <= 0 {
+		if radius == 0 {
+			alpha = 1 // Every walk is finite, so any alpha converges
+		} else {
+			alpha = .9 / radius
+		}
+	} else if alpha*radius >= 1 {
+		return nil
+	}
+
+	x := make([]float64, len(nodes))
+	next := make([]float64, len(nodes))
+	for iteration := 0; iteration < 10000; iteration++ {
+		for i := range next {
+			next[i] = beta
+		}
+		for i := range targets {
+			for k, j := range targets[i] {
+				next[j] += alpha * weights[i][k] * x[i]
+			}
+		}
+
+		change, total := 0.0, 0.0
+		for i := range x {
+			change += math.Abs(next[i] - x[i])
+			total += math.Abs(next[i])
+		}
+		x, next = next, x
+		if change <= 1e-12*total {
+			break
+		}
+	}
+
+	centrality := make(map[int]float64, len(nodes))
+	for i, node := range nodes {
+		centrality[node.ID()] = x[i]
+	}
+
+	return centrality
+}
+
+// Computes the matrix exponential e^A of the graph's symmetric affinity matrix from its eigendecomposition, e^A = V e^Λ V^T. Entry (p, q) weighs every walk from p to q by 1/k! for its
+// length k.
+func affinityExponential(graph core.Graph, Cost func(core.Node, core.Node) float64) (nodes []core.Node, exp [][]float64) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	nodes, indices := graphutil.IndexNodes(graph)
+	neighbors, affinities := undirectedAffinities(graph, nodes, indices, Cost)
+
+	a := linalg.NewMatrix(len(nodes), len(nodes))
+	for i := range neighbors {
+		for k, j := range neighbors[i] {
+			a[i][j] = affinities[i][k]
+		}
+	}
+	values, vectors := linalg.SymmetricEigen(a)
+
+	exp = linalg.NewMatrix(len(nodes), len(nodes))
+	for p := range exp {
+		for q := p; q < len(exp); q++ {
+			sum := 0.0
+			for k, value := range values {
+				sum += vectors[p][k] * vectors[q][k] * math.Exp(value)
+			}
+			exp[p][q], exp[q][p] = sum, sum
+		}
+	}
+
+	return nodes, exp
+}
+
+// Returns the subgraph centrality of Estrada and Rodríguez-Velázquez for every node, keyed by ID: the number of closed walks starting and ending at the node, with walks of length k
+// discounted by 1/k!, i.e. the diagonal of the matrix exponential of the adjacency matrix. Short cycles count the most, so nodes that sit in many small, tight subgraphs score highest.
+//
+// As in HarmonicLabels, the weight of an edge is its affinity, 1/Cost, and directed graphs are treated as undirected, with the affinities of the two directions summed. The
+// eigendecomposition is dense, so this is only suitable for graphs of up to a few thousand nodes.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func SubgraphCentrality(graph core.Graph, Cost func(core.Node, core.Node) float64) map[int]float64 {
+	nodes, exp := affinityExponential(graph, Cost)
+
+	centrality := make(map[int]float64, len(nodes))
+	for i, node := range nodes {
+		centrality[node.ID()] = exp[i][i]
+	}
+
+	return centrality
+}
+
+// Returns the communicability of Estrada and Hatano between every pair of nodes, keyed by their IDs: the number of walks between them, with walks of length k discounted by 1/k!, i.e. the
+// off-diagonal entries of the matrix exponential of the adjacency matrix (the diagonal, a node's communicability with itself, is its SubgraphCentrality). Unlike the shortest path
+// distance, it rewards pairs that are joined by many alternative routes. Pairs in different components have a communicability of 0.
+//
+// As in HarmonicLabels, the weight of an edge is its affinity, 1/Cost, and directed graphs are treated as undirected, with the affinities of the two directions summed. The
+// eigendecomposition is dense, so this is only suitable for graphs of up to a few thousand nodes.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func Communicability(graph core.Graph, Cost func(core.Node, core.Node) float64) map[int]map[int]float64 {
+	nodes, exp := affinityExponential(graph, Cost)
+
+	communicability := make(map[int]map[int]float64, len(nodes))
+	for p, node := range nodes {
+		communicability[node.ID()] = make(map[int]float64, len(nodes))
+		for q, other := range nodes {
+			communicability[node.ID()][other.ID()] = exp[p][q]
+		}
+	}
+
+	return communicability
+}
+
+// The electrical network view of a graph used by the current-flow measures: every edge is a resistor whose resistance is its Cost (so its conductance is its affinity). Holds the nodes
+// sorted by ID, the undirected adjacency with conductances, the component each node is in, and the Moore-Penrose pseudo-inverse of the weighted Laplacian. The pseudo-inverse is
+// computed from the Laplacian's eigendecomposition by inverting every nonzero eigenvalue; its null space is spanned by the indicator vectors of the components, so it works for each
+// component separately.
+type resistorNetwork struct {
+	nodes       []core.Node
+	neighbors   [][]int
+	conductance [][]float64
+	component   []int
+	pinv        [][]float64
+}
+
+func newResistorNetwork(graph core.Graph, Cost func(core.Node, core.Node) float64) *resistorNetwork {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	nodes, indices := graphutil.IndexNodes(graph)
+	neighbors, conductance := undirectedAffinities(graph, nodes, indices, Cost)
+
+	component := make([]int, len(nodes))
+	for i := range component {
+		component[i] = -1
+	}
+	for i := range nodes {
+		if component[i] != -1 {
+			continue
+		}
+		component[i] = i
+		queue := []int{i}
+		for k := 0; k < len(queue); k++ {
+			for _, j := range neighbors[queue[k]] {
+				if component[j] == -1 {
+					component[j] = i
+					queue = append(queue, j)
+				}
+			}
+		}
+	}
+
+	laplacian := linalg.NewMatrix(len(nodes), len(nodes))
+	for i := range neighbors {
+		for k, j := range neighbors[i] {
+			laplacian[i][j] -= conductance[i][k]
+			laplacian[i][i] += conductance[i][k]
+		}
+	}
+	values, vectors := linalg.SymmetricEigen(laplacian)
+
+	// Eigenvalues this small relative to the largest are the components' zero eigenvalues, up to rounding
+	largest := 0.0
+	if len(values) > 0 {
+		largest = values[len(values)-1]
+	}
+	pinv := linalg.NewMatrix(len(nodes), len(nodes))
+	for k, value := range values {
+		if value <= 1e-10*largest {
+			continue
+		}
+		for p := range pinv {
+			for q := p; q < len(pinv); q++ {
+				pinv[p][q] += vectors[p][k] * vectors[q][k] / value
+			}
+		}
+	}
+	for p := range pinv {
+		for q := 0; q < p; q++ {
+			pinv[p][q] = pinv[q][p]
+		}
+	}
+
+	return &resistorNetwork{nodes, neighbors, conductance, component, pinv}
+}
+
+// Returns the effective resistance between the nodes with indices u and v, or +Inf if they aren't connected
+func (network *resistorNetwork) resistance(u, v int) float64 {
+	if network.component[u] != network.component[v] {
+		return math.Inf(1)
+	}
+
+	return network.pinv[u][u] + network.pinv[v][v] - 2*network.pinv[u][v]
+}
+
+// Returns the current-flow betweenness of Newman (also called random-walk betweenness) for every node, keyed by ID. Shortest path betweenness only counts the paths that are exactly
+// shortest, so a node on a route that's only slightly longer gets nothing; instead, this treats the graph as an electrical network where every edge is a resistor (with resistance Cost),
+// and measures how much current flows through each node when a unit current is sent between every pair of nodes. Equivalently, it's the net number of times a random walk from one node
+// to the other passes through the node, averaged over all such walks.
+//
+// As with shortest path betweenness, a pair contributes nothing to its own endpoints, and only pairs in the same component are counted, so a tree gives the same result as shortest path
+// betweenness. The values are not normalized. Directed graphs are treated as undirected, with the conductances (affinities) of the two directions summed.
+//
+// This takes O(n^3 + n^2 m) time for the Laplacian's dense pseudo-inverse and the flows over every pair, so it's only suitable for graphs of up to a few thousand nodes.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func CurrentFlowBetweenness(graph core.Graph, Cost func(core.Node, core.Node) float64) map[int]float64 {
+	network := newResistorNetwork(graph, Cost)
+	n := len(network.nodes)
+
+	betweenness := make([]float64, n)
+	potentials := make([]float64, n)
+	for s := 0; s < n; s++ {
+		for t := s + 1; t < n; t++ {
+			if network.component[s] != network.component[t] {
+				continue
+			}
+
+			// The potentials for a unit current in at s and out at t are L+(e_s - e_t)
+			for i := range potentials {
+				potentials[i] = network.pinv[i][s] - network.pinv[i][t]
+			}
+
+			// Every unit of current through a node enters it and then leaves it, so half the absolute current over its edges is the throughput
+			for i := range potentials {
+				if i == s || i == t || network.component[i] != network.component[s] {
+					continue
+				}
+				current := 0.0
+				for k, j := range network.neighbors[i] {
+					current += network.conductance[i][k] * math.Abs(potentials[i]-potentials[j])
+				}
+				betweenness[i] += current / 2
+			}
+		}
+	}
+
+	centrality := make(map[int]float64, n)
+	for i, node := range network.nodes {
+		centrality[node.ID()] = betweenness[i]
+	}
+
+	return centrality
+}
+
+// Returns the current-flow closeness (also called information centrality) of every node, keyed by ID. This is the closeness centrality that comes from measuring distances by effective
+// resistance rather than by shortest path: the number of other nodes in the node's component divided by the sum of the effective resistances to them. Treating every edge as a resistor
+// (with resistance Cost), a node that's joined to the rest of the graph by many parallel paths is closer than one that has a single, equally short path. Isolated nodes have a closeness
+// of 0. Directed graphs are treated as undirected, with the conductances (affinities) of the two directions summed.
+//
+// The Laplacian's pseudo-inverse is dense, so this is only suitable for graphs of up to a few thousand nodes.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func CurrentFlowCloseness(graph core.Graph, Cost func(core.Node, core.Node) float64) map[int]float64 {
+	network := newResistorNetwork(graph, Cost)
+
+	closeness := make(map[int]float64, len(network.nodes))
+	for u, node := range network.nodes {
+		total, others := 0.0, 0
+		for v := range network.nodes {
+			if v != u && network.component[u] == network.component[v] {
+				total += network.resistance(u, v)
+				others++
+			}
+		}
+
+		if others == 0 {
+			closeness[node.ID()] = 0
+		} else {
+			closeness[node.ID()] = float64(others) / total
+		}
+	}
+
+	return closeness
+}
+
+// Returns the effective resistance between u and v: treating every edge as a resistor with resistance Cost, the resistance measured between u and v when a current is passed between
+// them. It's a distance that accounts for every path rather than just the shortest, so it drops as more independent routes join the two nodes, and it's a common measure of how robustly
+// they're connected. Adding up the resistances over all the edges (R(u, v) times the edge's conductance) gives the number of nodes minus the number of components, a fact used by
+// spectral sparsifiers. Returns 0 if u and v are the same node, and +Inf if they aren't connected or either isn't in the graph. Directed graphs are treated as undirected, with the
+// conductances (affinities) of the two directions summed.
+//
+// This solves the Laplacian system L x = e_u - e_v over u's component with the conjugate gradient method, which only needs the edges, so it's suitable for large sparse graphs. To get the
+// resistances between many pairs of a small graph, ResistanceDistances is faster.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func EffectiveResistance(graph core.Graph, u, v core.Node, Cost func(core.Node, core.Node) float64) float64 {
+	if !graph.NodeExists(u) || !graph.NodeExists(v) {
+		return math.Inf(1)
+	} else if u.ID() == v.ID() {
+		return 0
+	}
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	// Index u's component, which is all the system needs
+	nodes := []core.Node{u}
+	indices := map[int]int{u.ID(): 0}
+	for k := 0; k < len(nodes); k++ {
+		neighbors := graph.Successors(nodes[k])
+		if graph.IsDirected() {
+			neighbors = append(neighbors, graph.Predecessors(nodes[k])...)
+		}
+		for _, neighbor := range neighbors {
+			if _, ok := indices[neighbor.ID()]; !ok {
+				indices[neighbor.ID()] = len(nodes)
+				nodes = append(nodes, neighbor)
+			}
+		}
+	}
+	target, ok := indices[v.ID()]
+	if !ok {
+		return math.Inf(1)
+	}
+	neighbors, conductance := undirectedAffinities(graph, nodes, indices, Cost)
+
+	multiply := func(x, out []float64) {
+		for i := range x {
+			out[i] = 0
+			for k, j := range neighbors[i] {
+				out[i] += conductance[i][k] * (x[i] - x[j])
+			}
+		}
+	}
+	dot := func(a, b []float64) float64 {
+		sum := 0.0
+		for i := range a {
+			sum += a[i] * b[i]
+		}
+		return sum
+	}
+
+	n := len(nodes)
+	x := make([]float64, n)
+	r := make([]float64, n)
+	r[0], r[target] = 1, -1
+	p := append([]float64(nil), r...)
+	lp := make([]float64, n)
+	rr := dot(r, r)
+	for iteration := 0; iteration < 10*n && rr > 1e-24; iteration++ {
+		multiply(p, lp)
+		alpha := rr / dot(p, lp)
+		for i := range x {
+			x[i] += alpha * p[i]
+			r[i] -= alpha * lp[i]
+		}
+		next := dot(r, r)
+		for i := range p {
+			p[i] = r[i] + next/rr*p[i]
+		}
+		rr = next
+	}
+
+	return x[0] - x[target]
+}
+
+// Returns the effective resistance (see EffectiveResistance) between every pair of nodes, keyed by their IDs, from the pseudo-inverse of the Laplacian. Pairs in different components are
+// +Inf apart, and every node is 0 from itself. The resistance distance is a metric, and unlike the shortest path distance it shrinks when more paths are added, which makes it a useful
+// similarity for clustering and link prediction.
+//
+// The pseudo-inverse is dense, so this is only suitable for graphs of up to a few thousand nodes.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func ResistanceDistances(graph core.Graph, Cost func(core.Node, core.Node) float64) map[int]map[int]float64 {
+	network := newResistorNetwork(graph, Cost)
+
+	distances := make(map[int]map[int]float64, len(network.nodes))
+	for u, node := range network.nodes {
+		distances[node.ID()] = make(map[int]float64, len(network.nodes))
+		for v, other := range network.nodes {
+			if u == v {
+				distances[node.ID()][other.ID()] = 0
+			} else {
+				distances[node.ID()][other.ID()] = network.resistance(u, v)
+			}
+		}
+	}
+
+	return distances
+}
@@ -0,0 +1,145 @@
+package topo_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"math"
+	"testing"
+)
+
+func TestSpectralRadius(t *testing.T) {
+	// The spectral radius of a complete graph on n nodes is n-1, and of a star with k leaves sqrt(k)
+	complete := simple.NewGonumGraph(false)
+	complete.AddNode(core.GonumNode(0), nodes(1, 2, 3))
+	complete.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	complete.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(3)})
+	complete.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	if radius := topo.SpectralRadius(complete, nil); math.Abs(radius-3) > 1e-6 {
+		t.Errorf("K4 has spectral radius %f, want 3", radius)
+	}
+
+	star := simple.NewGonumGraph(false)
+	star.AddNode(core.GonumNode(0), nodes(1, 2, 3, 4))
+	if radius := topo.SpectralRadius(star, nil); math.Abs(radius-2) > 1e-6 {
+		t.Errorf("Star with 4 leaves has spectral radius %f, want 2", radius)
+	}
+
+	dag := simple.NewGonumGraph(true)
+	dag.AddNode(core.GonumNode(0), nodes(1, 2))
+	dag.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	if radius := topo.SpectralRadius(dag, nil); radius != 0 {
+		t.Errorf("DAG has spectral radius %f, want 0", radius)
+	}
+}
+
+func TestKatzCentrality(t *testing.T) {
+	// In the DAG 0->1->2, 0->2, node 2 is reached by walks 0-2, 1-2 and 0-1-2
+	dag := simple.NewGonumGraph(true)
+	dag.AddNode(core.GonumNode(0), nodes(1, 2))
+	dag.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	katz := topo.KatzCentrality(dag, .5, 1, nil)
+	for id, want := range map[int]float64{0: 1, 1: 1.5, 2: 1 + .5*1 + .5*1.5} {
+		if math.Abs(katz[id]-want) > 1e-9 {
+			t.Errorf("Node %d has Katz centrality %f, want %f", id, katz[id], want)
+		}
+	}
+
+	star := simple.NewGonumGraph(false)
+	star.AddNode(core.GonumNode(0), nodes(1, 2, 3, 4))
+	if topo.KatzCentrality(star, .6, 1, nil) != nil {
+		t.Error("Katz centrality converged with alpha above 1/spectral radius")
+	}
+	katz = topo.KatzCentrality(star, 0, 1, nil)
+	// With alpha = .45, x0 = 1 + 4 alpha x1 and x1 = 1 + alpha x0
+	alpha := .45
+	hub := (1 + 4*alpha) / (1 - 4*alpha*alpha)
+	if math.Abs(katz[0]-hub) > 1e-6 || math.Abs(katz[1]-(1+alpha*hub)) > 1e-6 {
+		t.Errorf("Got Katz centrality %v, want hub %f", katz, hub)
+	}
+}
+
+func TestCommunicability(t *testing.T) {
+	// For a single edge, e^A = [[cosh 1, sinh 1], [sinh 1, cosh 1]]
+	g := pathGraph(2)
+	g.AddNode(core.GonumNode(5), nil)
+
+	subgraph := topo.SubgraphCentrality(g, nil)
+	if math.Abs(subgraph[0]-math.Cosh(1)) > 1e-9 || math.Abs(subgraph[5]-1) > 1e-9 {
+		t.Errorf("Got subgraph centrality %v", subgraph)
+	}
+
+	communicability := topo.Communicability(g, nil)
+	if math.Abs(communicability[0][1]-math.Sinh(1)) > 1e-9 || math.Abs(communicability[1][0]-math.Sinh(1)) > 1e-9 {
+		t.Errorf("Nodes 0 and 1 have communicability %f, want %f", communicability[0][1], math.Sinh(1))
+	}
+	if math.Abs(communicability[0][5]) > 1e-9 {
+		t.Errorf("Disconnected nodes have communicability %f", communicability[0][5])
+	}
+}
+
+func TestCurrentFlowBetweenness(t *testing.T) {
+	// On a path, current can only flow one way, so this is the shortest path betweenness
+	betweenness := topo.CurrentFlowBetweenness(pathGraph(4), nil)
+	for id, want := range map[int]float64{0: 0, 1: 2, 2: 2, 3: 0} {
+		if math.Abs(betweenness[id]-want) > 1e-9 {
+			t.Errorf("Path node %d has betweenness %f, want %f", id, betweenness[id], want)
+		}
+	}
+
+	// In the 4-cycle 0-1-2-3, half the current from 0 to 2 goes through 1; and of the current between neighbors 0 and 3, the quarter that takes the long way round passes 1
+	cycle := pathGraph(4)
+	cycle.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(0)})
+	betweenness = topo.CurrentFlowBetweenness(cycle, nil)
+	for id := 0; id < 4; id++ {
+		if want := .5 + .25 + .25; math.Abs(betweenness[id]-want) > 1e-9 {
+			t.Errorf("Cycle node %d has betweenness %f, want %f", id, betweenness[id], want)
+		}
+	}
+}
+
+func TestCurrentFlowCloseness(t *testing.T) {
+	// Node 0 reaches 1 and 2 over two parallel paths each, in the 4-cycle 0-1-2-3, plus an isolated node 4
+	cycle := pathGraph(4)
+	cycle.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(0)})
+	cycle.AddNode(core.GonumNode(4), nil)
+
+	// The resistances from 0 are 3/4 to its neighbors (1 in parallel with 3) and 1 to the opposite node (2 in parallel with 2)
+	closeness := topo.CurrentFlowCloseness(cycle, nil)
+	if want := 3 / (.75 + .75 + 1); math.Abs(closeness[0]-want) > 1e-9 {
+		t.Errorf("Node 0 has closeness %f, want %f", closeness[0], want)
+	}
+	if closeness[4] != 0 {
+		t.Errorf("Isolated node has closeness %f", closeness[4])
+	}
+}
+
+func TestEffectiveResistance(t *testing.T) {
+	// The 4-cycle 0-1-2-3 with a chord 0-2 of resistance 2, plus an isolated node 4
+	g := pathGraph(4)
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(0)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, 2)
+	g.AddNode(core.GonumNode(4), nil)
+
+	// Between 0 and 2 there are three resistances of 2 in parallel, and between 1 and 3 no current takes the chord, leaving two resistances of 2
+	distances := topo.ResistanceDistances(g, nil)
+	for _, pair := range []struct {
+		u, v int
+		want float64
+	}{
+		{0, 2, 2. / 3},
+		{0, 1, 2. / 3},
+		{1, 3, 1},
+		{1, 1, 0},
+		{0, 4, math.Inf(1)},
+	} {
+		got := topo.EffectiveResistance(g, core.GonumNode(pair.u), core.GonumNode(pair.v), nil)
+		if math.Abs(got-pair.want) > 1e-9 && !(math.IsInf(got, 1) && math.IsInf(pair.want, 1)) {
+			t.Errorf("EffectiveResistance(%d, %d) = %f, want %f", pair.u, pair.v, got, pair.want)
+		}
+		if got := distances[pair.u][pair.v]; math.Abs(got-pair.want) > 1e-9 && !(math.IsInf(got, 1) && math.IsInf(pair.want, 1)) {
+			t.Errorf("ResistanceDistances[%d][%d] = %f, want %f", pair.u, pair.v, got, pair.want)
+		}
+	}
+}
@@ -0,0 +1,768 @@
+package topo
+
+import (
+	"github.com/nathankerr/graph/container"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/internal/linalg"
+	"github.com/nathankerr/graph/path"
+	"math"
+	"sort"
+)
+
+// HarmonicLabels spreads a handful of known labels over the rest of the graph using the harmonic function method of Zhu, Ghahramani and Lafferty (semi-supervised learning with Gaussian fields).
+// The labels map goes from a node's ID to its (known) label. Every unlabeled node ends up with a distribution over all the labels that is the weighted average of its neighbors' distributions, while labeled
+// nodes are clamped to their own label. The result is a map from node ID, to label, to the probability of the node having that label.
+//
+// The affinity between two adjacent nodes is the reciprocal of the cost between them, so cheap edges pull their endpoints' labels together more strongly than expensive ones. Costs must therefore be positive.
+// For a directed graph, edges are treated as if they were undirected (a node is influenced by both its successors and predecessors).
+//
+// The solution is found iteratively, and stops early once no distribution changes by more than a small tolerance. If maxIterations is <= 0, it will iterate until convergence.
+// Nodes that can't reach any labeled node get an empty distribution.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func HarmonicLabels(graph core.Graph, labels map[int]int, Cost func(core.Node, core.Node) float64, maxIterations int) map[int]map[int]float64 {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	nodes := graph.NodeList()
+	indices := make(map[int]int, len(nodes))
+	for i, node := range nodes {
+		indices[node.ID()] = i
+	}
+
+	labelSet := make(map[int]bool)
+	for _, label := range labels {
+		labelSet[label] = true
+	}
+	labelList := make([]int, 0, len(labelSet))
+	for label := range labelSet {
+		labelList = append(labelList, label)
+	}
+	sort.Ints(labelList)
+	labelIndices := make(map[int]int, len(labelList))
+	for i, label := range labelList {
+		labelIndices[label] = i
+	}
+
+	neighbors, affinities := undirectedAffinities(graph, nodes, indices, Cost)
+
+	dist := make([][]float64, len(nodes))
+	for i, node := range nodes {
+		dist[i] = make([]float64, len(labelList))
+		if label, ok := labels[node.ID()]; ok {
+			dist[i][labelIndices[label]] = 1
+		}
+	}
+
+	next := make([][]float64, len(nodes))
+	for i := range next {
+		next[i] = make([]float64, len(labelList))
+	}
+
+	const tolerance = 1e-9
+	for iter := 0; maxIterations <= 0 || iter < maxIterations; iter++ {
+		maxChange := 0.0
+		for i, node := range nodes {
+			copy(next[i], dist[i])
+			if _, ok := labels[node.ID()]; ok {
+				continue
+			}
+
+			total := 0.0
+			for j := range next[i] {
+				next[i][j] = 0
+			}
+			for k, neighbor := range neighbors[i] {
+				w := affinities[i][k]
+				total += w
+				for j, p := range dist[neighbor] {
+					next[i][j] += w * p
+				}
+			}
+			if total == 0 {
+				continue
+			}
+
+			for j := range next[i] {
+				next[i][j] /= total
+				maxChange = math.Max(maxChange, math.Abs(next[i][j]-dist[i][j]))
+			}
+		}
+
+		dist, next = next, dist
+		if maxChange < tolerance {
+			break
+		}
+	}
+
+	result := make(map[int]map[int]float64, len(nodes))
+	for i, node := range nodes {
+		result[node.ID()] = make(map[int]float64, len(labelList))
+		for j, p := range dist[i] {
+			if p > 0 {
+				result[node.ID()][labelList[j]] = p
+			}
+		}
+	}
+
+	return result
+}
+
+// Builds an undirected adjacency list over the node indices, where the affinity of each edge is 1/Cost. For directed graphs both directions of an edge are merged into one, with the
+// affinities summed if both exist.
+func undirectedAffinities(graph core.Graph, nodes []core.Node, indices map[int]int, Cost func(core.Node, core.Node) float64) (neighbors [][]int, affinities [][]float64) {
+	merged := make([]map[int]float64, len(nodes))
+	for i := range merged {
+		merged[i] = make(map[int]float64)
+	}
+
+	for i, node := range nodes {
+		for _, succ := range graph.Successors(node) {
+			j, ok := indices[succ.ID()]
+			if !ok || j == i {
+				continue
+			}
+			w := 1 / Cost(node, succ)
+			if graph.IsDirected() {
+				merged[i][j] += w
+				merged[j][i] += w
+			} else {
+				merged[i][j] = w
+			}
+		}
+	}
+
+	neighbors = make([][]int, len(nodes))
+	affinities = make([][]float64, len(nodes))
+	for i, m := range merged {
+		neighbors[i] = make([]int, 0, len(m))
+		for j := range m {
+			neighbors[i] = append(neighbors[i], j)
+		}
+		sort.Ints(neighbors[i])
+		affinities[i] = make([]float64, len(neighbors[i]))
+		for k, j := range neighbors[i] {
+			affinities[i][k] = m[j]
+		}
+	}
+
+	return neighbors, affinities
+}
+
+// SpectralClustering partitions the graph into (at most) k communities using the normalized spectral clustering algorithm of Ng, Jordan and Weiss. The nodes are embedded
+// into k dimensions using the eigenvectors belonging to the k smallest eigenvalues of the normalized Laplacian, and the embedding is then clustered with k-means.
+//
+// As in HarmonicLabels, the affinity between adjacent nodes is 1/Cost and directed graphs are treated as undirected. The eigendecomposition is dense, so this is only suitable for graphs
+// of up to a few thousand nodes.
+//
+// The communities are returned with their nodes sorted by ID, and the communities themselves are ordered by their lowest ID, so the output is deterministic for a given graph. Fewer than k
+// communities are returned if the graph has fewer than k nodes.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func SpectralClustering(graph core.Graph, k int, Cost func(core.Node, core.Node) float64) [][]core.Node {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	nodes := graph.NodeList()
+	sort.Sort(graphutil.NodeSorter(nodes))
+	if k <= 0 || len(nodes) == 0 {
+		return nil
+	}
+	if k > len(nodes) {
+		k = len(nodes)
+	}
+
+	indices := make(map[int]int, len(nodes))
+	for i, node := range nodes {
+		indices[node.ID()] = i
+	}
+	neighbors, affinities := undirectedAffinities(graph, nodes, indices, Cost)
+
+	invSqrtDegree := make([]float64, len(nodes))
+	for i := range nodes {
+		degree := 0.0
+		for _, w := range affinities[i] {
+			degree += w
+		}
+		if degree > 0 {
+			invSqrtDegree[i] = 1 / math.Sqrt(degree)
+		}
+	}
+
+	laplacian := linalg.NewMatrix(len(nodes), len(nodes))
+	for i := range nodes {
+		if invSqrtDegree[i] > 0 {
+			laplacian[i][i] = 1
+		}
+		for n, j := range neighbors[i] {
+			laplacian[i][j] = -affinities[i][n] * invSqrtDegree[i] * invSqrtDegree[j]
+		}
+	}
+
+	_, vectors := linalg.SymmetricEigen(laplacian)
+
+	points := linalg.NewMatrix(len(nodes), k)
+	for i := range nodes {
+		norm := 0.0
+		for j := 0; j < k; j++ {
+			points[i][j] = vectors[i][j]
+			norm += points[i][j] * points[i][j]
+		}
+		if norm > 0 {
+			norm = math.Sqrt(norm)
+			for j := range points[i] {
+				points[i][j] /= norm
+			}
+		}
+	}
+
+	assignments := kMeans(points, k)
+
+	communities := make([][]core.Node, k)
+	for i, cluster := range assignments {
+		communities[cluster] = append(communities[cluster], nodes[i])
+	}
+
+	// Nodes were visited in ID order, so the communities are internally sorted, and sorting by the first element orders the communities
+	nonEmpty := make([][]core.Node, 0, k)
+	for _, community := range communities {
+		if len(community) != 0 {
+			nonEmpty = append(nonEmpty, community)
+		}
+	}
+	sort.Sort(communitySorter(nonEmpty))
+
+	return nonEmpty
+}
+
+// Lloyd's algorithm, seeded deterministically with a farthest-first traversal starting at the first point. Returns the cluster index for every point.
+func kMeans(points [][]float64, k int) []int {
+	dim := len(points[0])
+	centroids := linalg.NewMatrix(k, dim)
+	copy(centroids[0], points[0])
+
+	distance := func(a, b []float64) float64 {
+		sum := 0.0
+		for i := range a {
+			sum += (a[i] - b[i]) * (a[i] - b[i])
+		}
+		return sum
+	}
+
+	for c := 1; c < k; c++ {
+		farthest, farthestDist := 0, -1.0
+		for i, point := range points {
+			nearest := math.Inf(1)
+			for _, centroid := range centroids[:c] {
+				nearest = math.Min(nearest, distance(point, centroid))
+			}
+			if nearest > farthestDist {
+				farthest, farthestDist = i, nearest
+			}
+		}
+		copy(centroids[c], points[farthest])
+	}
+
+	assignments := make([]int, len(points))
+	for iter := 0; iter < 100; iter++ {
+		changed := false
+		for i, point := range points {
+			best, bestDist := 0, math.Inf(1)
+			for c, centroid := range centroids {
+				if d := distance(point, centroid); d < bestDist {
+					best, bestDist = c, d
+				}
+			}
+			if iter == 0 || assignments[i] != best {
+				changed = true
+				assignments[i] = best
+			}
+		}
+
+		if !changed {
+			break
+		}
+
+		counts := make([]int, k)
+		for c := range centroids {
+			for j := range centroids[c] {
+				centroids[c][j] = 0
+			}
+		}
+		for i, point := range points {
+			counts[assignments[i]]++
+			for j, x := range point {
+				centroids[assignments[i]][j] += x
+			}
+		}
+		for c := range centroids {
+			if counts[c] == 0 {
+				continue
+			}
+			for j := range centroids[c] {
+				centroids[c][j] /= float64(counts[c])
+			}
+		}
+	}
+
+	return assignments
+}
+
+/** Sorts a list of communities by the ID of their first node **/
+
+type communitySorter [][]core.Node
+
+func (cl communitySorter) Len() int {
+	return len(cl)
+}
+
+func (cl communitySorter) Less(i, j int) bool {
+	return cl[i][0].ID() < cl[j][0].ID()
+}
+
+func (cl communitySorter) Swap(i, j int) {
+	cl[i], cl[j] = cl[j], cl[i]
+}
+
+// A Linkage determines how the distance between two clusters is derived from the distances between their members during agglomerative clustering.
+type Linkage int
+
+const (
+	SingleLinkage   Linkage = iota // The distance between the closest pair of members
+	CompleteLinkage                // The distance between the farthest pair of members
+	AverageLinkage                 // The mean distance over all pairs of members (UPGMA)
+)
+
+// A Dendrogram is the binary tree of merges produced by hierarchical clustering. Leaves hold a single Node and have a Distance of 0, every other Dendrogram is the merge of
+// its two Children at the given Distance.
+type Dendrogram struct {
+	Node     core.Node
+	Children [2]*Dendrogram
+	Distance float64
+}
+
+// Returns whether this Dendrogram is a single, unmerged node
+func (d *Dendrogram) IsLeaf() bool {
+	return d.Children[0] == nil
+}
+
+// Returns all the nodes under this point in the dendrogram, sorted by ID
+func (d *Dendrogram) Leaves() []core.Node {
+	leaves := make([]core.Node, 0)
+	var walk func(*Dendrogram)
+	walk = func(d *Dendrogram) {
+		if d.IsLeaf() {
+			leaves = append(leaves, d.Node)
+			return
+		}
+		walk(d.Children[0])
+		walk(d.Children[1])
+	}
+	walk(d)
+	sort.Sort(graphutil.NodeSorter(leaves))
+
+	return leaves
+}
+
+// Cuts the dendrogram at the given distance, returning the communities formed by all the merges at or below that distance. The communities are returned in the same order as SpectralClustering.
+func (d *Dendrogram) Cut(distance float64) [][]core.Node {
+	communities := make([][]core.Node, 0)
+	var walk func(*Dendrogram)
+	walk = func(d *Dendrogram) {
+		if d.IsLeaf() || d.Distance <= distance {
+			communities = append(communities, d.Leaves())
+			return
+		}
+		walk(d.Children[0])
+		walk(d.Children[1])
+	}
+	walk(d)
+	sort.Sort(communitySorter(communities))
+
+	return communities
+}
+
+// Cuts the dendrogram into (at most) k communities by undoing the k-1 highest merges. The communities are returned in the same order as SpectralClustering.
+func (d *Dendrogram) CutK(k int) [][]core.Node {
+	clusters := []*Dendrogram{d}
+	for len(clusters) < k {
+		highest := -1
+		for i, cluster := range clusters {
+			if !cluster.IsLeaf() && (highest == -1 || cluster.Distance > clusters[highest].Distance) {
+				highest = i
+			}
+		}
+		if highest == -1 {
+			break
+		}
+
+		split := clusters[highest]
+		clusters[highest] = split.Children[0]
+		clusters = append(clusters, split.Children[1])
+	}
+
+	communities := make([][]core.Node, len(clusters))
+	for i, cluster := range clusters {
+		communities[i] = cluster.Leaves()
+	}
+	sort.Sort(communitySorter(communities))
+
+	return communities
+}
+
+// Agglomerate performs agglomerative hierarchical clustering on the given nodes using an arbitrary (symmetric) distance function, such as one derived from node similarities.
+// Every node starts in its own cluster, and the two closest clusters (as measured by linkage) are merged until only one remains. Ties are broken in favor of the lowest node IDs, so
+// the result is deterministic.
+//
+// This runs in O(n^3) time over the number of nodes. It returns nil if nodes is empty.
+func Agglomerate(nodes []core.Node, distance func(core.Node, core.Node) float64, linkage Linkage) *Dendrogram {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	sorted := make([]core.Node, len(nodes))
+	copy(sorted, nodes)
+	sort.Sort(graphutil.NodeSorter(sorted))
+
+	clusters := make([]*Dendrogram, len(sorted))
+	sizes := make([]int, len(sorted))
+	dist := linalg.NewMatrix(len(sorted), len(sorted))
+	for i, node := range sorted {
+		clusters[i] = &Dendrogram{Node: node}
+		sizes[i] = 1
+		for j := 0; j < i; j++ {
+			dist[i][j] = distance(sorted[j], node)
+			dist[j][i] = dist[i][j]
+		}
+	}
+
+	for remaining := len(sorted); remaining > 1; remaining-- {
+		a, b := -1, -1
+		for i := range clusters {
+			if clusters[i] == nil {
+				continue
+			}
+			for j := i + 1; j < len(clusters); j++ {
+				if clusters[j] != nil && (a == -1 || dist[i][j] < dist[a][b]) {
+					a, b = i, j
+				}
+			}
+		}
+
+		clusters[a] = &Dendrogram{Children: [2]*Dendrogram{clusters[a], clusters[b]}, Distance: dist[a][b]}
+		clusters[b] = nil
+
+		// Lance-Williams update, the merged cluster takes a's slot
+		for i := range clusters {
+			if clusters[i] == nil || i == a {
+				continue
+			}
+			switch linkage {
+			case SingleLinkage:
+				dist[a][i] = math.Min(dist[a][i], dist[b][i])
+			case CompleteLinkage:
+				dist[a][i] = math.Max(dist[a][i], dist[b][i])
+			case AverageLinkage:
+				dist[a][i] = (float64(sizes[a])*dist[a][i] + float64(sizes[b])*dist[b][i]) / float64(sizes[a]+sizes[b])
+			}
+			dist[i][a] = dist[a][i]
+		}
+		sizes[a] += sizes[b]
+	}
+
+	for _, cluster := range clusters {
+		if cluster != nil {
+			return cluster
+		}
+	}
+
+	return nil
+}
+
+// HierarchicalClustering builds a dendrogram for the graph's nodes using their shortest path distances (as computed by Dijkstra's Algorithm) and the given linkage. In a directed graph the
+// distance between two nodes is the shorter of the two directions. Nodes in different components are infinitely far apart, and are only merged by the final, infinite-distance merges.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func HierarchicalClustering(graph core.Graph, Cost func(core.Node, core.Node) float64, linkage Linkage) *Dendrogram {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	nodes := graph.NodeList()
+	allCosts := make(map[int]map[int]float64, len(nodes))
+	for _, node := range nodes {
+		_, allCosts[node.ID()] = path.Dijkstra(node, graph, Cost)
+	}
+
+	distance := func(a, b core.Node) float64 {
+		d := math.Inf(1)
+		if cost, ok := allCosts[a.ID()][b.ID()]; ok {
+			d = cost
+		}
+		if cost, ok := allCosts[b.ID()][a.ID()]; ok {
+			d = math.Min(d, cost)
+		}
+		return d
+	}
+
+	return Agglomerate(nodes, distance, linkage)
+}
+
+// A ClusteringWeighting selects how ClusteringCoefficients accounts for edge weights.
+type ClusteringWeighting int
+
+const (
+	UnweightedClustering ClusteringWeighting = iota // The fraction of pairs of neighbors that are adjacent themselves, ignoring weights
+	BarratClustering                                // Barrat et al.: each closed pair counts by the mean weight of its two edges to the node, relative to the node's strength
+	OnnelaClustering                                // Onnela et al.: each triangle counts by the geometric mean of its three weights, relative to the graph's heaviest edge
+)
+
+// Returns the local clustering coefficient of every node, keyed by ID: how close the node's neighbors are to forming a clique. For UnweightedClustering this is the usual fraction of pairs
+// of neighbors that are themselves adjacent. The weighted variants reduce to it when all the weights are equal, but let strong ties count for more: Barrat's weighs each closed pair by the
+// node's own edges to it, while Onnela's weighs each triangle by the intensity of all three of its edges. Nodes with fewer than two neighbors have a coefficient of 0.
+//
+// As in HarmonicLabels, the weight of an edge is its affinity, 1/Cost, and directed graphs are treated as undirected, with the affinities of the two directions summed.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func ClusteringCoefficients(graph core.Graph, Cost func(core.Node, core.Node) float64, weighting ClusteringWeighting) map[int]float64 {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	nodes := graphutil.NodeSorter(graph.NodeList())
+	sort.Sort(nodes)
+	indices := make(map[int]int, len(nodes))
+	for i, node := range nodes {
+		indices[node.ID()] = i
+	}
+	neighbors, affinities := undirectedAffinities(graph, nodes, indices, Cost)
+
+	weights := make([]map[int]float64, len(nodes))
+	heaviest := 0.0
+	for i := range nodes {
+		weights[i] = make(map[int]float64, len(neighbors[i]))
+		for k, j := range neighbors[i] {
+			weights[i][j] = affinities[i][k]
+			heaviest = math.Max(heaviest, affinities[i][k])
+		}
+	}
+
+	coefficients := make(map[int]float64, len(nodes))
+	for i, node := range nodes {
+		degree := len(neighbors[i])
+		if degree < 2 {
+			coefficients[node.ID()] = 0
+			continue
+		}
+
+		strength := 0.0
+		for _, a := range affinities[i] {
+			strength += a
+		}
+
+		// Sums over unordered pairs of neighbors; the normalizations below are halved to match
+		sum := 0.0
+		for a, j := range neighbors[i] {
+			for _, k := range neighbors[i][a+1:] {
+				wjk, ok := weights[j][k]
+				if !ok {
+					continue
+				}
+				switch weighting {
+				case UnweightedClustering:
+					sum++
+				case BarratClustering:
+					sum += (weights[i][j] + weights[i][k]) / 2
+				case OnnelaClustering:
+					sum += math.Cbrt(weights[i][j] * weights[i][k] * wjk / (heaviest * heaviest * heaviest))
+				}
+			}
+		}
+
+		pairs := float64(degree*(degree-1)) / 2
+		if weighting == BarratClustering {
+			coefficients[node.ID()] = sum / (strength * float64(degree-1) / 2)
+		} else {
+			coefficients[node.ID()] = sum / pairs
+		}
+	}
+
+	return coefficients
+}
+
+// GirvanNewman builds a dendrogram by divisive clustering: the edge with the highest betweenness (the number of shortest paths passing through it) is removed over and over, since
+// the edges between communities carry most of the traffic between them, until no edges are left. Every time a removal splits a component in two, that split becomes a merge in the
+// dendrogram, whose Distance is the number of edges the graph had just before the split. Cut(m) therefore gives the components of the graph once it's down to m edges, and CutK(k) the
+// first k communities to separate. Components that were never connected are joined by infinite-distance merges, as in HierarchicalClustering.
+//
+// Betweenness is measured along shortest paths by Cost, and only recomputed for the component that lost an edge, but the whole process still takes O(m^2 n log n) time, so it's best
+// suited to graphs of up to a few thousand edges. As in HarmonicLabels, directed graphs are treated as undirected, with the two directions of an edge merged by summing their affinities
+// (1/Cost). Ties between edges are broken in favor of the lowest node IDs, so the result is deterministic. Returns nil for an empty graph.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func GirvanNewman(graph core.Graph, Cost func(core.Node, core.Node) float64) *Dendrogram {
+	Cost = graphutil.DefaultCost(graph, Cost)
+
+	nodes := graphutil.NodeSorter(graph.NodeList())
+	sort.Sort(nodes)
+	if len(nodes) == 0 {
+		return nil
+	}
+	indices := make(map[int]int, len(nodes))
+	for i, node := range nodes {
+		indices[node.ID()] = i
+	}
+
+	neighbors, affinities := undirectedAffinities(graph, nodes, indices, Cost)
+	lengths := make([][]float64, len(nodes))
+	removed := make(map[core.EdgeKey]bool)
+	remaining := 0
+	for i := range neighbors {
+		lengths[i] = make([]float64, len(affinities[i]))
+		for k, a := range affinities[i] {
+			lengths[i][k] = 1 / a
+		}
+		remaining += len(neighbors[i])
+	}
+	remaining /= 2
+
+	// Collects the component containing i over the edges that are left, in index order
+	component := func(i int) []int {
+		seen := map[int]bool{i: true}
+		members := []int{i}
+		for k := 0; k < len(members); k++ {
+			for _, j := range neighbors[members[k]] {
+				if !seen[j] && !removed[graphutil.UndirectedKey(members[k], j)] {
+					seen[j] = true
+					members = append(members, j)
+				}
+			}
+		}
+		sort.Ints(members)
+		return members
+	}
+
+	// Every component is represented by the (so far unfilled) dendrogram node that will hold it
+	clusters := make(map[int]*Dendrogram)
+	members := make(map[*Dendrogram][]int)
+	betweenness := make(map[core.EdgeKey]float64)
+	newCluster := func(component []int) *Dendrogram {
+		if len(component) == 1 {
+			return &Dendrogram{Node: nodes[component[0]]}
+		}
+		cluster := &Dendrogram{}
+		for _, i := range component {
+			clusters[i] = cluster
+		}
+		members[cluster] = component
+		edgeBetweenness(component, neighbors, lengths, removed, betweenness)
+		return cluster
+	}
+
+	roots := make([]*Dendrogram, 0)
+	placed := make([]bool, len(nodes))
+	for i := range nodes {
+		if !placed[i] {
+			c := component(i)
+			for _, j := range c {
+				placed[j] = true
+			}
+			roots = append(roots, newCluster(c))
+		}
+	}
+
+	for remaining > 0 {
+		// Keys are visited in order so that ties go to the lowest IDs
+		keys := make(graphutil.EdgeKeySorter, 0, len(betweenness))
+		for key := range betweenness {
+			keys = append(keys, key)
+		}
+		sort.Sort(keys)
+		best := keys[0]
+		for _, key := range keys[1:] {
+			if betweenness[key] > betweenness[best] {
+				best = key
+			}
+		}
+
+		removed[best] = true
+		remaining--
+		cluster := clusters[best.Head]
+		for _, i := range members[cluster] {
+			for _, j := range neighbors[i] {
+				delete(betweenness, core.EdgeKey{Head: i, Tail: j})
+			}
+		}
+
+		head := component(best.Head)
+		if len(head) == len(members[cluster]) {
+			edgeBetweenness(head, neighbors, lengths, removed, betweenness)
+			continue
+		}
+
+		delete(members, cluster)
+		cluster.Distance = float64(remaining + 1)
+		tail := component(best.Tail)
+		if head[0] > tail[0] {
+			head, tail = tail, head
+		}
+		cluster.Children = [2]*Dendrogram{newCluster(head), newCluster(tail)}
+	}
+
+	root := roots[0]
+	for _, other := range roots[1:] {
+		root = &Dendrogram{Children: [2]*Dendrogram{root, other}, Distance: math.Inf(1)}
+	}
+
+	return root
+}
+
+// Adds the edge betweenness of every remaining edge within the component (given as sorted node indices) to betweenness, keyed by the edge's index pair with the lower index first. This
+// is Brandes' algorithm with Dijkstra's Algorithm for the shortest paths; since every path is found from both of its ends, the values are twice the number of shortest paths through each
+// edge (or fraction thereof), which doesn't matter for comparing them.
+func edgeBetweenness(component []int, neighbors [][]int, lengths [][]float64, removed map[core.EdgeKey]bool, betweenness map[core.EdgeKey]float64) {
+	for _, i := range component {
+		for _, j := range neighbors[i] {
+			if i < j && !removed[core.EdgeKey{Head: i, Tail: j}] {
+				betweenness[core.EdgeKey{Head: i, Tail: j}] = 0
+			}
+		}
+	}
+
+	dist := make(map[int]float64, len(component))
+	sigma := make(map[int]float64, len(component))
+	delta := make(map[int]float64, len(component))
+	preds := make(map[int][]int, len(component))
+	queue := container.NewIndexedHeap()
+	for _, s := range component {
+		for _, i := range component {
+			delete(dist, i)
+			sigma[i], delta[i], preds[i] = 0, 0, preds[i][:0]
+		}
+
+		dist[s], sigma[s] = 0, 1
+		queue.Push(s, 0)
+		order := make([]int, 0, len(component))
+		for !queue.IsEmpty() {
+			v, d := queue.Pop()
+			order = append(order, v)
+			for k, w := range neighbors[v] {
+				if removed[graphutil.UndirectedKey(v, w)] {
+					continue
+				}
+				alt := d + lengths[v][k]
+				if old, ok := dist[w]; !ok || alt < old {
+					dist[w], sigma[w], preds[w] = alt, sigma[v], append(preds[w][:0], v)
+					queue.Push(w, alt)
+				} else if alt == old {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+
+		for k := len(order) - 1; k > 0; k-- {
+			w := order[k]
+			for _, v := range preds[w] {
+				c := sigma[v] / sigma[w] * (1 + delta[w])
+				betweenness[graphutil.UndirectedKey(v, w)] += c
+				delta[v] += c
+			}
+		}
+	}
+}
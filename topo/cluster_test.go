@@ -0,0 +1,155 @@
+package topo_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"math"
+	"testing"
+)
+
+// Builds an undirected path graph 0-1-2-...-(n-1)
+func pathGraph(n int) *simple.GonumGraph {
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nil)
+	for i := 1; i < n; i++ {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(i - 1), T: core.GonumNode(i)})
+	}
+
+	return g
+}
+
+func TestHarmonicLabels(t *testing.T) {
+	g := pathGraph(5)
+
+	dist := topo.HarmonicLabels(g, map[int]int{0: 1, 4: 2}, nil, 0)
+	for id, want := range map[int]float64{0: 1, 1: .75, 2: .5, 3: .25, 4: 0} {
+		if got := dist[id][1]; math.Abs(got-want) > 1e-6 {
+			t.Errorf("Node %d has probability %f of label 1, want %f", id, got, want)
+		}
+		if got := dist[id][2]; math.Abs(got-(1-want)) > 1e-6 {
+			t.Errorf("Node %d has probability %f of label 2, want %f", id, got, 1-want)
+		}
+	}
+
+	g.AddNode(core.GonumNode(10), nil)
+	dist = topo.HarmonicLabels(g, map[int]int{0: 1, 4: 2}, nil, 0)
+	if len(dist[10]) != 0 {
+		t.Error("Isolated node received a label distribution:", dist[10])
+	}
+}
+
+func TestSpectralClustering(t *testing.T) {
+	// Two triangles joined by a single bridge edge between 2 and 3
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1), core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(5)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(5)})
+
+	communities := topo.SpectralClustering(g, 2, nil)
+	if len(communities) != 2 {
+		t.Fatalf("Expected 2 communities, got %d", len(communities))
+	}
+	for c, want := range [][]int{{0, 1, 2}, {3, 4, 5}} {
+		if len(communities[c]) != len(want) {
+			t.Fatalf("Community %d is %v, want %v", c, communities[c], want)
+		}
+		for i, node := range communities[c] {
+			if node.ID() != want[i] {
+				t.Errorf("Community %d is %v, want %v", c, communities[c], want)
+				break
+			}
+		}
+	}
+}
+
+func TestHierarchicalClustering(t *testing.T) {
+	// 0-1-2 with cheap edges, 3-4 with a cheap edge, joined by an expensive 2-3 edge
+	g := pathGraph(5)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(1)}, 1)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)}, 1)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)}, 10)
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)}, 1)
+
+	for _, linkage := range []topo.Linkage{topo.SingleLinkage, topo.CompleteLinkage, topo.AverageLinkage} {
+		dendrogram := topo.HierarchicalClustering(g, nil, linkage)
+		if leaves := dendrogram.Leaves(); len(leaves) != 5 {
+			t.Fatalf("Linkage %d: dendrogram has %d leaves, want 5", linkage, len(leaves))
+		}
+
+		communities := dendrogram.CutK(2)
+		if len(communities) != 2 || len(communities[0]) != 3 || len(communities[1]) != 2 || communities[1][0].ID() != 3 {
+			t.Errorf("Linkage %d: CutK(2) gave %v", linkage, communities)
+		}
+	}
+
+	// Single linkage chains 0-1-2 together at distance 1, complete linkage only merges 2 in at distance 2
+	if communities := topo.HierarchicalClustering(g, nil, topo.SingleLinkage).Cut(1); len(communities) != 2 {
+		t.Errorf("Single linkage Cut(1) gave %v", communities)
+	}
+	if communities := topo.HierarchicalClustering(g, nil, topo.CompleteLinkage).Cut(1); len(communities) != 3 {
+		t.Errorf("Complete linkage Cut(1) gave %v", communities)
+	}
+}
+
+func TestGirvanNewman(t *testing.T) {
+	// Two triangles joined by a bridge between 2 and 3, plus an isolated node 6
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1), core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(5)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(5)})
+	g.AddNode(core.GonumNode(6), nil)
+
+	dendrogram := topo.GirvanNewman(g, nil)
+	if leaves := dendrogram.Leaves(); len(leaves) != 7 {
+		t.Fatalf("Dendrogram has %d leaves, want 7", len(leaves))
+	}
+	if !math.IsInf(dendrogram.Distance, 1) {
+		t.Errorf("Isolated node was merged at distance %f, want +Inf", dendrogram.Distance)
+	}
+
+	// The bridge goes first, leaving the two triangles' 6 edges
+	communities := dendrogram.CutK(3)
+	if len(communities) != 3 || len(communities[0]) != 3 || communities[1][0].ID() != 3 || communities[2][0].ID() != 6 {
+		t.Errorf("CutK(3) gave %v", communities)
+	}
+	if cut := dendrogram.Cut(6); len(cut) != 3 {
+		t.Errorf("Cut(6) gave %v, want the two triangles and node 6", cut)
+	}
+	if cut := dendrogram.Cut(4); len(cut) != 4 {
+		t.Errorf("Cut(4) gave %v, want one triangle split", cut)
+	}
+
+	if topo.GirvanNewman(simple.NewGonumGraph(false), nil) != nil {
+		t.Error("Empty graph gave a dendrogram")
+	}
+}
+
+func TestClusteringCoefficients(t *testing.T) {
+	// A triangle 0-1-2 with a heavy 0-2 edge, and 3 hanging off 2
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1), core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)}, .5)
+
+	onnela := math.Cbrt(.25)
+	for weighting, want := range map[topo.ClusteringWeighting]map[int]float64{
+		topo.UnweightedClustering: {0: 1, 1: 1, 2: 1. / 3, 3: 0},
+		topo.BarratClustering:     {0: 1, 1: 1, 2: .375, 3: 0},
+		topo.OnnelaClustering:     {0: onnela, 1: onnela, 2: onnela / 3, 3: 0},
+	} {
+		coefficients := topo.ClusteringCoefficients(g, nil, weighting)
+		for id, w := range want {
+			if got := coefficients[id]; math.Abs(got-w) > 1e-9 {
+				t.Errorf("Weighting %d: node %d has coefficient %f, want %f", weighting, id, got, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package topo
+
+import (
+	"fmt"
+	"github.com/nathankerr/graph/container"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/set"
+	"github.com/nathankerr/graph/xifo"
+	"math"
+	"sort"
+	"strings"
+)
+
+/* Basic Graph tests */
+
+// Also known as Tarjan's Strongly Connected Components Algorithm. This returns all the strongly connected components in the graph.
+//
+// A strongly connected component of a graph is a set of vertices where it's possible to reach any vertex in the set from any other (meaning there's a cycle between them)
+//
+// Generally speaking, a directed graph where the number of strongly connected components is equal to the number of nodes is acyclic, unless you count reflexive edges as a cycle (which requires only a little extra testing)
+//
+// An undirected graph should end up with as many SCCs as there are "islands" (or subgraphs) of connections, meaning having more than one strongly connected component implies that your graph is not fully connected.
+func Tarjan(graph core.Graph) (sccs [][]core.Node) {
+	index := 0
+	vStack := &xifo.GonumStack{}
+	stackSet := set.NewSet()
+	sccs = make([][]core.Node, 0)
+
+	nodes := graph.NodeList()
+	lowlinks := make(map[int]int, len(nodes))
+	indices := make(map[int]int, len(nodes))
+
+	var strongconnect func(core.Node)
+
+	strongconnect = func(node core.Node) {
+		indices[node.ID()] = index
+		lowlinks[node.ID()] = index
+		index += 1
+
+		vStack.Push(node)
+		stackSet.Add(node.ID())
+
+		for _, succ := range graph.Successors(node) {
+			if _, ok := indices[succ.ID()]; !ok {
+				strongconnect(succ)
+				lowlinks[node.ID()] = int(math.Min(float64(lowlinks[node.ID()]), float64(lowlinks[succ.ID()])))
+			} else if stackSet.Contains(succ.ID()) {
+				lowlinks[node.ID()] = int(math.Min(float64(lowlinks[node.ID()]), float64(lowlinks[succ.ID()])))
+			}
+		}
+
+		if lowlinks[node.ID()] == indices[node.ID()] {
+			scc := make([]core.Node, 0)
+			for {
+				v := vStack.Pop()
+				stackSet.Remove(v.(core.Node).ID())
+				scc = append(scc, v.(core.Node))
+				if v.(core.Node).ID() == node.ID() {
+					// Components are completed deep in the recursion as well as at the top, so collect them here
+					sccs = append(sccs, scc)
+					return
+				}
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		if _, ok := indices[n.ID()]; !ok {
+			strongconnect(n)
+		}
+	}
+
+	return sccs
+}
+
+// A CycleError is returned by TopologicalSort when the graph isn't acyclic. Cycle is one of the graph's cycles, listed in edge order, starting and ending with the same node.
+type CycleError struct {
+	Cycle []core.Node
+}
+
+func (err CycleError) Error() string {
+	ids := make([]string, len(err.Cycle))
+	for i, node := range err.Cycle {
+		ids[i] = fmt.Sprint(node.ID())
+	}
+
+	return "Graph has a cycle: " + strings.Join(ids, " -> ")
+}
+
+// Returns the nodes of a directed acyclic graph in dependency order, meaning every node comes before all of its successors. Ties are broken by node ID, lowest first, so the order is the
+// same on every run no matter what order the graph lists its nodes in. If the graph isn't acyclic it returns nil and a CycleError describing one of the cycles (self loops included).
+//
+// The edges of an undirected graph go both ways, so any undirected graph with an edge has a cycle.
+func TopologicalSort(graph core.Graph) ([]core.Node, error) {
+	nodes := graph.NodeList()
+	inDegrees := make(map[int]int, len(nodes))
+	byID := make(map[int]core.Node, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID()] = node
+		if _, ok := inDegrees[node.ID()]; !ok {
+			inDegrees[node.ID()] = 0
+		}
+		for _, succ := range graph.Successors(node) {
+			inDegrees[succ.ID()]++
+		}
+	}
+
+	// Kahn's algorithm, with a heap to always take the lowest ready ID
+	ready := container.NewIndexedHeap()
+	for id, degree := range inDegrees {
+		if degree == 0 {
+			ready.Push(id, float64(id))
+		}
+	}
+
+	sorted := make([]core.Node, 0, len(nodes))
+	for !ready.IsEmpty() {
+		id, _ := ready.Pop()
+		sorted = append(sorted, byID[id])
+		for _, succ := range graph.Successors(byID[id]) {
+			if inDegrees[succ.ID()]--; inDegrees[succ.ID()] == 0 {
+				ready.Push(succ.ID(), float64(succ.ID()))
+			}
+		}
+	}
+
+	if len(sorted) == len(nodes) {
+		return sorted, nil
+	}
+
+	// Every node left over has a predecessor that's also left over, so walking back through those from any of them must eventually repeat a node
+	remaining := make([]int, 0)
+	for id, degree := range inDegrees {
+		if degree > 0 {
+			remaining = append(remaining, id)
+		}
+	}
+	sort.Ints(remaining)
+
+	walk := make([]core.Node, 0)
+	position := make(map[int]int)
+	node := byID[remaining[0]]
+	for {
+		if i, ok := position[node.ID()]; ok {
+			walk = append(walk[i:], node)
+			break
+		}
+		position[node.ID()] = len(walk)
+		walk = append(walk, node)
+
+		var next core.Node
+		for _, pred := range graph.Predecessors(node) {
+			if inDegrees[pred.ID()] > 0 && (next == nil || pred.ID() < next.ID()) {
+				next = pred
+			}
+		}
+		node = next
+	}
+
+	// The walk went against the edges
+	cycle := make([]core.Node, len(walk))
+	for i, node := range walk {
+		cycle[len(walk)-1-i] = node
+	}
+
+	return nil, CycleError{Cycle: cycle}
+}
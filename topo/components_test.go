@@ -0,0 +1,57 @@
+package topo_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"testing"
+)
+
+func TestTopologicalSort(t *testing.T) {
+	// Added out of order, so that the result has to come from the tie-breaking
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(5), nodes(2, 0))
+	g.AddNode(core.GonumNode(4), nodes(0, 1))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(1)})
+	g.AddNode(core.GonumNode(6), nil)
+
+	sorted, err := topo.TopologicalSort(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{4, 5, 0, 2, 3, 1, 6}
+	if len(sorted) != len(want) {
+		t.Fatalf("Got %v, want %v", sorted, want)
+	}
+	for i, node := range sorted {
+		if node.ID() != want[i] {
+			t.Fatalf("Got %v, want %v", sorted, want)
+		}
+	}
+
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	sorted, err = topo.TopologicalSort(g)
+	cycleErr, ok := err.(topo.CycleError)
+	if sorted != nil || !ok {
+		t.Fatalf("Got %v and error %v from a cyclic graph", sorted, err)
+	}
+	cycle := cycleErr.Cycle
+	if len(cycle) != 4 || cycle[0].ID() != cycle[3].ID() {
+		t.Fatalf("Got cycle %v, want a rotation of 1 2 3 1", cycle)
+	}
+	for i := 0; i < 3; i++ {
+		if !g.IsSuccessor(cycle[i], cycle[i+1]) {
+			t.Errorf("Cycle %v isn't in edge order", cycle)
+		}
+	}
+	if err.Error() != "Graph has a cycle: 1 -> 2 -> 3 -> 1" {
+		t.Errorf("Got message %q", err.Error())
+	}
+
+	loop := simple.NewGonumGraph(true)
+	loop.AddNode(core.GonumNode(0), nodes(0))
+	if _, err := topo.TopologicalSort(loop); err == nil || len(err.(topo.CycleError).Cycle) != 2 {
+		t.Errorf("Got error %v for a self loop", err)
+	}
+}
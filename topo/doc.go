@@ -0,0 +1,15 @@
+// Package topo analyzes the structure of graphs: traversals, components, orderings, spanning trees, dominators, communities and centrality, and the algorithms for graphs too large to
+// handle one node at a time.
+//
+//	traverse.go       breadth and depth first traversals with visitor callbacks, and their trees
+//	components.go     strongly connected components and topological sorting
+//	spanning.go       minimum spanning trees (Prim, Kruskal) and their sensitivity to edge costs
+//	dominators.go     dominators and post-dominators
+//	cluster.go        community detection and clustering
+//	centrality.go     spectral and electrical centrality measures (Katz, communicability, current-flow betweenness and closeness) and effective resistance
+//	report.go         Report, a profile of a graph's size, degrees, components, clustering and costs, rendered as an HTML page
+//	parallel.go       multi-goroutine traversals for large graphs (BFS, connected components)
+//	vertexprogram.go  a Pregel-style runner for iterative vertex programs, and PageRank and label propagation built on it
+//	stream.go         algorithms that read the edges from disk in passes, for graphs too large for memory
+//	sample.go         uniform and weighted reservoir sampling of nodes and edges in one pass, for approximate analytics
+package topo
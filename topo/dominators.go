@@ -0,0 +1,108 @@
+package topo
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/set"
+)
+
+/* Control flow graph stuff */
+
+// A dominates B if and only if the only path through B travels through A
+//
+// # This returns all possible dominators for all nodes, it does not prune for strict dominators, immediate dominators etc
+//
+// The int map[int]*set.Set is the node's ID
+func Dominators(start core.Node, graph core.Graph) map[int]*set.Set {
+	allNodes := set.NewSet()
+	nlist := graph.NodeList()
+	dominators := make(map[int]*set.Set, len(nlist))
+	for _, node := range nlist {
+		allNodes.Add(node.ID())
+	}
+
+	for _, node := range nlist {
+		dominators[node.ID()] = set.NewSet()
+		if node.ID() == start.ID() {
+			dominators[node.ID()].Add(start.ID())
+		} else {
+			dominators[node.ID()].Copy(allNodes)
+		}
+	}
+
+	for somethingChanged := true; somethingChanged; {
+		somethingChanged = false
+		for _, node := range nlist {
+			if node.ID() == start.ID() {
+				continue
+			}
+			preds := graph.Predecessors(node)
+			if len(preds) == 0 {
+				continue
+			}
+			tmp := set.NewSet().Copy(dominators[preds[0].ID()])
+			for _, pred := range preds[1:] {
+				tmp.Intersection(tmp, dominators[pred.ID()])
+			}
+
+			dom := set.NewSet()
+			dom.Add(node.ID())
+
+			dom.Union(dom, tmp)
+			if !set.Equal(dom, dominators[node.ID()]) {
+				dominators[node.ID()] = dom
+				somethingChanged = true
+			}
+		}
+	}
+
+	return dominators
+}
+
+// A Postdominates B if and only if all paths from B travel through A
+//
+// This returns all possible post-dominators for all nodes, it does not prune for strict postdominators, immediate postdominators etc
+func PostDominators(end core.Node, graph core.Graph) map[int]*set.Set {
+	allNodes := set.NewSet()
+	nlist := graph.NodeList()
+	dominators := make(map[int]*set.Set, len(nlist))
+	for _, node := range nlist {
+		allNodes.Add(node.ID())
+	}
+
+	for _, node := range nlist {
+		dominators[node.ID()] = set.NewSet()
+		if node.ID() == end.ID() {
+			dominators[node.ID()].Add(end.ID())
+		} else {
+			dominators[node.ID()].Copy(allNodes)
+		}
+	}
+
+	for somethingChanged := true; somethingChanged; {
+		somethingChanged = false
+		for _, node := range nlist {
+			if node.ID() == end.ID() {
+				continue
+			}
+			succs := graph.Successors(node)
+			if len(succs) == 0 {
+				continue
+			}
+			tmp := set.NewSet().Copy(dominators[succs[0].ID()])
+			for _, succ := range succs[1:] {
+				tmp.Intersection(tmp, dominators[succ.ID()])
+			}
+
+			dom := set.NewSet()
+			dom.Add(node.ID())
+
+			dom.Union(dom, tmp)
+			if !set.Equal(dom, dominators[node.ID()]) {
+				dominators[node.ID()] = dom
+				somethingChanged = true
+			}
+		}
+	}
+
+	return dominators
+}
@@ -0,0 +1,242 @@
+package topo
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"sync/atomic"
+)
+
+/* Parallel traversals. These copy the graph into compact index arrays first, so the graph's read methods (Successors, Predecessors, NodeList) are called from several goroutines
+at once and must be safe for concurrent reads, which is true of all the graphs in package simple as long as nobody mutates them during the call */
+
+// The parameters of the direction-optimizing switch, from Beamer, Asanović and Patterson's "Direction-Optimizing Breadth-First Search". The search goes bottom-up once the frontier's
+// edges exceed 1/bfsAlpha of the edges left unexplored, and back top-down once the frontier shrinks below 1/bfsBeta of the nodes
+const (
+	bfsAlpha = 14
+	bfsBeta  = 24
+)
+
+// Returns the BFS level (the number of edges on a shortest unweighted path) of every node reachable from source, following outbound edges. Nodes that can't be reached aren't in the map.
+//
+// Each level is expanded by up to workers goroutines; workers <= 0 means runtime.GOMAXPROCS(0). The search is direction-optimizing: while the frontier is small it expands the frontier's
+// successors (top-down), and while the frontier is large it instead has every unvisited node look for a parent among its predecessors (bottom-up), which avoids examining most of the
+// edges on large low-diameter graphs. For small graphs the setup costs more than it saves, and a simple serial search will be faster.
+func ParallelBFS(source core.Node, graph core.Graph, workers int) map[int]int {
+	if !graph.NodeExists(source) {
+		return map[int]int{}
+	}
+
+	workers = graphutil.Workers(workers)
+	out, in := newIndexGraph(graph, false, workers)
+
+	levels := make([]int32, len(out.nodes))
+	for i := range levels {
+		levels[i] = -1
+	}
+	out.bfs(in, int32(out.indices[source.ID()]), levels, out.edgeCount(), workers)
+
+	result := make(map[int]int)
+	for i, level := range levels {
+		if level != -1 {
+			result[out.nodes[i].ID()] = int(level)
+		}
+	}
+
+	return result
+}
+
+// Returns the connected components of the graph, treating directed edges as undirected (so for a directed graph these are the weakly connected components). Components are found with the
+// same parallel BFS as ParallelBFS, one component at a time, and are returned in no particular order. workers <= 0 means runtime.GOMAXPROCS(0).
+func ParallelConnectedComponents(graph core.Graph, workers int) [][]core.Node {
+	workers = graphutil.Workers(workers)
+	adj, _ := newIndexGraph(graph, true, workers)
+
+	levels := make([]int32, len(adj.nodes))
+	for i := range levels {
+		levels[i] = -1
+	}
+
+	components := make([][]core.Node, 0)
+	unexplored := adj.edgeCount()
+	for i := range adj.nodes {
+		if levels[i] != -1 {
+			continue
+		}
+
+		component := make([]core.Node, 0)
+		for _, j := range adj.bfs(adj, int32(i), levels, unexplored, workers) {
+			component = append(component, adj.nodes[j])
+			unexplored -= adj.degree(j)
+		}
+		components = append(components, component)
+	}
+
+	return components
+}
+
+// A graph copied into compressed sparse row form: the neighbors of node i are targets[offsets[i]:offsets[i+1]], as indices into nodes
+type indexGraph struct {
+	nodes   []core.Node
+	indices map[int]int
+	offsets []int
+	targets []int32
+}
+
+// Copies graph into an indexGraph of successors and another of predecessors. For an undirected graph they're the same thing, so the predecessor graph is the successor graph. If symmetric
+// is true, both directions are merged into a single neighbor list and returned as both graphs.
+func newIndexGraph(graph core.Graph, symmetric bool, workers int) (out, in *indexGraph) {
+	nodes := graph.NodeList()
+	indices := make(map[int]int, len(nodes))
+	for i, node := range nodes {
+		indices[node.ID()] = i
+	}
+
+	build := func(neighbors func(node core.Node) []core.Node) *indexGraph {
+		lists := make([][]int32, len(nodes))
+		graphutil.ParallelRange(len(nodes), workers, func(_, lo, hi int) {
+			for i := lo; i < hi; i++ {
+				for _, neighbor := range neighbors(nodes[i]) {
+					if j, ok := indices[neighbor.ID()]; ok {
+						lists[i] = append(lists[i], int32(j))
+					}
+				}
+			}
+		})
+
+		ig := &indexGraph{nodes: nodes, indices: indices, offsets: make([]int, len(nodes)+1)}
+		for i, list := range lists {
+			ig.offsets[i+1] = ig.offsets[i] + len(list)
+		}
+		ig.targets = make([]int32, ig.offsets[len(nodes)])
+		for i, list := range lists {
+			copy(ig.targets[ig.offsets[i]:], list)
+		}
+
+		return ig
+	}
+
+	if !graph.IsDirected() {
+		out = build(graph.Successors)
+		return out, out
+	}
+
+	if symmetric {
+		out = build(func(node core.Node) []core.Node {
+			return append(graph.Successors(node), graph.Predecessors(node)...)
+		})
+		return out, out
+	}
+
+	return build(graph.Successors), build(graph.Predecessors)
+}
+
+func (ig *indexGraph) neighbors(i int32) []int32 {
+	return ig.targets[ig.offsets[i]:ig.offsets[i+1]]
+}
+
+func (ig *indexGraph) degree(i int32) int {
+	return ig.offsets[i+1] - ig.offsets[i]
+}
+
+func (ig *indexGraph) edgeCount() int {
+	return len(ig.targets)
+}
+
+// Runs a direction-optimizing BFS from source over ig (in must hold the reverse edges of ig), filling in levels for every node reached. Nodes whose level isn't -1 are treated as already
+// visited. unexplored is the number of edges from nodes not yet visited, used to decide when to switch directions. Returns the nodes reached, including source.
+func (ig *indexGraph) bfs(in *indexGraph, source int32, levels []int32, unexplored, workers int) (reached []int32) {
+	levels[source] = 0
+	frontier := []int32{source}
+	reached = []int32{source}
+	unexplored -= ig.degree(source)
+
+	bottomUp := false
+	for level := int32(0); len(frontier) != 0; level++ {
+		frontierEdges := 0
+		for _, i := range frontier {
+			frontierEdges += ig.degree(i)
+		}
+
+		if !bottomUp && frontierEdges > unexplored/bfsAlpha {
+			bottomUp = true
+		} else if bottomUp && len(frontier) < len(ig.nodes)/bfsBeta {
+			bottomUp = false
+		}
+
+		if bottomUp {
+			frontier = in.bottomUpStep(levels, level, workers)
+		} else {
+			frontier = ig.topDownStep(frontier, levels, level, workers)
+		}
+
+		for _, i := range frontier {
+			unexplored -= ig.degree(i)
+		}
+		reached = append(reached, frontier...)
+	}
+
+	return reached
+}
+
+// Expands every node of the frontier, claiming unvisited successors with a compare-and-swap so each node joins exactly one worker's share of the next frontier
+func (ig *indexGraph) topDownStep(frontier []int32, levels []int32, level int32, workers int) []int32 {
+	return parallelCollect(len(frontier), workers, func(lo, hi int, next []int32) []int32 {
+		for _, i := range frontier[lo:hi] {
+			for _, j := range ig.neighbors(i) {
+				if atomic.LoadInt32(&levels[j]) == -1 && atomic.CompareAndSwapInt32(&levels[j], -1, level+1) {
+					next = append(next, j)
+				}
+			}
+		}
+
+		return next
+	})
+}
+
+// Has every unvisited node look through its predecessors (ig is the reverse graph here) for one on the current level, stopping at the first one found. Each node is only written by the worker
+// that owns it, but its level is read by others so all accesses are atomic
+func (ig *indexGraph) bottomUpStep(levels []int32, level int32, workers int) []int32 {
+	return parallelCollect(len(ig.nodes), workers, func(lo, hi int, next []int32) []int32 {
+		for j := int32(lo); j < int32(hi); j++ {
+			if atomic.LoadInt32(&levels[j]) != -1 {
+				continue
+			}
+
+			for _, i := range ig.neighbors(j) {
+				if atomic.LoadInt32(&levels[i]) == level {
+					atomic.StoreInt32(&levels[j], level+1)
+					next = append(next, j)
+					break
+				}
+			}
+		}
+
+		return next
+	})
+}
+
+// Like parallelRange, but each chunk appends its results to its own slice and the slices are concatenated in chunk order
+func parallelCollect(n, workers int, f func(lo, hi int, results []int32) []int32) []int32 {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		return f(0, n, nil)
+	}
+
+	parts := make([][]int32, workers)
+	graphutil.ParallelRange(n, workers, func(chunk, lo, hi int) {
+		parts[chunk] = f(lo, hi, nil)
+	})
+
+	total := 0
+	for _, part := range parts {
+		total += len(part)
+	}
+	results := make([]int32, 0, total)
+	for _, part := range parts {
+		results = append(results, part...)
+	}
+
+	return results
+}
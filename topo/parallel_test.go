@@ -0,0 +1,103 @@
+package topo_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"math/rand"
+	"testing"
+)
+
+// Builds a random graph with n nodes and about n*degree edges, dense enough that ParallelBFS switches to bottom-up steps
+func randomGraph(n, degree int, directed bool, seed int64) *simple.GonumGraph {
+	rnd := rand.New(rand.NewSource(seed))
+	g := simple.NewGonumGraph(directed)
+	for i := 0; i < n; i++ {
+		g.AddNode(core.GonumNode(i), nil)
+	}
+	for i := 0; i < n*degree; i++ {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(rnd.Intn(n)), T: core.GonumNode(rnd.Intn(n))})
+	}
+
+	return g
+}
+
+func serialBFS(source core.Node, g core.Graph) map[int]int {
+	levels := map[int]int{source.ID(): 0}
+	frontier := []core.Node{source}
+	for len(frontier) != 0 {
+		next := []core.Node{}
+		for _, node := range frontier {
+			for _, succ := range g.Successors(node) {
+				if _, ok := levels[succ.ID()]; !ok {
+					levels[succ.ID()] = levels[node.ID()] + 1
+					next = append(next, succ)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return levels
+}
+
+func TestParallelBFS(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		g := randomGraph(2000, 4, directed, 1)
+		want := serialBFS(core.GonumNode(0), g)
+		for _, workers := range []int{1, 4} {
+			got := topo.ParallelBFS(core.GonumNode(0), g, workers)
+			if len(got) != len(want) {
+				t.Errorf("Directed %t, %d workers: reached %d nodes, want %d", directed, workers, len(got), len(want))
+			}
+			for id, level := range want {
+				if got[id] != level {
+					t.Errorf("Directed %t, %d workers: node %d has level %d, want %d", directed, workers, id, got[id], level)
+					break
+				}
+			}
+		}
+	}
+
+	if levels := topo.ParallelBFS(core.GonumNode(5), simple.NewGonumGraph(true), 0); len(levels) != 0 {
+		t.Error("Search from a missing node reached", levels)
+	}
+}
+
+func TestParallelConnectedComponents(t *testing.T) {
+	// Two dense random pieces, a directed chain that's only weakly connected, and an isolated node
+	g := randomGraph(1000, 4, true, 2)
+	for i := 1000; i < 1500; i++ {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(i + 500), T: core.GonumNode(i)})
+	}
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2000), T: core.GonumNode(2001)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2002), T: core.GonumNode(2001)})
+	g.AddNode(core.GonumNode(3000), nil)
+
+	want := topo.ParallelConnectedComponents(g, 1)
+	got := topo.ParallelConnectedComponents(g, 4)
+	if len(got) != len(want) {
+		t.Fatalf("Found %d components with 4 workers, %d with 1", len(got), len(want))
+	}
+
+	seen := make(map[int]int)
+	for c, component := range got {
+		for _, node := range component {
+			if _, ok := seen[node.ID()]; ok {
+				t.Fatalf("Node %d is in more than one component", node.ID())
+			}
+			seen[node.ID()] = c
+		}
+	}
+	if len(seen) != len(g.NodeList()) {
+		t.Errorf("Components cover %d nodes, want %d", len(seen), len(g.NodeList()))
+	}
+	if seen[2000] != seen[2002] || seen[1000] != seen[1500] || seen[3000] == seen[2000] {
+		t.Error("Weakly connected nodes were split, or unconnected nodes were merged")
+	}
+	for _, edge := range g.EdgeList() {
+		if seen[edge.Head().ID()] != seen[edge.Tail().ID()] {
+			t.Fatalf("Edge %v crosses components", edge)
+		}
+	}
+}
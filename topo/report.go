@@ -0,0 +1,229 @@
+package topo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"html/template"
+	"io"
+	"sort"
+)
+
+// A Report profiles a graph with the measures worth a first look when a new dataset comes in: its size and density, its degree distribution, how it falls apart into components,
+// how clustered it is, and its edge costs. NewReport computes one, and WriteHTML renders it as a standalone HTML page with the degree distribution and component sizes drawn as SVG
+// bar charts.
+//
+// A node's degree here is its number of neighbors in an undirected graph, and its in-degree plus its out-degree in a directed one.
+type Report struct {
+	Name                 string  // From the graph's metadata, if it has any
+	Directed             bool    // Whether the graph is directed
+	Nodes, Edges         int     // Each edge of an undirected graph is counted once
+	SelfLoops            int     // The number of edges from a node to itself
+	Density              float64 // The fraction of all possible edges (between distinct nodes) that are in the graph
+	MinDegree, MaxDegree int
+	MeanDegree           float64
+	DegreeHistogram      []int            // The number of nodes of each degree, see DegreeHistogram
+	ComponentSizes       []int            // The sizes of the (weakly, in a directed graph) connected components, largest first
+	StrongComponentSizes []int            // The sizes of the strongly connected components, largest first, for a directed graph only
+	AverageClustering    float64          // The mean of the nodes' unweighted clustering coefficients
+	Weights              core.WeightStats // The edge costs, as given by EdgeWeightStats
+}
+
+// Computes the Report of the graph. This costs as much as its most expensive measure, the clustering coefficients, which take O(m * maximum degree) time.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func NewReport(graph core.Graph, Cost func(core.Node, core.Node) float64) *Report {
+	report := &Report{Name: core.GraphMetadata(graph).Name, Directed: graph.IsDirected(), Weights: core.EdgeWeightStats(graph, Cost)}
+
+	nodes := graph.NodeList()
+	report.Nodes = len(nodes)
+	degrees := make([]int, len(nodes))
+	for i, node := range nodes {
+		succs := graph.Successors(node)
+		for _, succ := range succs {
+			if succ.ID() == node.ID() {
+				report.SelfLoops++
+			} else if graph.IsDirected() || node.ID() < succ.ID() {
+				report.Edges++
+			}
+		}
+		if graph.IsDirected() {
+			degrees[i] = len(succs) + len(graph.Predecessors(node))
+		} else {
+			degrees[i] = len(succs)
+		}
+	}
+	report.Edges += report.SelfLoops
+
+	if n := float64(report.Nodes); n > 1 {
+		pairs := n * (n - 1)
+		if !graph.IsDirected() {
+			pairs /= 2
+		}
+		report.Density = float64(report.Edges-report.SelfLoops) / pairs
+	}
+
+	if len(degrees) > 0 {
+		sort.Ints(degrees)
+		report.MinDegree, report.MaxDegree = degrees[0], degrees[len(degrees)-1]
+		total := 0
+		for _, d := range degrees {
+			total += d
+		}
+		report.MeanDegree = float64(total) / float64(len(degrees))
+	}
+	report.DegreeHistogram = core.DegreeHistogram(degrees)
+
+	report.ComponentSizes = componentSizes(ParallelConnectedComponents(graph, 0))
+	if graph.IsDirected() {
+		report.StrongComponentSizes = componentSizes(Tarjan(graph))
+	}
+
+	if len(nodes) > 0 {
+		total := 0.0
+		for _, c := range ClusteringCoefficients(graph, Cost, UnweightedClustering) {
+			total += c
+		}
+		report.AverageClustering = total / float64(len(nodes))
+	}
+
+	return report
+}
+
+func componentSizes(components [][]core.Node) []int {
+	sizes := make([]int, len(components))
+	for i, component := range components {
+		sizes[i] = len(component)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+
+	return sizes
+}
+
+// Writes the report as a standalone HTML page, with no scripts or external resources, so it can be mailed or archived as a single file. The name is escaped.
+func (report *Report) WriteHTML(w io.Writer) error {
+	// Only the 50 largest components are drawn, since the rest are usually a long tail of singletons
+	components := report.ComponentSizes
+	if len(components) > 50 {
+		components = components[:50]
+	}
+	degreeLabels := make([]string, len(report.DegreeHistogram))
+	for d := range degreeLabels {
+		degreeLabels[d] = fmt.Sprint(d)
+	}
+
+	return reportTemplate.Execute(w, struct {
+		*Report
+		DegreeChart, ComponentChart template.HTML
+	}{report, svgBarChart(binCounts(report.DegreeHistogram, degreeLabels, 60)), svgBarChart(components, nil)})
+}
+
+// Merges neighboring counts until there are at most maxBars of them, so that a histogram with thousands of degrees still draws as a readable chart. Each merged bar is labelled with
+// the range of labels it covers.
+func binCounts(counts []int, labels []string, maxBars int) ([]int, []string) {
+	if len(counts) <= maxBars {
+		return counts, labels
+	}
+
+	width := (len(counts) + maxBars - 1) / maxBars
+	binned := make([]int, 0, maxBars)
+	binLabels := make([]string, 0, maxBars)
+	for start := 0; start < len(counts); start += width {
+		end := start + width
+		if end > len(counts) {
+			end = len(counts)
+		}
+		sum := 0
+		for _, c := range counts[start:end] {
+			sum += c
+		}
+		binned = append(binned, sum)
+		binLabels = append(binLabels, labels[start]+"-"+labels[end-1])
+	}
+
+	return binned, binLabels
+}
+
+// Draws the values as an SVG bar chart, with a tooltip per bar giving its label (if there are labels) and value. The SVG is built from numbers and the labels, which are escaped.
+func svgBarChart(values []int, labels []string) template.HTML {
+	const width, height, gap = 600, 200, 1
+
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	if max > 0 {
+		barWidth := float64(width) / float64(len(values))
+		for i, v := range values {
+			h := float64(height) * float64(v) / float64(max)
+			title := fmt.Sprint(v)
+			if labels != nil {
+				title = template.HTMLEscapeString(labels[i]) + ": " + title
+			}
+			fmt.Fprintf(&buf, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="#4a7ab5"><title>%s</title></rect>`, float64(i)*barWidth, height-h, barWidth-gap, h, title)
+		}
+	}
+	buf.WriteString(`</svg>`)
+
+	return template.HTML(buf.String())
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{if .Name}}{{.Name}}{{else}}Graph{{end}} report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+td { text-align: right; }
+</style>
+</head>
+<body>
+<h1>{{if .Name}}{{.Name}}{{else}}Graph{{end}} report</h1>
+
+<h2>Structure</h2>
+<table>
+<tr><th>Directed</th><td>{{.Directed}}</td></tr>
+<tr><th>Nodes</th><td>{{.Nodes}}</td></tr>
+<tr><th>Edges</th><td>{{.Edges}}</td></tr>
+<tr><th>Self loops</th><td>{{.SelfLoops}}</td></tr>
+<tr><th>Density</th><td>{{printf "%.6g" .Density}}</td></tr>
+<tr><th>Average clustering</th><td>{{printf "%.6g" .AverageClustering}}</td></tr>
+</table>
+
+<h2>Degrees</h2>
+<table>
+<tr><th>Minimum</th><td>{{.MinDegree}}</td></tr>
+<tr><th>Maximum</th><td>{{.MaxDegree}}</td></tr>
+<tr><th>Mean</th><td>{{printf "%.6g" .MeanDegree}}</td></tr>
+</table>
+{{.DegreeChart}}
+
+<h2>Components</h2>
+<table>
+<tr><th>Connected components</th><td>{{len .ComponentSizes}}</td></tr>
+<tr><th>Largest</th><td>{{if .ComponentSizes}}{{index .ComponentSizes 0}}{{else}}0{{end}}</td></tr>
+{{if .Directed}}<tr><th>Strongly connected components</th><td>{{len .StrongComponentSizes}}</td></tr>
+<tr><th>Largest strong</th><td>{{if .StrongComponentSizes}}{{index .StrongComponentSizes 0}}{{else}}0{{end}}</td></tr>
+{{end}}</table>
+{{.ComponentChart}}
+
+<h2>Edge costs</h2>
+<table>
+<tr><th>Total</th><td>{{printf "%.6g" .Weights.Total}}</td></tr>
+<tr><th>Minimum</th><td>{{printf "%.6g" .Weights.Min}}</td></tr>
+<tr><th>Maximum</th><td>{{printf "%.6g" .Weights.Max}}</td></tr>
+<tr><th>Mean</th><td>{{printf "%.6g" .Weights.Mean}}</td></tr>
+<tr><th>Median</th><td>{{printf "%.6g" .Weights.Median}}</td></tr>
+<tr><th>Standard deviation</th><td>{{printf "%.6g" .Weights.StdDev}}</td></tr>
+</table>
+</body>
+</html>
+`))
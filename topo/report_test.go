@@ -0,0 +1,72 @@
+package topo_test
+
+import (
+	"bytes"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	// A triangle with a tail, and a separate edge
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nodes(1, 2))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.AddNode(core.GonumNode(4), nodes(5))
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(4), T: core.GonumNode(5)}, 3)
+	g.Metadata().Name = "<triangle>"
+
+	report := topo.NewReport(g, nil)
+	if report.Nodes != 6 || report.Edges != 5 || report.SelfLoops != 0 || report.Density != 5.0/15 {
+		t.Errorf("Got %d nodes, %d edges and density %f", report.Nodes, report.Edges, report.Density)
+	}
+	if report.MinDegree != 1 || report.MaxDegree != 3 || report.MeanDegree != 10.0/6 || len(report.DegreeHistogram) != 4 || report.DegreeHistogram[1] != 3 {
+		t.Errorf("Got degrees %d to %d, mean %f and histogram %v", report.MinDegree, report.MaxDegree, report.MeanDegree, report.DegreeHistogram)
+	}
+	if len(report.ComponentSizes) != 2 || report.ComponentSizes[0] != 4 || report.StrongComponentSizes != nil {
+		t.Errorf("Got component sizes %v and %v", report.ComponentSizes, report.StrongComponentSizes)
+	}
+	// Nodes 0 and 1 are fully clustered, and node 2 has one of its three pairs of neighbors joined
+	if want := (1 + 1 + 1.0/3) / 6; report.AverageClustering < want-1e-12 || report.AverageClustering > want+1e-12 {
+		t.Errorf("Got average clustering %f, want %f", report.AverageClustering, want)
+	}
+	if report.Weights.Count != 5 || report.Weights.Max != 3 {
+		t.Errorf("Got weights %+v", report.Weights)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	if !strings.Contains(html, "&lt;triangle&gt; report") || strings.Contains(html, "<triangle>") || strings.Count(html, "<svg") != 2 || strings.Contains(html, "Strongly") {
+		t.Errorf("Got report:\n%s", html)
+	}
+
+	directed := simple.NewGonumGraph(true)
+	directed.AddNode(core.GonumNode(0), nodes(1))
+	for _, tail := range []int{0, 1, 2} {
+		directed.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(tail)})
+	}
+	report = topo.NewReport(directed, nil)
+	if report.Edges != 4 || report.SelfLoops != 1 || report.Density != 3.0/6 || len(report.StrongComponentSizes) != 2 || report.StrongComponentSizes[0] != 2 {
+		t.Errorf("Got %d edges, %d self loops, density %f and strong components %v", report.Edges, report.SelfLoops, report.Density, report.StrongComponentSizes)
+	}
+	buf.Reset()
+	if err := report.WriteHTML(&buf); err != nil || !strings.Contains(buf.String(), "Strongly connected components") {
+		t.Errorf("Got report %s with error %v", buf.String(), err)
+	}
+
+	// Wide histograms are binned
+	star := simple.NewGonumGraph(false)
+	for id := 1; id <= 200; id++ {
+		star.AddNode(core.GonumNode(id), nodes(0))
+	}
+	buf.Reset()
+	if err := topo.NewReport(star, nil).WriteHTML(&buf); err != nil || strings.Count(buf.String(), "<rect") > 61 || !strings.Contains(buf.String(), "<title>0-3: 200</title>") {
+		t.Errorf("Got report %s with error %v", buf.String(), err)
+	}
+}
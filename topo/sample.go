@@ -0,0 +1,218 @@
+package topo
+
+import (
+	"container/heap"
+	"github.com/nathankerr/graph/core"
+	"math"
+	"math/rand"
+)
+
+/* Reservoir sampling. A reservoir keeps a fixed size random sample of items that are offered to it one at a time, without knowing how many will come, so it can sample a graph's
+nodes or edges in a single pass over any listing of them, including an EdgeStream too large for memory. That's enough for approximate analytics, such as estimating a degree or cost
+distribution from a few thousand edges of a huge graph */
+
+// A Reservoir keeps a uniform random sample of up to k of the items added to it: after n items have been added, each of them is in the sample with probability k/n (Vitter's algorithm R).
+// It holds only the sample, however many items are added.
+type Reservoir struct {
+	k     int
+	rnd   *rand.Rand
+	items []interface{}
+	seen  int
+}
+
+// Returns an empty reservoir of size k (a k <= 0 samples nothing). Random numbers come from rnd, or from math/rand's default source if it's nil; pass a seeded source to make the
+// sample repeatable.
+func NewReservoir(k int, rnd *rand.Rand) *Reservoir {
+	if k < 0 {
+		k = 0
+	}
+
+	return &Reservoir{k: k, rnd: rnd, items: make([]interface{}, 0, k)}
+}
+
+// Offers an item to the sample
+func (res *Reservoir) Add(item interface{}) {
+	res.seen++
+	if len(res.items) < res.k {
+		res.items = append(res.items, item)
+	} else if i := randIntn(res.rnd, res.seen); i < res.k {
+		res.items[i] = item
+	}
+}
+
+// Returns the sample, in no particular order. It has k items, or all of them if fewer than k have been added.
+func (res *Reservoir) Sample() []interface{} {
+	return append([]interface{}(nil), res.items...)
+}
+
+// Returns how many items have been added
+func (res *Reservoir) Seen() int {
+	return res.seen
+}
+
+// A WeightedReservoir keeps a random sample of up to k of the items added to it, drawn without replacement with probability proportional to their weights (the A-Res algorithm of
+// Efraimidis and Spirakis). Items with a weight that isn't positive are never sampled. Like Reservoir, it holds only the sample.
+type WeightedReservoir struct {
+	k    int
+	rnd  *rand.Rand
+	heap reservoirHeap
+	seen int
+}
+
+// Returns an empty weighted reservoir of size k, taking random numbers from rnd as NewReservoir does
+func NewWeightedReservoir(k int, rnd *rand.Rand) *WeightedReservoir {
+	if k < 0 {
+		k = 0
+	}
+
+	return &WeightedReservoir{k: k, rnd: rnd, heap: make(reservoirHeap, 0, k)}
+}
+
+// Offers an item with the given weight to the sample
+func (res *WeightedReservoir) Add(item interface{}, weight float64) {
+	res.seen++
+	if !(weight > 0) || res.k == 0 {
+		return
+	}
+
+	// Each item gets the key u^(1/weight) for a uniform u, and the sample is the k largest keys. Comparing log(u)/weight instead keeps small weights from underflowing to 0.
+	key := math.Log(randFloat64(res.rnd)) / weight
+	if len(res.heap) < res.k {
+		heap.Push(&res.heap, reservoirItem{item, key})
+	} else if key > res.heap[0].key {
+		res.heap[0] = reservoirItem{item, key}
+		heap.Fix(&res.heap, 0)
+	}
+}
+
+// Returns the sample, in no particular order. It has k items, or all the items with positive weights if there are fewer than k.
+func (res *WeightedReservoir) Sample() []interface{} {
+	items := make([]interface{}, len(res.heap))
+	for i, ri := range res.heap {
+		items[i] = ri.item
+	}
+
+	return items
+}
+
+// Returns how many items have been added, including those that couldn't be sampled
+func (res *WeightedReservoir) Seen() int {
+	return res.seen
+}
+
+// Returns a uniform random sample of up to k of the graph's nodes
+func SampleNodes(graph core.Graph, k int, rnd *rand.Rand) []core.Node {
+	res := NewReservoir(k, rnd)
+	for _, node := range graph.NodeList() {
+		res.Add(node)
+	}
+
+	sample := make([]core.Node, len(res.items))
+	for i, item := range res.items {
+		sample[i] = item.(core.Node)
+	}
+	return sample
+}
+
+// Returns a random sample of up to k of the graph's edges, drawn uniformly if weight is nil or otherwise without replacement with probability proportional to weight(edge). As with
+// EdgeList, an undirected graph's edges are offered in both directions. To sample edges in proportion to their costs, pass a weight that looks them up, such as
+// func(e Edge) float64 { return cgraph.Cost(e.Head(), e.Tail()) }.
+func SampleEdges(graph core.Graph, k int, weight func(core.Edge) float64, rnd *rand.Rand) []core.Edge {
+	var items []interface{}
+	if weight == nil {
+		res := NewReservoir(k, rnd)
+		for _, edge := range graph.EdgeList() {
+			res.Add(edge)
+		}
+		items = res.items
+	} else {
+		res := NewWeightedReservoir(k, rnd)
+		for _, edge := range graph.EdgeList() {
+			res.Add(edge, weight(edge))
+		}
+		items = res.Sample()
+	}
+
+	sample := make([]core.Edge, len(items))
+	for i, item := range items {
+		sample[i] = item.(core.Edge)
+	}
+	return sample
+}
+
+// Returns a random sample of up to k of the streamed edges in one pass, drawn uniformly if weight is nil or otherwise in proportion to weight(head, tail), as SampleEdges does. Only the
+// sample is held in memory.
+func StreamSampleEdges(stream EdgeStream, k int, weight func(head, tail int) float64, rnd *rand.Rand) ([]core.EdgeKey, error) {
+	var items []interface{}
+	var err error
+	if weight == nil {
+		res := NewReservoir(k, rnd)
+		err = stream.Edges(func(head, tail int) error {
+			res.Add(core.EdgeKey{Head: head, Tail: tail})
+			return nil
+		})
+		items = res.items
+	} else {
+		res := NewWeightedReservoir(k, rnd)
+		err = stream.Edges(func(head, tail int) error {
+			res.Add(core.EdgeKey{Head: head, Tail: tail}, weight(head, tail))
+			return nil
+		})
+		items = res.Sample()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sample := make([]core.EdgeKey, len(items))
+	for i, item := range items {
+		sample[i] = item.(core.EdgeKey)
+	}
+	return sample, nil
+}
+
+func randIntn(rnd *rand.Rand, n int) int {
+	if rnd == nil {
+		return rand.Intn(n)
+	}
+	return rnd.Intn(n)
+}
+
+// Returns a uniform random number in (0, 1], so that its log is finite
+func randFloat64(rnd *rand.Rand) float64 {
+	if rnd == nil {
+		return 1 - rand.Float64()
+	}
+	return 1 - rnd.Float64()
+}
+
+type reservoirItem struct {
+	item interface{}
+	key  float64
+}
+
+// A min-heap of reservoir items by key, so the item to evict is on top
+type reservoirHeap []reservoirItem
+
+func (h reservoirHeap) Len() int {
+	return len(h)
+}
+
+func (h reservoirHeap) Less(i, j int) bool {
+	return h[i].key < h[j].key
+}
+
+func (h reservoirHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *reservoirHeap) Push(x interface{}) {
+	*h = append(*h, x.(reservoirItem))
+}
+
+func (h *reservoirHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
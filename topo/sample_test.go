@@ -0,0 +1,106 @@
+package topo_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestReservoir(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const trials = 20000
+
+	// Each of 10 items should be in a sample of 3 with probability 0.3
+	counts := make([]int, 10)
+	for trial := 0; trial < trials; trial++ {
+		res := topo.NewReservoir(3, rnd)
+		for i := 0; i < 10; i++ {
+			res.Add(i)
+		}
+		if len(res.Sample()) != 3 || res.Seen() != 10 {
+			t.Fatalf("Got sample %v after %d items", res.Sample(), res.Seen())
+		}
+		for _, item := range res.Sample() {
+			counts[item.(int)]++
+		}
+	}
+	for i, count := range counts {
+		if p := float64(count) / trials; math.Abs(p-0.3) > 0.02 {
+			t.Errorf("Item %d was sampled with probability %f, want 0.3", i, p)
+		}
+	}
+
+	// With one item to draw, it's the one of weight 3 three times in four; the item of weight 0 is never drawn
+	heavy := 0
+	for trial := 0; trial < trials; trial++ {
+		res := topo.NewWeightedReservoir(1, rnd)
+		res.Add("light", 1)
+		res.Add("none", 0)
+		res.Add("heavy", 3)
+		switch res.Sample()[0] {
+		case "heavy":
+			heavy++
+		case "none":
+			t.Fatal("Sampled an item of weight 0")
+		}
+	}
+	if p := float64(heavy) / trials; math.Abs(p-0.75) > 0.02 {
+		t.Errorf("The heavy item was sampled with probability %f, want 0.75", p)
+	}
+
+	res := topo.NewWeightedReservoir(5, rnd)
+	res.Add(1, 2)
+	res.Add(2, -1)
+	if len(res.Sample()) != 1 || res.Seen() != 2 || len(topo.NewReservoir(-1, rnd).Sample()) != 0 {
+		t.Errorf("Got sample %v of a short weighted listing", res.Sample())
+	}
+}
+
+func TestSampleGraph(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := simple.NewGonumGraph(true)
+	for id := 0; id < 20; id++ {
+		g.AddNode(core.GonumNode(id), nil)
+	}
+	for id := 0; id < 20; id++ {
+		g.AddEdge(core.GonumEdge{H: core.GonumNode(id), T: core.GonumNode((id + 1) % 20)})
+	}
+	g.SetEdgeCost(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)}, 0)
+
+	seen := make(map[int]bool)
+	for _, node := range topo.SampleNodes(g, 5, rnd) {
+		if !g.NodeExists(node) || seen[node.ID()] {
+			t.Errorf("Sampled node %v twice or from outside the graph", node)
+		}
+		seen[node.ID()] = true
+	}
+	if len(seen) != 5 || len(topo.SampleNodes(g, 50, rnd)) != 20 {
+		t.Errorf("Sampled nodes %v", seen)
+	}
+
+	for _, edge := range topo.SampleEdges(g, 19, func(e core.Edge) float64 { return g.Cost(e.Head(), e.Tail()) }, rnd) {
+		if !g.IsSuccessor(edge.Head(), edge.Tail()) || edge.Head().ID() == 3 {
+			t.Errorf("Sampled edge %v, which isn't in the graph or has no weight", edge)
+		}
+	}
+	if edges := topo.SampleEdges(g, 4, nil, rnd); len(edges) != 4 {
+		t.Errorf("Sampled edges %v", edges)
+	}
+
+	keys, err := topo.StreamSampleEdges(topo.GraphEdgeStream{Graph: g}, 30, nil, rnd)
+	if err != nil || len(keys) != 20 {
+		t.Errorf("Sampled streamed edges %v with error %v", keys, err)
+	}
+	keys, err = topo.StreamSampleEdges(topo.GraphEdgeStream{Graph: g}, 3, func(head, tail int) float64 { return float64(head) }, rnd)
+	if err != nil || len(keys) != 3 {
+		t.Errorf("Sampled streamed edges %v with error %v", keys, err)
+	}
+	for _, key := range keys {
+		if key.Head == 0 || key.Tail != (key.Head+1)%20 {
+			t.Errorf("Sampled streamed edge %v", key)
+		}
+	}
+}
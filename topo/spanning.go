@@ -0,0 +1,285 @@
+package topo
+
+import (
+	"github.com/nathankerr/graph/container"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/set"
+	"sort"
+)
+
+/* Implements minimum-spanning tree algorithms */
+
+// Returns the edges a minimum spanning tree can be built from, keyed by their (undirected) EdgeKeys and sorted by key: the graph is treated as undirected, so if both directions of an
+// edge exist only the cheaper one is kept, and self loops are dropped. The edges are the graph's own, as listed by EdgeList, weighted by Cost.
+func spanningCandidates(graph core.Graph, Cost func(core.Node, core.Node) float64) (keys graphutil.EdgeKeySorter, edges map[core.EdgeKey]core.WeightedEdge) {
+	edgeCost := graphutil.DefaultEdgeCost(graph, Cost)
+	edges = make(map[core.EdgeKey]core.WeightedEdge)
+	for _, edge := range graph.EdgeList() {
+		if edge.Head().ID() == edge.Tail().ID() {
+			continue
+		}
+		key := core.KeyOf(edge, false)
+		cost := edgeCost(edge)
+		if old, ok := edges[key]; !ok || cost < old.Weight {
+			edges[key] = core.WeightedEdge{Edge: edge, Weight: cost}
+		}
+	}
+
+	keys = make(graphutil.EdgeKeySorter, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Sort(keys)
+
+	return keys, edges
+}
+
+// Generates a minimum spanning forest with Prim's algorithm, growing a tree from the lowest ID node of each connected component in turn. Returns the edges of the forest, in the order
+// they were added, along with their total weight. A connected graph with n nodes gives n-1 edges; otherwise there's one tree per component.
+//
+// The graph is treated as undirected: if both directions of an edge exist the cheaper one is used, and self loops are ignored. The returned edges are the graph's own, as listed by
+// EdgeList, so an undirected edge may be returned in either direction. Ties between edges of the same weight are always broken the same way, so the forest is deterministic, but it may
+// differ from Kruskal's when there are ties. The total weight never does.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func Prim(graph core.Graph, Cost func(core.Node, core.Node) float64) (forest []core.Edge, weight float64) {
+	nodes, indices := graphutil.IndexNodes(graph)
+	keys, edges := spanningCandidates(graph, Cost)
+
+	adjacent := make([][]core.EdgeKey, len(nodes))
+	for _, key := range keys {
+		adjacent[indices[key.Head]] = append(adjacent[indices[key.Head]], key)
+		adjacent[indices[key.Tail]] = append(adjacent[indices[key.Tail]], key)
+	}
+
+	forest = make([]core.Edge, 0)
+	inTree := make([]bool, len(nodes))
+	best := make([]core.EdgeKey, len(nodes)) // The cheapest edge joining each node on the heap to the tree
+	queue := container.NewIndexedHeap()
+	for root := range nodes {
+		if inTree[root] {
+			continue
+		}
+
+		queue.Push(root, 0)
+		for !queue.IsEmpty() {
+			i, _ := queue.Pop()
+			inTree[i] = true
+			if i != root {
+				forest = append(forest, edges[best[i]].Edge)
+				weight += edges[best[i]].Weight
+			}
+
+			for _, key := range adjacent[i] {
+				j := indices[key.Head]
+				if j == i {
+					j = indices[key.Tail]
+				}
+				if inTree[j] {
+					continue
+				}
+				if old, ok := queue.Priority(j); !ok || edges[key].Weight < old {
+					best[j] = key
+					queue.Push(j, edges[key].Weight)
+				}
+			}
+		}
+	}
+
+	return forest, weight
+}
+
+// Generates a minimum spanning forest with Kruskal's algorithm, using a set.DisjointSet to track which trees the nodes are in. Returns the edges of the forest, in order of weight, along
+// with their total weight. A connected graph with n nodes gives n-1 edges; otherwise there's one tree per component.
+//
+// The graph is treated as undirected: if both directions of an edge exist the cheaper one is used, and self loops are ignored. The returned edges are the graph's own, as listed by
+// EdgeList, so an undirected edge may be returned in either direction. Ties between edges of the same weight are broken by their endpoints' IDs, so the forest is deterministic.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func Kruskal(graph core.Graph, Cost func(core.Node, core.Node) float64) (forest []core.Edge, weight float64) {
+	keys, edges := spanningCandidates(graph, Cost)
+
+	edgeWeights := make(edgeSorter, len(keys))
+	for i, key := range keys {
+		edgeWeights[i] = edges[key]
+	}
+	sort.Stable(edgeWeights)
+
+	ds := set.NewDisjointSet()
+	for _, node := range graph.NodeList() {
+		ds.MakeSet(node.ID())
+	}
+
+	forest = make([]core.Edge, 0)
+	for _, edge := range edgeWeights {
+		if s1, s2 := ds.Find(edge.Head().ID()), ds.Find(edge.Tail().ID()); s1 != nil && s2 != nil && s1 != s2 {
+			ds.Union(s1, s2)
+			forest = append(forest, edge.Edge)
+			weight += edge.Weight
+		}
+	}
+
+	return forest, weight
+}
+
+// An EdgeReplacement pairs an edge that isn't in a minimum spanning tree with the most expensive tree edge on the cycle it would close. Edge would enter the tree, in place of Replaces,
+// as soon as its weight dropped below Replaces.Weight; Edge.Weight - Replaces.Weight (never negative) is how much cheaper it would need to become.
+type EdgeReplacement struct {
+	Edge     core.WeightedEdge
+	Replaces core.WeightedEdge
+}
+
+// Computes a minimum spanning forest along with its sensitivity to changes in the edges left out of it. For every non-tree edge, the returned replacements give the heaviest tree edge on
+// the path between its endpoints, which is the edge it would replace if it were made cheap enough; this answers what-if questions such as "how much would upgrading this link have to
+// cost before it's worth using" without recomputing the tree.
+//
+// The graph is treated as undirected: every edge is reported with the lower ID as its Head, self loops are ignored, and if both directions of an edge exist the cheaper one is used. Ties
+// between edges of the same weight are broken by their endpoints' IDs, so the tree is deterministic. The tree edges are returned in the order Kruskal's algorithm picks them, and the replacements sorted by
+// their edges' Head IDs, then Tail IDs.
+//
+// As with other algorithms that use Cost, the order of precedence is Argument > Interface > UniformCost
+func MSTSensitivity(graph core.Graph, Cost func(core.Node, core.Node) float64) (tree []core.WeightedEdge, replacements []EdgeReplacement) {
+	Cost = graphutil.DefaultCost(graph, Cost)
+	nodes, indices := graphutil.IndexNodes(graph)
+
+	weights := make(map[core.EdgeKey]float64)
+	for _, node := range nodes {
+		for _, succ := range graph.Successors(node) {
+			if succ.ID() == node.ID() {
+				continue
+			}
+			key := core.KeyOf(core.GonumEdge{H: node, T: succ}, false)
+			cost := Cost(node, succ)
+			if old, ok := weights[key]; !ok || cost < old {
+				weights[key] = cost
+			}
+		}
+	}
+	keys := make(graphutil.EdgeKeySorter, 0, len(weights))
+	for key := range weights {
+		keys = append(keys, key)
+	}
+	sort.Sort(keys)
+	edges := make(edgeSorter, len(keys))
+	for i, key := range keys {
+		edges[i] = core.WeightedEdge{Edge: core.GonumEdge{H: nodes[indices[key.Head]], T: nodes[indices[key.Tail]]}, Weight: weights[key]}
+	}
+	sort.Stable(edges)
+
+	// Kruskal's algorithm, over node indices
+	parents := make([]int, len(nodes))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	adjacent := make([][]int, len(nodes)) // Indices into tree
+	nonTree := make([]core.WeightedEdge, 0)
+	for _, edge := range edges {
+		h, t := indices[edge.Head().ID()], indices[edge.Tail().ID()]
+		if a, b := find(h), find(t); a != b {
+			parents[a] = b
+			adjacent[h] = append(adjacent[h], len(tree))
+			adjacent[t] = append(adjacent[t], len(tree))
+			tree = append(tree, edge)
+		} else {
+			nonTree = append(nonTree, edge)
+		}
+	}
+
+	// Root every tree, recording each node's depth, parent and the tree edge to its parent
+	depth := make([]int, len(nodes))
+	up := make([]int, len(nodes))
+	upEdge := make([]int, len(nodes))
+	visited := make([]bool, len(nodes))
+	for root := range nodes {
+		if visited[root] {
+			continue
+		}
+		visited[root], up[root], upEdge[root] = true, root, -1
+		stack := []int{root}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, e := range adjacent[v] {
+				w := indices[tree[e].Head().ID()]
+				if w == v {
+					w = indices[tree[e].Tail().ID()]
+				}
+				if !visited[w] {
+					visited[w], depth[w], up[w], upEdge[w] = true, depth[v]+1, v, e
+					stack = append(stack, w)
+				}
+			}
+		}
+	}
+
+	// Climbs from both endpoints to their lowest common ancestor, keeping the heaviest edge passed
+	heaviest := func(u, v int) int {
+		best := -1
+		climb := func(x int) int {
+			if best == -1 || tree[upEdge[x]].Weight > tree[best].Weight {
+				best = upEdge[x]
+			}
+			return up[x]
+		}
+		for depth[u] > depth[v] {
+			u = climb(u)
+		}
+		for depth[v] > depth[u] {
+			v = climb(v)
+		}
+		for u != v {
+			u, v = climb(u), climb(v)
+		}
+		return best
+	}
+
+	sort.Sort(weightedEdgeKeySorter(nonTree))
+	replacements = make([]EdgeReplacement, len(nonTree))
+	for i, edge := range nonTree {
+		replacements[i] = EdgeReplacement{edge, tree[heaviest(indices[edge.Head().ID()], indices[edge.Tail().ID()])]}
+	}
+
+	return tree, replacements
+}
+
+/* Purely internal data structures and functions (mostly for sorting) */
+
+/** Sorts a list of edges by weight, agnostic to repeated edges as well as direction **/
+
+type edgeSorter []core.WeightedEdge
+
+func (el edgeSorter) Len() int {
+	return len(el)
+}
+
+func (el edgeSorter) Less(i, j int) bool {
+	return el[i].Weight < el[j].Weight
+}
+
+func (el edgeSorter) Swap(i, j int) {
+	el[i], el[j] = el[j], el[i]
+}
+
+// Sorts weighted edges by their head's ID, then their tail's
+type weightedEdgeKeySorter []core.WeightedEdge
+
+func (el weightedEdgeKeySorter) Len() int {
+	return len(el)
+}
+
+func (el weightedEdgeKeySorter) Less(i, j int) bool {
+	return el[i].Head().ID() < el[j].Head().ID() || (el[i].Head().ID() == el[j].Head().ID() && el[i].Tail().ID() < el[j].Tail().ID())
+}
+
+func (el weightedEdgeKeySorter) Swap(i, j int) {
+	el[i], el[j] = el[j], el[i]
+}
@@ -0,0 +1,74 @@
+package topo_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"testing"
+)
+
+func TestMSTSensitivity(t *testing.T) {
+	// The square 0-1-2-3 with rising costs, a chord 0-2, and a separate edge 8-9
+	g := simple.NewGonumGraph(false)
+	for _, e := range []struct {
+		h, t int
+		cost float64
+	}{{0, 1, 1}, {1, 2, 2}, {2, 3, 3}, {3, 0, 4}, {0, 2, 5}, {8, 9, 1}} {
+		g.AddNode(core.GonumNode(e.h), nil)
+		edge := core.GonumEdge{H: core.GonumNode(e.h), T: core.GonumNode(e.t)}
+		g.AddEdge(edge)
+		g.SetEdgeCost(edge, e.cost)
+	}
+
+	tree, replacements := topo.MSTSensitivity(g, nil)
+	if len(tree) != 4 || tree[0].Weight != 1 || tree[3].Weight != 3 {
+		t.Errorf("Got tree %v", tree)
+	}
+	if len(replacements) != 2 {
+		t.Fatalf("Got replacements %v, want 2", replacements)
+	}
+	for i, want := range [][4]int{{0, 2, 1, 2}, {0, 3, 2, 3}} {
+		r := replacements[i]
+		if r.Edge.Head().ID() != want[0] || r.Edge.Tail().ID() != want[1] || r.Replaces.Head().ID() != want[2] || r.Replaces.Tail().ID() != want[3] {
+			t.Errorf("Replacement %d is %v replacing %v, want %v", i, r.Edge, r.Replaces, want)
+		}
+	}
+}
+
+func TestMinimumSpanningForest(t *testing.T) {
+	// A square with a diagonal, a triangle with both directions of one edge, and an isolated node
+	g := simple.NewGonumGraph(true)
+	for _, e := range []struct {
+		h, t int
+		cost float64
+	}{{0, 1, 1}, {1, 2, 2}, {2, 3, 1}, {3, 0, 3}, {0, 2, 1.5}, {5, 6, 4}, {6, 5, 2}, {6, 7, 1}, {7, 5, 5}, {7, 7, 0}} {
+		g.AddNode(core.GonumNode(e.h), nil)
+		edge := core.GonumEdge{H: core.GonumNode(e.h), T: core.GonumNode(e.t)}
+		g.AddEdge(edge)
+		g.SetEdgeCost(edge, e.cost)
+	}
+	g.AddNode(core.GonumNode(9), nil)
+
+	for name, mst := range map[string]func(core.Graph, func(core.Node, core.Node) float64) ([]core.Edge, float64){"Prim": topo.Prim, "Kruskal": topo.Kruskal} {
+		forest, weight := mst(g, nil)
+		if len(forest) != 5 || weight != 6.5 {
+			t.Errorf("%s gave %v weighing %v, want 5 edges weighing 6.5", name, forest, weight)
+		}
+
+		components := simple.NewGonumGraph(false)
+		for _, edge := range forest {
+			if !g.IsSuccessor(edge.Head(), edge.Tail()) {
+				t.Errorf("%s returned %v, which isn't in the graph", name, edge)
+			}
+			components.AddNode(edge.Head(), nil)
+			components.AddEdge(edge)
+		}
+		if sccs := topo.Tarjan(components); len(sccs) != 2 {
+			t.Errorf("%s forest has %d trees, want 2", name, len(sccs))
+		}
+
+		if forest, weight := mst(simple.NewGonumGraph(false), nil); len(forest) != 0 || weight != 0 {
+			t.Errorf("%s gave %v weighing %v for the empty graph", name, forest, weight)
+		}
+	}
+}
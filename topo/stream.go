@@ -0,0 +1,194 @@
+package topo
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/* Edge streaming. The algorithms here never hold the edges in memory, instead reading them from an EdgeStream once per pass, so they work on graphs whose edges don't fit in RAM.
+They still keep a few words per node (the "semi-external" model), which is what makes a small number of passes enough */
+
+// An EdgeStream is a list of directed edges, given by their head and tail node IDs, that can be read from start to finish any number of times. Each call to Edges is one pass.
+type EdgeStream interface {
+	Edges(fn func(head, tail int) error) error // Calls fn for each edge in order, stopping at (and returning) the first error from fn or from reading the edges
+}
+
+// An EdgeFile streams edges from a text file on disk, which is reopened for every pass. Each line holds the head and tail IDs of one edge separated by whitespace or commas, as
+// ReadEdgeList reads them; anything after them on the line is ignored (so weighted edge lists can be read too). Blank lines and lines starting with '#' or '%' are skipped, but a header
+// line isn't, so strip any header first.
+type EdgeFile string
+
+func (ef EdgeFile) Edges(fn func(head, tail int) error) error {
+	file, err := os.Open(string(ef))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' || text[0] == '%' {
+			continue
+		}
+
+		fields, err := graphutil.SplitEdgeLine(text)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", string(ef), line, err)
+		}
+		if len(fields) < 2 {
+			return fmt.Errorf("%s:%d: Expected two node IDs, got %q", string(ef), line, text)
+		}
+		head, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", string(ef), line, err)
+		}
+		tail, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", string(ef), line, err)
+		}
+
+		if err := fn(head, tail); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// A GraphEdgeStream streams the edges of an in-memory Graph, which is mostly useful for testing code written against EdgeStream. As with EdgeList, an undirected graph gives both directions of each edge.
+type GraphEdgeStream struct {
+	core.Graph
+}
+
+func (ges GraphEdgeStream) Edges(fn func(head, tail int) error) error {
+	for _, edge := range ges.EdgeList() {
+		if err := fn(edge.Head().ID(), edge.Tail().ID()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the out-degree and in-degree of every node that appears in the stream, in one pass
+func StreamDegrees(stream EdgeStream) (outDegrees, inDegrees map[int]int, err error) {
+	outDegrees, inDegrees = make(map[int]int), make(map[int]int)
+	err = stream.Edges(func(head, tail int) error {
+		outDegrees[head]++
+		inDegrees[tail]++
+		if _, ok := outDegrees[tail]; !ok {
+			outDegrees[tail] = 0
+		}
+		if _, ok := inDegrees[head]; !ok {
+			inDegrees[head] = 0
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return outDegrees, inDegrees, nil
+}
+
+// Finds the connected components of the streamed graph in one pass with union-find, ignoring edge direction (so for a directed graph these are the weakly connected components). Returns a
+// map from every node ID to a component label; two nodes are in the same component exactly when their labels are equal. The label is the ID of one of the component's nodes.
+func StreamConnectedComponents(stream EdgeStream) (map[int]int, error) {
+	// A union-find over the IDs themselves, rather than a set.DisjointSet, to keep the per-node memory down to a couple of map entries
+	parents := make(map[int]int)
+	var find func(id int) int
+	find = func(id int) int {
+		parent, ok := parents[id]
+		if !ok {
+			parents[id] = id
+			return id
+		}
+		if parent != id {
+			parent = find(parent)
+			parents[id] = parent
+		}
+		return parent
+	}
+
+	ranks := make(map[int]int)
+	err := stream.Edges(func(head, tail int) error {
+		h, t := find(head), find(tail)
+		if h == t {
+			return nil
+		}
+
+		if ranks[h] < ranks[t] {
+			h, t = t, h
+		}
+		parents[t] = h
+		if ranks[h] == ranks[t] {
+			ranks[h]++
+		}
+		delete(ranks, t) // Only roots need ranks
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	components := make(map[int]int, len(parents))
+	for id := range parents {
+		components[id] = find(id)
+	}
+
+	return components, nil
+}
+
+// Computes PageRank over the streamed graph with one pass for the degrees and one pass per iteration (100 if iterations <= 0), keeping only two ranks and a degree per node in memory. The
+// results match PageRank on the equivalent in-memory graph: nodes without successors are treated as linking to every node, and the ranks sum to 1.
+func StreamPageRank(stream EdgeStream, damping float64, iterations int) (map[int]float64, error) {
+	if iterations <= 0 {
+		iterations = 100
+	}
+
+	outDegrees, _, err := StreamDegrees(stream)
+	if err != nil {
+		return nil, err
+	}
+	if len(outDegrees) == 0 {
+		return map[int]float64{}, nil
+	}
+
+	n := float64(len(outDegrees))
+	ranks := make(map[int]float64, len(outDegrees))
+	for id := range outDegrees {
+		ranks[id] = 1 / n
+	}
+
+	for i := 0; i < iterations; i++ {
+		dangling := 0.0
+		for id, degree := range outDegrees {
+			if degree == 0 {
+				dangling += ranks[id]
+			}
+		}
+
+		next := make(map[int]float64, len(ranks))
+		base := (1-damping)/n + damping*dangling/n
+		for id := range ranks {
+			next[id] = base
+		}
+		err = stream.Edges(func(head, tail int) error {
+			next[tail] += damping * ranks[head] / float64(outDegrees[head])
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ranks = next
+	}
+
+	return ranks, nil
+}
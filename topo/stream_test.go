@@ -0,0 +1,73 @@
+package topo_test
+
+import (
+	"github.com/nathankerr/graph/topo"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestEdgeFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "edges")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("# head tail weight\n1 2 0.5\n\n2\t3\n% comment\n3 1\n4, 5\n")
+	file.Close()
+
+	stream := topo.EdgeFile(file.Name())
+	out, in, err := topo.StreamDegrees(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 5 || out[1] != 1 || in[1] != 1 || out[5] != 0 || in[4] != 0 {
+		t.Errorf("Got out-degrees %v and in-degrees %v", out, in)
+	}
+
+	components, err := topo.StreamConnectedComponents(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if components[1] != components[3] || components[4] != components[5] || components[1] == components[4] {
+		t.Errorf("Got components %v", components)
+	}
+
+	ioutil.WriteFile(file.Name(), []byte("1 2\n2 x\n"), 0644)
+	if _, _, err := topo.StreamDegrees(stream); err == nil {
+		t.Error("No error for a malformed edge")
+	}
+}
+
+func TestStreamMatchesInMemory(t *testing.T) {
+	g := randomGraph(300, 2, true, 3)
+	stream := topo.GraphEdgeStream{Graph: g}
+
+	want := topo.PageRank(g, .85, 30)
+	got, err := topo.StreamPageRank(stream, .85, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nodes without any edges never show up in a stream, so only compare the ones that do
+	for id, rank := range got {
+		if math.Abs(rank-want[id]) > 1e-3 {
+			t.Errorf("Node %d has streamed rank %f, want %f", id, rank, want[id])
+		}
+	}
+
+	components, err := topo.StreamConnectedComponents(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, component := range topo.ParallelConnectedComponents(g, 0) {
+		if len(component) == 1 {
+			continue
+		}
+		for _, node := range component {
+			if components[node.ID()] != components[component[0].ID()] {
+				t.Fatalf("Node %d isn't in the same component as node %d", node.ID(), component[0].ID())
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package topo
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"github.com/nathankerr/graph/simple"
+)
+
+// A Visitor receives callbacks as BreadthFirst or DepthFirst traverse a graph. Any of the callbacks may be nil.
+//
+// Every edge out of a reached node is reported to exactly one of TreeEdge, BackEdge or OtherEdge. TreeEdge gets the edges the traversal first reaches a node through, which form a tree
+// rooted at the start. In a depth first traversal BackEdge gets the edges that lead to a node still being explored (an ancestor in the tree, so each one closes a cycle) and OtherEdge the
+// ones that lead to a node that's finished (a forward or cross edge). A breadth first traversal can't tell those apart cheaply, so it gives all of its non-tree edges to OtherEdge.
+//
+// The edges of an undirected graph are reported once, not once per direction: the reverse of a tree edge is skipped, and in a depth first traversal the remaining edges are all back
+// edges, since there are no forward or cross edges in an undirected graph.
+type Visitor struct {
+	Visit     func(node core.Node) bool // Called when a node is first reached; return false to stop the traversal
+	TreeEdge  func(e core.Edge)
+	BackEdge  func(e core.Edge)
+	OtherEdge func(e core.Edge)
+	Finish    func(node core.Node) // Called once everything reachable from node has been explored. Depth first only
+}
+
+// Visits every node reachable from start in breadth first order, calling visit on each as it's reached, until visit returns false. Returns the node the traversal stopped at, or nil if
+// it visited every reachable node (or start isn't in the graph). Use BreadthFirst for edge callbacks.
+func BFS(start core.Node, graph core.Graph, visit func(core.Node) bool) core.Node {
+	return BreadthFirst(start, graph, &Visitor{Visit: visit})
+}
+
+// Visits every node reachable from start in depth first order (preorder), calling visit on each as it's reached, until visit returns false. Returns the node the traversal stopped at, or
+// nil if it visited every reachable node (or start isn't in the graph). Use DepthFirst for edge callbacks and postorder. To find a path rather than visit nodes, see DepthFirstSearch.
+func DFS(start core.Node, graph core.Graph, visit func(core.Node) bool) core.Node {
+	return DepthFirst(start, graph, &Visitor{Visit: visit})
+}
+
+// Traverses the nodes reachable from start in breadth first order, so that nodes are visited in order of their distance (in edges) from start, and successors in the order the graph
+// lists them. Returns the node at which the visitor stopped the traversal, or nil if it ran to completion.
+func BreadthFirst(start core.Node, graph core.Graph, visitor *Visitor) core.Node {
+	if !graph.NodeExists(start) {
+		return nil
+	}
+	if visitor == nil {
+		visitor = &Visitor{}
+	}
+	undirected := !graph.IsDirected()
+
+	const (
+		queued = iota + 1
+		done
+	)
+	state := map[int]int{start.ID(): queued}
+	if visitor.Visit != nil && !visitor.Visit(start) {
+		return start
+	}
+
+	queue := []core.Node{start}
+	for k := 0; k < len(queue); k++ {
+		node := queue[k]
+		for _, succ := range graph.Successors(node) {
+			e := core.GonumEdge{H: node, T: succ}
+			switch state[succ.ID()] {
+			case 0:
+				state[succ.ID()] = queued
+				if visitor.TreeEdge != nil {
+					visitor.TreeEdge(e)
+				}
+				if visitor.Visit != nil && !visitor.Visit(succ) {
+					return succ
+				}
+				queue = append(queue, succ)
+			case done:
+				// In an undirected graph this edge was already reported from the other end, as a tree edge if this is the reverse of one
+				if !undirected && visitor.OtherEdge != nil {
+					visitor.OtherEdge(e)
+				}
+			default:
+				if visitor.OtherEdge != nil {
+					visitor.OtherEdge(e)
+				}
+			}
+		}
+		state[node.ID()] = done
+	}
+
+	return nil
+}
+
+type dfsFrame struct {
+	node          core.Node
+	successors    []core.Node
+	next          int
+	skippedParent bool
+}
+
+// Traverses the nodes reachable from start in depth first order, following successors in the order the graph lists them. Nodes are passed to Visit in preorder and to Finish in
+// postorder. Returns the node at which the visitor stopped the traversal, or nil if it ran to completion. The traversal keeps its own stack, so it works on graphs of any depth.
+func DepthFirst(start core.Node, graph core.Graph, visitor *Visitor) core.Node {
+	if !graph.NodeExists(start) {
+		return nil
+	}
+	if visitor == nil {
+		visitor = &Visitor{}
+	}
+	undirected := !graph.IsDirected()
+
+	const (
+		open = iota + 1
+		finished
+	)
+	state := map[int]int{start.ID(): open}
+	if visitor.Visit != nil && !visitor.Visit(start) {
+		return start
+	}
+
+	stack := []*dfsFrame{{node: start, successors: graph.Successors(start)}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if top.next == len(top.successors) {
+			state[top.node.ID()] = finished
+			if visitor.Finish != nil {
+				visitor.Finish(top.node)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		succ := top.successors[top.next]
+		top.next++
+		e := core.GonumEdge{H: top.node, T: succ}
+		switch state[succ.ID()] {
+		case 0:
+			state[succ.ID()] = open
+			if visitor.TreeEdge != nil {
+				visitor.TreeEdge(e)
+			}
+			if visitor.Visit != nil && !visitor.Visit(succ) {
+				return succ
+			}
+			stack = append(stack, &dfsFrame{node: succ, successors: graph.Successors(succ)})
+		case open:
+			if undirected && !top.skippedParent && len(stack) > 1 && stack[len(stack)-2].node.ID() == succ.ID() {
+				top.skippedParent = true
+				continue
+			}
+			if visitor.BackEdge != nil {
+				visitor.BackEdge(e)
+			}
+		case finished:
+			if !undirected && visitor.OtherEdge != nil {
+				visitor.OtherEdge(e)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the breadth first tree of the nodes reachable from start: a directed graph holding every reachable node, with an edge from each node to the ones it was the first to reach,
+// at the graph's cost. The path from start to any node in the tree has the fewest possible edges. Returns an empty graph if start isn't in the graph.
+func BFSTree(start core.Node, graph core.Graph) *simple.GonumGraph {
+	tree := traversalTree(start, graph)
+	BreadthFirst(start, graph, &Visitor{TreeEdge: tree.add})
+	return tree.GonumGraph
+}
+
+// Returns the depth first tree of the nodes reachable from start: a directed graph holding every reachable node, with an edge from each node to the ones it was the first to reach, at
+// the graph's cost. Returns an empty graph if start isn't in the graph.
+func DFSTree(start core.Node, graph core.Graph) *simple.GonumGraph {
+	tree := traversalTree(start, graph)
+	DepthFirst(start, graph, &Visitor{TreeEdge: tree.add})
+	return tree.GonumGraph
+}
+
+type treeBuilder struct {
+	*simple.GonumGraph
+	cost func(core.Node, core.Node) float64
+}
+
+func traversalTree(start core.Node, graph core.Graph) treeBuilder {
+	tree := treeBuilder{simple.NewGonumGraph(true), graphutil.DefaultCost(graph, nil)}
+	if graph.NodeExists(start) {
+		tree.AddNode(start, nil)
+	}
+
+	return tree
+}
+
+func (tree treeBuilder) add(e core.Edge) {
+	tree.AddNode(e.Tail(), nil)
+	tree.AddEdge(e)
+	tree.SetEdgeCost(e, tree.cost(e.Head(), e.Tail()))
+}
@@ -0,0 +1,94 @@
+package topo_test
+
+import (
+	"fmt"
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"testing"
+)
+
+func TestTraversals(t *testing.T) {
+	// 0 -> 1 -> 3 -> 0 (a cycle), 0 -> 2 -> 3 (a cross edge once 3 is finished), 0 -> 3 (a forward edge), and 4, unreachable
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), nodes(1))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(0), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(0)})
+	g.AddNode(core.GonumNode(4), nodes(0))
+
+	// Successors come out of a map, so only check what doesn't depend on their order
+	var order []int
+	var tree, back, other int
+	topo.DepthFirst(core.GonumNode(0), g, &topo.Visitor{
+		Visit:     func(node core.Node) bool { order = append(order, node.ID()); return true },
+		TreeEdge:  func(core.Edge) { tree++ },
+		BackEdge:  func(e core.Edge) { back++ },
+		OtherEdge: func(core.Edge) { other++ },
+	})
+	if len(order) != 4 || order[0] != 0 || tree != 3 || back != 1 || other != 2 {
+		t.Errorf("DFS visited %v with %d tree, %d back and %d other edges, want 4 nodes and 3, 1 and 2", order, tree, back, other)
+	}
+
+	order, tree, other = nil, 0, 0
+	topo.BreadthFirst(core.GonumNode(0), g, &topo.Visitor{
+		Visit:     func(node core.Node) bool { order = append(order, node.ID()); return true },
+		TreeEdge:  func(core.Edge) { tree++ },
+		OtherEdge: func(core.Edge) { other++ },
+	})
+	if len(order) != 4 || order[0] != 0 || order[3] == 0 || tree != 3 || other != 3 {
+		t.Errorf("BFS visited %v with %d tree and %d other edges, want 3 and 3", order, tree, other)
+	}
+
+	// 3 is a neighbor of 0, so breadth first finds it first, whatever the order
+	steps := 0
+	stopped := topo.BFS(core.GonumNode(0), g, func(node core.Node) bool { steps++; return node.ID() != 3 })
+	if stopped == nil || stopped.ID() != 3 || steps > 4 {
+		t.Errorf("BFS stopped at %v after %d nodes", stopped, steps)
+	}
+	if stopped := topo.DFS(core.GonumNode(0), g, func(core.Node) bool { return true }); stopped != nil {
+		t.Errorf("DFS stopped at %v", stopped)
+	}
+
+	bfsTree := topo.BFSTree(core.GonumNode(0), g)
+	if len(bfsTree.NodeList()) != 4 || len(bfsTree.EdgeList()) != 3 || !bfsTree.IsSuccessor(core.GonumNode(0), core.GonumNode(3)) {
+		t.Errorf("Got BFS tree with edges %v", bfsTree.EdgeList())
+	}
+	if dfsTree := topo.DFSTree(core.GonumNode(0), g); len(dfsTree.NodeList()) != 4 || len(dfsTree.EdgeList()) != 3 || len(dfsTree.Predecessors(core.GonumNode(0))) != 0 {
+		t.Errorf("Got DFS tree with edges %v", dfsTree.EdgeList())
+	}
+}
+
+func TestUndirectedTraversals(t *testing.T) {
+	// A square, 0-1-2-3-0, with a tail 3-4
+	g := simple.NewGonumGraph(false)
+	g.AddNode(core.GonumNode(0), nodes(1, 3))
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)})
+
+	for name, traverse := range map[string]func(core.Node, core.Graph, *topo.Visitor) core.Node{"BFS": topo.BreadthFirst, "DFS": topo.DepthFirst} {
+		counts := make(map[string]int)
+		finished := 0
+		traverse(core.GonumNode(0), g, &topo.Visitor{
+			TreeEdge:  func(core.Edge) { counts["tree"]++ },
+			BackEdge:  func(core.Edge) { counts["back"]++ },
+			OtherEdge: func(core.Edge) { counts["other"]++ },
+			Finish:    func(core.Node) { finished++ },
+		})
+
+		// Every edge is reported once, and the one that closes the square isn't a tree edge
+		if counts["tree"] != 4 || counts["back"]+counts["other"] != 1 {
+			t.Errorf("%s reported %v", name, counts)
+		}
+		if want := map[string]int{"BFS": 0, "DFS": 5}[name]; finished != want {
+			t.Errorf("%s finished %d nodes, want %d", name, finished, want)
+		}
+	}
+
+	if got := fmt.Sprint(topo.BFSTree(core.GonumNode(7), g).NodeList()); got != "[]" {
+		t.Errorf("Tree from a missing node has nodes %s", got)
+	}
+}
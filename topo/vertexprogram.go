@@ -0,0 +1,314 @@
+package topo
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/internal/graphutil"
+	"runtime"
+)
+
+// A VertexProgram is an iterative algorithm written from the point of view of a single node, in the style of Google's Pregel. RunVertexProgram executes it in bulk-synchronous supersteps:
+// during each superstep Compute is called (concurrently) for every active vertex with the messages sent to it during the previous superstep, and messages sent during this superstep are
+// delivered at the start of the next one.
+//
+// A vertex stays active until it calls VoteToHalt, and is reactivated if it's sent a message. Compute is only ever called for a given vertex by one goroutine at a time, and may freely read
+// and write that vertex's Value, but must not touch other vertices.
+type VertexProgram interface {
+	Init(node core.Node) interface{}                // Returns the starting Value of the node
+	Compute(vertex *Vertex, messages []interface{}) // The messages slice is reused once Compute returns, so copy anything that needs to be kept
+}
+
+// A Vertex is a node's view of the graph while a VertexProgram is running
+type Vertex struct {
+	core.Node
+	Value     interface{}
+	Superstep int // Starts at 0
+
+	index  int32
+	halted bool
+	worker int
+	run    *vertexRun
+}
+
+type vertexMessage struct {
+	to      int32
+	message interface{}
+}
+
+type vertexRun struct {
+	out, in    *indexGraph
+	vertices   []Vertex
+	owners     []int // The worker that owns, and receives messages for, each vertex
+	inboxes    [][]interface{}
+	outboxes   [][][]vertexMessage // outboxes[sender][owner]
+	partials   []float64           // Each worker's share of the aggregate during this superstep
+	aggregated float64
+}
+
+// Runs the program over the graph for at most the given number of supersteps, and returns the final Value of every node. If iterations <= 0, it runs until every vertex has voted to halt
+// and no messages are left undelivered. The work is split between runtime.GOMAXPROCS(0) goroutines.
+//
+// The graph is copied before the program starts, so the graph's read methods are called concurrently (see ParallelBFS), and changes to the graph while the program runs aren't seen.
+func RunVertexProgram(graph core.Graph, program VertexProgram, iterations int) map[int]interface{} {
+	workers := runtime.GOMAXPROCS(0)
+	out, in := newIndexGraph(graph, false, workers)
+	n := len(out.nodes)
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	run := &vertexRun{
+		out:      out,
+		in:       in,
+		vertices: make([]Vertex, n),
+		owners:   make([]int, n),
+		inboxes:  make([][]interface{}, n),
+		outboxes: make([][][]vertexMessage, workers),
+		partials: make([]float64, workers),
+	}
+	for w := range run.outboxes {
+		run.outboxes[w] = make([][]vertexMessage, workers)
+	}
+	graphutil.ParallelRange(n, workers, func(chunk, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			run.vertices[i] = Vertex{Node: out.nodes[i], Value: program.Init(out.nodes[i]), index: int32(i), worker: chunk, run: run}
+			run.owners[i] = chunk
+		}
+	})
+
+	for step := 0; iterations <= 0 || step < iterations; step++ {
+		active := make([]bool, workers)
+		graphutil.ParallelRange(n, workers, func(chunk, lo, hi int) {
+			for i := lo; i < hi; i++ {
+				vertex := &run.vertices[i]
+				if vertex.halted && len(run.inboxes[i]) == 0 {
+					continue
+				}
+
+				vertex.halted = false
+				vertex.Superstep = step
+				program.Compute(vertex, run.inboxes[i])
+				if !vertex.halted {
+					active[chunk] = true
+				}
+			}
+		})
+
+		run.aggregated = 0
+		for w, partial := range run.partials {
+			run.aggregated += partial
+			run.partials[w] = 0
+		}
+
+		// Each worker collects the messages for the vertices it owns, so delivery doesn't need any locking
+		delivered := make([]bool, workers)
+		graphutil.ParallelRange(n, workers, func(chunk, lo, hi int) {
+			for i := lo; i < hi; i++ {
+				run.inboxes[i] = run.inboxes[i][:0]
+			}
+			for sender := range run.outboxes {
+				for _, msg := range run.outboxes[sender][chunk] {
+					run.inboxes[msg.to] = append(run.inboxes[msg.to], msg.message)
+					delivered[chunk] = true
+				}
+				run.outboxes[sender][chunk] = run.outboxes[sender][chunk][:0]
+			}
+		})
+
+		done := true
+		for w := 0; w < workers; w++ {
+			if active[w] || delivered[w] {
+				done = false
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	values := make(map[int]interface{}, n)
+	for _, vertex := range run.vertices {
+		values[vertex.ID()] = vertex.Value
+	}
+
+	return values
+}
+
+// Returns the nodes this vertex has outbound edges to
+func (v *Vertex) Successors() []core.Node {
+	return v.nodes(v.run.out.neighbors(v.index))
+}
+
+// Returns the nodes this vertex has inbound edges from
+func (v *Vertex) Predecessors() []core.Node {
+	return v.nodes(v.run.in.neighbors(v.index))
+}
+
+func (v *Vertex) nodes(indices []int32) []core.Node {
+	nodes := make([]core.Node, len(indices))
+	for i, j := range indices {
+		nodes[i] = v.run.out.nodes[j]
+	}
+
+	return nodes
+}
+
+// Returns len(Successors()) without building the list
+func (v *Vertex) OutDegree() int {
+	return v.run.out.degree(v.index)
+}
+
+// Returns the number of nodes in the graph the program is running on
+func (v *Vertex) NumVertices() int {
+	return len(v.run.vertices)
+}
+
+// Sends a message that the given node will receive in the next superstep. Messages to nodes that aren't in the graph are dropped.
+func (v *Vertex) SendTo(node core.Node, message interface{}) {
+	if i, ok := v.run.out.indices[node.ID()]; ok {
+		v.send(int32(i), message)
+	}
+}
+
+// Sends the same message to every successor
+func (v *Vertex) SendToSuccessors(message interface{}) {
+	for _, i := range v.run.out.neighbors(v.index) {
+		v.send(i, message)
+	}
+}
+
+// Sends the same message to every predecessor
+func (v *Vertex) SendToPredecessors(message interface{}) {
+	for _, i := range v.run.in.neighbors(v.index) {
+		v.send(i, message)
+	}
+}
+
+func (v *Vertex) send(to int32, message interface{}) {
+	box := &v.run.outboxes[v.worker][v.run.owners[to]]
+	*box = append(*box, vertexMessage{to, message})
+}
+
+// Deactivates the vertex until it receives a message
+func (v *Vertex) VoteToHalt() {
+	v.halted = true
+}
+
+// Adds x to this superstep's global sum, which every vertex can read with Aggregated during the next superstep
+func (v *Vertex) Aggregate(x float64) {
+	v.run.partials[v.worker] += x
+}
+
+// Returns the sum of everything passed to Aggregate during the previous superstep
+func (v *Vertex) Aggregated() float64 {
+	return v.run.aggregated
+}
+
+/* Algorithms implemented as vertex programs */
+
+type pageRankProgram struct {
+	damping float64
+	n       float64
+}
+
+func (pr pageRankProgram) Init(node core.Node) interface{} {
+	return 1 / pr.n
+}
+
+func (pr pageRankProgram) Compute(v *Vertex, messages []interface{}) {
+	if v.Superstep > 0 {
+		sum := v.Aggregated() / pr.n // The rank of nodes without successors is spread over the whole graph
+		for _, msg := range messages {
+			sum += msg.(float64)
+		}
+		v.Value = (1-pr.damping)/pr.n + pr.damping*sum
+	}
+
+	rank := v.Value.(float64)
+	if degree := v.OutDegree(); degree > 0 {
+		v.SendToSuccessors(rank / float64(degree))
+	} else {
+		v.Aggregate(rank)
+	}
+}
+
+// Computes the PageRank of every node with the given damping factor (usually 0.85), using iterations rounds of the power method (or 100 if iterations <= 0). The ranks sum to 1. Nodes
+// without successors are treated as if they linked to every node, and an undirected graph is treated as having edges in both directions.
+//
+// This runs on RunVertexProgram, so it uses all available cores.
+func PageRank(graph core.Graph, damping float64, iterations int) map[int]float64 {
+	if iterations <= 0 {
+		iterations = 100
+	}
+
+	nodes := graph.NodeList()
+	if len(nodes) == 0 {
+		return map[int]float64{}
+	}
+
+	values := RunVertexProgram(graph, pageRankProgram{damping, float64(len(nodes))}, iterations+1)
+	ranks := make(map[int]float64, len(values))
+	for id, value := range values {
+		ranks[id] = value.(float64)
+	}
+
+	return ranks
+}
+
+type labelPropagationProgram struct{}
+
+func (labelPropagationProgram) Init(node core.Node) interface{} {
+	return node.ID()
+}
+
+func (labelPropagationProgram) Compute(v *Vertex, messages []interface{}) {
+	if v.Superstep > 1 && v.Aggregated() == 0 { // Nobody changed their label last superstep
+		v.VoteToHalt()
+		return
+	}
+
+	if v.Superstep > 0 {
+		label := v.Value.(int)
+		counts := map[int]int{label: 1}
+		for _, msg := range messages {
+			counts[msg.(int)]++
+		}
+
+		best := label
+		for l, count := range counts {
+			if count > counts[best] || (count == counts[best] && l < best) {
+				best = l
+			}
+		}
+		if best != label {
+			v.Value = best
+			v.Aggregate(1)
+		}
+	}
+
+	v.SendToSuccessors(v.Value)
+	if v.run.in != v.run.out {
+		v.SendToPredecessors(v.Value)
+	}
+}
+
+// Finds communities with synchronous label propagation (Raghavan, Albert and Kumara). Every node starts with its own ID as its label, and then repeatedly adopts the label that is most
+// common among itself and its neighbors, preferring the lowest label on ties, until no label changes or iterations supersteps have run (or 100 if iterations <= 0, since synchronous
+// updates can oscillate forever on some graphs). Edge direction is ignored. Returns each node's final label; nodes that share a label form a community.
+//
+// This runs on RunVertexProgram, so it uses all available cores.
+func LabelPropagation(graph core.Graph, iterations int) map[int]int {
+	if iterations <= 0 {
+		iterations = 100
+	}
+
+	values := RunVertexProgram(graph, labelPropagationProgram{}, iterations)
+	labels := make(map[int]int, len(values))
+	for id, value := range values {
+		labels[id] = value.(int)
+	}
+
+	return labels
+}
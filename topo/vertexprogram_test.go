@@ -0,0 +1,98 @@
+package topo_test
+
+import (
+	"github.com/nathankerr/graph/core"
+	"github.com/nathankerr/graph/simple"
+	"github.com/nathankerr/graph/topo"
+	"math"
+	"testing"
+)
+
+// Counts down from each node's ID, halting at zero, to check that halted vertices stay halted and that the run stops on its own
+type countdownProgram struct{}
+
+func (countdownProgram) Init(node core.Node) interface{} {
+	return node.ID()
+}
+
+func (countdownProgram) Compute(v *topo.Vertex, messages []interface{}) {
+	if v.Value.(int) == 0 {
+		v.VoteToHalt()
+		return
+	}
+	v.Value = v.Value.(int) - 1
+}
+
+func TestRunVertexProgram(t *testing.T) {
+	g := pathGraph(20)
+	values := topo.RunVertexProgram(g, countdownProgram{}, 0)
+	for id, value := range values {
+		if value.(int) != 0 {
+			t.Errorf("Node %d finished with value %v, want 0", id, value)
+		}
+	}
+
+	values = topo.RunVertexProgram(g, countdownProgram{}, 5)
+	if values[3].(int) != 0 || values[12].(int) != 7 {
+		t.Errorf("After 5 supersteps nodes 3 and 12 have values %v and %v, want 0 and 7", values[3], values[12])
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	// 0 -> 1 -> 2 -> 0, 2 -> 3, and 3 has no successors
+	g := simple.NewGonumGraph(true)
+	g.AddNode(core.GonumNode(0), []core.Node{core.GonumNode(1)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(1), T: core.GonumNode(2)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(0)})
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(2), T: core.GonumNode(3)})
+
+	// Plain serial power iteration to compare against
+	const d, n = 0.85, 4.0
+	want := map[int]float64{0: .25, 1: .25, 2: .25, 3: .25}
+	for i := 0; i < 50; i++ {
+		next := map[int]float64{}
+		for id := range want {
+			next[id] = (1-d)/n + d*want[3]/n
+		}
+		next[1] += d * want[0]
+		next[2] += d * want[1]
+		next[0] += d * want[2] / 2
+		next[3] += d * want[2] / 2
+		want = next
+	}
+
+	ranks := topo.PageRank(g, d, 50)
+	sum := 0.0
+	for id, rank := range ranks {
+		sum += rank
+		if math.Abs(rank-want[id]) > 1e-9 {
+			t.Errorf("Node %d has rank %f, want %f", id, rank, want[id])
+		}
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Ranks sum to %f, want 1", sum)
+	}
+}
+
+func TestLabelPropagation(t *testing.T) {
+	// Two 4-cliques joined by a single edge between 3 and 4
+	g := simple.NewGonumGraph(false)
+	for i := 0; i < 8; i++ {
+		g.AddNode(core.GonumNode(i), nil)
+	}
+	for _, clique := range [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}} {
+		for i, a := range clique {
+			for _, b := range clique[i+1:] {
+				g.AddEdge(core.GonumEdge{H: core.GonumNode(a), T: core.GonumNode(b)})
+			}
+		}
+	}
+	g.AddEdge(core.GonumEdge{H: core.GonumNode(3), T: core.GonumNode(4)})
+
+	labels := topo.LabelPropagation(g, 0)
+	for id := 1; id < 8; id++ {
+		if sameClique := id < 4; (labels[id] == labels[0]) != sameClique {
+			t.Errorf("Node %d has label %d and node 0 has label %d", id, labels[id], labels[0])
+		}
+	}
+}